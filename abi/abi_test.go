@@ -46,6 +46,15 @@ func TestABI(t *testing.T) {
 		assert.Nil(t, method.DecodeInput(input, &v))
 		assert.Equal(t, key, thor.Bytes32(v.Key))
 		assert.Equal(t, value, v.Value)
+
+		args, err := method.DecodeInputArgs(input)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(args))
+		assert.Equal(t, "_key", args[0].Name)
+		assert.Equal(t, "bytes32", args[0].Type)
+		assert.Equal(t, [32]byte(key), args[0].Value)
+		assert.Equal(t, "_value", args[1].Name)
+		assert.Equal(t, value, args[1].Value)
 	}
 
 	// pack/unpack output
@@ -82,5 +91,16 @@ func TestABI(t *testing.T) {
 
 		assert.Equal(t, value, d)
 
+		key := thor.BytesToBytes32([]byte("k"))
+		args, err := event.DecodeArgs([]thor.Bytes32{event.ID(), key}, data)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(args))
+		assert.Equal(t, "key", args[0].Name)
+		assert.Equal(t, [32]byte(key), args[0].Value)
+		assert.Equal(t, "value", args[1].Name)
+		assert.Equal(t, value, args[1].Value)
+
+		_, err = event.DecodeArgs([]thor.Bytes32{event.ID()}, data)
+		assert.NotNil(t, err, "indexed topic missing")
 	}
 }
@@ -6,6 +6,8 @@
 package abi
 
 import (
+	"errors"
+
 	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/vechain/thor/thor"
 )
@@ -50,3 +52,48 @@ func (e *Event) Encode(args ...interface{}) ([]byte, error) {
 func (e *Event) Decode(data []byte, v interface{}) error {
 	return e.argsWithoutIndexed.Unpack(v, data)
 }
+
+// DecodeArgs decodes event topics and data into named args, in the order of event inputs.
+// Indexed args of dynamic types are stored as hashes in topics, so hashes returned for them.
+func (e *Event) DecodeArgs(topics []thor.Bytes32, data []byte) ([]*Arg, error) {
+	if !e.event.Anonymous {
+		if len(topics) == 0 || topics[0] != e.id {
+			return nil, errors.New("topics has incorrect event id")
+		}
+		topics = topics[1:]
+	}
+	values, err := e.argsWithoutIndexed.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]*Arg, 0, len(e.event.Inputs))
+	for _, input := range e.event.Inputs {
+		var value interface{}
+		if input.Indexed {
+			if len(topics) == 0 {
+				return nil, errors.New("topics too short")
+			}
+			if value, err = decodeTopic(input.Type, topics[0]); err != nil {
+				return nil, err
+			}
+			topics = topics[1:]
+		} else {
+			value, values = values[0], values[1:]
+		}
+		args = append(args, &Arg{input.Name, input.Type.String(), value})
+	}
+	return args, nil
+}
+
+func decodeTopic(typ ethabi.Type, topic thor.Bytes32) (interface{}, error) {
+	switch typ.T {
+	case ethabi.StringTy, ethabi.BytesTy, ethabi.SliceTy, ethabi.ArrayTy:
+		return topic, nil
+	}
+	values, err := ethabi.Arguments{{Type: typ}}.UnpackValues(topic[:])
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
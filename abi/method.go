@@ -50,6 +50,23 @@ func (m *Method) DecodeInput(input []byte, v interface{}) error {
 	return m.method.Inputs.Unpack(v, input[4:])
 }
 
+// DecodeInputArgs decode input data into named args, in the order of method inputs.
+func (m *Method) DecodeInputArgs(input []byte) ([]*Arg, error) {
+	if !bytes.HasPrefix(input, m.id[:]) {
+		return nil, errors.New("input has incorrect prefix")
+	}
+	values, err := m.method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	args := make([]*Arg, 0, len(values))
+	for i, value := range values {
+		input := m.method.Inputs[i]
+		args = append(args, &Arg{input.Name, input.Type.String(), value})
+	}
+	return args, nil
+}
+
 // EncodeOutput encode output args to data.
 func (m *Method) EncodeOutput(args ...interface{}) ([]byte, error) {
 	return m.method.Outputs.Pack(args...)
@@ -63,6 +80,13 @@ func (m *Method) DecodeOutput(output []byte, v interface{}) error {
 	return m.method.Outputs.Unpack(v, output)
 }
 
+// Arg is a decoded argument.
+type Arg struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
 // MethodID method id.
 type MethodID [4]byte
 
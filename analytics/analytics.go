@@ -0,0 +1,151 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package analytics maintains per-block statistics series of the chain.
+package analytics
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/co"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+var log = log15.New("pkg", "analytics")
+
+// Analytics records statistics of blocks as they are imported, and persists them as series.
+// Statistics of blocks not yet recorded, e.g. imported before, are computed on demand.
+type Analytics struct {
+	chain     *chain.Chain
+	stateC    *state.Creator
+	proposers *poa.ProposerCache
+	store     kv.GetPutter
+	cancel    func()
+	goes      co.Goes
+
+	lastPacked atomic.Value // *packer.Breakdown
+}
+
+// New creates analytics, and starts recording newly imported blocks.
+// If store is nil, nothing is recorded, and statistics always computed on demand.
+func New(chain *chain.Chain, stateC *state.Creator, store kv.GetPutter) *Analytics {
+	ctx, cancel := context.WithCancel(context.Background())
+	a := &Analytics{
+		chain:     chain,
+		stateC:    stateC,
+		proposers: poa.NewProposerCache(stateC),
+		store:     store,
+		cancel:    cancel,
+	}
+	if store != nil {
+		// created before returning, so that blocks imported afterwards are all recorded
+		ticker := chain.NewTicker()
+		reader := chain.NewBlockReader(chain.BestBlock().Header().ID())
+		a.goes.Go(func() { a.run(ctx, ticker, reader) })
+	}
+	return a
+}
+
+// Close stops recording.
+func (a *Analytics) Close() {
+	a.cancel()
+	a.goes.Wait()
+}
+
+func (a *Analytics) run(ctx context.Context, ticker co.Waiter, reader chain.BlockReader) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+		for {
+			blocks, err := reader.Read()
+			if err != nil {
+				log.Warn("failed to read blocks", "err", err)
+				reader = a.chain.NewBlockReader(a.chain.BestBlock().Header().ID())
+				break
+			}
+			if len(blocks) == 0 {
+				break
+			}
+			for _, b := range blocks {
+				a.record(b.Header())
+			}
+		}
+	}
+}
+
+// record records statistics of the block.
+func (a *Analytics) record(header *block.Header) {
+	if _, err := a.energyTotals(header); err != nil {
+		log.Warn("failed to record energy stat", "id", header.ID(), "err", err)
+	}
+	if _, err := a.utilizationRecord(header); err != nil {
+		log.Warn("failed to record utilization stat", "id", header.ID(), "err", err)
+	}
+	if header.Number() > 0 {
+		if _, err := a.proposerRecord(header); err != nil {
+			log.Warn("failed to record proposer stat", "id", header.ID(), "err", err)
+		}
+	}
+	if err := a.indexApprovals(header); err != nil {
+		log.Warn("failed to index energy approvals", "id", header.ID(), "err", err)
+	}
+}
+
+// load loads the recorded value of the block. False returned if not recorded.
+func (a *Analytics) load(prefix []byte, id thor.Bytes32, v interface{}) (bool, error) {
+	if a.store == nil {
+		return false, nil
+	}
+	data, err := a.store.Get(seriesKey(prefix, id))
+	if err != nil {
+		if a.store.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, rlp.DecodeBytes(data, v)
+}
+
+func (a *Analytics) save(prefix []byte, id thor.Bytes32, v interface{}) error {
+	if a.store == nil {
+		return nil
+	}
+	data, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		return err
+	}
+	return a.store.Put(seriesKey(prefix, id), data)
+}
+
+// trunkHeaders returns headers of trunk blocks in range [from, to].
+func (a *Analytics) trunkHeaders(from, to uint32) ([]*block.Header, error) {
+	var headers []*block.Header
+	for num := from; num <= to; num++ {
+		header, err := a.chain.GetTrunkBlockHeader(num)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+		if num == to {
+			break
+		}
+	}
+	return headers, nil
+}
+
+func seriesKey(prefix []byte, id thor.Bytes32) []byte {
+	return append(append([]byte(nil), prefix...), id[:]...)
+}
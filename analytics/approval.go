@@ -0,0 +1,95 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package analytics
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+// energyapproval + owner + spender -> empty, for each pair ever seen in energy Approval events
+var energyApprovalPrefix = []byte("energyapproval")
+
+var approvalEventID = func() thor.Bytes32 {
+	ev, _ := builtin.Energy.ABI.EventByName("Approval")
+	return ev.ID()
+}()
+
+// EnergyAllowance is the energy (VTHO) amount a spender is approved to transfer from an owner.
+type EnergyAllowance struct {
+	Spender   thor.Address
+	Allowance *big.Int
+}
+
+// EnergyAllowances returns non-zero allowances of the owner at the best block, sorted by spender address.
+// Spenders are looked up from Approval events indexed, so approvals in blocks imported before recording
+// started are not listed.
+func (a *Analytics) EnergyAllowances(owner thor.Address) ([]*EnergyAllowance, error) {
+	if a.store == nil {
+		return nil, nil
+	}
+	best := a.chain.BestBlock().Header()
+	st, err := a.stateC.NewState(best.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	energy := builtin.Energy.Native(st, best.Timestamp())
+
+	prefix := append(append([]byte(nil), energyApprovalPrefix...), owner[:]...)
+	it := a.store.NewIterator(*kv.NewRangeWithBytesPrefix(prefix))
+	defer it.Release()
+
+	var allowances []*EnergyAllowance
+	for it.Next() {
+		spender := thor.BytesToAddress(it.Key()[len(prefix):])
+		// revoked, spent, or approved on a side branch
+		if allowance := energy.GetAllowance(owner, spender); allowance.Sign() > 0 {
+			allowances = append(allowances, &EnergyAllowance{spender, allowance})
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	return allowances, nil
+}
+
+// indexApprovals indexes owner and spender pairs of energy Approval events emitted in the block.
+func (a *Analytics) indexApprovals(header *block.Header) error {
+	if a.store == nil {
+		return nil
+	}
+	receipts, err := a.chain.GetBlockReceipts(header.ID())
+	if err != nil {
+		return err
+	}
+	batch := a.store.NewBatch()
+	for _, r := range receipts {
+		for _, output := range r.Outputs {
+			for _, ev := range output.Events {
+				if ev.Address != builtin.Energy.Address || len(ev.Topics) != 3 || ev.Topics[0] != approvalEventID {
+					continue
+				}
+				owner := thor.BytesToAddress(ev.Topics[1][:])
+				spender := thor.BytesToAddress(ev.Topics[2][:])
+				key := append(append(append([]byte(nil), energyApprovalPrefix...), owner[:]...), spender[:]...)
+				if err := batch.Put(key, []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	return batch.Write()
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package analytics
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+)
+
+var energyStatPrefix = []byte("energystat")
+
+// energyTotals totals of energy at a block, persisted per block id.
+type energyTotals struct {
+	Supply *big.Int
+	Burned *big.Int
+}
+
+// EnergyStat energy (VTHO) statistics of a block.
+type EnergyStat struct {
+	Header      *block.Header
+	TotalSupply *big.Int // total supply after the block
+	TotalBurned *big.Int // total net burned after the block
+	Generated   *big.Int // generated in the block, by VET holding
+	Burned      *big.Int // net burned in the block, consumed by txs minus rewarded
+}
+
+// EnergyStats returns energy statistics of trunk blocks in range [from, to].
+func (a *Analytics) EnergyStats(from, to uint32) ([]*EnergyStat, error) {
+	headers, err := a.trunkHeaders(from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]*EnergyStat, 0, len(headers))
+	for _, header := range headers {
+		stat, err := a.energyStat(header)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+func (a *Analytics) energyStat(header *block.Header) (*EnergyStat, error) {
+	totals, err := a.energyTotals(header)
+	if err != nil {
+		return nil, err
+	}
+	// initial supply of genesis is allocated, not generated
+	parentTotals := totals
+	if header.Number() > 0 {
+		parent, err := a.chain.GetBlockHeader(header.ParentID())
+		if err != nil {
+			return nil, err
+		}
+		if parentTotals, err = a.energyTotals(parent); err != nil {
+			return nil, err
+		}
+	}
+	return &EnergyStat{
+		Header:      header,
+		TotalSupply: totals.Supply,
+		TotalBurned: totals.Burned,
+		Generated:   new(big.Int).Sub(totals.Supply, parentTotals.Supply),
+		Burned:      new(big.Int).Sub(totals.Burned, parentTotals.Burned),
+	}, nil
+}
+
+// energyTotals loads recorded totals of the block, or computes and records them from state.
+func (a *Analytics) energyTotals(header *block.Header) (*energyTotals, error) {
+	var totals energyTotals
+	if found, err := a.load(energyStatPrefix, header.ID(), &totals); err != nil || found {
+		return &totals, err
+	}
+
+	st, err := a.stateC.NewState(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	energy := builtin.Energy.Native(st, header.Timestamp())
+	totals.Supply = energy.TotalSupply()
+	totals.Burned = energy.TotalBurned()
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	if err := a.save(energyStatPrefix, header.ID(), &totals); err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
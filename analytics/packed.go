@@ -0,0 +1,23 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package analytics
+
+import (
+	"github.com/vechain/thor/packer"
+)
+
+// RecordPacked records the breakdown of the block packed by the local node.
+// It's kept in memory only.
+func (a *Analytics) RecordPacked(breakdown *packer.Breakdown) {
+	a.lastPacked.Store(breakdown)
+}
+
+// LastPacked returns the breakdown of the block last packed by the local node.
+// Nil returned if nothing packed since started.
+func (a *Analytics) LastPacked() *packer.Breakdown {
+	breakdown, _ := a.lastPacked.Load().(*packer.Breakdown)
+	return breakdown
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package analytics
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/thor"
+)
+
+var proposerStatPrefix = []byte("proposerstat")
+
+// proposerRecord proposing facts of a block, persisted per block id.
+type proposerRecord struct {
+	Signer   thor.Address
+	Missed   []thor.Address // proposers of skipped slots
+	GasUsed  uint64
+	GasLimit uint64
+}
+
+// ProposerStat statistics of a block proposer (signer) over a range of blocks.
+type ProposerStat struct {
+	Signer      thor.Address
+	Proposed    uint32  // count of blocks proposed
+	Missed      uint32  // count of slots missed
+	AvgFullness float64 // average gas used ratio of blocks proposed
+}
+
+// ProposerStats returns statistics of proposers, who proposed or missed trunk blocks in range [from, to].
+// Stats are sorted by signer address.
+func (a *Analytics) ProposerStats(from, to uint32) ([]*ProposerStat, error) {
+	headers, err := a.trunkHeaders(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[thor.Address]*ProposerStat)
+	statOf := func(signer thor.Address) *ProposerStat {
+		stat := stats[signer]
+		if stat == nil {
+			stat = &ProposerStat{Signer: signer}
+			stats[signer] = stat
+		}
+		return stat
+	}
+	for _, header := range headers {
+		if header.Number() == 0 {
+			continue
+		}
+		record, err := a.proposerRecord(header)
+		if err != nil {
+			return nil, err
+		}
+		stat := statOf(record.Signer)
+		stat.Proposed++
+		// accumulated as sum here, and averaged below
+		stat.AvgFullness += float64(record.GasUsed) / float64(record.GasLimit)
+		for _, missed := range record.Missed {
+			statOf(missed).Missed++
+		}
+	}
+
+	result := make([]*ProposerStat, 0, len(stats))
+	for _, stat := range stats {
+		if stat.Proposed > 0 {
+			stat.AvgFullness /= float64(stat.Proposed)
+		}
+		result = append(result, stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Signer.Bytes(), result[j].Signer.Bytes()) < 0
+	})
+	return result, nil
+}
+
+// proposerRecord loads the recorded proposing facts of the block, or computes and records them.
+// The block must not be genesis.
+func (a *Analytics) proposerRecord(header *block.Header) (*proposerRecord, error) {
+	var record proposerRecord
+	if found, err := a.load(proposerStatPrefix, header.ID(), &record); err != nil || found {
+		return &record, err
+	}
+
+	signer, err := header.Signer()
+	if err != nil {
+		return nil, err
+	}
+	parent, err := a.chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		return nil, err
+	}
+	proposers, err := a.proposers.Proposers(parent)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := poa.NewScheduler(signer, proposers, parent.Number(), parent.Timestamp())
+	if err != nil {
+		return nil, err
+	}
+
+	record.Signer = signer
+	for _, p := range sched.Skipped(header.Timestamp()) {
+		record.Missed = append(record.Missed, p.Address)
+	}
+	record.GasUsed = header.GasUsed()
+	record.GasLimit = header.GasLimit()
+
+	if err := a.save(proposerStatPrefix, header.ID(), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
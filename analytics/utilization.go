@@ -0,0 +1,103 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package analytics
+
+import (
+	"github.com/vechain/thor/block"
+)
+
+var utilizationStatPrefix = []byte("utilstat")
+
+// utilizationRecord utilization facts of a block, persisted per block id.
+type utilizationRecord struct {
+	GasUsed         uint64
+	GasLimit        uint64
+	TxCount         uint64
+	GasPriceCoefSum uint64
+}
+
+// UtilizationStat network utilization statistics over an interval of blocks.
+type UtilizationStat struct {
+	From            *block.Header // first block of the interval
+	To              *block.Header // last block of the interval
+	GasUsed         uint64
+	GasLimit        uint64
+	Fullness        float64 // gas used percentage of gas limit
+	TxCount         uint64
+	AvgGasPriceCoef float64 // average gas price coef of txs, 0 if no txs
+}
+
+// UtilizationStats returns utilization statistics of trunk blocks in range [from, to],
+// aggregated per interval of blocks. The last interval may be shorter.
+func (a *Analytics) UtilizationStats(from, to uint32, interval uint32) ([]*UtilizationStat, error) {
+	headers, err := a.trunkHeaders(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if interval == 0 {
+		interval = 1
+	}
+
+	var (
+		stats       []*UtilizationStat
+		stat        *UtilizationStat
+		gasPriceSum uint64
+	)
+	finish := func() {
+		if stat.GasLimit > 0 {
+			stat.Fullness = float64(stat.GasUsed) * 100 / float64(stat.GasLimit)
+		}
+		if stat.TxCount > 0 {
+			stat.AvgGasPriceCoef = float64(gasPriceSum) / float64(stat.TxCount)
+		}
+		stats = append(stats, stat)
+	}
+	for i, header := range headers {
+		record, err := a.utilizationRecord(header)
+		if err != nil {
+			return nil, err
+		}
+		if uint32(i)%interval == 0 {
+			if stat != nil {
+				finish()
+			}
+			stat = &UtilizationStat{From: header}
+			gasPriceSum = 0
+		}
+		stat.To = header
+		stat.GasUsed += record.GasUsed
+		stat.GasLimit += record.GasLimit
+		stat.TxCount += record.TxCount
+		gasPriceSum += record.GasPriceCoefSum
+	}
+	if stat != nil {
+		finish()
+	}
+	return stats, nil
+}
+
+// utilizationRecord loads recorded utilization of the block, or computes and records it from block body.
+func (a *Analytics) utilizationRecord(header *block.Header) (*utilizationRecord, error) {
+	var record utilizationRecord
+	if found, err := a.load(utilizationStatPrefix, header.ID(), &record); err != nil || found {
+		return &record, err
+	}
+
+	body, err := a.chain.GetBlockBody(header.ID())
+	if err != nil {
+		return nil, err
+	}
+	record.GasUsed = header.GasUsed()
+	record.GasLimit = header.GasLimit()
+	record.TxCount = uint64(len(body.Txs))
+	for _, tx := range body.Txs {
+		record.GasPriceCoefSum += uint64(tx.GasPriceCoef())
+	}
+	if err := a.save(utilizationStatPrefix, header.ID(), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
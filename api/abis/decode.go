@@ -0,0 +1,128 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package abis
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/abi"
+	"github.com/vechain/thor/thor"
+)
+
+// VIP180 (ERC20 compatible) token ABI.
+const vip180ABI = `[
+	{"constant":true,"inputs":[],"name":"name","outputs":[{"name":"","type":"string"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"totalSupply","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"_owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"balance","type":"uint256"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transfer","outputs":[{"name":"success","type":"bool"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"_from","type":"address"},{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"success","type":"bool"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"_spender","type":"address"},{"name":"_value","type":"uint256"}],"name":"approve","outputs":[{"name":"success","type":"bool"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"_owner","type":"address"},{"name":"_spender","type":"address"}],"name":"allowance","outputs":[{"name":"remaining","type":"uint256"}],"type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"_from","type":"address"},{"indexed":true,"name":"_to","type":"address"},{"indexed":false,"name":"_value","type":"uint256"}],"name":"Transfer","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"_owner","type":"address"},{"indexed":true,"name":"_spender","type":"address"},{"indexed":false,"name":"_value","type":"uint256"}],"name":"Approval","type":"event"}
+]`
+
+// Arg is a decoded argument in JSON friendly form.
+// Integers wider than 64 bits are hex encoded, and bytes are hex encoded.
+type Arg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Decoded is a decoded contract call or event.
+type Decoded struct {
+	Name string `json:"name"`
+	Args []*Arg `json:"args"`
+}
+
+// DecodeCall decodes the input of a call to the contract. Nil returned if no matched ABI.
+// codeHashFunc nil means code hash unknown.
+func (r *Registry) DecodeCall(to thor.Address, codeHashFunc CodeHashFunc, input []byte) (*Decoded, error) {
+	abis, err := r.Lookup(to, codeHashFunc)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range abis {
+		method, err := a.MethodByInput(input)
+		if err != nil {
+			continue
+		}
+		args, err := method.DecodeInputArgs(input)
+		if err != nil {
+			continue
+		}
+		return &Decoded{method.Name(), convertArgs(args)}, nil
+	}
+	return nil, nil
+}
+
+// DecodeEvent decodes an event emitted by the contract. Nil returned if no matched ABI.
+// codeHashFunc nil means code hash unknown.
+func (r *Registry) DecodeEvent(address thor.Address, codeHashFunc CodeHashFunc, topics []thor.Bytes32, data []byte) (*Decoded, error) {
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	abis, err := r.Lookup(address, codeHashFunc)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range abis {
+		event, found := a.EventByID(topics[0])
+		if !found {
+			continue
+		}
+		args, err := event.DecodeArgs(topics, data)
+		if err != nil {
+			continue
+		}
+		return &Decoded{event.Name(), convertArgs(args)}, nil
+	}
+	return nil, nil
+}
+
+func convertArgs(args []*abi.Arg) []*Arg {
+	converted := make([]*Arg, 0, len(args))
+	for _, arg := range args {
+		converted = append(converted, &Arg{arg.Name, arg.Type, convertValue(reflect.ValueOf(arg.Value))})
+	}
+	return converted
+}
+
+func convertValue(v reflect.Value) interface{} {
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		return (*math.HexOrDecimal256)(value)
+	case common.Address:
+		return thor.Address(value)
+	case thor.Bytes32:
+		return value
+	case []byte:
+		return hexutil.Bytes(value)
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// fixed bytes
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Bytes(b)
+		}
+		values := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, convertValue(v.Index(i)))
+		}
+		return values
+	}
+	return v.Interface()
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package abis
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/abi"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/builtin/gen"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// keys are namespaced to not be mixed up with trie nodes, which are keyed by 32 bytes hashes in the same store
+var (
+	keyPrefix         = []byte("abiregistry.")
+	addressKeyPrefix  = append(append([]byte(nil), keyPrefix...), 'a')
+	codeHashKeyPrefix = append(append([]byte(nil), keyPrefix...), 'c')
+)
+
+// CodeHashFunc returns the code hash of the contract.
+type CodeHashFunc func(address thor.Address) (thor.Bytes32, error)
+
+// StateCodeHash returns CodeHashFunc which reads code hashes from the state.
+func StateCodeHash(state *state.State) CodeHashFunc {
+	return func(address thor.Address) (thor.Bytes32, error) {
+		codeHash := state.GetCodeHash(address)
+		return codeHash, state.Err()
+	}
+}
+
+// Entry is an ABI bound to a contract address or a contract code hash.
+// Entries bound to neither are well-known ABIs, tried for any contract.
+type Entry struct {
+	Address  *thor.Address   `json:"address,omitempty"`
+	CodeHash *thor.Bytes32   `json:"codeHash,omitempty"`
+	Name     string          `json:"name"`
+	ABI      json.RawMessage `json:"abi"`
+	Builtin  bool            `json:"builtin"` // bundled, read only
+}
+
+type entry struct {
+	Entry
+	abi *abi.ABI
+}
+
+func newEntry(e *Entry) (*entry, error) {
+	abi, err := abi.New(e.ABI)
+	if err != nil {
+		return nil, err
+	}
+	return &entry{*e, abi}, nil
+}
+
+// Registry manages ABIs, which are used to decode contract calls and events.
+// Uploaded ABIs are persisted, and well-known ABIs are bundled.
+type Registry struct {
+	store      kv.GetPutter
+	lock       sync.RWMutex
+	builtins   map[thor.Address]*entry
+	wellKnowns []*entry
+	byAddress  map[thor.Address]*entry
+	byCodeHash map[thor.Bytes32]*entry
+}
+
+// New creates the registry, with bundled and persisted ABIs loaded.
+func New(store kv.GetPutter) (*Registry, error) {
+	r := &Registry{
+		store:      store,
+		builtins:   make(map[thor.Address]*entry),
+		byAddress:  make(map[thor.Address]*entry),
+		byCodeHash: make(map[thor.Bytes32]*entry),
+	}
+
+	for _, c := range []struct {
+		name    string
+		address thor.Address
+	}{
+		{"Params", builtin.Params.Address},
+		{"Authority", builtin.Authority.Address},
+		{"Energy", builtin.Energy.Address},
+		{"Executor", builtin.Executor.Address},
+		{"Prototype", builtin.Prototype.Address},
+		{"Extension", builtin.Extension.Address},
+	} {
+		address := c.address
+		e, err := newEntry(&Entry{
+			Address: &address,
+			Name:    c.name,
+			ABI:     gen.MustAsset("compiled/" + c.name + ".abi"),
+			Builtin: true,
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, "load builtin ABI "+c.name)
+		}
+		r.builtins[address] = e
+	}
+
+	for _, wk := range []*Entry{
+		{Name: "VIP180", ABI: json.RawMessage(vip180ABI), Builtin: true},
+		// events emitted by the Prototype contract on behalf of any contract
+		{Name: "PrototypeEvent", ABI: gen.MustAsset("compiled/PrototypeEvent.abi"), Builtin: true},
+	} {
+		e, err := newEntry(wk)
+		if err != nil {
+			return nil, errors.WithMessage(err, "load well-known ABI "+wk.Name)
+		}
+		r.wellKnowns = append(r.wellKnowns, e)
+	}
+
+	it := store.NewIterator(*kv.NewRangeWithBytesPrefix(keyPrefix))
+	defer it.Release()
+	for it.Next() {
+		if !isEntryKey(it.Key()) {
+			continue
+		}
+		var stored Entry
+		if err := json.Unmarshal(it.Value(), &stored); err != nil {
+			return nil, errors.WithMessage(err, "decode ABI entry")
+		}
+		e, err := newEntry(&stored)
+		if err != nil {
+			return nil, errors.WithMessage(err, "load ABI entry")
+		}
+		r.index(e)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Add validates and registers the ABI, replacing the one bound to the same address or code hash.
+func (r *Registry) Add(e *Entry) error {
+	if (e.Address == nil) == (e.CodeHash == nil) {
+		return utils.BadRequest(errors.New("exactly one of address and codeHash required"))
+	}
+	if e.Address != nil && r.builtins[*e.Address] != nil {
+		return utils.BadRequest(errors.New("address: builtin contract"))
+	}
+	added := *e
+	added.Builtin = false
+	ent, err := newEntry(&added)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "abi"))
+	}
+
+	data, err := json.Marshal(&added)
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if err := r.store.Put(entryKey(&added), data); err != nil {
+		return err
+	}
+	r.index(ent)
+	return nil
+}
+
+// Remove removes the ABI bound to the address or code hash. False returned if not found.
+func (r *Registry) Remove(address *thor.Address, codeHash *thor.Bytes32) (bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var e *entry
+	if address != nil {
+		e = r.byAddress[*address]
+		delete(r.byAddress, *address)
+	} else if codeHash != nil {
+		e = r.byCodeHash[*codeHash]
+		delete(r.byCodeHash, *codeHash)
+	}
+	if e == nil {
+		return false, nil
+	}
+	return true, r.store.Delete(entryKey(&e.Entry))
+}
+
+// List returns all ABIs, bundled ones first.
+func (r *Registry) List() []*Entry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var bundled, uploaded []*Entry
+	for _, e := range r.builtins {
+		bundled = append(bundled, copyEntry(e))
+	}
+	for _, e := range r.byAddress {
+		uploaded = append(uploaded, copyEntry(e))
+	}
+	for _, e := range r.byCodeHash {
+		uploaded = append(uploaded, copyEntry(e))
+	}
+	sortEntries(bundled)
+	sortEntries(uploaded)
+	for _, e := range r.wellKnowns {
+		bundled = append(bundled, copyEntry(e))
+	}
+	return append(bundled, uploaded...)
+}
+
+// Lookup returns candidate ABIs for the contract, in order of precedence:
+// bound to the address, bound to the code hash, and well-known ones.
+// codeHashFunc is called only if any ABI bound to code hash, and nil means code hash unknown.
+func (r *Registry) Lookup(address thor.Address, codeHashFunc CodeHashFunc) ([]*abi.ABI, error) {
+	var codeHash thor.Bytes32
+	r.lock.RLock()
+	hasCodeHashBound := len(r.byCodeHash) > 0
+	r.lock.RUnlock()
+	if hasCodeHashBound && codeHashFunc != nil {
+		var err error
+		if codeHash, err = codeHashFunc(address); err != nil {
+			return nil, err
+		}
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var abis []*abi.ABI
+	if e := r.builtins[address]; e != nil {
+		abis = append(abis, e.abi)
+	}
+	if e := r.byAddress[address]; e != nil {
+		abis = append(abis, e.abi)
+	}
+	if !codeHash.IsZero() {
+		if e := r.byCodeHash[codeHash]; e != nil {
+			abis = append(abis, e.abi)
+		}
+	}
+	for _, e := range r.wellKnowns {
+		abis = append(abis, e.abi)
+	}
+	return abis, nil
+}
+
+func (r *Registry) index(e *entry) {
+	if e.Address != nil {
+		r.byAddress[*e.Address] = e
+	} else {
+		r.byCodeHash[*e.CodeHash] = e
+	}
+}
+
+func entryKey(e *Entry) []byte {
+	if e.Address != nil {
+		return append(append([]byte(nil), addressKeyPrefix...), e.Address.Bytes()...)
+	}
+	return append(append([]byte(nil), codeHashKeyPrefix...), e.CodeHash.Bytes()...)
+}
+
+// isEntryKey returns whether the key is of an entry, to skip keys of others sharing the prefix.
+func isEntryKey(key []byte) bool {
+	switch {
+	case bytes.HasPrefix(key, addressKeyPrefix):
+		return len(key) == len(addressKeyPrefix)+20
+	case bytes.HasPrefix(key, codeHashKeyPrefix):
+		return len(key) == len(codeHashKeyPrefix)+32
+	}
+	return false
+}
+
+func copyEntry(e *entry) *Entry {
+	c := e.Entry
+	return &c
+}
+
+func sortEntries(entries []*Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return string(entryKey(entries[i])) < string(entryKey(entries[j]))
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package abis_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/abi"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+const counterABI = `[
+	{"constant":false,"inputs":[{"name":"n","type":"uint8"}],"name":"set","outputs":[],"type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"who","type":"address"},{"indexed":false,"name":"n","type":"uint8"}],"name":"Set","type":"event"}
+]`
+
+func TestRegistry(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	// keys of others sharing the store
+	db.Put(append([]byte("abiregistry.a"), make([]byte, 19)...), []byte("x"))
+	db.Put(thor.Blake2b([]byte("trie node")).Bytes(), []byte("x"))
+	registry, err := abis.New(db)
+	assert.Nil(t, err)
+
+	contract := thor.BytesToAddress([]byte("contract"))
+	codeHash := thor.BytesToBytes32([]byte("code"))
+	codeHashFunc := func(addr thor.Address) (thor.Bytes32, error) {
+		assert.Equal(t, contract, addr)
+		return codeHash, nil
+	}
+
+	assert.NotNil(t, registry.Add(&abis.Entry{Name: "counter", ABI: json.RawMessage(counterABI)}), "unbound")
+	assert.NotNil(t, registry.Add(&abis.Entry{Address: &builtin.Energy.Address, ABI: json.RawMessage(counterABI)}), "builtin")
+	assert.NotNil(t, registry.Add(&abis.Entry{CodeHash: &codeHash, ABI: json.RawMessage(`{}`)}), "bad abi")
+	assert.Nil(t, registry.Add(&abis.Entry{CodeHash: &codeHash, Name: "counter", ABI: json.RawMessage(counterABI)}))
+
+	// well-known VIP180 for any contract
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	clause := tx.NewTokenTransferClause(contract, recipient, big.NewInt(10))
+	decoded, err := registry.DecodeCall(contract, nil, clause.Data())
+	assert.Nil(t, err)
+	assert.Equal(t, &abis.Decoded{"transfer", []*abis.Arg{
+		{"_to", "address", recipient},
+		{"_value", "uint256", (*math.HexOrDecimal256)(big.NewInt(10))},
+	}}, decoded)
+
+	// bound by code hash
+	set, _ := mustABI(counterABI).MethodByName("set")
+	input, _ := set.EncodeInput(uint8(7))
+	decoded, err = registry.DecodeCall(contract, nil, input)
+	assert.Nil(t, err)
+	assert.Nil(t, decoded)
+	decoded, err = registry.DecodeCall(contract, codeHashFunc, input)
+	assert.Nil(t, err)
+	assert.Equal(t, &abis.Decoded{"set", []*abis.Arg{{"n", "uint8", uint8(7)}}}, decoded)
+
+	event, _ := mustABI(counterABI).EventByName("Set")
+	data, _ := event.Encode(uint8(7))
+	topics := []thor.Bytes32{event.ID(), thor.BytesToBytes32(recipient.Bytes())}
+	decoded, err = registry.DecodeEvent(contract, codeHashFunc, topics, data)
+	assert.Nil(t, err)
+	assert.Equal(t, &abis.Decoded{"Set", []*abis.Arg{
+		{"who", "address", recipient},
+		{"n", "uint8", uint8(7)},
+	}}, decoded)
+	decoded, err = registry.DecodeEvent(contract, codeHashFunc, topics[:1], data)
+	assert.Nil(t, err)
+	assert.Nil(t, decoded)
+
+	// builtin
+	paramsSet, _ := builtin.Params.ABI.MethodByName("set")
+	input, _ = paramsSet.EncodeInput(thor.KeyBaseGasPrice, big.NewInt(1))
+	decoded, _ = registry.DecodeCall(builtin.Params.Address, nil, input)
+	assert.Equal(t, "set", decoded.Name)
+	assert.Equal(t, "bytes32", decoded.Args[0].Type)
+
+	// persisted
+	registry, err = abis.New(db)
+	assert.Nil(t, err)
+	list := registry.List()
+	assert.Equal(t, "counter", list[len(list)-1].Name)
+	assert.Equal(t, codeHash, *list[len(list)-1].CodeHash)
+
+	removed, err := registry.Remove(nil, &codeHash)
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	removed, _ = registry.Remove(nil, &codeHash)
+	assert.False(t, removed)
+
+	registry, _ = abis.New(db)
+	for _, e := range registry.List() {
+		assert.True(t, e.Builtin)
+	}
+}
+
+func mustABI(data string) *abi.ABI {
+	a, err := abi.New([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
-	"strconv"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -22,6 +21,7 @@ import (
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tracing"
 	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/xenv"
 )
@@ -58,7 +58,7 @@ func (a *Accounts) handleGetCode(w http.ResponseWriter, req *http.Request) error
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "address"))
 	}
-	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	h, err := a.handleRevision(req.Context(), req.URL.Query().Get("revision"))
 	if err != nil {
 		return err
 	}
@@ -99,12 +99,47 @@ func (a *Accounts) getStorage(addr thor.Address, key thor.Bytes32, stateRoot tho
 	return storage, nil
 }
 
+func (a *Accounts) getStorageUsage(addr thor.Address, stateRoot thor.Bytes32) (*StorageUsage, error) {
+	state, err := a.stateCreator.NewState(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+	usage := state.GetStorageUsage(addr)
+	if err := state.Err(); err != nil {
+		return nil, err
+	}
+	if usage == nil {
+		return &StorageUsage{}, nil
+	}
+	return &StorageUsage{
+		Tracked: true,
+		Slots:   usage.Slots,
+		Bytes:   usage.Bytes,
+	}, nil
+}
+
+func (a *Accounts) handleGetStorageUsage(w http.ResponseWriter, req *http.Request) error {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	h, err := a.handleRevision(req.Context(), req.URL.Query().Get("revision"))
+	if err != nil {
+		return err
+	}
+	usage, err := a.getStorageUsage(addr, h.StateRoot())
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, usage)
+}
+
 func (a *Accounts) handleGetAccount(w http.ResponseWriter, req *http.Request) error {
 	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "address"))
 	}
-	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	h, err := a.handleRevision(req.Context(), req.URL.Query().Get("revision"))
 	if err != nil {
 		return err
 	}
@@ -124,7 +159,7 @@ func (a *Accounts) handleGetStorage(w http.ResponseWriter, req *http.Request) er
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "key"))
 	}
-	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	h, err := a.handleRevision(req.Context(), req.URL.Query().Get("revision"))
 	if err != nil {
 		return err
 	}
@@ -140,7 +175,7 @@ func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request)
 	if err := utils.ParseJSON(req.Body, &callData); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
-	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	h, err := a.handleRevision(req.Context(), req.URL.Query().Get("revision"))
 	if err != nil {
 		return err
 	}
@@ -160,9 +195,10 @@ func (a *Accounts) handleCallContract(w http.ResponseWriter, req *http.Request)
 				Data:  callData.Data,
 			},
 		},
-		Gas:      callData.Gas,
-		GasPrice: callData.GasPrice,
-		Caller:   callData.Caller,
+		Gas:            callData.Gas,
+		GasPrice:       callData.GasPrice,
+		Caller:         callData.Caller,
+		StateOverrides: callData.StateOverrides,
 	}
 	results, err := a.batchCall(req.Context(), batchCallData, h)
 	if err != nil {
@@ -176,7 +212,7 @@ func (a *Accounts) handleCallBatchCode(w http.ResponseWriter, req *http.Request)
 	if err := utils.ParseJSON(req.Body, &batchCallData); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
-	h, err := a.handleRevision(req.URL.Query().Get("revision"))
+	h, err := a.handleRevision(req.Context(), req.URL.Query().Get("revision"))
 	if err != nil {
 		return err
 	}
@@ -192,10 +228,20 @@ func (a *Accounts) batchCall(ctx context.Context, batchCallData *BatchCallData,
 	if err != nil {
 		return nil, err
 	}
-	state, err := a.stateCreator.NewState(header.StateRoot())
+	overrides, err := batchCallData.StateOverrides.convert()
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "stateOverrides"))
+	}
+	ctx, span := tracing.Start(ctx, "accounts.batchCall")
+	defer span.End()
+	span.SetAttribute("block.number", header.Number())
+
+	state, err := a.stateCreator.NewStateWithContext(ctx, header.StateRoot())
 	if err != nil {
 		return nil, err
 	}
+	// the state is discarded after calls, so overrides never persist
+	overrides.Apply(state, header.Timestamp())
 	signer, _ := header.Signer()
 	rt := runtime.New(a.chain.NewSeeker(header.ParentID()), state,
 		&xenv.BlockContext{
@@ -212,6 +258,8 @@ func (a *Accounts) batchCall(ctx context.Context, batchCallData *BatchCallData,
 			Origin:     *caller,
 			GasPrice:   gasPrice,
 			ProvedWork: &big.Int{}})
+		_, clauseSpan := tracing.Start(ctx, "runtime.executeClause")
+		clauseSpan.SetAttribute("clause.index", int64(i))
 		go func() {
 			out, _ := exec()
 			vmout <- out
@@ -219,8 +267,12 @@ func (a *Accounts) batchCall(ctx context.Context, batchCallData *BatchCallData,
 		select {
 		case <-ctx.Done():
 			interrupt()
+			clauseSpan.End()
 			return nil, ctx.Err()
 		case out := <-vmout:
+			clauseSpan.SetAttribute("gas.used", gas-out.LeftOverGas)
+			clauseSpan.SetAttribute("reverted", out.VMErr != nil)
+			clauseSpan.End()
 			if err := rt.Seeker().Err(); err != nil {
 				return nil, err
 			}
@@ -276,32 +328,10 @@ func (a *Accounts) handleBatchCallData(batchCallData *BatchCallData) (gas uint64
 	return
 }
 
-func (a *Accounts) handleRevision(revision string) (*block.Header, error) {
-	if revision == "" || revision == "best" {
-		return a.chain.BestBlock().Header(), nil
-	}
-	if len(revision) == 66 || len(revision) == 64 {
-		blockID, err := thor.ParseBytes32(revision)
-		if err != nil {
-			return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
-		}
-		h, err := a.chain.GetBlockHeader(blockID)
-		if err != nil {
-			if a.chain.IsNotFound(err) {
-				return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
-			}
-			return nil, err
-		}
-		return h, nil
-	}
-	n, err := strconv.ParseUint(revision, 0, 0)
-	if err != nil {
-		return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
-	}
-	if n > math.MaxUint32 {
-		return nil, utils.BadRequest(errors.WithMessage(errors.New("block number out of max uint32"), "revision"))
-	}
-	h, err := a.chain.GetTrunkBlockHeader(uint32(n))
+func (a *Accounts) handleRevision(ctx context.Context, revision string) (*block.Header, error) {
+	_, span := tracing.Start(ctx, "chain.resolveRevision")
+	defer span.End()
+	h, err := utils.ResolveRevision(a.chain, revision)
 	if err != nil {
 		if a.chain.IsNotFound(err) {
 			return nil, utils.BadRequest(errors.WithMessage(err, "revision"))
@@ -317,6 +347,7 @@ func (a *Accounts) Mount(root *mux.Router, pathPrefix string) {
 	sub.Path("/*").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallBatchCode))
 	sub.Path("/{address}").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetAccount))
 	sub.Path("/{address}/code").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(a.handleGetCode))
+	sub.Path("/{address}/storage").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetStorageUsage))
 	sub.Path("/{address}/storage/{key}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetStorage))
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallContract))
 	sub.Path("/{address}").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleCallContract))
@@ -89,6 +89,8 @@ var storageValue = byte(1)
 
 var contractAddr thor.Address
 
+var deployBlockID thor.Bytes32 // the block transferring value to addr and deploying the contract
+
 var bytecode = common.Hex2Bytes("608060405234801561001057600080fd5b50610125806100206000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806324b8ba5f14604e578063bb4e3f4d14607b575b600080fd5b348015605957600080fd5b506079600480360381019080803560ff16906020019092919050505060cf565b005b348015608657600080fd5b5060b3600480360381019080803560ff169060200190929190803560ff16906020019092919050505060ec565b604051808260ff1660ff16815260200191505060405180910390f35b806000806101000a81548160ff021916908360ff16021790555050565b60008183019050929150505600a165627a7a723058201584add23e31d36c569b468097fe01033525686b59bbb263fb3ab82e9553dae50029")
 
 var runtimeBytecode = common.Hex2Bytes("6080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806324b8ba5f14604e578063bb4e3f4d14607b575b600080fd5b348015605957600080fd5b506079600480360381019080803560ff16906020019092919050505060cf565b005b348015608657600080fd5b5060b3600480360381019080803560ff169060200190929190803560ff16906020019092919050505060ec565b604051808260ff1660ff16815260200191505060405180910390f35b806000806101000a81548160ff021916908360ff16021790555050565b60008183019050929150505600a165627a7a723058201584add23e31d36c569b468097fe01033525686b59bbb263fb3ab82e9553dae50029")
@@ -105,9 +107,13 @@ func TestAccount(t *testing.T) {
 	getAccount(t)
 	getCode(t)
 	getStorage(t)
+	getStorageUsage(t)
 	deployContractWithCall(t)
 	callContract(t)
 	batchCall(t)
+	callWithStateOverrides(t)
+	callWithRevertReason(t)
+	callWithCallerAndRevision(t)
 }
 
 func getAccount(t *testing.T) {
@@ -126,6 +132,22 @@ func getAccount(t *testing.T) {
 	assert.Equal(t, math.HexOrDecimal256(*value), acc.Balance, "balance should be equal")
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
 
+	// historical state, by block number or id
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+addr.String()+"?revision=0")
+	assert.Equal(t, http.StatusOK, statusCode, "OK")
+	acc = accounts.Account{}
+	if err := json.Unmarshal(res, &acc); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, (*big.Int)(&acc.Balance).Sign(), "not transferred at genesis")
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+addr.String()+"?revision="+deployBlockID.String())
+	assert.Equal(t, http.StatusOK, statusCode, "OK")
+	acc = accounts.Account{}
+	if err := json.Unmarshal(res, &acc); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, math.HexOrDecimal256(*value), acc.Balance, "transferred in block 1")
 }
 
 func getCode(t *testing.T) {
@@ -147,6 +169,13 @@ func getCode(t *testing.T) {
 	}
 	assert.Equal(t, runtimeBytecode, c, "code should be equal")
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/code?revision=0")
+	assert.Equal(t, http.StatusOK, statusCode, "OK")
+	if err := json.Unmarshal(res, &code); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "0x", code["code"], "not deployed at genesis")
 }
 
 func getStorage(t *testing.T) {
@@ -171,6 +200,34 @@ func getStorage(t *testing.T) {
 	}
 	assert.Equal(t, thor.BytesToBytes32([]byte{storageValue}), h, "storage should be equal")
 	assert.Equal(t, http.StatusOK, statusCode, "OK")
+
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage/"+storageKey.String()+"?revision=1")
+	assert.Equal(t, http.StatusOK, statusCode, "OK")
+	if err := json.Unmarshal(res, &value); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, thor.Bytes32{}.String(), value["value"], "not set in block 1")
+}
+
+func getStorageUsage(t *testing.T) {
+	_, statusCode := httpGet(t, ts.URL+"/accounts/"+invalidAddr+"/storage")
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+
+	res, statusCode := httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage")
+	assert.Equal(t, http.StatusOK, statusCode, "OK")
+	var usage accounts.StorageUsage
+	if err := json.Unmarshal(res, &usage); err != nil {
+		t.Fatal(err)
+	}
+	// the slot 0 with value 1
+	assert.Equal(t, accounts.StorageUsage{Tracked: true, Slots: 1, Bytes: 33}, usage)
+
+	res, _ = httpGet(t, ts.URL+"/accounts/"+contractAddr.String()+"/storage?revision=0")
+	usage = accounts.StorageUsage{}
+	if err := json.Unmarshal(res, &usage); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, accounts.StorageUsage{Tracked: true}, usage, "not deployed")
 }
 
 func initAccountServer(t *testing.T) {
@@ -188,6 +245,7 @@ func initAccountServer(t *testing.T) {
 	transaction := buildTxWithClauses(t, chain.Tag(), claTransfer, claDeploy)
 	contractAddr = thor.CreateContractAddress(transaction.ID(), 1, 0)
 	packTx(chain, stateC, transaction, t)
+	deployBlockID = chain.BestBlock().Header().ID()
 
 	method := "set"
 	abi, err := ABI.New([]byte(abiJSON))
@@ -230,7 +288,7 @@ func packTx(chain *chain.Chain, stateC *state.Creator, transaction *tx.Transacti
 	if err != nil {
 		t.Fatal(err)
 	}
-	b, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -367,10 +425,82 @@ func batchCall(t *testing.T) {
 			t.Fatal(err)
 		}
 		assert.Equal(t, a+b, ret, "should be equal")
+
+		intrinsicGas, _ := tx.ClauseIntrinsicGas(tx.NewClause(&contractAddr).WithData(input))
+		assert.Equal(t, intrinsicGas, result.GasBreakdown.Intrinsic)
+		assert.Equal(t, result.GasUsed, result.GasBreakdown.Execution)
 	}
 	assert.Equal(t, http.StatusOK, statusCode)
 }
 
+func callWithStateOverrides(t *testing.T) {
+	target := thor.BytesToAddress([]byte("target"))
+	// returns storage slot 0:
+	//
+	// PUSH1 0 SLOAD PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	code := "0x60005460005260206000f3"
+	slotValue := thor.BytesToBytes32([]byte("value"))
+
+	reqBody := &accounts.CallData{
+		StateOverrides: accounts.StateOverrides{
+			target.String(): {
+				Code:    &code,
+				Storage: map[string]string{thor.Bytes32{}.String(): slotValue.String()},
+			},
+		},
+	}
+	res, statusCode := httpPost(t, ts.URL+"/accounts/"+target.String(), reqBody)
+	assert.Equal(t, http.StatusOK, statusCode)
+	var output *accounts.CallResult
+	if err := json.Unmarshal(res, &output); err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, output.Reverted)
+	assert.Equal(t, hexutil.Encode(slotValue.Bytes()), output.Data)
+
+	// not persisted
+	res, statusCode = httpGet(t, ts.URL+"/accounts/"+target.String()+"/code")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var codeRes map[string]string
+	if err := json.Unmarshal(res, &codeRes); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "0x", codeRes["code"])
+
+	badCode := "bad"
+	reqBody.StateOverrides[target.String()].Code = &badCode
+	_, statusCode = httpPost(t, ts.URL+"/accounts/"+target.String(), reqBody)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad code")
+
+	_, statusCode = httpPost(t, ts.URL+"/accounts/"+target.String(), &accounts.CallData{
+		StateOverrides: accounts.StateOverrides{"bad address": {}},
+	})
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad address")
+}
+
+func callWithRevertReason(t *testing.T) {
+	target := thor.BytesToAddress([]byte("target"))
+	// reverts with Error("boom") appended to the code:
+	//
+	// PUSH1 100 PUSH1 12 PUSH1 0 CODECOPY PUSH1 100 PUSH1 0 REVERT
+	code := "0x6064600c60003960646000fd" +
+		"08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"626f6f6d00000000000000000000000000000000000000000000000000000000"
+
+	res, statusCode := httpPost(t, ts.URL+"/accounts/"+target.String(), &accounts.CallData{
+		StateOverrides: accounts.StateOverrides{target.String(): {Code: &code}},
+	})
+	assert.Equal(t, http.StatusOK, statusCode)
+	var output *accounts.CallResult
+	if err := json.Unmarshal(res, &output); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, output.Reverted)
+	assert.Equal(t, "boom", output.RevertReason)
+}
+
 func httpPost(t *testing.T, url string, body interface{}) ([]byte, int) {
 	data, err := json.Marshal(body)
 	if err != nil {
@@ -400,3 +530,44 @@ func httpGet(t *testing.T, url string) ([]byte, int) {
 	}
 	return r, res.StatusCode
 }
+
+func callWithCallerAndRevision(t *testing.T) {
+	a, b := uint8(1), uint8(2)
+	abi, err := ABI.New([]byte(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := abi.MethodByName("add")
+	input, err := m.EncodeInput(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func(to thor.Address, revision string, body *accounts.CallData) *accounts.CallResult {
+		res, statusCode := httpPost(t, ts.URL+"/accounts/"+to.String()+"?revision="+revision, body)
+		assert.Equal(t, http.StatusOK, statusCode)
+		var output *accounts.CallResult
+		if err := json.Unmarshal(res, &output); err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+
+	output := call(contractAddr, "0", &accounts.CallData{Data: hexutil.Encode(input)})
+	assert.False(t, output.Reverted)
+	assert.Equal(t, "0x", output.Data, "not deployed at genesis")
+
+	output = call(contractAddr, deployBlockID.String(), &accounts.CallData{Data: hexutil.Encode(input)})
+	assert.False(t, output.Reverted)
+	assert.NotEqual(t, "0x", output.Data, "deployed in the block")
+
+	// value transferred from the caller
+	caller := genesis.DevAccounts()[0].Address
+	output = call(addr, "best", &accounts.CallData{Value: (*math.HexOrDecimal256)(big.NewInt(1))})
+	assert.True(t, output.Reverted, "insufficient balance")
+	output = call(addr, "best", &accounts.CallData{Value: (*math.HexOrDecimal256)(big.NewInt(1)), Caller: &caller})
+	assert.False(t, output.Reverted)
+	if assert.Len(t, output.Transfers, 1) {
+		assert.Equal(t, caller, output.Transfers[0].Sender)
+		assert.Equal(t, addr, output.Transfers[0].Recipient)
+	}
+}
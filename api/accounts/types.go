@@ -6,11 +6,15 @@
 package accounts
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/pkg/errors"
 	"github.com/vechain/thor/api/transactions"
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/vm"
 )
 
 //Account for marshal account
@@ -20,34 +24,54 @@ type Account struct {
 	HasCode bool                 `json:"hasCode"`
 }
 
+//StorageUsage for marshal storage usage of an account.
+//Tracked is false if the storage was changed before usage tracking, since usage is not backfilled.
+type StorageUsage struct {
+	Tracked bool   `json:"tracked"`
+	Slots   uint64 `json:"slots"`
+	Bytes   uint64 `json:"bytes"`
+}
+
 //CallData represents contract-call body
 type CallData struct {
-	Value    *math.HexOrDecimal256 `json:"value"`
-	Data     string                `json:"data"`
-	Gas      uint64                `json:"gas"`
-	GasPrice *math.HexOrDecimal256 `json:"gasPrice"`
-	Caller   *thor.Address         `json:"caller"`
+	Value          *math.HexOrDecimal256 `json:"value"`
+	Data           string                `json:"data"`
+	Gas            uint64                `json:"gas"`
+	GasPrice       *math.HexOrDecimal256 `json:"gasPrice"`
+	Caller         *thor.Address         `json:"caller"`
+	StateOverrides StateOverrides        `json:"stateOverrides"`
 }
 
 type CallResult struct {
-	Data      string                   `json:"data"`
-	Events    []*transactions.Event    `json:"events"`
-	Transfers []*transactions.Transfer `json:"transfers"`
-	GasUsed   uint64                   `json:"gasUsed"`
-	Reverted  bool                     `json:"reverted"`
-	VMError   string                   `json:"vmError"`
+	Data         string                   `json:"data"`
+	Events       []*transactions.Event    `json:"events"`
+	Transfers    []*transactions.Transfer `json:"transfers"`
+	GasUsed      uint64                   `json:"gasUsed"`
+	GasBreakdown GasBreakdown             `json:"gasBreakdown"`
+	Reverted     bool                     `json:"reverted"`
+	VMError      string                   `json:"vmError"`
+	RevertReason string                   `json:"revertReason,omitempty"` // decoded from data if reverted with reason
+}
+
+// GasBreakdown gas consumption of a clause
+type GasBreakdown struct {
+	Intrinsic uint64 `json:"intrinsic"`
+	Execution uint64 `json:"execution"`
+	Refund    uint64 `json:"refund"`
 }
 
 func convertCallResultWithInputGas(vo *runtime.Output, inputGas uint64) *CallResult {
 	gasUsed := inputGas - vo.LeftOverGas
 	var (
-		vmError  string
-		reverted bool
+		vmError      string
+		reverted     bool
+		revertReason string
 	)
 
 	if vo.VMErr != nil {
 		reverted = true
 		vmError = vo.VMErr.Error()
+		revertReason, _ = vm.UnpackRevertReason(vo.Data)
 	}
 
 	events := make([]*transactions.Event, len(vo.Events))
@@ -78,8 +102,14 @@ func convertCallResultWithInputGas(vo *runtime.Output, inputGas uint64) *CallRes
 		Events:    events,
 		Transfers: transfers,
 		GasUsed:   gasUsed,
-		Reverted:  reverted,
-		VMError:   vmError,
+		GasBreakdown: GasBreakdown{
+			vo.GasBreakdown.Intrinsic,
+			vo.GasBreakdown.Execution,
+			vo.GasBreakdown.Refund,
+		},
+		Reverted:     reverted,
+		VMError:      vmError,
+		RevertReason: revertReason,
 	}
 }
 
@@ -94,10 +124,64 @@ type Clauses []Clause
 
 //BatchCallData executes a batch of codes
 type BatchCallData struct {
-	Clauses  Clauses               `json:"clauses"`
-	Gas      uint64                `json:"gas"`
-	GasPrice *math.HexOrDecimal256 `json:"gasPrice"`
-	Caller   *thor.Address         `json:"caller"`
+	Clauses        Clauses               `json:"clauses"`
+	Gas            uint64                `json:"gas"`
+	GasPrice       *math.HexOrDecimal256 `json:"gasPrice"`
+	Caller         *thor.Address         `json:"caller"`
+	StateOverrides StateOverrides        `json:"stateOverrides"`
+}
+
+// AccountOverride account fields overridden before executing calls
+type AccountOverride struct {
+	Balance *math.HexOrDecimal256 `json:"balance"`
+	Energy  *math.HexOrDecimal256 `json:"energy"`
+	Code    *string               `json:"code"`
+	Storage map[string]string     `json:"storage"`
+}
+
+// StateOverrides maps address to account override
+type StateOverrides map[string]*AccountOverride
+
+func (o StateOverrides) convert() (runtime.StateOverrides, error) {
+	overrides := make(runtime.StateOverrides, len(o))
+	for addrStr, acc := range o {
+		addr, err := thor.ParseAddress(addrStr)
+		if err != nil {
+			return nil, errors.WithMessage(err, "address")
+		}
+		if acc == nil {
+			continue
+		}
+		converted := &runtime.AccountOverride{
+			Balance: (*big.Int)(acc.Balance),
+			Energy:  (*big.Int)(acc.Energy),
+		}
+		if (converted.Balance != nil && converted.Balance.Sign() < 0) ||
+			(converted.Energy != nil && converted.Energy.Sign() < 0) {
+			return nil, errors.New(addrStr + ": negative amount")
+		}
+		if acc.Code != nil {
+			if converted.Code, err = hexutil.Decode(*acc.Code); err != nil {
+				return nil, errors.WithMessage(err, addrStr+": code")
+			}
+		}
+		if len(acc.Storage) > 0 {
+			converted.Storage = make(map[thor.Bytes32]thor.Bytes32, len(acc.Storage))
+			for keyStr, valueStr := range acc.Storage {
+				key, err := thor.ParseBytes32(keyStr)
+				if err != nil {
+					return nil, errors.WithMessage(err, addrStr+": storage key")
+				}
+				value, err := thor.ParseBytes32(valueStr)
+				if err != nil {
+					return nil, errors.WithMessage(err, addrStr+": storage value")
+				}
+				converted.Storage[key] = value
+			}
+		}
+		overrides[addr] = converted
+	}
+	return overrides, nil
 }
 
 type BatchCallResults []*CallResult
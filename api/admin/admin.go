@@ -0,0 +1,182 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/thor"
+)
+
+type Admin struct {
+	nw       Network
+	webhooks Webhooks
+	abis     ABIs
+	tunables Tunables
+}
+
+func New(nw Network, webhooks Webhooks, abis ABIs, tunables Tunables) *Admin {
+	return &Admin{
+		nw,
+		webhooks,
+		abis,
+		tunables,
+	}
+}
+
+func (a *Admin) handleGetPeers(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, node.ConvertPeersStats(a.nw.PeersStats()))
+}
+
+func (a *Admin) handleAddPeer(w http.ResponseWriter, req *http.Request) error {
+	var body AddPeer
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	node, err := discover.ParseNode(body.Enode)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "enode"))
+	}
+	a.nw.AddTrustedPeer(node)
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
+func (a *Admin) handleDisconnectPeer(w http.ResponseWriter, req *http.Request) error {
+	nodeID, err := discover.HexID(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	if !a.nw.DisconnectPeer(nodeID) {
+		return utils.HTTPError(errors.New("peer not connected"), http.StatusNotFound)
+	}
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
+func (a *Admin) handleBanPeer(w http.ResponseWriter, req *http.Request) error {
+	nodeID, err := discover.HexID(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	var body BanPeer
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if body.Duration == 0 {
+		return utils.BadRequest(errors.New("duration: should be greater than 0"))
+	}
+	a.nw.BanPeer(nodeID, time.Duration(body.Duration)*time.Second)
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
+func (a *Admin) handleGetWebhooks(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, a.webhooks.List())
+}
+
+func (a *Admin) handleAddWebhook(w http.ResponseWriter, req *http.Request) error {
+	var body subscriptions.Webhook
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	hook, err := a.webhooks.Add(&body)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, hook)
+}
+
+func (a *Admin) handleRemoveWebhook(w http.ResponseWriter, req *http.Request) error {
+	removed, err := a.webhooks.Remove(mux.Vars(req)["id"])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return utils.HTTPError(errors.New("webhook not found"), http.StatusNotFound)
+	}
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
+func (a *Admin) handleGetABIs(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, a.abis.List())
+}
+
+func (a *Admin) handleAddABI(w http.ResponseWriter, req *http.Request) error {
+	var body abis.Entry
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if err := a.abis.Add(&body); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
+// handleRemoveABI removes the ABI bound to the address or code hash, distinguished by length.
+func (a *Admin) handleRemoveABI(w http.ResponseWriter, req *http.Request) error {
+	key := mux.Vars(req)["key"]
+	var (
+		removed bool
+		err     error
+	)
+	if address, parseErr := thor.ParseAddress(key); parseErr == nil {
+		removed, err = a.abis.Remove(&address, nil)
+	} else if codeHash, parseErr := thor.ParseBytes32(key); parseErr == nil {
+		removed, err = a.abis.Remove(nil, &codeHash)
+	} else {
+		return utils.BadRequest(errors.New("key: should be address or code hash"))
+	}
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return utils.HTTPError(errors.New("abi not found"), http.StatusNotFound)
+	}
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
+func (a *Admin) handleGetTuning(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, a.tunables.Tuning())
+}
+
+func (a *Admin) handleTune(w http.ResponseWriter, req *http.Request) error {
+	var body Tuning
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if err := a.tunables.Tune(&body); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, a.tunables.Tuning())
+}
+
+func (a *Admin) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/network/peers").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetPeers))
+	sub.Path("/network/peers").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleAddPeer))
+	sub.Path("/network/peers/{id}").Methods("DELETE").HandlerFunc(utils.WrapHandlerFunc(a.handleDisconnectPeer))
+	sub.Path("/network/peers/{id}/ban").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleBanPeer))
+
+	sub.Path("/webhooks").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetWebhooks))
+	sub.Path("/webhooks").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleAddWebhook))
+	sub.Path("/webhooks/{id}").Methods("DELETE").HandlerFunc(utils.WrapHandlerFunc(a.handleRemoveWebhook))
+
+	sub.Path("/abis").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetABIs))
+	sub.Path("/abis").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleAddABI))
+	sub.Path("/abis/{key}").Methods("DELETE").HandlerFunc(utils.WrapHandlerFunc(a.handleRemoveABI))
+
+	if a.tunables != nil {
+		sub.Path("/tuning").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(a.handleGetTuning))
+		sub.Path("/tuning").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(a.handleTune))
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/api/admin"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/lvldb"
+)
+
+const testEnode = "enode://a979fb575495b8d6db44f750317d0f4622bf4c2aa3365d6af7c284339968eef29b69ad0dce72a4d8db5ebb4968de0e3bec910127f134779fbcb0cb6d3331163c@52.16.188.185:30303"
+
+type fakeNetwork struct {
+	trusted []*discover.Node
+	banned  map[discover.NodeID]time.Duration
+}
+
+func (n *fakeNetwork) PeersStats() []*comm.PeerStats                 { return nil }
+func (n *fakeNetwork) AddTrustedPeer(node *discover.Node)            { n.trusted = append(n.trusted, node) }
+func (n *fakeNetwork) DisconnectPeer(nodeID discover.NodeID) bool    { return false }
+func (n *fakeNetwork) BanPeer(id discover.NodeID, dur time.Duration) { n.banned[id] = dur }
+
+type fakeWebhooks struct {
+	hooks []*subscriptions.Webhook
+}
+
+func (w *fakeWebhooks) Add(hook *subscriptions.Webhook) (*subscriptions.Webhook, error) {
+	added := *hook
+	added.ID = "1"
+	added.Secret = ""
+	w.hooks = append(w.hooks, &added)
+	return &added, nil
+}
+
+func (w *fakeWebhooks) Remove(id string) (bool, error) {
+	for i, hook := range w.hooks {
+		if hook.ID == id {
+			w.hooks = append(w.hooks[:i], w.hooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (w *fakeWebhooks) List() []*subscriptions.Webhook { return w.hooks }
+
+func TestAdmin(t *testing.T) {
+	nw := &fakeNetwork{banned: make(map[discover.NodeID]time.Duration)}
+	router := mux.NewRouter()
+	admin.New(nw, &fakeWebhooks{}, nil, nil).Mount(router, "/admin")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, statusCode := httpDo(t, "GET", ts.URL+"/admin/network/peers", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "null", res)
+
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/network/peers", `{"enode":"invalid"}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/network/peers", `{"enode":"`+testEnode+`"}`)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1, len(nw.trusted))
+
+	node := discover.MustParseNode(testEnode)
+
+	_, statusCode = httpDo(t, "DELETE", ts.URL+"/admin/network/peers/"+node.ID.String(), "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/network/peers/"+node.ID.String()+"/ban", `{"duration":0}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/network/peers/"+node.ID.String()+"/ban", `{"duration":60}`)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, time.Minute, nw.banned[node.ID])
+}
+
+func TestWebhooks(t *testing.T) {
+	webhooks := &fakeWebhooks{}
+	router := mux.NewRouter()
+	admin.New(&fakeNetwork{}, webhooks, nil, nil).Mount(router, "/admin")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	_, statusCode := httpDo(t, "POST", ts.URL+"/admin/webhooks", `{"url":`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	res, statusCode := httpDo(t, "POST", ts.URL+"/admin/webhooks", `{"url":"http://localhost","subject":"block","secret":"s"}`)
+	assert.Equal(t, http.StatusOK, statusCode)
+	var hook subscriptions.Webhook
+	assert.Nil(t, json.Unmarshal([]byte(res), &hook))
+	assert.Equal(t, "1", hook.ID)
+	assert.Equal(t, "", hook.Secret)
+
+	res, statusCode = httpDo(t, "GET", ts.URL+"/admin/webhooks", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var hooks []*subscriptions.Webhook
+	assert.Nil(t, json.Unmarshal([]byte(res), &hooks))
+	assert.Equal(t, []*subscriptions.Webhook{&hook}, hooks)
+
+	_, statusCode = httpDo(t, "DELETE", ts.URL+"/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	_, statusCode = httpDo(t, "DELETE", ts.URL+"/admin/webhooks/1", "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestABIs(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	registry, err := abis.New(db)
+	assert.Nil(t, err)
+	router := mux.NewRouter()
+	admin.New(&fakeNetwork{}, &fakeWebhooks{}, registry, nil).Mount(router, "/admin")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	const (
+		address = "0x7567d83b7b8d80addcb281a71d54fc7b3364ffed"
+		abiJSON = `[{"inputs":[],"name":"f","outputs":[],"type":"function"}]`
+	)
+	_, statusCode := httpDo(t, "POST", ts.URL+"/admin/abis", `{"name":"c","abi":`+abiJSON+`}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "address or code hash required")
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/abis", `{"address":"`+address+`","name":"c","abi":{}}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode, "bad abi")
+
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/abis", `{"address":"`+address+`","name":"c","abi":`+abiJSON+`}`)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	res, statusCode := httpDo(t, "GET", ts.URL+"/admin/abis", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var entries []*abis.Entry
+	assert.Nil(t, json.Unmarshal([]byte(res), &entries))
+	last := entries[len(entries)-1]
+	assert.Equal(t, address, last.Address.String())
+	assert.False(t, last.Builtin)
+
+	_, statusCode = httpDo(t, "DELETE", ts.URL+"/admin/abis/0x01", "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	_, statusCode = httpDo(t, "DELETE", ts.URL+"/admin/abis/"+address, "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	_, statusCode = httpDo(t, "DELETE", ts.URL+"/admin/abis/"+address, "")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+type fakeTunables struct {
+	tuning     admin.Tuning
+	persistErr error
+}
+
+func (f *fakeTunables) Tuning() *admin.Tuning { return &f.tuning }
+func (f *fakeTunables) Tune(tuning *admin.Tuning) error {
+	if tuning.MaxPeers != nil {
+		if *tuning.MaxPeers < 1 {
+			return utils.BadRequest(errors.New("max peers should be positive"))
+		}
+		f.tuning.MaxPeers = tuning.MaxPeers
+	}
+	if tuning.TxPoolLimit != nil {
+		f.tuning.TxPoolLimit = tuning.TxPoolLimit
+	}
+	return f.persistErr
+}
+
+func TestTuning(t *testing.T) {
+	limit := 10000
+	tunables := &fakeTunables{tuning: admin.Tuning{TxPoolLimit: &limit}}
+	router := mux.NewRouter()
+	admin.New(&fakeNetwork{}, &fakeWebhooks{}, nil, tunables).Mount(router, "/admin")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, statusCode := httpDo(t, "GET", ts.URL+"/admin/tuning", "")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var tuning admin.Tuning
+	assert.Nil(t, json.Unmarshal([]byte(res), &tuning))
+	assert.Equal(t, 10000, *tuning.TxPoolLimit)
+	assert.Nil(t, tuning.MaxPeers)
+
+	res, statusCode = httpDo(t, "POST", ts.URL+"/admin/tuning", `{"maxPeers":10}`)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Nil(t, json.Unmarshal([]byte(res), &tuning))
+	assert.Equal(t, 10000, *tuning.TxPoolLimit)
+	assert.Equal(t, 10, *tuning.MaxPeers)
+
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/tuning", `{"maxPeers":0}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/tuning", `{"unknown":1}`)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	assert.Equal(t, 10, *tunables.tuning.MaxPeers)
+
+	tunables.persistErr = errors.New("disk full")
+	_, statusCode = httpDo(t, "POST", ts.URL+"/admin/tuning", `{"maxPeers":20}`)
+	assert.Equal(t, http.StatusInternalServerError, statusCode)
+}
+
+func httpDo(t *testing.T, method, url, body string) (string, int) {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(r), res.StatusCode
+}
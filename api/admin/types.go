@@ -0,0 +1,64 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package admin
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/thor"
+)
+
+// Network operations to manage peers.
+type Network interface {
+	PeersStats() []*comm.PeerStats
+	AddTrustedPeer(node *discover.Node)
+	DisconnectPeer(nodeID discover.NodeID) bool
+	BanPeer(nodeID discover.NodeID, duration time.Duration)
+}
+
+// Webhooks operations to manage webhooks.
+type Webhooks interface {
+	Add(hook *subscriptions.Webhook) (*subscriptions.Webhook, error)
+	Remove(id string) (bool, error)
+	List() []*subscriptions.Webhook
+}
+
+// ABIs operations to manage contract ABIs.
+type ABIs interface {
+	Add(entry *abis.Entry) error
+	Remove(address *thor.Address, codeHash *thor.Bytes32) (bool, error)
+	List() []*abis.Entry
+}
+
+// Tunables operations to tune the node at runtime. Nil if not tunable.
+type Tunables interface {
+	Tuning() *Tuning
+	// Tune applies non-nil fields of the tuning, and persists them to the runtime config.
+	// Bad request error returned if any field invalid, and nothing applied.
+	Tune(tuning *Tuning) error
+}
+
+// Tuning settings that can be tuned at runtime.
+// Absent fields are left unchanged.
+type Tuning struct {
+	TxPoolLimit           *int    `json:"txPoolLimit"`
+	TxPoolLimitPerAccount *int    `json:"txPoolLimitPerAccount"`
+	TxPoolMaxLifetime     *uint64 `json:"txPoolMaxLifetime"` // in seconds
+	MaxPeers              *int    `json:"maxPeers"`
+	TxRelay               *string `json:"txRelay"`
+}
+
+type AddPeer struct {
+	Enode string `json:"enode"`
+}
+
+type BanPeer struct {
+	Duration uint64 `json:"duration"` // in seconds
+}
@@ -8,25 +8,31 @@ package api
 import (
 	"net/http"
 	"net/http/pprof"
-	"strings"
 
 	assetfs "github.com/elazarl/go-bindata-assetfs"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/vechain/thor/analytics"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/api/accounts"
+	"github.com/vechain/thor/api/batch"
 	"github.com/vechain/thor/api/blocks"
 	"github.com/vechain/thor/api/debug"
 	"github.com/vechain/thor/api/doc"
 	"github.com/vechain/thor/api/events"
 	"github.com/vechain/thor/api/eventslegacy"
 	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/api/prototype"
 	"github.com/vechain/thor/api/subscriptions"
 	"github.com/vechain/thor/api/transactions"
 	"github.com/vechain/thor/api/transfers"
 	"github.com/vechain/thor/api/transferslegacy"
+	"github.com/vechain/thor/api/txtracker"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/tracing"
 	"github.com/vechain/thor/txpool"
 )
 
@@ -35,19 +41,19 @@ func New(
 	chain *chain.Chain,
 	stateCreator *state.Creator,
 	txPool *txpool.TxPool,
-	logDB *logdb.LogDB,
+	logDB logdb.Store,
 	nw node.Network,
-	allowedOrigins string,
+	stats *analytics.Analytics,
+	tracker *txtracker.Tracker,
+	abiRegistry *abis.Registry,
+	allowedOrigins *utils.AllowedOrigins,
 	backtraceLimit uint32,
 	callGasLimit uint64,
+	batchLimit int,
 	pprofOn bool,
+	gasProfiling bool,
 	skipLogs bool) (http.HandlerFunc, func()) {
 
-	origins := strings.Split(strings.TrimSpace(allowedOrigins), ",")
-	for i, o := range origins {
-		origins[i] = strings.ToLower(strings.TrimSpace(o))
-	}
-
 	router := mux.NewRouter()
 
 	// to serve api doc and swagger-ui
@@ -67,6 +73,14 @@ func New(
 	accounts.New(chain, stateCreator, callGasLimit).
 		Mount(router, "/accounts")
 
+	// nil if logs not written
+	writtenLogDB := logDB
+	if skipLogs {
+		writtenLogDB = nil
+	}
+	prototype.New(chain, stateCreator, writtenLogDB).
+		Mount(router, "/prototype")
+
 	if !skipLogs {
 		eventslegacy.New(logDB).
 			Mount(router, "/events")
@@ -74,7 +88,7 @@ func New(
 			Mount(router, "/transfers")
 		eventslegacy.New(logDB).
 			Mount(router, "/logs/events")
-		events.New(logDB).
+		events.New(logDB, chain, stateCreator, abiRegistry).
 			Mount(router, "/logs/event")
 		transferslegacy.New(logDB).
 			Mount(router, "/logs/transfers")
@@ -83,14 +97,19 @@ func New(
 	}
 	blocks.New(chain).
 		Mount(router, "/blocks")
-	transactions.New(chain, txPool).
-		Mount(router, "/transactions")
-	debug.New(chain, stateCreator).
+	txs := transactions.New(chain, stateCreator, txPool, tracker, abiRegistry)
+	txs.Mount(router, "/transactions")
+	txs.MountPending(router, "/accounts/{address}/transactions/pending")
+	debug.New(chain, stateCreator, gasProfiling).
 		Mount(router, "/debug")
-	node.New(nw).
+	node.New(nw, chain, stateCreator, txPool, stats).
 		Mount(router, "/node")
-	subs := subscriptions.New(chain, origins, backtraceLimit)
+	subs := subscriptions.New(chain, stateCreator, txPool, abiRegistry, writtenLogDB, allowedOrigins, backtraceLimit)
 	subs.Mount(router, "/subscriptions")
+	if batchLimit > 0 {
+		batch.New(router, batchLimit).
+			Mount(router, "/batch")
+	}
 
 	if pprofOn {
 		router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
@@ -100,10 +119,11 @@ func New(
 		router.PathPrefix("/debug/pprof/").HandlerFunc(pprof.Index)
 	}
 
-	handler := handlers.CompressHandler(router)
+	handler := tracing.Handler(handlers.CompressHandler(router))
 	handler = handlers.CORS(
-		handlers.AllowedOrigins(origins),
-		handlers.AllowedHeaders([]string{"content-type"}))(handler)
+		handlers.AllowedOrigins(nil),
+		handlers.AllowedOriginValidator(allowedOrigins.Allowed),
+		handlers.AllowedHeaders([]string{"content-type", transactions.IdempotencyKeyHeader}))(handler)
 	return handler.ServeHTTP,
 		subs.Close // subscriptions handles hijacked conns, which need to be closed
 }
@@ -0,0 +1,117 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+)
+
+// Batch executes batched sub requests against the API handler, in order.
+type Batch struct {
+	handler http.Handler
+	limit   int
+}
+
+// New creates batch API, which accepts at most limit sub requests per batch.
+func New(handler http.Handler, limit int) *Batch {
+	return &Batch{
+		handler,
+		limit,
+	}
+}
+
+func (b *Batch) handleBatch(w http.ResponseWriter, req *http.Request) error {
+	var subs []*Request
+	if err := utils.ParseJSON(req.Body, &subs); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if len(subs) > b.limit {
+		return utils.BadRequest(errors.Errorf("body: exceeds %v requests", b.limit))
+	}
+	for i, sub := range subs {
+		if sub == nil {
+			return utils.BadRequest(errors.Errorf("requests[%v]: null", i))
+		}
+		switch sub.Method {
+		case "GET", "POST":
+		default:
+			return utils.BadRequest(errors.Errorf("requests[%v].method: unsupported", i))
+		}
+		if !strings.HasPrefix(sub.Path, "/") {
+			return utils.BadRequest(errors.Errorf("requests[%v].path: should start with '/'", i))
+		}
+		// no nesting, and no streaming
+		if strings.HasPrefix(sub.Path, "/batch") || strings.HasPrefix(sub.Path, "/subscriptions") {
+			return utils.BadRequest(errors.Errorf("requests[%v].path: not allowed", i))
+		}
+	}
+
+	results := make([]*Response, 0, len(subs))
+	for _, sub := range subs {
+		results = append(results, b.execute(req, sub))
+	}
+	return utils.WriteJSON(w, results)
+}
+
+// execute executes the sub request in context of the batch request.
+func (b *Batch) execute(batchReq *http.Request, sub *Request) *Response {
+	subReq, err := http.NewRequest(sub.Method, sub.Path, bytes.NewReader(sub.Body))
+	if err != nil {
+		return &Response{Status: http.StatusBadRequest, Error: err.Error()}
+	}
+	subReq = subReq.WithContext(batchReq.Context())
+	subReq.RemoteAddr = batchReq.RemoteAddr
+	if len(sub.Body) > 0 {
+		subReq.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := newRecorder()
+	b.handler.ServeHTTP(rec, subReq)
+
+	resp := &Response{Status: rec.status}
+	body := bytes.TrimSpace(rec.body.Bytes())
+	switch {
+	case len(body) == 0:
+	case rec.status >= 400:
+		resp.Error = string(body)
+	case json.Valid(body):
+		resp.Body = json.RawMessage(body)
+	default:
+		resp.Error = "non-JSON response"
+	}
+	return resp
+}
+
+func (b *Batch) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(b.handleBatch))
+}
+
+// recorder records response of sub request.
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		header: make(http.Header),
+		status: http.StatusOK,
+	}
+}
+
+func (r *recorder) Header() http.Header         { return r.header }
+func (r *recorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *recorder) WriteHeader(status int)      { r.status = status }
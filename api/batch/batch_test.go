@@ -0,0 +1,93 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package batch_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/batch"
+	"github.com/vechain/thor/api/blocks"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+)
+
+func TestBatch(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	b0, _, err := genesis.NewDevnet().Build(state.NewCreator(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	router := mux.NewRouter()
+	blocks.New(chain).Mount(router, "/blocks")
+	router.Path("/echo").Methods("POST").HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+		w.Write(data)
+	})
+	batch.New(router, 3).Mount(router, "/batch")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, status := httpPost(t, ts.URL+"/batch", []*batch.Request{
+		{Method: "GET", Path: "/blocks/0"},
+		{Method: "GET", Path: "/blocks/0x01g"},
+		{Method: "POST", Path: "/echo", Body: json.RawMessage(`{"a":1}`)},
+	})
+	assert.Equal(t, http.StatusOK, status)
+	var results []*batch.Response
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, len(results))
+
+	assert.Equal(t, http.StatusOK, results[0].Status)
+	var blk blocks.Block
+	assert.Nil(t, json.Unmarshal(results[0].Body, &blk))
+	assert.Equal(t, b0.Header().ID(), blk.ID)
+
+	assert.Equal(t, http.StatusBadRequest, results[1].Status)
+	assert.NotEmpty(t, results[1].Error)
+
+	assert.Equal(t, http.StatusOK, results[2].Status)
+	assert.Equal(t, `{"a":1}`, string(results[2].Body))
+
+	for _, subs := range [][]*batch.Request{
+		make([]*batch.Request, 4),
+		{{Method: "PUT", Path: "/echo"}},
+		{{Method: "GET", Path: "blocks/0"}},
+		{{Method: "POST", Path: "/batch"}},
+	} {
+		_, status = httpPost(t, ts.URL+"/batch", subs)
+		assert.Equal(t, http.StatusBadRequest, status)
+	}
+}
+
+func httpPost(t *testing.T, url string, obj interface{}) ([]byte, int) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
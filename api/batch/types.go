@@ -0,0 +1,22 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package batch
+
+import "encoding/json"
+
+// Request a sub request in batch.
+type Request struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"` // path with query, e.g. /blocks/best
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response result of a sub request.
+type Response struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Error  string          `json:"error,omitempty"` // error message, if status is not 2xx
+}
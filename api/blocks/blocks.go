@@ -7,13 +7,12 @@ package blocks
 
 import (
 	"net/http"
-	"strconv"
 
-	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/api/utils"
-	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/thor"
 )
@@ -29,58 +28,55 @@ func New(chain *chain.Chain) *Blocks {
 }
 
 func (b *Blocks) handleGetBlock(w http.ResponseWriter, req *http.Request) error {
-	revision, err := b.parseRevision(mux.Vars(req)["revision"])
+	raw := req.URL.Query().Get("raw")
+	if raw != "" && raw != "false" && raw != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "raw"))
+	}
+	wantsRLP, err := utils.WantsRLP(req)
 	if err != nil {
-		return utils.BadRequest(errors.WithMessage(err, "revision"))
+		return err
 	}
-	block, err := b.getBlock(revision)
+	header, err := utils.ResolveRevision(b.chain, mux.Vars(req)["revision"])
 	if err != nil {
 		if b.chain.IsNotFound(err) {
+			if wantsRLP {
+				return utils.HTTPError(errors.New("block not found"), http.StatusNotFound)
+			}
 			return utils.WriteJSON(w, nil)
 		}
 		return err
 	}
-	isTrunk, err := b.isTrunk(block.Header().ID(), block.Header().Number())
-	if err != nil {
-		return err
+	if wantsRLP {
+		// raw block is the RLP encoding of block, serve it without decoding
+		data, err := b.chain.GetBlockRaw(header.ID())
+		if err != nil {
+			return err
+		}
+		return utils.WriteRLP(w, data)
 	}
-	blk, err := convertBlock(block, isTrunk)
+	block, err := b.chain.GetBlock(header.ID())
 	if err != nil {
+		if b.chain.IsNotFound(err) {
+			return utils.WriteJSON(w, nil)
+		}
 		return err
 	}
-	return utils.WriteJSON(w, blk)
-}
-
-func (b *Blocks) parseRevision(revision string) (interface{}, error) {
-	if revision == "" || revision == "best" {
-		return nil, nil
-	}
-	if len(revision) == 66 || len(revision) == 64 {
-		blockID, err := thor.ParseBytes32(revision)
+	if raw == "true" {
+		data, err := rlp.EncodeToBytes(block)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		return blockID, nil
+		return utils.WriteJSON(w, &RawBlock{hexutil.Encode(data)})
 	}
-	n, err := strconv.ParseUint(revision, 0, 0)
+	isTrunk, err := b.isTrunk(block.Header().ID(), block.Header().Number())
 	if err != nil {
-		return nil, err
-	}
-	if n > math.MaxUint32 {
-		return nil, errors.New("block number out of max uint32")
+		return err
 	}
-	return uint32(n), err
-}
-
-func (b *Blocks) getBlock(revision interface{}) (*block.Block, error) {
-	switch revision.(type) {
-	case thor.Bytes32:
-		return b.chain.GetBlock(revision.(thor.Bytes32))
-	case uint32:
-		return b.chain.GetTrunkBlock(revision.(uint32))
-	default:
-		return b.chain.BestBlock(), nil
+	blk, err := convertBlock(block, isTrunk)
+	if err != nil {
+		return err
 	}
+	return utils.WriteJSON(w, blk)
 }
 
 func (b *Blocks) isTrunk(blkID thor.Bytes32, blkNum uint32) (bool, error) {
@@ -14,7 +14,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/api/blocks"
@@ -71,6 +73,58 @@ func TestBlock(t *testing.T) {
 	checkBlock(t, blk, rb)
 	assert.Equal(t, http.StatusOK, statusCode)
 
+	res, statusCode = httpGet(t, ts.URL+"/blocks/1?raw=true")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var raw blocks.RawBlock
+	if err := json.Unmarshal(res, &raw); err != nil {
+		t.Fatal(err)
+	}
+	var decoded block.Block
+	if err := rlp.DecodeBytes(hexutil.MustDecode(raw.Raw), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, blk.Header().ID(), decoded.Header().ID())
+	assert.Equal(t, len(blk.Transactions()), len(decoded.Transactions()))
+
+	_, statusCode = httpGet(t, ts.URL+"/blocks/1?raw=1")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestBlockRLP(t *testing.T) {
+	initBlockServer(t)
+	defer ts.Close()
+
+	check := func(res *http.Response) {
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/rlp", res.Header.Get("Content-Type"))
+		var decoded block.Block
+		if err := rlp.Decode(res.Body, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, blk.Header().ID(), decoded.Header().ID())
+		assert.Equal(t, len(blk.Transactions()), len(decoded.Transactions()))
+	}
+
+	res, err := http.Get(ts.URL + "/blocks/1?format=rlp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(res)
+
+	req, _ := http.NewRequest("GET", ts.URL+"/blocks/best", nil)
+	req.Header.Set("Accept", "application/json;q=0.5, application/rlp")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(res)
+
+	_, statusCode := httpGet(t, ts.URL+"/blocks/100?format=rlp")
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	_, statusCode = httpGet(t, ts.URL+"/blocks/1?format=xml")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
 }
 
 func initBlockServer(t *testing.T) {
@@ -109,7 +163,7 @@ func initBlockServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	block, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	block, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
 	if err != nil {
 		t.Fatal(err)
 	}
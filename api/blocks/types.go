@@ -29,6 +29,11 @@ type Block struct {
 	Transactions []thor.Bytes32 `json:"transactions"`
 }
 
+// RawBlock rlp encoded block, which can be verified and executed by the receiver.
+type RawBlock struct {
+	Raw string `json:"raw"`
+}
+
 func convertBlock(b *block.Block, isTrunk bool) (*Block, error) {
 	if b == nil {
 		return nil, nil
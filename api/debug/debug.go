@@ -19,6 +19,7 @@ import (
 	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/consensus"
+	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
@@ -27,17 +28,31 @@ import (
 	"github.com/vechain/thor/vm"
 )
 
-var devNetGenesisID = thor.MustParseBytes32("0x00000000973ceb7f343a58b08f0693d6701a5fd354ff73d7058af3fba222aea4")
+var (
+	devNetGenesisID       = thor.MustParseBytes32("0x00000000973ceb7f343a58b08f0693d6701a5fd354ff73d7058af3fba222aea4")
+	faucetDevNetGenesisID = genesis.NewDevnetWithOptions(genesis.DevnetOptions{Faucet: true}).ID()
+)
+
+// maxStorageResult limits entries returned by a storage range request.
+const maxStorageResult = 1000
 
 type Debug struct {
-	chain  *chain.Chain
-	stateC *state.Creator
+	chain      *chain.Chain
+	stateC     *state.Creator
+	gasProfile *vm.GasProfile // nil if gas profiling off
 }
 
-func New(chain *chain.Chain, stateC *state.Creator) *Debug {
+// New creates debug api. If gasProfiling is true, gas usage of traced executions are aggregated
+// by contract functions, and reported by /gas-profile.
+func New(chain *chain.Chain, stateC *state.Creator, gasProfiling bool) *Debug {
+	var gasProfile *vm.GasProfile
+	if gasProfiling {
+		gasProfile = vm.NewGasProfile()
+	}
 	return &Debug{
 		chain,
 		stateC,
+		gasProfile,
 	}
 }
 
@@ -56,7 +71,8 @@ func (d *Debug) handleTxEnv(ctx context.Context, blockID thor.Bytes32, txIndex u
 	if clauseIndex >= uint64(len(txs[txIndex].Clauses())) {
 		return nil, nil, utils.Forbidden(errors.New("clause index out of range"))
 	}
-	skipPoA := d.chain.GenesisBlock().Header().ID() == devNetGenesisID
+	genesisID := d.chain.GenesisBlock().Header().ID()
+	skipPoA := genesisID == devNetGenesisID || genesisID == faucetDevNetGenesisID
 	rt, err := consensus.New(d.chain, d.stateC).NewRuntimeForReplay(block.Header(), skipPoA)
 	if err != nil {
 		return nil, nil, err
@@ -97,19 +113,61 @@ func (d *Debug) traceTransaction(ctx context.Context, tracer vm.Tracer, blockID
 	if err != nil {
 		return nil, err
 	}
-	rt.SetVMConfig(vm.Config{Debug: true, Tracer: tracer})
+	rt.SetVMConfig(vm.Config{Debug: true, Tracer: d.profiled(tracer)})
 	gasUsed, output, err := txExec.NextClause()
 	if err != nil {
 		return nil, err
 	}
+	return tracerResult(tracer, gasUsed, output)
+}
+
+// traceClauses traces all clauses of an existed transaction, with a new tracer for each clause.
+func (d *Debug) traceClauses(ctx context.Context, newTracer func() (vm.Tracer, error), blockID thor.Bytes32, txIndex uint64) ([]interface{}, error) {
+	rt, txExec, err := d.handleTxEnv(ctx, blockID, txIndex, 0)
+	if err != nil {
+		return nil, err
+	}
+	var results []interface{}
+	for txExec.HasNextClause() {
+		tracer, err := newTracer()
+		if err != nil {
+			return nil, err
+		}
+		rt.SetVMConfig(vm.Config{Debug: true, Tracer: d.profiled(tracer)})
+		gasUsed, output, err := txExec.NextClause()
+		if err != nil {
+			return nil, err
+		}
+		res, err := tracerResult(tracer, gasUsed, output)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// profiled attaches the gas profiler to the tracer if gas profiling on.
+func (d *Debug) profiled(tracer vm.Tracer) vm.Tracer {
+	if d.gasProfile == nil {
+		return tracer
+	}
+	return vm.NewGasProfiler(d.gasProfile, tracer)
+}
+
+func tracerResult(tracer vm.Tracer, gasUsed uint64, output *runtime.Output) (interface{}, error) {
 	switch tr := tracer.(type) {
 	case *vm.StructLogger:
-		return &ExecutionResult{
+		result := &ExecutionResult{
 			Gas:         gasUsed,
 			Failed:      output.VMErr != nil,
 			ReturnValue: hexutil.Encode(output.Data),
 			StructLogs:  formatLogs(tr.StructLogs()),
-		}, nil
+		}
+		if result.Failed {
+			result.RevertReason, _ = vm.UnpackRevertReason(output.Data)
+		}
+		return result, nil
 	case *tracers.Tracer:
 		return tr.GetResult()
 	default:
@@ -125,9 +183,9 @@ func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request)
 	if opt == nil {
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
-	var tracer vm.Tracer
+	var newTracer func() (vm.Tracer, error)
 	if opt.Name == "" {
-		tracer = vm.NewStructLogger(nil)
+		newTracer = func() (vm.Tracer, error) { return vm.NewStructLogger(nil), nil }
 	} else {
 		name := opt.Name
 		if !strings.HasSuffix(name, "Tracer") {
@@ -137,16 +195,30 @@ func (d *Debug) handleTraceTransaction(w http.ResponseWriter, req *http.Request)
 		if !ok {
 			return utils.BadRequest(errors.New("name: unsupported tracer"))
 		}
-		tr, err := tracers.New(code)
+		newTracer = func() (vm.Tracer, error) { return tracers.New(code) }
+	}
+
+	// target without clause index means all clauses of the tx
+	if strings.Count(opt.Target, "/") == 1 {
+		blockID, txIndex, err := d.parseTxTarget(strings.Split(opt.Target, "/"))
+		if err != nil {
+			return err
+		}
+		res, err := d.traceClauses(req.Context(), newTracer, blockID, txIndex)
 		if err != nil {
 			return err
 		}
-		tracer = tr
+		return utils.WriteJSON(w, res)
 	}
+
 	blockID, txIndex, clauseIndex, err := d.parseTarget(opt.Target)
 	if err != nil {
 		return err
 	}
+	tracer, err := newTracer()
+	if err != nil {
+		return err
+	}
 	res, err := d.traceTransaction(req.Context(), tracer, blockID, txIndex, clauseIndex)
 	if err != nil {
 		return err
@@ -159,7 +231,27 @@ func (d *Debug) debugStorage(ctx context.Context, contractAddress thor.Address,
 	if err != nil {
 		return nil, err
 	}
-	storageTrie, err := rt.State().BuildStorageTrie(contractAddress)
+	return storageRangeOf(rt.State(), contractAddress, keyStart, maxResult)
+}
+
+// debugBlockStorage returns storage range of the state after the block.
+func (d *Debug) debugBlockStorage(contractAddress thor.Address, blockID thor.Bytes32, keyStart []byte, maxResult int) (*StorageRangeResult, error) {
+	header, err := d.chain.GetBlockHeader(blockID)
+	if err != nil {
+		if d.chain.IsNotFound(err) {
+			return nil, utils.Forbidden(errors.New("block not found"))
+		}
+		return nil, err
+	}
+	st, err := d.stateC.NewState(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	return storageRangeOf(st, contractAddress, keyStart, maxResult)
+}
+
+func storageRangeOf(st *state.State, contractAddress thor.Address, keyStart []byte, maxResult int) (*StorageRangeResult, error) {
+	storageTrie, err := st.BuildStorageTrie(contractAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -197,9 +289,8 @@ func (d *Debug) handleDebugStorage(w http.ResponseWriter, req *http.Request) err
 	if opt == nil {
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
-	blockID, txIndex, clauseIndex, err := d.parseTarget(opt.Target)
-	if err != nil {
-		return err
+	if opt.MaxResult > maxStorageResult {
+		return utils.BadRequest(fmt.Errorf("maxResult: exceeds limit of %v", maxStorageResult))
 	}
 	var keyStart []byte
 	if opt.KeyStart != "" {
@@ -209,6 +300,24 @@ func (d *Debug) handleDebugStorage(w http.ResponseWriter, req *http.Request) err
 		}
 		keyStart = k
 	}
+
+	// target of block only means the state after the block
+	if !strings.Contains(opt.Target, "/") {
+		blockID, err := thor.ParseBytes32(opt.Target)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "target"))
+		}
+		res, err := d.debugBlockStorage(opt.Address, blockID, keyStart, opt.MaxResult)
+		if err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, res)
+	}
+
+	blockID, txIndex, clauseIndex, err := d.parseTarget(opt.Target)
+	if err != nil {
+		return err
+	}
 	res, err := d.debugStorage(req.Context(), opt.Address, blockID, txIndex, clauseIndex, keyStart, opt.MaxResult)
 	if err != nil {
 		return err
@@ -216,40 +325,62 @@ func (d *Debug) handleDebugStorage(w http.ResponseWriter, req *http.Request) err
 	return utils.WriteJSON(w, res)
 }
 
+func (d *Debug) handleGetGasProfile(w http.ResponseWriter, req *http.Request) error {
+	if d.gasProfile == nil {
+		return utils.Forbidden(errors.New("gas profiling disabled"))
+	}
+	return utils.WriteJSON(w, d.gasProfile.Report())
+}
+
+func (d *Debug) handleResetGasProfile(w http.ResponseWriter, req *http.Request) error {
+	if d.gasProfile == nil {
+		return utils.Forbidden(errors.New("gas profiling disabled"))
+	}
+	d.gasProfile.Reset()
+	return utils.WriteJSON(w, map[string]interface{}{})
+}
+
 func (d *Debug) parseTarget(target string) (blockID thor.Bytes32, txIndex uint64, clauseIndex uint64, err error) {
 	parts := strings.Split(target, "/")
 	if len(parts) != 3 {
 		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.New("target:" + target + " unsupported"))
 	}
+	blockID, txIndex, err = d.parseTxTarget(parts[:2])
+	if err != nil {
+		return thor.Bytes32{}, 0, 0, err
+	}
+	clauseIndex, err = strconv.ParseUint(parts[2], 0, 0)
+	if err != nil {
+		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[2]"))
+	}
+	return
+}
+
+// parseTxTarget parses block id and tx id or index.
+func (d *Debug) parseTxTarget(parts []string) (blockID thor.Bytes32, txIndex uint64, err error) {
 	blockID, err = thor.ParseBytes32(parts[0])
 	if err != nil {
-		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
+		return thor.Bytes32{}, 0, utils.BadRequest(errors.WithMessage(err, "target[0]"))
 	}
 	if len(parts[1]) == 64 || len(parts[1]) == 66 {
 		txID, err := thor.ParseBytes32(parts[1])
 		if err != nil {
-			return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
+			return thor.Bytes32{}, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
 		}
 		txMeta, err := d.chain.GetTransactionMeta(txID, blockID)
 		if err != nil {
 			if d.chain.IsNotFound(err) {
-				return thor.Bytes32{}, 0, 0, utils.Forbidden(errors.New("transaction not found"))
+				return thor.Bytes32{}, 0, utils.Forbidden(errors.New("transaction not found"))
 			}
-			return thor.Bytes32{}, 0, 0, err
-		}
-		txIndex = txMeta.Index
-	} else {
-		i, err := strconv.ParseUint(parts[1], 0, 0)
-		if err != nil {
-			return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
+			return thor.Bytes32{}, 0, err
 		}
-		txIndex = i
+		return blockID, txMeta.Index, nil
 	}
-	clauseIndex, err = strconv.ParseUint(parts[2], 0, 0)
+	txIndex, err = strconv.ParseUint(parts[1], 0, 0)
 	if err != nil {
-		return thor.Bytes32{}, 0, 0, utils.BadRequest(errors.WithMessage(err, "target[2]"))
+		return thor.Bytes32{}, 0, utils.BadRequest(errors.WithMessage(err, "target[1]"))
 	}
-	return
+	return blockID, txIndex, nil
 }
 
 func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
@@ -257,5 +388,7 @@ func (d *Debug) Mount(root *mux.Router, pathPrefix string) {
 
 	sub.Path("/tracers").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleTraceTransaction))
 	sub.Path("/storage-range").Methods(http.MethodPost).HandlerFunc(utils.WrapHandlerFunc(d.handleDebugStorage))
+	sub.Path("/gas-profile").Methods(http.MethodGet).HandlerFunc(utils.WrapHandlerFunc(d.handleGetGasProfile))
+	sub.Path("/gas-profile").Methods(http.MethodDelete).HandlerFunc(utils.WrapHandlerFunc(d.handleResetGasProfile))
 
 }
@@ -0,0 +1,298 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package debug_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/debug"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
+)
+
+//	contract Test {
+//	    uint8 value;
+//	    function set(uint8 v) public { value = v; }
+//	}
+var bytecode = common.Hex2Bytes("608060405234801561001057600080fd5b50610125806100206000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806324b8ba5f14604e578063bb4e3f4d14607b575b600080fd5b348015605957600080fd5b506079600480360381019080803560ff16906020019092919050505060cf565b005b348015608657600080fd5b5060b3600480360381019080803560ff169060200190929190803560ff16906020019092919050505060ec565b604051808260ff1660ff16815260200191505060405180910390f35b806000806101000a81548160ff021916908360ff16021790555050565b60008183019050929150505600a165627a7a723058201584add23e31d36c569b468097fe01033525686b59bbb263fb3ab82e9553dae50029")
+
+// set(uint8) selector
+var setSelector = common.Hex2Bytes("24b8ba5f")
+
+var (
+	ts           *httptest.Server
+	contractAddr thor.Address
+	callBlock    *chainBlock
+)
+
+type chainBlock struct {
+	id   thor.Bytes32
+	txID thor.Bytes32
+}
+
+func TestDebug(t *testing.T) {
+	initDebugServer(t)
+	defer ts.Close()
+
+	traceClause(t)
+	traceTransaction(t)
+	traceBadTarget(t)
+	storageRange(t)
+	gasProfile(t)
+}
+
+func initDebugServer(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b)
+
+	deploy := buildTx(t, chain.Tag(), tx.NewClause(nil).WithData(bytecode))
+	contractAddr = thor.CreateContractAddress(deploy.ID(), 0, 0)
+	packTx(t, chain, stateC, deploy)
+
+	call := buildTx(t, chain.Tag(),
+		tx.NewClause(&contractAddr).WithData(setInput(1)),
+		tx.NewClause(&contractAddr).WithData(setInput(2)))
+	packTx(t, chain, stateC, call)
+	callBlock = &chainBlock{chain.BestBlock().Header().ID(), call.ID()}
+
+	router := mux.NewRouter()
+	debug.New(chain, stateC, true).Mount(router, "/debug")
+	ts = httptest.NewServer(router)
+}
+
+func setInput(v byte) []byte {
+	arg := make([]byte, 32)
+	arg[31] = v
+	return append(append([]byte(nil), setSelector...), arg...)
+}
+
+func traceClause(t *testing.T) {
+	res, statusCode := httpPost(t, ts.URL+"/debug/tracers", &debug.TracerOption{
+		Target: callBlock.id.String() + "/0/1",
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var result debug.ExecutionResult
+	assert.Nil(t, json.Unmarshal(res, &result))
+	assert.False(t, result.Failed)
+	assert.NotEmpty(t, result.StructLogs)
+	var ops []string
+	for _, log := range result.StructLogs {
+		ops = append(ops, log.Op)
+	}
+	assert.Contains(t, ops, "SSTORE")
+}
+
+func traceTransaction(t *testing.T) {
+	res, statusCode := httpPost(t, ts.URL+"/debug/tracers", &debug.TracerOption{
+		Target: callBlock.id.String() + "/" + callBlock.txID.String(),
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var results []*debug.ExecutionResult
+	assert.Nil(t, json.Unmarshal(res, &results))
+	assert.Equal(t, 2, len(results))
+
+	res, statusCode = httpPost(t, ts.URL+"/debug/tracers", &debug.TracerOption{
+		Name:   "call",
+		Target: callBlock.id.String() + "/0",
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var calls []map[string]interface{}
+	assert.Nil(t, json.Unmarshal(res, &calls))
+	assert.Equal(t, 2, len(calls))
+	for _, call := range calls {
+		assert.Equal(t, "CALL", call["type"])
+	}
+}
+
+func traceBadTarget(t *testing.T) {
+	for target, code := range map[string]int{
+		callBlock.id.String():            http.StatusBadRequest,
+		callBlock.id.String() + "/x":     http.StatusBadRequest,
+		callBlock.id.String() + "/1":     http.StatusForbidden,
+		callBlock.id.String() + "/0/2":   http.StatusForbidden,
+		thor.Bytes32{}.String() + "/0/0": http.StatusForbidden,
+		callBlock.id.String() + "/0/0/0": http.StatusBadRequest,
+		callBlock.id.String() + "/0x0/0": http.StatusOK,
+	} {
+		_, statusCode := httpPost(t, ts.URL+"/debug/tracers", &debug.TracerOption{Target: target})
+		assert.Equal(t, code, statusCode, target)
+	}
+}
+
+func storageRange(t *testing.T) {
+	// state before the second clause
+	res, statusCode := httpPost(t, ts.URL+"/debug/storage-range", &debug.StorageRangeOption{
+		Address:   contractAddr,
+		Target:    callBlock.id.String() + "/0/1",
+		MaxResult: 10,
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var result debug.StorageRangeResult
+	assert.Nil(t, json.Unmarshal(res, &result))
+	assert.Equal(t, 1, len(result.Storage))
+	assert.Nil(t, result.NextKey)
+	for _, entry := range result.Storage {
+		assert.Equal(t, thor.BytesToBytes32([]byte{1}), *entry.Value)
+	}
+
+	// state after the block
+	res, statusCode = httpPost(t, ts.URL+"/debug/storage-range", &debug.StorageRangeOption{
+		Address:   contractAddr,
+		Target:    callBlock.id.String(),
+		MaxResult: 10,
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	result = debug.StorageRangeResult{}
+	assert.Nil(t, json.Unmarshal(res, &result))
+	assert.Equal(t, 1, len(result.Storage))
+	for _, entry := range result.Storage {
+		assert.Equal(t, thor.BytesToBytes32([]byte{2}), *entry.Value)
+	}
+
+	// paging
+	res, statusCode = httpPost(t, ts.URL+"/debug/storage-range", &debug.StorageRangeOption{
+		Address: contractAddr,
+		Target:  callBlock.id.String(),
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	result = debug.StorageRangeResult{}
+	assert.Nil(t, json.Unmarshal(res, &result))
+	assert.Equal(t, 0, len(result.Storage))
+	assert.NotNil(t, result.NextKey)
+
+	for target, code := range map[string]int{
+		"0x1":                   http.StatusBadRequest,
+		thor.Bytes32{}.String(): http.StatusForbidden,
+	} {
+		_, statusCode = httpPost(t, ts.URL+"/debug/storage-range", &debug.StorageRangeOption{Address: contractAddr, Target: target})
+		assert.Equal(t, code, statusCode, target)
+	}
+	_, statusCode = httpPost(t, ts.URL+"/debug/storage-range", &debug.StorageRangeOption{Address: contractAddr, Target: callBlock.id.String(), MaxResult: 1001})
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func gasProfile(t *testing.T) {
+	res, statusCode := httpDo(t, http.MethodDelete, ts.URL+"/debug/gas-profile")
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+
+	res, statusCode = httpPost(t, ts.URL+"/debug/tracers", &debug.TracerOption{
+		Name:   "call",
+		Target: callBlock.id.String() + "/0",
+	})
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+
+	res, statusCode = httpDo(t, http.MethodGet, ts.URL+"/debug/gas-profile")
+	assert.Equal(t, http.StatusOK, statusCode, string(res))
+	var report []*vm.GasProfileEntry
+	assert.Nil(t, json.Unmarshal(res, &report))
+	assert.Equal(t, 1, len(report))
+	assert.Equal(t, common.Address(contractAddr), report[0].Address)
+	assert.Equal(t, setSelector, []byte(report[0].Selector))
+	assert.Equal(t, uint64(2), report[0].Calls)
+	assert.Equal(t, uint64(0), report[0].Reverted)
+	assert.NotZero(t, report[0].GasUsed)
+	assert.Equal(t, report[0].GasUsed, report[0].SelfGas)
+
+	// disabled
+	router := mux.NewRouter()
+	debug.New(nil, nil, false).Mount(router, "/debug")
+	disabled := httptest.NewServer(router)
+	defer disabled.Close()
+	_, statusCode = httpDo(t, http.MethodGet, disabled.URL+"/debug/gas-profile")
+	assert.Equal(t, http.StatusForbidden, statusCode)
+}
+
+func buildTx(t *testing.T, chainTag byte, clauses ...*tx.Clause) *tx.Transaction {
+	builder := new(tx.Builder).
+		ChainTag(chainTag).
+		Expiration(10).
+		Gas(1000000)
+	for _, c := range clauses {
+		builder.Clause(c)
+	}
+	trx := builder.Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func packTx(t *testing.T, chain *chain.Chain, stateC *state.Creator, trx *tx.Transaction) {
+	packer := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, &genesis.DevAccounts()[0].Address)
+	flow, err := packer.Schedule(chain.BestBlock().Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := flow.Adopt(trx); err != nil {
+		t.Fatal(err)
+	}
+	b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b, receipts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func httpPost(t *testing.T, url string, body interface{}) ([]byte, int) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
+
+func httpDo(t *testing.T, method string, url string) ([]byte, int) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
@@ -15,10 +15,11 @@ type TracerOption struct {
 }
 
 type ExecutionResult struct {
-	Gas         uint64         `json:"gas"`
-	Failed      bool           `json:"failed"`
-	ReturnValue string         `json:"returnValue"`
-	StructLogs  []StructLogRes `json:"structLogs"`
+	Gas          uint64         `json:"gas"`
+	Failed       bool           `json:"failed"`
+	ReturnValue  string         `json:"returnValue"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	StructLogs   []StructLogRes `json:"structLogs"`
 }
 
 type StructLogRes struct {
@@ -71,10 +72,10 @@ func formatLogs(logs []vm.StructLog) []StructLogRes {
 }
 
 type StorageRangeOption struct {
-	Address   thor.Address
-	KeyStart  string
-	MaxResult int
-	Target    string
+	Address   thor.Address `json:"address"`
+	KeyStart  string       `json:"keyStart"`
+	MaxResult int          `json:"maxResult"`
+	Target    string       `json:"target"` // blockID/(txIndex|txID)/clauseIndex, or blockID for state after the block
 }
 
 type StorageRangeResult struct {
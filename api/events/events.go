@@ -11,31 +11,88 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
 )
 
+// maxCountGroups limits count of groups returned by count API.
+const maxCountGroups = 1000
+
 type Events struct {
-	db *logdb.LogDB
+	db           logdb.Store
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	abis         *abis.Registry // nil if events not decoded
 }
 
-func New(db *logdb.LogDB) *Events {
+func New(db logdb.Store, chain *chain.Chain, stateCreator *state.Creator, abis *abis.Registry) *Events {
 	return &Events{
 		db,
+		chain,
+		stateCreator,
+		abis,
 	}
 }
 
-//Filter query events with option
-func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*FilteredEvent, error) {
+//Filter query events with option. The cursor of the last event is returned if the page is full.
+func (e *Events) filter(ctx context.Context, ef *EventFilter) ([]*FilteredEvent, *logdb.Cursor, error) {
 	events, err := e.db.FilterEvents(ctx, convertEventFilter(ef))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	fes := make([]*FilteredEvent, len(events))
-	for i, e := range events {
-		fes[i] = convertEvent(e)
+	for i, event := range events {
+		fes[i] = convertEvent(event)
+		if err := e.decode(fes[i], event); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(ef.Sort) == 0 && !ef.IncludeRemoved && ef.Options != nil && ef.Options.Limit > 0 && uint64(len(events)) == ef.Options.Limit {
+		return fes, events[len(events)-1].Cursor(), nil
+	}
+	return fes, nil, nil
+}
+
+// decode decodes the event with known ABIs of the contract.
+func (e *Events) decode(fe *FilteredEvent, event *logdb.Event) error {
+	if e.abis == nil {
+		return nil
+	}
+	var topics []thor.Bytes32
+	for _, topic := range event.Topics {
+		if topic != nil {
+			topics = append(topics, *topic)
+		}
+	}
+	decoded, err := e.abis.DecodeEvent(event.Address, e.codeHashAt(event.BlockID), topics, event.Data)
+	if err != nil {
+		return err
+	}
+	fe.Decoded = decoded
+	return nil
+}
+
+// codeHashAt returns abis.CodeHashFunc which reads code hashes from the state of the block.
+// Zero code hash returned if the block is pruned.
+func (e *Events) codeHashAt(blockID thor.Bytes32) abis.CodeHashFunc {
+	return func(address thor.Address) (thor.Bytes32, error) {
+		header, err := e.chain.GetBlockHeader(blockID)
+		if err != nil {
+			if e.chain.IsNotFound(err) {
+				return thor.Bytes32{}, nil
+			}
+			return thor.Bytes32{}, err
+		}
+		state, err := e.stateCreator.NewState(header.StateRoot())
+		if err != nil {
+			return thor.Bytes32{}, err
+		}
+		return abis.StateCodeHash(state)(address)
 	}
-	return fes, nil
 }
 
 func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
@@ -43,15 +100,68 @@ func (e *Events) handleFilter(w http.ResponseWriter, req *http.Request) error {
 	if err := utils.ParseJSON(req.Body, &filter); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
-	fes, err := e.filter(req.Context(), &filter)
+	fes, next, err := e.filter(req.Context(), &filter)
+	if _, ok := err.(*logdb.ResultTooLargeError); ok {
+		return utils.BadRequest(err)
+	}
+	if err == logdb.ErrUnsupportedSortKey || err == logdb.ErrCursorWithSort || err == logdb.ErrCursorWithRemoved {
+		return utils.BadRequest(err)
+	}
 	if err != nil {
 		return err
 	}
+	if next != nil {
+		w.Header().Set(utils.NextCursorHeader, next.String())
+	}
 	return utils.WriteJSON(w, fes)
 }
 
+// handleCount counts events matching the filter, and groups them if 'groupBy' is 'address', 'topic0' or 'txOrigin'.
+func (e *Events) handleCount(w http.ResponseWriter, req *http.Request) error {
+	var filter EventFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	f := convertEventFilter(&filter)
+	count, err := e.db.CountEvents(req.Context(), f)
+	if err != nil {
+		return err
+	}
+	result := &CountResult{Count: count}
+	if by := req.URL.Query().Get("groupBy"); by != "" {
+		groups, err := e.db.CountEventsBy(req.Context(), f, logdb.GroupBy(by), maxCountGroups)
+		if err == logdb.ErrUnsupportedGroupBy {
+			return utils.BadRequest(errors.WithMessage(err, "groupBy"))
+		}
+		if err != nil {
+			return err
+		}
+		result.Groups = convertGroupCounts(groups)
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleAggregate counts events matching the filter in buckets of block time, by 'bucket' of 'hour' or 'day'.
+func (e *Events) handleAggregate(w http.ResponseWriter, req *http.Request) error {
+	var filter EventFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	bucket := req.URL.Query().Get("bucket")
+	aggs, err := e.db.AggregateEvents(req.Context(), convertEventFilter(&filter), logdb.Bucket(bucket))
+	if err == logdb.ErrUnsupportedBucket {
+		return utils.BadRequest(errors.WithMessage(err, "bucket"))
+	}
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, convertAggregates(aggs))
+}
+
 func (e *Events) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(e.handleFilter))
+	sub.Path("/count").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(e.handleCount))
+	sub.Path("/aggregate").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(e.handleAggregate))
 }
@@ -9,15 +9,20 @@ import (
 	"bytes"
 	"encoding/json"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/api/events"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 )
@@ -29,6 +34,10 @@ func TestEvents(t *testing.T) {
 	initEventServer(t)
 	defer ts.Close()
 	getEvents(t)
+	getEventsByCursor(t)
+	getEventsByTx(t)
+	countEvents(t)
+	aggregateEvents(t)
 }
 
 func getEvents(t *testing.T) {
@@ -68,6 +77,105 @@ func getEvents(t *testing.T) {
 	}
 	assert.Equal(t, limit, len(logs), "should be `limit` logs")
 }
+
+func getEventsByCursor(t *testing.T) {
+	filter := &events.EventFilter{
+		Options: &logdb.Options{Limit: 60},
+	}
+	var all []*events.FilteredEvent
+	for {
+		data, _ := json.Marshal(filter)
+		res, err := http.Post(ts.URL+"/logs/event", "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var logs []*events.FilteredEvent
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&logs))
+		res.Body.Close()
+		all = append(all, logs...)
+
+		next := res.Header.Get(utils.NextCursorHeader)
+		if next == "" {
+			break
+		}
+		filter.Options.Cursor = &logdb.Cursor{}
+		assert.Nil(t, filter.Options.Cursor.UnmarshalText([]byte(next)))
+	}
+	assert.Equal(t, 100, len(all))
+	for i, log := range all {
+		assert.Equal(t, uint32(i+1), log.Meta.BlockNumber)
+	}
+}
+
+func getEventsByTx(t *testing.T) {
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`{"criteriaSet":[{"txID":"0x0000000000000000000000000000000000000000000000000000000000000007"}]}`, 1},
+		{`{"criteriaSet":[{"txID":"0x0000000000000000000000000000000000000000000000000000000000000007","clauseIndex":1}]}`, 1},
+		{`{"criteriaSet":[{"txID":"0x0000000000000000000000000000000000000000000000000000000000000007","clauseIndex":0}]}`, 0},
+		{`{"criteriaSet":[{"clauseIndex":0}]}`, 50},
+		{`{"exclusionSet":[{"clauseIndex":0}]}`, 50},
+		{`{"criteriaSet":[{"clauseIndex":0}],"exclusionSet":[{"address":"0x000000000000000000000000636f6e7472616374"}]}`, 0},
+	} {
+		res, err := http.Post(ts.URL+"/logs/event", "application/json", bytes.NewReader([]byte(tt.body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var logs []*events.FilteredEvent
+		assert.Nil(t, json.NewDecoder(res.Body).Decode(&logs))
+		res.Body.Close()
+		if assert.Equal(t, tt.want, len(logs), tt.body) && tt.want == 1 {
+			assert.Equal(t, thor.BytesToBytes32([]byte{7}), logs[0].Meta.TxID)
+		}
+	}
+}
+
+func countEvents(t *testing.T) {
+	filter := &events.EventFilter{
+		Range: &logdb.Range{Unit: logdb.Block, From: 1, To: 10},
+	}
+	var result events.CountResult
+	if err := json.Unmarshal(httpPost(t, ts.URL+"/logs/event/count?groupBy=address", filter), &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(10), result.Count)
+	if assert.Len(t, result.Groups, 1) {
+		assert.Equal(t, contractAddr.Bytes(), []byte(*result.Groups[0].Key))
+		assert.Equal(t, uint64(10), result.Groups[0].Count)
+	}
+
+	data, _ := json.Marshal(filter)
+	res, err := http.Post(ts.URL+"/logs/event/count?groupBy=sender", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func aggregateEvents(t *testing.T) {
+	filter := &events.EventFilter{
+		Range: &logdb.Range{Unit: logdb.Block, From: 1, To: 10},
+	}
+	var aggs []*events.Aggregate
+	if err := json.Unmarshal(httpPost(t, ts.URL+"/logs/event/aggregate?bucket=day", filter), &aggs); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, aggs, 1) {
+		assert.Equal(t, uint64(10), aggs[0].Count)
+	}
+
+	data, _ := json.Marshal(filter)
+	res, err := http.Post(ts.URL+"/logs/event/aggregate?bucket=week", "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
 func initEventServer(t *testing.T) {
 	db, err := logdb.NewMem()
 	if err != nil {
@@ -81,8 +189,8 @@ func initEventServer(t *testing.T) {
 
 	header := new(block.Builder).Build().Header()
 	for i := 0; i < 100; i++ {
-		if err := db.Prepare(header).ForTransaction(thor.BytesToBytes32([]byte("txID")), thor.BytesToAddress([]byte("txOrigin"))).
-			Insert(tx.Events{txEv}, nil, 0).Commit(); err != nil {
+		if err := db.Prepare(header).ForTransaction(thor.BytesToBytes32([]byte{byte(i)}), thor.BytesToAddress([]byte("txOrigin"))).
+			Insert(tx.Events{txEv}, nil, uint32(i%2)).Commit(); err != nil {
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -91,7 +199,7 @@ func initEventServer(t *testing.T) {
 	}
 
 	router := mux.NewRouter()
-	events.New(db).Mount(router, "/logs/event")
+	events.New(db, nil, nil, nil).Mount(router, "/logs/event")
 	ts = httptest.NewServer(router)
 }
 
@@ -111,3 +219,43 @@ func httpPost(t *testing.T, url string, obj interface{}) []byte {
 	}
 	return r
 }
+
+func TestDecodedEvents(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv, _ := lvldb.NewMem()
+	registry, err := abis.New(kv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// VIP180 transfer event of any token
+	transfer, _ := builtin.Energy.ABI.EventByName("Transfer")
+	from, to := thor.BytesToAddress([]byte("from")), thor.BytesToAddress([]byte("to"))
+	data, _ := transfer.Encode(big.NewInt(10))
+	txEv := &tx.Event{
+		Address: contractAddr,
+		Topics:  []thor.Bytes32{transfer.ID(), thor.BytesToBytes32(from.Bytes()), thor.BytesToBytes32(to.Bytes())},
+		Data:    data,
+	}
+	header := new(block.Builder).Build().Header()
+	if err := db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(tx.Events{txEv}, nil, 0).Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	events.New(db, nil, nil, registry).Mount(router, "/logs/event")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	var fes []*events.FilteredEvent
+	if err := json.Unmarshal(httpPost(t, ts.URL+"/logs/event", &events.EventFilter{}), &fes); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Equal(t, 1, len(fes)) && assert.NotNil(t, fes[0].Decoded) {
+		assert.Equal(t, "Transfer", fes[0].Decoded.Name)
+		assert.Equal(t, 3, len(fes[0].Decoded.Args))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/thor"
 )
@@ -20,6 +21,8 @@ type LogMeta struct {
 	TxID           thor.Bytes32 `json:"txID"`
 	TxOrigin       thor.Address `json:"txOrigin"`
 	ClauseIndex    uint32       `json:"clauseIndex"`
+	LogIndex       uint32       `json:"logIndex"`          // index in events emitted by the clause
+	Removed        bool         `json:"removed,omitempty"` // the block is orphaned
 }
 
 type TopicSet struct {
@@ -36,6 +39,7 @@ type FilteredEvent struct {
 	Topics  []*thor.Bytes32 `json:"topics"`
 	Data    string          `json:"data"`
 	Meta    LogMeta         `json:"meta"`
+	Decoded *abis.Decoded   `json:"decoded,omitempty"` // decoded event, if the ABI of the contract known
 }
 
 //convert a logdb.Event into a json format Event
@@ -50,6 +54,8 @@ func convertEvent(event *logdb.Event) *FilteredEvent {
 			TxID:           event.TxID,
 			TxOrigin:       event.TxOrigin,
 			ClauseIndex:    event.ClauseIndex,
+			LogIndex:       event.LogIndex,
+			Removed:        event.Removed,
 		},
 	}
 	fe.Topics = make([]*thor.Bytes32, 0)
@@ -89,37 +95,92 @@ func (e *FilteredEvent) String() string {
 type EventCriteria struct {
 	Address *thor.Address `json:"address"`
 	TopicSet
+	TxID        *thor.Bytes32 `json:"txID"`
+	ClauseIndex *uint32       `json:"clauseIndex"`
+	LogIndex    *uint32       `json:"logIndex"`
 }
 
 type EventFilter struct {
-	CriteriaSet []*EventCriteria `json:"criteriaSet"`
-	Range       *logdb.Range     `json:"range"`
-	Options     *logdb.Options   `json:"options"`
-	Order       logdb.Order      `json:"order"`
+	CriteriaSet  []*EventCriteria `json:"criteriaSet"`
+	ExclusionSet []*EventCriteria `json:"exclusionSet"` // events matching any of them are excluded
+	Range        *logdb.Range     `json:"range"`
+	Options      *logdb.Options   `json:"options"`
+	Order        logdb.Order      `json:"order"`
+	Sort         []*logdb.Sort    `json:"sort"` // keys to sort by, no cursor returned if set
+
+	IncludeRemoved bool `json:"includeRemoved"` // to include logs of orphaned blocks, no cursor returned if set
 }
 
 func convertEventFilter(filter *EventFilter) *logdb.EventFilter {
-	f := &logdb.EventFilter{
-		Range:   filter.Range,
-		Options: filter.Options,
-		Order:   filter.Order,
+	return &logdb.EventFilter{
+		CriteriaSet:  convertEventCriteriaSet(filter.CriteriaSet),
+		ExclusionSet: convertEventCriteriaSet(filter.ExclusionSet),
+		Range:        filter.Range,
+		Options:      filter.Options,
+		Order:        filter.Order,
+		Sort:         filter.Sort,
+
+		IncludeRemoved: filter.IncludeRemoved,
+	}
+}
+
+func convertEventCriteriaSet(set []*EventCriteria) []*logdb.EventCriteria {
+	if len(set) == 0 {
+		return nil
 	}
-	if len(filter.CriteriaSet) > 0 {
-		criterias := make([]*logdb.EventCriteria, len(filter.CriteriaSet))
-		for i, criteria := range filter.CriteriaSet {
-			var topics [5]*thor.Bytes32
-			topics[0] = criteria.Topic0
-			topics[1] = criteria.Topic1
-			topics[2] = criteria.Topic2
-			topics[3] = criteria.Topic3
-			topics[4] = criteria.Topic4
-			criteria := &logdb.EventCriteria{
-				Address: criteria.Address,
-				Topics:  topics,
-			}
-			criterias[i] = criteria
+	criterias := make([]*logdb.EventCriteria, len(set))
+	for i, criteria := range set {
+		var topics [5]*thor.Bytes32
+		topics[0] = criteria.Topic0
+		topics[1] = criteria.Topic1
+		topics[2] = criteria.Topic2
+		topics[3] = criteria.Topic3
+		topics[4] = criteria.Topic4
+		criterias[i] = &logdb.EventCriteria{
+			Address:     criteria.Address,
+			Topics:      topics,
+			TxID:        criteria.TxID,
+			ClauseIndex: criteria.ClauseIndex,
+			LogIndex:    criteria.LogIndex,
+		}
+	}
+	return criterias
+}
+
+// CountResult count of logs matching the filter, with the largest groups if grouped.
+type CountResult struct {
+	Count  uint64        `json:"count"`
+	Groups []*GroupCount `json:"groups,omitempty"`
+}
+
+// GroupCount count of logs sharing the key, which is null if the column is empty.
+type GroupCount struct {
+	Key   *hexutil.Bytes `json:"key"`
+	Count uint64         `json:"count"`
+}
+
+func convertGroupCounts(groups []*logdb.GroupCount) []*GroupCount {
+	converted := make([]*GroupCount, len(groups))
+	for i, g := range groups {
+		converted[i] = &GroupCount{Count: g.Count}
+		if g.Key != nil {
+			key := hexutil.Bytes(g.Key)
+			converted[i].Key = &key
 		}
-		f.CriteriaSet = criterias
 	}
-	return f
+	return converted
+}
+
+// Aggregate count of events in the bucket starting at the block time.
+type Aggregate struct {
+	Time  uint64 `json:"time"`
+	Count uint64 `json:"count"`
+}
+
+func convertAggregates(aggs []*logdb.Aggregate) []*Aggregate {
+	converted := make([]*Aggregate, len(aggs))
+	for i, agg := range aggs {
+		converted[i] = &Aggregate{Time: agg.Time, Count: agg.Count}
+	}
+	return converted
 }
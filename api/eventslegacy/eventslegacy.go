@@ -17,10 +17,10 @@ import (
 )
 
 type EventsLegacy struct {
-	db *logdb.LogDB
+	db logdb.Store
 }
 
-func New(db *logdb.LogDB) *EventsLegacy {
+func New(db logdb.Store) *EventsLegacy {
 	return &EventsLegacy{
 		db,
 	}
@@ -60,6 +60,9 @@ func (e *EventsLegacy) handleFilter(w http.ResponseWriter, req *http.Request) er
 		filter.Order = logdb.DESC
 	}
 	fes, err := e.filter(req.Context(), &filter)
+	if _, ok := err.(*logdb.ResultTooLargeError); ok {
+		return utils.BadRequest(err)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/thor"
+)
+
+const (
+	// gasPriceSampleBlocks count of recent blocks to sample.
+	gasPriceSampleBlocks = 20
+	// fullBlockThreshold blocks are treated as full if fullness exceeds it.
+	fullBlockThreshold = 0.8
+)
+
+// suggestGasPrice suggests gas price coef from recent block fullness and pending txs in pool.
+func (n *Node) suggestGasPrice() (*GasPrice, error) {
+	best := n.chain.BestBlock().Header()
+	st, err := n.stateC.NewState(best.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	baseGasPrice := builtin.Params.Native(st).Get(thor.KeyBaseGasPrice)
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+
+	// sample recent blocks
+	var (
+		fullness float64
+		sampled  int
+		coefs    []int
+	)
+	header := best
+	for i := 0; i < gasPriceSampleBlocks && header.Number() > 0; i++ {
+		if header.GasLimit() > 0 {
+			fullness += float64(header.GasUsed()) / float64(header.GasLimit())
+		}
+		sampled++
+		block, err := n.chain.GetBlock(header.ID())
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Transactions() {
+			coefs = append(coefs, int(tx.GasPriceCoef()))
+		}
+		if header, err = n.chain.GetBlockHeader(header.ParentID()); err != nil {
+			return nil, err
+		}
+	}
+	if sampled > 0 {
+		fullness /= float64(sampled)
+	}
+
+	var suggested int
+	// blocks are full, to compete with the median of included txs
+	if fullness > fullBlockThreshold && len(coefs) > 0 {
+		sort.Ints(coefs)
+		suggested = coefs[len(coefs)/2]
+	}
+
+	// pending txs exceed next block, to outbid the tx at the boundary
+	var pendingGas uint64
+	if n.pool != nil {
+		// executables sorted by price from high to low
+		exceeded := false
+		for _, tx := range n.pool.Executables() {
+			pendingGas += tx.Gas()
+			if pendingGas > best.GasLimit() && !exceeded {
+				exceeded = true
+				if coef := int(tx.GasPriceCoef()) + 1; coef > suggested {
+					suggested = coef
+				}
+			}
+		}
+	}
+	if suggested > math.MaxUint8 {
+		suggested = math.MaxUint8
+	}
+
+	return &GasPrice{
+		BaseGasPrice:  (*math.HexOrDecimal256)(baseGasPrice),
+		GasPriceCoef:  uint8(suggested),
+		BlockFullness: fullness,
+		PendingGas:    pendingGas,
+	}, nil
+}
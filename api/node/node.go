@@ -6,19 +6,53 @@
 package node
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	ethmath "github.com/ethereum/go-ethereum/common/math"
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/analytics"
 	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/poa"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
+)
+
+const (
+	// maxStatsRangeSize max count of blocks per statistics query.
+	maxStatsRangeSize = 1000
+	// maxProposerStatsRangeSize max count of blocks per proposer statistics query, about a day.
+	maxProposerStatsRangeSize = 8640
+	// maxUtilizationStatsRangeSize max count of blocks per utilization statistics query, about a day.
+	maxUtilizationStatsRangeSize = 8640
+	// defaultScheduleSlots default count of upcoming slots per proposer schedule query, about 30 minutes.
+	defaultScheduleSlots = 180
+	// maxScheduleSlots max count of upcoming slots per proposer schedule query, about a day.
+	maxScheduleSlots = 8640
 )
 
 type Node struct {
-	nw Network
+	nw        Network
+	chain     *chain.Chain
+	stateC    *state.Creator
+	pool      *txpool.TxPool // nil if no tx pool
+	stats     *analytics.Analytics
+	proposers *poa.ProposerCache
 }
 
-func New(nw Network) *Node {
+func New(nw Network, chain *chain.Chain, stateC *state.Creator, pool *txpool.TxPool, stats *analytics.Analytics) *Node {
 	return &Node{
 		nw,
+		chain,
+		stateC,
+		pool,
+		stats,
+		poa.NewProposerCache(stateC),
 	}
 }
 
@@ -30,8 +64,228 @@ func (n *Node) handleNetwork(w http.ResponseWriter, req *http.Request) error {
 	return utils.WriteJSON(w, n.PeersStats())
 }
 
+func (n *Node) handleGasPrice(w http.ResponseWriter, req *http.Request) error {
+	price, err := n.suggestGasPrice()
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, price)
+}
+
+// handleEnergyStats returns energy statistics of trunk blocks in range [from, to].
+func (n *Node) handleEnergyStats(w http.ResponseWriter, req *http.Request) error {
+	from, to, err := n.parseStatsRange(req, maxStatsRangeSize)
+	if err != nil {
+		return err
+	}
+	stats, err := n.stats.EnergyStats(from, to)
+	if err != nil {
+		return err
+	}
+	result := make([]*EnergyStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, &EnergyStat{
+			Number:      stat.Header.Number(),
+			ID:          stat.Header.ID(),
+			Timestamp:   stat.Header.Timestamp(),
+			TotalSupply: (*ethmath.HexOrDecimal256)(stat.TotalSupply),
+			TotalBurned: (*ethmath.HexOrDecimal256)(stat.TotalBurned),
+			Generated:   (*ethmath.HexOrDecimal256)(stat.Generated),
+			Burned:      (*ethmath.HexOrDecimal256)(stat.Burned),
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleProposerStats returns statistics of proposers over trunk blocks in range [from, to].
+func (n *Node) handleProposerStats(w http.ResponseWriter, req *http.Request) error {
+	from, to, err := n.parseStatsRange(req, maxProposerStatsRangeSize)
+	if err != nil {
+		return err
+	}
+	stats, err := n.stats.ProposerStats(from, to)
+	if err != nil {
+		return err
+	}
+	result := make([]*ProposerStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, &ProposerStat{
+			Signer:      stat.Signer,
+			Proposed:    stat.Proposed,
+			Missed:      stat.Missed,
+			AvgFullness: stat.AvgFullness,
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleProposerSchedule returns proposers of upcoming slots since now, if no block is produced upon the best block.
+// Only active proposers are scheduled.
+func (n *Node) handleProposerSchedule(w http.ResponseWriter, req *http.Request) error {
+	slots := defaultScheduleSlots
+	if s := req.URL.Query().Get("slots"); s != "" {
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "slots"))
+		}
+		if v == 0 || v > maxScheduleSlots {
+			return utils.BadRequest(errors.Errorf("slots: out of range [1, %v]", maxScheduleSlots))
+		}
+		slots = int(v)
+	}
+	best := n.chain.BestBlock().Header()
+	proposers, err := n.proposers.Proposers(best)
+	if err != nil {
+		return err
+	}
+	result := &ProposerSchedule{
+		ParentID:     best.ID(),
+		ParentNumber: best.Number(),
+		Slots:        []*ScheduleSlot{},
+	}
+	for _, slot := range poa.Upcoming(proposers, best.Number(), best.Timestamp(), uint64(time.Now().Unix()), slots) {
+		result.Slots = append(result.Slots, &ScheduleSlot{
+			Timestamp: slot.Timestamp,
+			Proposer:  slot.Proposer,
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleUtilizationStats returns network utilization statistics of trunk blocks in range [from, to],
+// aggregated per interval of blocks.
+func (n *Node) handleUtilizationStats(w http.ResponseWriter, req *http.Request) error {
+	from, to, err := n.parseStatsRange(req, maxUtilizationStatsRangeSize)
+	if err != nil {
+		return err
+	}
+	interval := uint64(1)
+	if s := req.URL.Query().Get("interval"); s != "" {
+		if interval, err = strconv.ParseUint(s, 10, 32); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "interval"))
+		}
+		if interval == 0 {
+			return utils.BadRequest(errors.New("interval: should be positive"))
+		}
+	}
+	stats, err := n.stats.UtilizationStats(from, to, uint32(interval))
+	if err != nil {
+		return err
+	}
+	result := make([]*UtilizationStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, &UtilizationStat{
+			FromNumber:      stat.From.Number(),
+			ToNumber:        stat.To.Number(),
+			FromTimestamp:   stat.From.Timestamp(),
+			ToTimestamp:     stat.To.Timestamp(),
+			GasUsed:         stat.GasUsed,
+			GasLimit:        stat.GasLimit,
+			Fullness:        stat.Fullness,
+			TxCount:         stat.TxCount,
+			AvgGasPriceCoef: stat.AvgGasPriceCoef,
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handlePackedBreakdown returns payments and rewards of the block last packed by this node.
+// Null returned if no block packed since started.
+func (n *Node) handlePackedBreakdown(w http.ResponseWriter, req *http.Request) error {
+	breakdown := n.stats.LastPacked()
+	if breakdown == nil {
+		return utils.WriteJSON(w, nil)
+	}
+	result := &PackedBreakdown{
+		BlockID:     breakdown.BlockID,
+		Number:      breakdown.Number,
+		Beneficiary: breakdown.Beneficiary,
+		TotalPaid:   (*ethmath.HexOrDecimal256)(breakdown.TotalPaid),
+		TotalReward: (*ethmath.HexOrDecimal256)(breakdown.TotalReward),
+		Txs:         make([]*TxPayment, 0, len(breakdown.Txs)),
+	}
+	for _, tx := range breakdown.Txs {
+		result.Txs = append(result.Txs, &TxPayment{
+			TxID:     tx.TxID,
+			GasPayer: tx.GasPayer,
+			GasUsed:  tx.GasUsed,
+			Paid:     (*ethmath.HexOrDecimal256)(tx.Paid),
+			Reward:   (*ethmath.HexOrDecimal256)(tx.Reward),
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleEnergyAllowances returns spenders approved to transfer energy from the account, with current allowances.
+func (n *Node) handleEnergyAllowances(w http.ResponseWriter, req *http.Request) error {
+	owner, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	allowances, err := n.stats.EnergyAllowances(owner)
+	if err != nil {
+		return err
+	}
+	result := make([]*EnergyAllowance, 0, len(allowances))
+	for _, a := range allowances {
+		result = append(result, &EnergyAllowance{
+			Spender:   a.Spender,
+			Allowance: (*ethmath.HexOrDecimal256)(a.Allowance),
+		})
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// parseStatsRange parses block range [from, to] of statistics query.
+// To defaults to the best block, and from defaults to cover the max range.
+func (n *Node) parseStatsRange(req *http.Request, maxRange uint32) (uint32, uint32, error) {
+	best := n.chain.BestBlock().Header().Number()
+	to, err := parseBlockNumber(req.URL.Query().Get("to"), best)
+	if err != nil {
+		return 0, 0, utils.BadRequest(errors.WithMessage(err, "to"))
+	}
+	if to > best {
+		to = best
+	}
+	from := uint32(0)
+	if to >= maxRange {
+		from = to - maxRange + 1
+	}
+	if from, err = parseBlockNumber(req.URL.Query().Get("from"), from); err != nil {
+		return 0, 0, utils.BadRequest(errors.WithMessage(err, "from"))
+	}
+	if from > to {
+		return 0, 0, utils.BadRequest(errors.New("from: greater than to"))
+	}
+	if to-from >= maxRange {
+		return 0, 0, utils.BadRequest(errors.Errorf("range: exceeds %v blocks", maxRange))
+	}
+	return from, to, nil
+}
+
+func parseBlockNumber(s string, defaultNum uint32) (uint32, error) {
+	if s == "" {
+		return defaultNum, nil
+	}
+	n, err := strconv.ParseUint(s, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxUint32 {
+		return 0, errors.New("block number out of max uint32")
+	}
+	return uint32(n), nil
+}
+
 func (n *Node) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("/network/peers").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleNetwork))
+	sub.Path("/gasprice").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleGasPrice))
+	sub.Path("/energy/stats").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleEnergyStats))
+	sub.Path("/energy/allowances/{address}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleEnergyAllowances))
+	sub.Path("/stats").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleUtilizationStats))
+	sub.Path("/proposers/stats").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleProposerStats))
+	sub.Path("/proposers/schedule").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handleProposerSchedule))
+	sub.Path("/packer/breakdown").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(n.handlePackedBreakdown))
 }
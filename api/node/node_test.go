@@ -7,19 +7,26 @@ package node_test
 import (
 	"encoding/json"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/analytics"
 	"github.com/vechain/thor/api/node"
+	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/comm"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/txpool"
 )
 
@@ -33,6 +40,15 @@ func TestNode(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, 0, len(peersStats), "count should be zero")
+
+	res = httpGet(t, ts.URL+"/node/gasprice")
+	var gasPrice node.GasPrice
+	if err := json.Unmarshal(res, &gasPrice); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, thor.InitialBaseGasPrice, (*big.Int)(gasPrice.BaseGasPrice))
+	assert.Equal(t, uint8(0), gasPrice.GasPriceCoef)
+	assert.Equal(t, uint64(0), gasPrice.PendingGas)
 }
 
 func initCommServer(t *testing.T) {
@@ -45,16 +61,225 @@ func initCommServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	chain, _ := chain.New(db, b)
-	comm := comm.New(chain, txpool.New(chain, stateC, txpool.Options{
+	pool := txpool.New(chain, stateC, txpool.Options{
 		Limit:           10000,
 		LimitPerAccount: 16,
 		MaxLifetime:     10 * time.Minute,
-	}))
+	})
+	comm := comm.New(chain, pool)
 	router := mux.NewRouter()
-	node.New(comm).Mount(router, "/node")
+	node.New(comm, chain, stateC, pool, analytics.New(chain, stateC, nil)).Mount(router, "/node")
 	ts = httptest.NewServer(router)
 }
 
+func TestStats(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	stats := analytics.New(chain, stateC, db)
+	defer stats.Close()
+
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	trx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		Clause(tx.NewClause(&recipient).WithValue(big.NewInt(1))).
+		Expiration(10).
+		Gas(21000).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, flow.Adopt(trx))
+	b1, stage, receipts, breakdown, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b1, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	node.New(nil, chain, stateC, nil, stats).Mount(router, "/node")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	assert.Equal(t, "null", string(httpGet(t, ts.URL+"/node/packer/breakdown")))
+	stats.RecordPacked(breakdown)
+	var packed *node.PackedBreakdown
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/packer/breakdown"), &packed); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b1.Header().ID(), packed.BlockID)
+	assert.Equal(t, 1, len(packed.Txs))
+	assert.Equal(t, trx.ID(), packed.Txs[0].TxID)
+	assert.Equal(t, genesis.DevAccounts()[0].Address, packed.Txs[0].GasPayer)
+	assert.Equal(t, receipts[0].Paid, (*big.Int)(packed.TotalPaid))
+	assert.Equal(t, receipts[0].Reward, (*big.Int)(packed.TotalReward))
+
+	var result []*node.EnergyStat
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/energy/stats?from=0"), &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, b1.Header().ID(), result[1].ID)
+	assert.Equal(t, 0, (*big.Int)(result[0].Generated).Sign())
+	supplyDelta := new(big.Int).Sub((*big.Int)(result[1].TotalSupply), (*big.Int)(result[0].TotalSupply))
+	assert.Equal(t, supplyDelta, (*big.Int)(result[1].Generated))
+	assert.Equal(t, 1, supplyDelta.Sign())
+
+	// 70% of tx fee burned, and the rest rewarded
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipts[0].GasUsed), thor.InitialBaseGasPrice)
+	burned := new(big.Int).Sub(fee, receipts[0].Reward)
+	assert.Equal(t, burned, (*big.Int)(result[1].Burned))
+	assert.Equal(t, burned, new(big.Int).Sub((*big.Int)(result[1].TotalBurned), (*big.Int)(result[0].TotalBurned)))
+
+	res, err := http.Get(ts.URL + "/node/energy/stats?from=2&to=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	var proposers []*node.ProposerStat
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/proposers/stats"), &proposers); err != nil {
+		t.Fatal(err)
+	}
+	// the only proposer missed many slots since genesis
+	assert.Equal(t, 1, len(proposers))
+	assert.Equal(t, genesis.DevAccounts()[0].Address, proposers[0].Signer)
+	assert.Equal(t, uint32(1), proposers[0].Proposed)
+	assert.Equal(t, uint32(thor.MaxBlockProposers), proposers[0].Missed)
+	assert.Equal(t, float64(receipts[0].GasUsed)/float64(b1.Header().GasLimit()), proposers[0].AvgFullness)
+
+	var schedule *node.ProposerSchedule
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/proposers/schedule?slots=5"), &schedule); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b1.Header().ID(), schedule.ParentID)
+	assert.Equal(t, 5, len(schedule.Slots))
+	for i, slot := range schedule.Slots {
+		assert.Equal(t, genesis.DevAccounts()[0].Address, slot.Proposer)
+		assert.Equal(t, uint64(0), (slot.Timestamp-b1.Header().Timestamp())%thor.BlockInterval)
+		if i > 0 {
+			assert.Equal(t, schedule.Slots[i-1].Timestamp+thor.BlockInterval, slot.Timestamp)
+		}
+	}
+	res, err = http.Get(ts.URL + "/node/proposers/schedule?slots=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	var utilization []*node.UtilizationStat
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/stats?from=0&interval=2"), &utilization); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(utilization))
+	assert.Equal(t, uint32(0), utilization[0].FromNumber)
+	assert.Equal(t, uint32(1), utilization[0].ToNumber)
+	assert.Equal(t, uint64(1), utilization[0].TxCount)
+	assert.Equal(t, receipts[0].GasUsed, utilization[0].GasUsed)
+	assert.Equal(t, b0.Header().GasLimit()+b1.Header().GasLimit(), utilization[0].GasLimit)
+	assert.Equal(t, float64(0), utilization[0].AvgGasPriceCoef)
+
+	utilization = nil
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/node/stats?from=0"), &utilization); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(utilization))
+	assert.Equal(t, uint64(0), utilization[0].TxCount)
+	assert.Equal(t, float64(receipts[0].GasUsed)*100/float64(b1.Header().GasLimit()), utilization[1].Fullness)
+
+	res, err = http.Get(ts.URL + "/node/stats?interval=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestEnergyAllowances(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	stats := analytics.New(chain, stateC, db)
+	defer stats.Close()
+
+	owner := genesis.DevAccounts()[0]
+	spender := thor.BytesToAddress([]byte("spender"))
+	approve, _ := builtin.Energy.ABI.MethodByName("approve")
+	data, err := approve.EncodeInput(spender, big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		Clause(tx.NewClause(&builtin.Energy.Address).WithData(data)).
+		Expiration(10).
+		Gas(100000).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), owner.PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	flow, err := packer.New(chain, stateC, owner.Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, flow.Adopt(trx))
+	b1, stage, receipts, _, err := flow.Pack(owner.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b1, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	node.New(nil, chain, stateC, nil, stats).Mount(router, "/node")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	// approvals are indexed asynchronously
+	var allowances []*node.EnergyAllowance
+	for i := 0; i < 50 && len(allowances) == 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+		if err := json.Unmarshal(httpGet(t, ts.URL+"/node/energy/allowances/"+owner.Address.String()), &allowances); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, 1, len(allowances))
+	assert.Equal(t, spender, allowances[0].Spender)
+	assert.Equal(t, big.NewInt(100), (*big.Int)(allowances[0].Allowance))
+
+	assert.Equal(t, "[]", string(httpGet(t, ts.URL+"/node/energy/allowances/"+spender.String())))
+
+	res, err := http.Get(ts.URL + "/node/energy/allowances/0x01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
 func httpGet(t *testing.T, url string) []byte {
 	res, err := http.Get(url)
 	if err != nil {
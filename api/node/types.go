@@ -6,6 +6,7 @@
 package node
 
 import (
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/vechain/thor/comm"
 	"github.com/vechain/thor/thor"
 )
@@ -14,6 +15,84 @@ type Network interface {
 	PeersStats() []*comm.PeerStats
 }
 
+// GasPrice current base gas price and suggested gas price coef.
+type GasPrice struct {
+	BaseGasPrice  *math.HexOrDecimal256 `json:"baseGasPrice"`
+	GasPriceCoef  uint8                 `json:"gasPriceCoef"`  // suggested
+	BlockFullness float64               `json:"blockFullness"` // average gas used ratio of recent blocks
+	PendingGas    uint64                `json:"pendingGas"`    // total gas of executable txs in pool
+}
+
+// EnergyStat energy (VTHO) statistics of a block.
+type EnergyStat struct {
+	Number      uint32                `json:"number"`
+	ID          thor.Bytes32          `json:"id"`
+	Timestamp   uint64                `json:"timestamp"`
+	TotalSupply *math.HexOrDecimal256 `json:"totalSupply"`
+	TotalBurned *math.HexOrDecimal256 `json:"totalBurned"`
+	Generated   *math.HexOrDecimal256 `json:"generated"`
+	Burned      *math.HexOrDecimal256 `json:"burned"`
+}
+
+// ProposerStat statistics of a block proposer over a range of blocks.
+type ProposerStat struct {
+	Signer      thor.Address `json:"signer"`
+	Proposed    uint32       `json:"proposed"`    // count of blocks proposed
+	Missed      uint32       `json:"missed"`      // count of slots missed
+	AvgFullness float64      `json:"avgFullness"` // average gas used ratio of blocks proposed
+}
+
+// ProposerSchedule proposers of upcoming slots, which hold as long as no block is produced upon the parent.
+type ProposerSchedule struct {
+	ParentID     thor.Bytes32    `json:"parentID"`
+	ParentNumber uint32          `json:"parentNumber"`
+	Slots        []*ScheduleSlot `json:"slots"`
+}
+
+// ScheduleSlot a block time slot and its proposer.
+type ScheduleSlot struct {
+	Timestamp uint64       `json:"timestamp"`
+	Proposer  thor.Address `json:"proposer"`
+}
+
+// UtilizationStat network utilization statistics over an interval of blocks.
+type UtilizationStat struct {
+	FromNumber      uint32  `json:"fromNumber"`
+	ToNumber        uint32  `json:"toNumber"`
+	FromTimestamp   uint64  `json:"fromTimestamp"`
+	ToTimestamp     uint64  `json:"toTimestamp"`
+	GasUsed         uint64  `json:"gasUsed"`
+	GasLimit        uint64  `json:"gasLimit"`
+	Fullness        float64 `json:"fullness"` // gas used percentage of gas limit
+	TxCount         uint64  `json:"txCount"`
+	AvgGasPriceCoef float64 `json:"avgGasPriceCoef"`
+}
+
+// TxPayment payment of a tx in a packed block.
+type TxPayment struct {
+	TxID     thor.Bytes32          `json:"txID"`
+	GasPayer thor.Address          `json:"gasPayer"`
+	GasUsed  uint64                `json:"gasUsed"`
+	Paid     *math.HexOrDecimal256 `json:"paid"`
+	Reward   *math.HexOrDecimal256 `json:"reward"`
+}
+
+// PackedBreakdown payments and rewards of a block packed by this node.
+type PackedBreakdown struct {
+	BlockID     thor.Bytes32          `json:"blockID"`
+	Number      uint32                `json:"number"`
+	Beneficiary thor.Address          `json:"beneficiary"`
+	TotalPaid   *math.HexOrDecimal256 `json:"totalPaid"`
+	TotalReward *math.HexOrDecimal256 `json:"totalReward"`
+	Txs         []*TxPayment          `json:"txs"`
+}
+
+// EnergyAllowance energy amount a spender is approved to transfer from an owner.
+type EnergyAllowance struct {
+	Spender   thor.Address          `json:"spender"`
+	Allowance *math.HexOrDecimal256 `json:"allowance"`
+}
+
 type PeerStats struct {
 	Name        string       `json:"name"`
 	BestBlockID thor.Bytes32 `json:"bestBlockID"`
@@ -22,6 +101,7 @@ type PeerStats struct {
 	NetAddr     string       `json:"netAddr"`
 	Inbound     bool         `json:"inbound"`
 	Duration    uint64       `json:"duration"`
+	Latency     uint64       `json:"latency"`
 }
 
 func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
@@ -38,6 +118,7 @@ func ConvertPeersStats(ss []*comm.PeerStats) []*PeerStats {
 			NetAddr:     peerStats.NetAddr,
 			Inbound:     peerStats.Inbound,
 			Duration:    peerStats.Duration,
+			Latency:     peerStats.Latency,
 		}
 	}
 	return peersStats
@@ -0,0 +1,194 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package prototype
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+var userEventID = func() thor.Bytes32 {
+	event, found := builtin.Prototype.Events().EventByName("$User")
+	if !found {
+		panic("event not found")
+	}
+	return event.ID()
+}()
+
+// Prototype exposes multi-party payment (MPP) data of contracts, read natively from state.
+type Prototype struct {
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	logDB        logdb.Store
+}
+
+// New creates prototype API. Users listing is unavailable if logDB is nil.
+func New(chain *chain.Chain, stateCreator *state.Creator, logDB logdb.Store) *Prototype {
+	return &Prototype{
+		chain,
+		stateCreator,
+		logDB,
+	}
+}
+
+// prepare parses the contract address and revision, and returns the state at the revision.
+func (p *Prototype) prepare(req *http.Request) (thor.Address, *block.Header, *state.State, error) {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return thor.Address{}, nil, nil, utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	header, err := utils.ResolveRevision(p.chain, req.URL.Query().Get("revision"))
+	if err != nil {
+		if p.chain.IsNotFound(err) {
+			return thor.Address{}, nil, nil, utils.BadRequest(errors.WithMessage(err, "revision"))
+		}
+		return thor.Address{}, nil, nil, err
+	}
+	st, err := p.stateCreator.NewState(header.StateRoot())
+	if err != nil {
+		return thor.Address{}, nil, nil, err
+	}
+	return addr, header, st, nil
+}
+
+func (p *Prototype) handleGetSettings(w http.ResponseWriter, req *http.Request) error {
+	addr, header, st, err := p.prepare(req)
+	if err != nil {
+		return err
+	}
+	binding := builtin.Prototype.Native(st).Bind(addr)
+	master := st.GetMaster(addr)
+	credit, recoveryRate := binding.CreditPlan()
+	sponsor := binding.CurrentSponsor()
+
+	settings := &Settings{
+		CreditPlan: CreditPlan{
+			math.HexOrDecimal256(*credit),
+			math.HexOrDecimal256(*recoveryRate),
+		},
+	}
+	if !master.IsZero() {
+		settings.Master = &master
+	}
+	if !sponsor.IsZero() {
+		settings.CurrentSponsor = &sponsor
+		settings.SponsorEnergy = (*math.HexOrDecimal256)(st.GetEnergy(sponsor, header.Timestamp()))
+	}
+	if err := st.Err(); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, settings)
+}
+
+func (p *Prototype) handleGetUser(w http.ResponseWriter, req *http.Request) error {
+	addr, header, st, err := p.prepare(req)
+	if err != nil {
+		return err
+	}
+	user, err := thor.ParseAddress(mux.Vars(req)["user"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "user"))
+	}
+	binding := builtin.Prototype.Native(st).Bind(addr)
+	result := &User{
+		Address: user,
+		IsUser:  binding.IsUser(user),
+		Credit:  math.HexOrDecimal256(*binding.UserCredit(user, header.Timestamp())),
+	}
+	if err := st.Err(); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleGetUsers lists users of the contract. Candidates are collected from $User events,
+// and then checked against state.
+func (p *Prototype) handleGetUsers(w http.ResponseWriter, req *http.Request) error {
+	addr, header, st, err := p.prepare(req)
+	if err != nil {
+		return err
+	}
+	events, err := p.logDB.FilterEvents(req.Context(), &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{
+			Address: &addr,
+			Topics:  [5]*thor.Bytes32{&userEventID},
+		}},
+		Range: &logdb.Range{
+			Unit: logdb.Block,
+			To:   uint64(header.Number()),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	binding := builtin.Prototype.Native(st).Bind(addr)
+	seen := make(map[thor.Address]bool)
+	users := []*User{}
+	for _, event := range events {
+		if event.Topics[1] == nil {
+			continue
+		}
+		user := thor.BytesToAddress(event.Topics[1].Bytes())
+		if seen[user] {
+			continue
+		}
+		seen[user] = true
+		if binding.IsUser(user) {
+			users = append(users, &User{
+				Address: user,
+				IsUser:  true,
+				Credit:  math.HexOrDecimal256(*binding.UserCredit(user, header.Timestamp())),
+			})
+		}
+	}
+	if err := st.Err(); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, users)
+}
+
+func (p *Prototype) handleGetSponsor(w http.ResponseWriter, req *http.Request) error {
+	addr, _, st, err := p.prepare(req)
+	if err != nil {
+		return err
+	}
+	sponsor, err := thor.ParseAddress(mux.Vars(req)["sponsor"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "sponsor"))
+	}
+	binding := builtin.Prototype.Native(st).Bind(addr)
+	result := &Sponsor{
+		Address:   sponsor,
+		IsSponsor: binding.IsSponsor(sponsor),
+		IsCurrent: binding.CurrentSponsor() == sponsor,
+	}
+	if err := st.Err(); err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
+}
+
+func (p *Prototype) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/{address}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(p.handleGetSettings))
+	if p.logDB != nil {
+		sub.Path("/{address}/users").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(p.handleGetUsers))
+	}
+	sub.Path("/{address}/users/{user}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(p.handleGetUser))
+	sub.Path("/{address}/sponsors/{sponsor}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(p.handleGetSponsor))
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package prototype_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/prototype"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+var (
+	ts       *httptest.Server
+	self     = genesis.DevAccounts()[0]
+	master   = genesis.DevAccounts()[1]
+	sponsor  = genesis.DevAccounts()[2]
+	user     = genesis.DevAccounts()[3].Address
+	removed  = genesis.DevAccounts()[4].Address
+	stranger = genesis.DevAccounts()[5].Address
+)
+
+func TestPrototype(t *testing.T) {
+	initPrototypeServer(t)
+	defer ts.Close()
+
+	getSettings(t)
+	getUsers(t)
+	getUser(t)
+	getSponsor(t)
+}
+
+func initPrototypeServer(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packTxs(t, chain, stateC, logDB,
+		buildTx(t, chain.Tag(), self.PrivateKey, 1,
+			prototypeClause(t, "setCreditPlan", self.Address, big.NewInt(1000), big.NewInt(10)),
+			prototypeClause(t, "addUser", self.Address, user),
+			prototypeClause(t, "addUser", self.Address, removed),
+			prototypeClause(t, "removeUser", self.Address, removed),
+			prototypeClause(t, "setMaster", self.Address, master.Address)),
+		buildTx(t, chain.Tag(), sponsor.PrivateKey, 2,
+			prototypeClause(t, "sponsor", self.Address)))
+	packTxs(t, chain, stateC, logDB,
+		buildTx(t, chain.Tag(), master.PrivateKey, 3,
+			prototypeClause(t, "selectSponsor", self.Address, sponsor.Address)))
+
+	router := mux.NewRouter()
+	prototype.New(chain, stateC, logDB).Mount(router, "/prototype")
+	ts = httptest.NewServer(router)
+}
+
+func getSettings(t *testing.T) {
+	res, statusCode := httpGet(t, ts.URL+"/prototype/"+self.Address.String())
+	assert.Equal(t, http.StatusOK, statusCode)
+	var settings prototype.Settings
+	assert.Nil(t, json.Unmarshal(res, &settings))
+	assert.Equal(t, master.Address, *settings.Master)
+	assert.Equal(t, big.NewInt(1000), (*big.Int)(&settings.CreditPlan.Credit))
+	assert.Equal(t, big.NewInt(10), (*big.Int)(&settings.CreditPlan.RecoveryRate))
+	assert.Equal(t, sponsor.Address, *settings.CurrentSponsor)
+	assert.NotNil(t, settings.SponsorEnergy)
+
+	// before settings applied
+	res, statusCode = httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"?revision=0")
+	assert.Equal(t, http.StatusOK, statusCode)
+	settings = prototype.Settings{}
+	assert.Nil(t, json.Unmarshal(res, &settings))
+	assert.Nil(t, settings.Master)
+	assert.Nil(t, settings.CurrentSponsor)
+
+	_, statusCode = httpGet(t, ts.URL+"/prototype/0x01")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+	_, statusCode = httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"?revision=100")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func getUsers(t *testing.T) {
+	res, statusCode := httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"/users")
+	assert.Equal(t, http.StatusOK, statusCode)
+	var users []*prototype.User
+	assert.Nil(t, json.Unmarshal(res, &users))
+	assert.Equal(t, 1, len(users))
+	assert.Equal(t, user, users[0].Address)
+	assert.Equal(t, big.NewInt(1000), (*big.Int)(&users[0].Credit))
+
+	res, statusCode = httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"/users?revision=0")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "[]", string(res))
+}
+
+func getUser(t *testing.T) {
+	for _, c := range []struct {
+		addr   thor.Address
+		isUser bool
+		credit int64
+	}{
+		{user, true, 1000},
+		{removed, false, 0},
+		{stranger, false, 0},
+	} {
+		res, statusCode := httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"/users/"+c.addr.String())
+		assert.Equal(t, http.StatusOK, statusCode)
+		var u prototype.User
+		assert.Nil(t, json.Unmarshal(res, &u))
+		assert.Equal(t, c.addr, u.Address)
+		assert.Equal(t, c.isUser, u.IsUser)
+		assert.Equal(t, c.credit, (*big.Int)(&u.Credit).Int64())
+	}
+}
+
+func getSponsor(t *testing.T) {
+	res, statusCode := httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"/sponsors/"+sponsor.Address.String())
+	assert.Equal(t, http.StatusOK, statusCode)
+	var s prototype.Sponsor
+	assert.Nil(t, json.Unmarshal(res, &s))
+	assert.True(t, s.IsSponsor)
+	assert.True(t, s.IsCurrent)
+
+	res, statusCode = httpGet(t, ts.URL+"/prototype/"+self.Address.String()+"/sponsors/"+stranger.String())
+	assert.Equal(t, http.StatusOK, statusCode)
+	s = prototype.Sponsor{}
+	assert.Nil(t, json.Unmarshal(res, &s))
+	assert.False(t, s.IsSponsor)
+	assert.False(t, s.IsCurrent)
+}
+
+func prototypeClause(t *testing.T, name string, args ...interface{}) *tx.Clause {
+	method, found := builtin.Prototype.ABI.MethodByName(name)
+	if !found {
+		t.Fatal("method not found: " + name)
+	}
+	clause, err := tx.NewClauseFromABI(builtin.Prototype.Address, method, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clause
+}
+
+func buildTx(t *testing.T, chainTag byte, key *ecdsa.PrivateKey, nonce uint64, clauses ...*tx.Clause) *tx.Transaction {
+	builder := new(tx.Builder).
+		ChainTag(chainTag).
+		Expiration(100).
+		Gas(1000000).
+		Nonce(nonce)
+	for _, c := range clauses {
+		builder.Clause(c)
+	}
+	trx := builder.Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func packTxs(t *testing.T, chain *chain.Chain, stateC *state.Creator, logDB *logdb.LogDB, txs ...*tx.Transaction) {
+	best := chain.BestBlock()
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(best.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, trx := range txs {
+		if err := flow.Adopt(trx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := logDB.Prepare(b.Header())
+	for i, trx := range b.Transactions() {
+		if receipts[i].Reverted {
+			t.Fatal("tx reverted")
+		}
+		origin, _ := trx.Signer()
+		for j, output := range receipts[i].Outputs {
+			batch.ForTransaction(trx.ID(), origin).Insert(output.Events, output.Transfers, uint32(j))
+		}
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func httpGet(t *testing.T, url string) ([]byte, int) {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	r, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, res.StatusCode
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package prototype
+
+import (
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/thor"
+)
+
+// CreditPlan credit plan of a contract, for users to consume.
+type CreditPlan struct {
+	Credit       math.HexOrDecimal256 `json:"credit"`
+	RecoveryRate math.HexOrDecimal256 `json:"recoveryRate"` // credit recovered per second
+}
+
+// Settings the multi-party payment (MPP) settings of a contract.
+type Settings struct {
+	Master         *thor.Address         `json:"master"`
+	CreditPlan     CreditPlan            `json:"creditPlan"`
+	CurrentSponsor *thor.Address         `json:"currentSponsor"`
+	SponsorEnergy  *math.HexOrDecimal256 `json:"sponsorEnergy"` // energy of current sponsor
+}
+
+// User a user of a contract, with remaining credit.
+type User struct {
+	Address thor.Address         `json:"address"`
+	IsUser  bool                 `json:"isUser"`
+	Credit  math.HexOrDecimal256 `json:"credit"`
+}
+
+// Sponsor a sponsor of a contract.
+type Sponsor struct {
+	Address   thor.Address `json:"address"`
+	IsSponsor bool         `json:"isSponsor"`
+	IsCurrent bool         `json:"isCurrent"`
+}
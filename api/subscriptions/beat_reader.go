@@ -55,6 +55,14 @@ func (br *beatReader) Read() ([]interface{}, bool, error) {
 			}
 			origin, _ := txs[i].Signer()
 			bloomContent.add(origin.Bytes())
+			// addresses called or created by clauses, which may produce no logs
+			for j, clause := range txs[i].Clauses() {
+				if to := clause.To(); to != nil {
+					bloomContent.add(to.Bytes())
+				} else {
+					bloomContent.add(thor.CreateContractAddress(txs[i].ID(), uint32(j), 0).Bytes())
+				}
+			}
 		}
 		signer, _ := header.Signer()
 		bloomContent.add(signer.Bytes())
@@ -0,0 +1,97 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestBeat(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	// a call without value, which produces neither events nor transfers
+	callee := thor.BytesToAddress([]byte("callee"))
+	trx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		Clause(tx.NewClause(&callee)).
+		Clause(tx.NewClause(nil)).
+		Expiration(10).
+		Gas(100000).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, flow.Adopt(trx))
+	b1, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b1, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	subs := subscriptions.New(chain, stateC, nil, nil, nil, utils.NewAllowedOrigins("*"), 100)
+	subs.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	defer subs.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		strings.Replace(ts.URL, "http", "ws", 1)+"/subscriptions/beat?pos="+b0.Header().ID().String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var msg subscriptions.BeatMessage
+	assert.Nil(t, conn.ReadJSON(&msg))
+	assert.Equal(t, b1.Header().ID(), msg.ID)
+	assert.Equal(t, b0.Header().ID(), msg.ParentID)
+
+	bloom := thor.NewBloom(int(msg.K))
+	copy(bloom.Bits[:], hexutil.MustDecode(msg.Bloom))
+	// items are added with leading zeros trimmed
+	test := func(addr thor.Address) bool {
+		return bloom.Test(bytes.TrimLeft(addr.Bytes(), "\x00"))
+	}
+	assert.True(t, test(genesis.DevAccounts()[1].Address), "origin")
+	assert.True(t, test(callee), "callee")
+	assert.True(t, test(thor.CreateContractAddress(trx.ID(), 1, 0)), "created contract")
+	assert.False(t, test(thor.BytesToAddress([]byte("stranger"))))
+}
@@ -6,19 +6,25 @@
 package subscriptions
 
 import (
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 )
 
 type eventReader struct {
 	chain       *chain.Chain
+	stateC      *state.Creator
+	abis        *abis.Registry
 	filter      *EventFilter
 	blockReader chain.BlockReader
 }
 
-func newEventReader(chain *chain.Chain, position thor.Bytes32, filter *EventFilter) *eventReader {
+func newEventReader(chain *chain.Chain, stateC *state.Creator, abis *abis.Registry, position thor.Bytes32, filter *EventFilter) *eventReader {
 	return &eventReader{
 		chain:       chain,
+		stateC:      stateC,
+		abis:        abis,
 		filter:      filter,
 		blockReader: chain.NewBlockReader(position),
 	}
@@ -31,24 +37,48 @@ func (er *eventReader) Read() ([]interface{}, bool, error) {
 	}
 	var msgs []interface{}
 	for _, block := range blocks {
-		receipts, err := er.chain.GetBlockReceipts(block.Header().ID())
+		blockMsgs, err := eventMessages(er.chain, er.stateC, er.abis, block, er.filter)
 		if err != nil {
 			return nil, false, err
 		}
-		txs := block.Transactions()
-		for i, receipt := range receipts {
-			for j, output := range receipt.Outputs {
-				for _, event := range output.Events {
-					if er.filter.Match(event) {
-						msg, err := convertEvent(block.Header(), txs[i], uint32(j), event, block.Obsolete)
-						if err != nil {
-							return nil, false, err
+		msgs = append(msgs, blockMsgs...)
+	}
+	return msgs, len(blocks) > 0, nil
+}
+
+// eventMessages returns messages of events in the block matching the filter.
+// Events are decoded if registry not nil.
+func eventMessages(chain *chain.Chain, stateC *state.Creator, registry *abis.Registry, block *chain.Block, filter *EventFilter) ([]interface{}, error) {
+	receipts, err := chain.GetBlockReceipts(block.Header().ID())
+	if err != nil {
+		return nil, err
+	}
+	codeHashFunc := func(address thor.Address) (thor.Bytes32, error) {
+		state, err := stateC.NewState(block.Header().StateRoot())
+		if err != nil {
+			return thor.Bytes32{}, err
+		}
+		return abis.StateCodeHash(state)(address)
+	}
+	var msgs []interface{}
+	txs := block.Transactions()
+	for i, receipt := range receipts {
+		for j, output := range receipt.Outputs {
+			for _, event := range output.Events {
+				if filter.Match(event) {
+					msg, err := convertEvent(block.Header(), txs[i], uint32(j), event, block.Obsolete)
+					if err != nil {
+						return nil, err
+					}
+					if registry != nil {
+						if msg.Decoded, err = registry.DecodeEvent(event.Address, codeHashFunc, event.Topics, event.Data); err != nil {
+							return nil, err
 						}
-						msgs = append(msgs, msg)
 					}
+					msgs = append(msgs, msg)
 				}
 			}
 		}
 	}
-	return msgs, len(blocks) > 0, nil
+	return msgs, nil
 }
@@ -0,0 +1,283 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// maxMultiplexSubscriptions limits count of subscriptions per multiplexed connection.
+const maxMultiplexSubscriptions = 32
+
+// Ops of MultiplexRequest.
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+)
+
+// Types of MultiplexMessage.
+const (
+	MsgSubscribed   = "subscribed"
+	MsgUnsubscribed = "unsubscribed"
+	MsgData         = "data"
+	MsgError        = "error"
+)
+
+// MultiplexRequest control message sent by the client over a multiplexed connection.
+type MultiplexRequest struct {
+	Op      string      `json:"op"`
+	ID      string      `json:"id"`                // chosen by the client, unique within the connection
+	Subject string      `json:"subject,omitempty"` // as the path of single subscription, e.g. 'event'
+	Params  QueryParams `json:"params,omitempty"`  // as the query of single subscription
+}
+
+// MultiplexMessage message sent by the server over a multiplexed connection.
+// Data is the message of the subject, e.g. EventMessage.
+type MultiplexMessage struct {
+	ID    string      `json:"id"`
+	Type  string      `json:"type"`
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// QueryParams query params in json. Each value is a string, or an array of strings for repeated params.
+type QueryParams map[string][]string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *QueryParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	params := make(QueryParams, len(raw))
+	for k, v := range raw {
+		var str string
+		if err := json.Unmarshal(v, &str); err == nil {
+			params[k] = []string{str}
+			continue
+		}
+		var strs []string
+		if err := json.Unmarshal(v, &strs); err != nil {
+			return errors.Errorf("params.%v: expected string or array of strings", k)
+		}
+		params[k] = strs
+	}
+	*p = params
+	return nil
+}
+
+// multiplexConn serves subscriptions over one websocket connection.
+// Each subscription holds at most one message pending to write, so that a subscription producing fast,
+// e.g. backtracing from an old position, is paused by a slow client rather than buffered, and
+// takes turns with other subscriptions.
+type multiplexConn struct {
+	s    *Subscriptions
+	conn *websocket.Conn
+	out  chan *MultiplexMessage
+	quit chan struct{}
+	wg   sync.WaitGroup
+
+	lock sync.Mutex
+	subs map[string]*multiplexSub
+}
+
+type multiplexSub struct {
+	unsub chan struct{} // closed to unsubscribe
+	done  chan struct{} // closed when piping ended
+}
+
+func (s *Subscriptions) handleMultiplex(w http.ResponseWriter, req *http.Request) error {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	conn, err := s.upgrader.Upgrade(w, req, nil)
+	// since the conn is hijacked here, no error should be returned in lines below
+	if err != nil {
+		log.Debug("upgrade to websocket", "err", err)
+		return nil
+	}
+
+	mc := &multiplexConn{
+		s:    s,
+		conn: conn,
+		out:  make(chan *MultiplexMessage),
+		quit: make(chan struct{}),
+		subs: make(map[string]*multiplexSub),
+	}
+	closed := make(chan struct{})
+	mc.wg.Add(1)
+	go func() {
+		defer mc.wg.Done()
+		defer close(closed)
+		mc.readLoop()
+	}()
+
+	var closeMsg []byte
+	if err := mc.writeLoop(closed); err != nil {
+		closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())
+	} else {
+		closeMsg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+	}
+	close(mc.quit)
+
+	if err := conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
+		log.Debug("write close message", "err", err)
+	}
+	if err := conn.Close(); err != nil {
+		log.Debug("close websocket", "err", err)
+	}
+	mc.wg.Wait()
+	return nil
+}
+
+func (mc *multiplexConn) writeLoop(closed <-chan struct{}) error {
+	for {
+		select {
+		case <-mc.s.done:
+			return nil
+		case <-closed:
+			return nil
+		case msg := <-mc.out:
+			if err := mc.conn.WriteJSON(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// send queues the message to write, returns false if the connection is closing.
+func (mc *multiplexConn) send(msg *MultiplexMessage) bool {
+	select {
+	case mc.out <- msg:
+		return true
+	case <-mc.quit:
+		return false
+	}
+}
+
+func (mc *multiplexConn) readLoop() {
+	for {
+		_, data, err := mc.conn.ReadMessage()
+		if err != nil {
+			log.Debug("websocket read err", "err", err)
+			return
+		}
+		var req MultiplexRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			mc.send(&MultiplexMessage{Type: MsgError, Error: err.Error()})
+			continue
+		}
+		if err := mc.handle(&req); err != nil {
+			mc.send(&MultiplexMessage{ID: req.ID, Type: MsgError, Error: err.Error()})
+		}
+	}
+}
+
+func (mc *multiplexConn) handle(req *MultiplexRequest) error {
+	if req.ID == "" {
+		return errors.New("id: required")
+	}
+	switch req.Op {
+	case OpSubscribe:
+		return mc.subscribe(req)
+	case OpUnsubscribe:
+		return mc.unsubscribe(req.ID)
+	default:
+		return errors.Errorf("op: unsupported %q", req.Op)
+	}
+}
+
+func (mc *multiplexConn) subscribe(req *MultiplexRequest) error {
+	reader, err := mc.s.newReader(req.Subject, url.Values(req.Params))
+	if err != nil {
+		return err
+	}
+
+	sub := &multiplexSub{make(chan struct{}), make(chan struct{})}
+	mc.lock.Lock()
+	if _, ok := mc.subs[req.ID]; ok {
+		mc.lock.Unlock()
+		closeReader(reader)
+		return errors.New("id: already subscribed")
+	}
+	if len(mc.subs) >= maxMultiplexSubscriptions {
+		mc.lock.Unlock()
+		closeReader(reader)
+		return errors.Errorf("exceeds %v subscriptions", maxMultiplexSubscriptions)
+	}
+	mc.subs[req.ID] = sub
+	mc.lock.Unlock()
+
+	// sent before any data of the subscription
+	mc.send(&MultiplexMessage{ID: req.ID, Type: MsgSubscribed})
+
+	mc.wg.Add(1)
+	go func() {
+		defer mc.wg.Done()
+		defer close(sub.done)
+		defer closeReader(reader)
+		if err := mc.pipe(req.ID, reader, sub.unsub); err != nil {
+			mc.lock.Lock()
+			// not unsubscribed in the meantime
+			if mc.subs[req.ID] == sub {
+				delete(mc.subs, req.ID)
+			}
+			mc.lock.Unlock()
+			mc.send(&MultiplexMessage{ID: req.ID, Type: MsgError, Error: err.Error()})
+		}
+	}()
+	return nil
+}
+
+func (mc *multiplexConn) unsubscribe(id string) error {
+	mc.lock.Lock()
+	sub, ok := mc.subs[id]
+	delete(mc.subs, id)
+	mc.lock.Unlock()
+	if !ok {
+		return errors.New("id: not subscribed")
+	}
+	close(sub.unsub)
+	// no data of the subscription after unsubscribed
+	<-sub.done
+	mc.send(&MultiplexMessage{ID: id, Type: MsgUnsubscribed})
+	return nil
+}
+
+// pipe pipes messages of the reader until unsubscribed or the connection closing.
+func (mc *multiplexConn) pipe(id string, reader msgReader, unsub <-chan struct{}) error {
+	ticker := mc.s.chain.NewTicker()
+	for {
+		msgs, hasMore, err := reader.Read()
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			select {
+			case mc.out <- &MultiplexMessage{ID: id, Type: MsgData, Data: msg}:
+			case <-unsub:
+				return nil
+			case <-mc.quit:
+				return nil
+			}
+		}
+		if !hasMore {
+			select {
+			case <-unsub:
+				return nil
+			case <-mc.quit:
+				return nil
+			case <-ticker.C():
+			}
+		}
+	}
+}
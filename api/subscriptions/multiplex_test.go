@@ -0,0 +1,135 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+type multiplexMessage struct {
+	ID    string          `json:"id"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+	Error string          `json:"error"`
+}
+
+func TestMultiplex(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	trx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		Clause(tx.NewClause(&recipient).WithValue(big.NewInt(100))).
+		Expiration(10).
+		Gas(21000).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, flow.Adopt(trx))
+	b1, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b1, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	subs := subscriptions.New(chain, stateC, nil, nil, nil, utils.NewAllowedOrigins("*"), 100)
+	subs.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	defer subs.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(strings.Replace(ts.URL, "http", "ws", 1)+"/subscriptions/multiplex", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	send := func(req string) {
+		assert.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte(req)))
+	}
+	read := func() *multiplexMessage {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var msg multiplexMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		return &msg
+	}
+
+	// control messages are handled in order, so are replies
+	send(`{"op":"subscribe","id":"x","subject":"foo"}`)
+	assert.Equal(t, multiplexMessage{ID: "x", Type: subscriptions.MsgError, Error: "not found"}, *read())
+	send(`{"op":"subscribe","id":"x","subject":"txstatus","params":{"id":"0x1"}}`)
+	assert.Equal(t, subscriptions.MsgError, read().Type, "invalid id")
+	send(`{"op":"unsubscribe","id":"x"}`)
+	assert.Equal(t, subscriptions.MsgError, read().Type, "not subscribed")
+	send(`{"op":"foo","id":"x"}`)
+	assert.Equal(t, subscriptions.MsgError, read().Type)
+
+	send(`{"op":"subscribe","id":"tx","subject":"txstatus","params":{"pos":"` + b0.Header().ID().String() + `","id":["` + trx.ID().String() + `"]}}`)
+	assert.Equal(t, multiplexMessage{ID: "tx", Type: subscriptions.MsgSubscribed}, *read())
+
+	msg := read()
+	assert.Equal(t, "tx", msg.ID)
+	assert.Equal(t, subscriptions.MsgData, msg.Type)
+	var status subscriptions.TxStatusMessage
+	assert.Nil(t, json.Unmarshal(msg.Data, &status))
+	assert.Equal(t, trx.ID(), status.TxID)
+	assert.False(t, status.Reverted)
+	assert.Equal(t, b1.Header().ID(), status.Meta.BlockID)
+
+	send(`{"op":"subscribe","id":"tx","subject":"block"}`)
+	assert.Equal(t, multiplexMessage{ID: "tx", Type: subscriptions.MsgError, Error: "id: already subscribed"}, *read())
+
+	send(`{"op":"subscribe","id":"blk","subject":"block","params":{"pos":"` + b0.Header().ID().String() + `"}}`)
+	assert.Equal(t, multiplexMessage{ID: "blk", Type: subscriptions.MsgSubscribed}, *read())
+	msg = read()
+	assert.Equal(t, "blk", msg.ID)
+	var blk subscriptions.BlockMessage
+	assert.Nil(t, json.Unmarshal(msg.Data, &blk))
+	assert.Equal(t, b1.Header().ID(), blk.ID)
+
+	send(`{"op":"unsubscribe","id":"blk"}`)
+	assert.Equal(t, multiplexMessage{ID: "blk", Type: subscriptions.MsgUnsubscribed}, *read())
+	send(`{"op":"unsubscribe","id":"tx"}`)
+	assert.Equal(t, multiplexMessage{ID: "tx", Type: subscriptions.MsgUnsubscribed}, *read())
+}
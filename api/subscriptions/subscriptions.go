@@ -7,21 +7,30 @@ package subscriptions
 
 import (
 	"net/http"
+	"net/url"
 	"sync"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
 )
 
 type Subscriptions struct {
 	backtraceLimit uint32
 	chain          *chain.Chain
+	stateC         *state.Creator
+	pool           *txpool.TxPool
+	abis           *abis.Registry
+	logDB          logdb.Store
 	upgrader       *websocket.Upgrader
 	done           chan struct{}
 	wg             sync.WaitGroup
@@ -31,14 +40,33 @@ type msgReader interface {
 	Read() (msgs []interface{}, hasMore bool, err error)
 }
 
+// closeReader releases resources held by the reader, if any.
+func closeReader(reader msgReader) {
+	if c, ok := reader.(interface{ Close() }); ok {
+		c.Close()
+	}
+}
+
 var (
 	log = log15.New("pkg", "subscriptions")
 )
 
-func New(chain *chain.Chain, allowedOrigins []string, backtraceLimit uint32) *Subscriptions {
+func New(
+	chain *chain.Chain,
+	stateC *state.Creator,
+	pool *txpool.TxPool,
+	abis *abis.Registry,
+	logDB logdb.Store,
+	allowedOrigins *utils.AllowedOrigins,
+	backtraceLimit uint32,
+) *Subscriptions {
 	return &Subscriptions{
 		backtraceLimit: backtraceLimit,
 		chain:          chain,
+		stateC:         stateC,
+		pool:           pool,
+		abis:           abis,
+		logDB:          logDB,
 		upgrader: &websocket.Upgrader{
 			EnableCompression: true,
 			CheckOrigin: func(r *http.Request) bool {
@@ -46,52 +74,47 @@ func New(chain *chain.Chain, allowedOrigins []string, backtraceLimit uint32) *Su
 				if origin == "" {
 					return true
 				}
-				for _, allowedOrigin := range allowedOrigins {
-					if allowedOrigin == origin || allowedOrigin == "*" {
-						return true
-					}
-				}
-				return false
+				return allowedOrigins.Allowed(origin)
 			},
 		},
 		done: make(chan struct{}),
 	}
 }
 
-func (s *Subscriptions) handleBlockReader(w http.ResponseWriter, req *http.Request) (*blockReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
+func (s *Subscriptions) handleBlockReader(query url.Values) (*blockReader, error) {
+	position, err := s.parsePosition(query.Get("pos"))
 	if err != nil {
 		return nil, err
 	}
 	return newBlockReader(s.chain, position), nil
 }
 
-func (s *Subscriptions) handleEventReader(w http.ResponseWriter, req *http.Request) (*eventReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
+func (s *Subscriptions) handleEventReader(query url.Values) (*eventReader, error) {
+	position, err := s.parsePosition(query.Get("pos"))
 	if err != nil {
 		return nil, err
 	}
-	address, err := parseAddress(req.URL.Query().Get("addr"))
+	address, err := parseAddress(query.Get("addr"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "addr"))
 	}
-	t0, err := parseTopic(req.URL.Query().Get("t0"))
+	t0, err := parseTopic(query.Get("t0"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "t0"))
 	}
-	t1, err := parseTopic(req.URL.Query().Get("t1"))
+	t1, err := parseTopic(query.Get("t1"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "t1"))
 	}
-	t2, err := parseTopic(req.URL.Query().Get("t2"))
+	t2, err := parseTopic(query.Get("t2"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "t2"))
 	}
-	t3, err := parseTopic(req.URL.Query().Get("t3"))
+	t3, err := parseTopic(query.Get("t3"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "t3"))
 	}
-	t4, err := parseTopic(req.URL.Query().Get("t4"))
+	t4, err := parseTopic(query.Get("t4"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "t4"))
 	}
@@ -103,23 +126,23 @@ func (s *Subscriptions) handleEventReader(w http.ResponseWriter, req *http.Reque
 		Topic3:  t3,
 		Topic4:  t4,
 	}
-	return newEventReader(s.chain, position, eventFilter), nil
+	return newEventReader(s.chain, s.stateC, s.abis, position, eventFilter), nil
 }
 
-func (s *Subscriptions) handleTransferReader(w http.ResponseWriter, req *http.Request) (*transferReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
+func (s *Subscriptions) handleTransferReader(query url.Values) (*transferReader, error) {
+	position, err := s.parsePosition(query.Get("pos"))
 	if err != nil {
 		return nil, err
 	}
-	txOrigin, err := parseAddress(req.URL.Query().Get("txOrigin"))
+	txOrigin, err := parseAddress(query.Get("txOrigin"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "txOrigin"))
 	}
-	sender, err := parseAddress(req.URL.Query().Get("sender"))
+	sender, err := parseAddress(query.Get("sender"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "sender"))
 	}
-	recipient, err := parseAddress(req.URL.Query().Get("recipient"))
+	recipient, err := parseAddress(query.Get("recipient"))
 	if err != nil {
 		return nil, utils.BadRequest(errors.WithMessage(err, "recipient"))
 	}
@@ -131,42 +154,133 @@ func (s *Subscriptions) handleTransferReader(w http.ResponseWriter, req *http.Re
 	return newTransferReader(s.chain, position, transferFilter), nil
 }
 
-func (s *Subscriptions) handleBeatReader(w http.ResponseWriter, req *http.Request) (*beatReader, error) {
-	position, err := s.parsePosition(req.URL.Query().Get("pos"))
+func (s *Subscriptions) handleBeatReader(query url.Values) (*beatReader, error) {
+	position, err := s.parsePosition(query.Get("pos"))
 	if err != nil {
 		return nil, err
 	}
 	return newBeatReader(s.chain, position), nil
 }
 
-func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request) error {
-	s.wg.Add(1)
-	defer s.wg.Done()
+func (s *Subscriptions) handleWatchReader(query url.Values) (*watchReader, error) {
+	position, err := s.parsePosition(query.Get("pos"))
+	if err != nil {
+		return nil, err
+	}
+	addrs := query["addr"]
+	if len(addrs) == 0 {
+		return nil, utils.BadRequest(errors.New("addr: required"))
+	}
+	if len(addrs) > maxWatchAddresses {
+		return nil, utils.BadRequest(errors.Errorf("addr: exceeds %v addresses", maxWatchAddresses))
+	}
+	filter := &WatchFilter{}
+	for _, a := range addrs {
+		addr, err := thor.ParseAddress(a)
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "addr"))
+		}
+		filter.Addresses = append(filter.Addresses, addr)
+	}
+	return newWatchReader(s.chain, s.stateC, position, filter), nil
+}
+
+func (s *Subscriptions) handleTxStatusReader(query url.Values) (*txStatusReader, error) {
+	position, err := s.parsePosition(query.Get("pos"))
+	if err != nil {
+		return nil, err
+	}
+	ids := query["id"]
+	if len(ids) == 0 {
+		return nil, utils.BadRequest(errors.New("id: required"))
+	}
+	if len(ids) > maxTxStatusIDs {
+		return nil, utils.BadRequest(errors.Errorf("id: exceeds %v ids", maxTxStatusIDs))
+	}
+	txIDs := make(map[thor.Bytes32]bool, len(ids))
+	for _, str := range ids {
+		id, err := thor.ParseBytes32(str)
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "id"))
+		}
+		txIDs[id] = true
+	}
+	return newTxStatusReader(s.chain, position, txIDs), nil
+}
 
+func (s *Subscriptions) handleTxLifecycleReader(query url.Values) (*txLifecycleReader, error) {
+	origin, err := parseAddress(query.Get("origin"))
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "origin"))
+	}
+	ids := query["id"]
+	if (len(ids) == 0) == (origin == nil) {
+		return nil, utils.BadRequest(errors.New("either id or origin required"))
+	}
+	if len(ids) > maxTxStatusIDs {
+		return nil, utils.BadRequest(errors.Errorf("id: exceeds %v ids", maxTxStatusIDs))
+	}
+	txIDs := make([]thor.Bytes32, 0, len(ids))
+	for _, str := range ids {
+		id, err := thor.ParseBytes32(str)
+		if err != nil {
+			return nil, utils.BadRequest(errors.WithMessage(err, "id"))
+		}
+		txIDs = append(txIDs, id)
+	}
+	return newTxLifecycleReader(s.chain, s.pool, txIDs, origin), nil
+}
+
+func (s *Subscriptions) handleTruncateReader() (*truncateReader, error) {
+	feed, ok := s.logDB.(truncateFeed)
+	if !ok {
+		return nil, utils.HTTPError(errors.New("log store: truncate events not supported"), http.StatusNotFound)
+	}
+	return newTruncateReader(feed), nil
+}
+
+// newReader creates the message reader of the subject, with options in the query.
+// The reader should be closed by closeReader.
+func (s *Subscriptions) newReader(subject string, query url.Values) (msgReader, error) {
 	var (
 		reader msgReader
 		err    error
 	)
-	switch mux.Vars(req)["subject"] {
+	switch subject {
 	case "block":
-		if reader, err = s.handleBlockReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleBlockReader(query)
 	case "event":
-		if reader, err = s.handleEventReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleEventReader(query)
 	case "transfer":
-		if reader, err = s.handleTransferReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleTransferReader(query)
 	case "beat":
-		if reader, err = s.handleBeatReader(w, req); err != nil {
-			return err
-		}
+		reader, err = s.handleBeatReader(query)
+	case "watch":
+		reader, err = s.handleWatchReader(query)
+	case "txstatus":
+		reader, err = s.handleTxStatusReader(query)
+	case "txlifecycle":
+		reader, err = s.handleTxLifecycleReader(query)
+	case "truncate":
+		reader, err = s.handleTruncateReader()
 	default:
-		return utils.HTTPError(errors.New("not found"), http.StatusNotFound)
+		return nil, utils.HTTPError(errors.New("not found"), http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+func (s *Subscriptions) handleSubject(w http.ResponseWriter, req *http.Request) error {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	reader, err := s.newReader(mux.Vars(req)["subject"], req.URL.Query())
+	if err != nil {
+		return err
 	}
+	defer closeReader(reader)
 
 	conn, err := s.upgrader.Upgrade(w, req, nil)
 	// since the conn is hijacked here, no error should be returned in lines below
@@ -284,5 +398,6 @@ func (s *Subscriptions) Close() {
 func (s *Subscriptions) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
+	sub.Path("/multiplex").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(s.handleMultiplex))
 	sub.Path("/{subject}").Methods("Get").HandlerFunc(utils.WrapHandlerFunc(s.handleSubject))
 }
@@ -31,28 +31,39 @@ func (tr *transferReader) Read() ([]interface{}, bool, error) {
 	}
 	var msgs []interface{}
 	for _, block := range blocks {
-		receipts, err := tr.chain.GetBlockReceipts(block.Header().ID())
+		blockMsgs, err := transferMessages(tr.chain, block, tr.filter)
 		if err != nil {
 			return nil, false, err
 		}
-		txs := block.Transactions()
-		for i, receipt := range receipts {
-			for j, output := range receipt.Outputs {
-				for _, transfer := range output.Transfers {
-					origin, err := txs[i].Signer()
+		msgs = append(msgs, blockMsgs...)
+	}
+	return msgs, len(blocks) > 0, nil
+}
+
+// transferMessages returns messages of transfers in the block matching the filter.
+func transferMessages(chain *chain.Chain, block *chain.Block, filter *TransferFilter) ([]interface{}, error) {
+	receipts, err := chain.GetBlockReceipts(block.Header().ID())
+	if err != nil {
+		return nil, err
+	}
+	var msgs []interface{}
+	txs := block.Transactions()
+	for i, receipt := range receipts {
+		for j, output := range receipt.Outputs {
+			for _, transfer := range output.Transfers {
+				origin, err := txs[i].Signer()
+				if err != nil {
+					return nil, err
+				}
+				if filter.Match(transfer, origin) {
+					msg, err := convertTransfer(block.Header(), txs[i], uint32(j), transfer, block.Obsolete)
 					if err != nil {
-						return nil, false, err
-					}
-					if tr.filter.Match(transfer, origin) {
-						msg, err := convertTransfer(block.Header(), txs[i], uint32(j), transfer, block.Obsolete)
-						if err != nil {
-							return nil, false, err
-						}
-						msgs = append(msgs, msg)
+						return nil, err
 					}
+					msgs = append(msgs, msg)
 				}
 			}
 		}
 	}
-	return msgs, len(blocks) > 0, nil
+	return msgs, nil
 }
@@ -0,0 +1,74 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/logdb"
+)
+
+// maxPendingTruncates limits messages buffered for a slow client, which then gets disconnected,
+// since log db writes are blocked until truncate events received.
+const maxPendingTruncates = 64
+
+// truncateFeed is implemented by log stores which post truncate events, e.g. logdb.LogDB.
+type truncateFeed interface {
+	SubscribeTruncateEvent(ch chan *logdb.TruncateEvent) event.Subscription
+}
+
+// truncateReader pipes truncate events of the log store. Events are received by its own
+// goroutine, so that log db writes are not blocked by the client.
+type truncateReader struct {
+	sub     event.Subscription
+	lock    sync.Mutex
+	pending []interface{}
+	err     error
+}
+
+func newTruncateReader(feed truncateFeed) *truncateReader {
+	ch := make(chan *logdb.TruncateEvent, 16)
+	tr := &truncateReader{sub: feed.SubscribeTruncateEvent(ch)}
+	go tr.loop(ch)
+	return tr
+}
+
+func (tr *truncateReader) loop(ch <-chan *logdb.TruncateEvent) {
+	for {
+		select {
+		case ev := <-ch:
+			tr.lock.Lock()
+			if len(tr.pending) >= maxPendingTruncates {
+				tr.err = errors.New("too many pending messages")
+			} else {
+				tr.pending = append(tr.pending, &TruncateMessage{ev.From, ev.TxIDs, ev.TooMany})
+			}
+			tr.lock.Unlock()
+		case <-tr.sub.Err():
+			// unsubscribed
+			return
+		}
+	}
+}
+
+func (tr *truncateReader) Read() ([]interface{}, bool, error) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	if tr.err != nil {
+		return nil, false, tr.err
+	}
+	msgs := tr.pending
+	tr.pending = nil
+	return msgs, false, nil
+}
+
+// Close unsubscribes from the log store.
+func (tr *truncateReader) Close() {
+	tr.sub.Unsubscribe()
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestTruncate(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	logDB, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logDB.Close()
+
+	router := mux.NewRouter()
+	subscriptions.New(chain, stateC, nil, nil, nil, utils.NewAllowedOrigins("*"), 100).Mount(router, "/none")
+	subs := subscriptions.New(chain, stateC, nil, nil, logDB, utils.NewAllowedOrigins("*"), 100)
+	subs.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	defer subs.Close()
+
+	res, err := http.Get(ts.URL + "/none/truncate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, "no log db")
+
+	conn, _, err := websocket.DefaultDialer.Dial(strings.Replace(ts.URL, "http", "ws", 1)+"/subscriptions/truncate", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	txID := thor.BytesToBytes32([]byte("tx"))
+	header := new(block.Builder).ParentID(b0.Header().ID()).Build().Header()
+	if err := logDB.Prepare(header).ForTransaction(txID, thor.Address{}).
+		Insert(tx.Events{{Address: thor.BytesToAddress([]byte("addr"))}}, nil, 0).Commit(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, logDB.Truncate(0))
+	// wait for the event received by the reader
+	time.Sleep(10 * time.Millisecond)
+
+	// messages piped on new block
+	b1 := new(block.Builder).ParentID(b0.Header().ID()).TotalScore(1).Build()
+	sig, _ := crypto.Sign(b1.Header().SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if _, err := chain.AddBlock(b1.WithSignature(sig), nil); err != nil {
+		t.Fatal(err)
+	}
+	var msg subscriptions.TruncateMessage
+	assert.Nil(t, conn.ReadJSON(&msg))
+	assert.Equal(t, subscriptions.TruncateMessage{From: 1, TxIDs: []thor.Bytes32{txID}}, msg)
+}
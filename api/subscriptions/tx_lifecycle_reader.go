@@ -0,0 +1,149 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/txpool"
+)
+
+// txLifecycleReader follows subscribed txs through the pool and the best chain, and emits
+// messages on transitions. Txs are checked on every new block, and are no longer followed
+// once finalized or dropped.
+type txLifecycleReader struct {
+	chain       *chain.Chain
+	pool        *txpool.TxPool
+	origin      *thor.Address     // to follow txs sent by, if not nil
+	blockReader chain.BlockReader // to find txs of the origin in new blocks
+	ids         []thor.Bytes32    // followed txs, in order of being followed
+	last        map[thor.Bytes32]*TxLifecycleMessage
+}
+
+func newTxLifecycleReader(chain *chain.Chain, pool *txpool.TxPool, ids []thor.Bytes32, origin *thor.Address) *txLifecycleReader {
+	lr := &txLifecycleReader{
+		chain:  chain,
+		pool:   pool,
+		origin: origin,
+		last:   make(map[thor.Bytes32]*TxLifecycleMessage),
+	}
+	for _, id := range ids {
+		lr.follow(id)
+	}
+	if origin != nil {
+		lr.blockReader = chain.NewBlockReader(chain.BestBlock().Header().ID())
+	}
+	return lr
+}
+
+func (lr *txLifecycleReader) follow(id thor.Bytes32) {
+	if _, ok := lr.last[id]; !ok {
+		lr.ids = append(lr.ids, id)
+		lr.last[id] = nil
+	}
+}
+
+func (lr *txLifecycleReader) Read() ([]interface{}, bool, error) {
+	pooled := make(map[thor.Bytes32]bool)
+	if lr.pool != nil {
+		for _, tx := range lr.pool.Dump() {
+			pooled[tx.ID()] = true
+			if lr.origin != nil {
+				if origin, err := tx.Signer(); err == nil && origin == *lr.origin {
+					lr.follow(tx.ID())
+				}
+			}
+		}
+	}
+	if lr.origin != nil {
+		for {
+			blocks, err := lr.blockReader.Read()
+			if err != nil {
+				return nil, false, err
+			}
+			if len(blocks) == 0 {
+				break
+			}
+			for _, block := range blocks {
+				if block.Obsolete {
+					continue
+				}
+				for _, tx := range block.Transactions() {
+					if origin, err := tx.Signer(); err == nil && origin == *lr.origin {
+						lr.follow(tx.ID())
+					}
+				}
+			}
+		}
+	}
+
+	best := lr.chain.BestBlock().Header()
+	var (
+		msgs []interface{}
+		ids  []thor.Bytes32
+	)
+	for _, id := range lr.ids {
+		last := lr.last[id]
+		meta, err := lr.chain.GetTransactionMeta(id, best.ID())
+		if err != nil {
+			if !lr.chain.IsNotFound(err) {
+				return nil, false, err
+			}
+			switch {
+			case pooled[id]:
+				if last == nil || last.Status != TxLifecyclePooled {
+					last = &TxLifecycleMessage{TxID: id, Status: TxLifecyclePooled}
+					msgs = append(msgs, last)
+				}
+			case last != nil:
+				// neither on the chain nor in the pool any more
+				msgs = append(msgs, &TxLifecycleMessage{TxID: id, Status: TxLifecycleDropped})
+				delete(lr.last, id)
+				continue
+			}
+			lr.last[id] = last
+			ids = append(ids, id)
+			continue
+		}
+
+		header, err := lr.chain.GetBlockHeader(meta.BlockID)
+		if err != nil {
+			return nil, false, err
+		}
+		msg := &TxLifecycleMessage{
+			TxID:          id,
+			Confirmations: best.Number() - header.Number(),
+			Meta: &WatchMeta{
+				BlockID:        header.ID(),
+				BlockNumber:    header.Number(),
+				BlockTimestamp: header.Timestamp(),
+			},
+		}
+		switch {
+		case last == nil || last.Meta == nil || last.Meta.BlockID != header.ID():
+			msg.Status = TxLifecycleIncluded
+		case last.Confirmations != msg.Confirmations:
+			msg.Status = TxLifecycleConfirmed
+		default:
+			msg = last
+		}
+		if msg != last {
+			msgs = append(msgs, msg)
+		}
+		if msg.Confirmations >= utils.FinalizedDepth {
+			finalized := *msg
+			finalized.Status = TxLifecycleFinalized
+			msgs = append(msgs, &finalized)
+			delete(lr.last, id)
+			continue
+		}
+		lr.last[id] = msg
+		ids = append(ids, id)
+	}
+	lr.ids = ids
+	return msgs, false, nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txpool"
+)
+
+func TestTxLifecycle(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	pool := txpool.New(chain, stateC, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Minute})
+	defer pool.Close()
+
+	sender := genesis.DevAccounts()[1]
+	newTx := func(nonce uint64) *tx.Transaction {
+		recipient := thor.BytesToAddress([]byte("recipient"))
+		trx := new(tx.Builder).
+			ChainTag(chain.Tag()).
+			Clause(tx.NewClause(&recipient)).
+			Expiration(100).
+			Gas(21000).
+			Nonce(nonce).
+			Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), sender.PrivateKey)
+		return trx.WithSignature(sig)
+	}
+	packed, dropped := newTx(1), newTx(2)
+	assert.Nil(t, pool.Add(packed))
+	assert.Nil(t, pool.Add(dropped))
+
+	router := mux.NewRouter()
+	subs := subscriptions.New(chain, stateC, pool, nil, nil, utils.NewAllowedOrigins("*"), 100)
+	subs.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	defer subs.Close()
+
+	for _, query := range []string{"", "id=0x01", "origin=0x01", "origin=" + sender.Address.String() + "&id=" + packed.ID().String()} {
+		res, err := http.Get(ts.URL + "/subscriptions/txlifecycle?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(
+		strings.Replace(ts.URL, "http", "ws", 1)+"/subscriptions/txlifecycle?origin="+sender.Address.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	read := func() *subscriptions.TxLifecycleMessage {
+		var msg subscriptions.TxLifecycleMessage
+		assert.Nil(t, conn.ReadJSON(&msg))
+		return &msg
+	}
+	pooled := map[thor.Bytes32]bool{}
+	for i := 0; i < 2; i++ {
+		msg := read()
+		assert.Equal(t, subscriptions.TxLifecyclePooled, msg.Status)
+		assert.Nil(t, msg.Meta)
+		pooled[msg.TxID] = true
+	}
+	assert.Equal(t, map[thor.Bytes32]bool{packed.ID(): true, dropped.ID(): true}, pooled)
+
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, flow.Adopt(packed))
+	b1, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	pool.Remove(dropped.Hash(), dropped.ID())
+	if _, err := chain.AddBlock(b1, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := map[thor.Bytes32]*subscriptions.TxLifecycleMessage{}
+	for i := 0; i < 2; i++ {
+		msg := read()
+		msgs[msg.TxID] = msg
+	}
+	if msg := msgs[packed.ID()]; assert.NotNil(t, msg) {
+		assert.Equal(t, subscriptions.TxLifecycleIncluded, msg.Status)
+		assert.Equal(t, uint32(0), msg.Confirmations)
+		if assert.NotNil(t, msg.Meta) {
+			assert.Equal(t, b1.Header().ID(), msg.Meta.BlockID)
+		}
+	}
+	if msg := msgs[dropped.ID()]; assert.NotNil(t, msg) {
+		assert.Equal(t, subscriptions.TxLifecycleDropped, msg.Status)
+	}
+
+	b2 := new(block.Builder).ParentID(b1.Header().ID()).TotalScore(b1.Header().TotalScore() + 1).Build()
+	sig, _ := crypto.Sign(b2.Header().SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if _, err := chain.AddBlock(b2.WithSignature(sig), nil); err != nil {
+		t.Fatal(err)
+	}
+	msg := read()
+	assert.Equal(t, packed.ID(), msg.TxID)
+	assert.Equal(t, subscriptions.TxLifecycleConfirmed, msg.Status)
+	assert.Equal(t, uint32(1), msg.Confirmations)
+}
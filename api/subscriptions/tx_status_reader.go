@@ -0,0 +1,63 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// maxTxStatusIDs limits count of txs per tx status subscription.
+const maxTxStatusIDs = 1000
+
+type txStatusReader struct {
+	chain       *chain.Chain
+	ids         map[thor.Bytes32]bool
+	blockReader chain.BlockReader
+}
+
+func newTxStatusReader(chain *chain.Chain, position thor.Bytes32, ids map[thor.Bytes32]bool) *txStatusReader {
+	return &txStatusReader{
+		chain:       chain,
+		ids:         ids,
+		blockReader: chain.NewBlockReader(position),
+	}
+}
+
+func (tr *txStatusReader) Read() ([]interface{}, bool, error) {
+	blocks, err := tr.blockReader.Read()
+	if err != nil {
+		return nil, false, err
+	}
+	var msgs []interface{}
+	for _, block := range blocks {
+		header := block.Header()
+		var receipts tx.Receipts
+		for i, trx := range block.Transactions() {
+			if !tr.ids[trx.ID()] {
+				continue
+			}
+			// load receipts only if any tx matched
+			if receipts == nil {
+				if receipts, err = tr.chain.GetBlockReceipts(header.ID()); err != nil {
+					return nil, false, err
+				}
+			}
+			msgs = append(msgs, &TxStatusMessage{
+				TxID:     trx.ID(),
+				Reverted: receipts[i].Reverted,
+				Meta: WatchMeta{
+					BlockID:        header.ID(),
+					BlockNumber:    header.Number(),
+					BlockTimestamp: header.Timestamp(),
+				},
+				Obsolete: block.Obsolete,
+			})
+		}
+	}
+	return msgs, len(blocks) > 0, nil
+}
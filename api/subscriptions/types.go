@@ -8,6 +8,7 @@ package subscriptions
 import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/thor"
@@ -111,6 +112,7 @@ type EventMessage struct {
 	Data     string         `json:"data"`
 	Meta     LogMeta        `json:"meta"`
 	Obsolete bool           `json:"obsolete"`
+	Decoded  *abis.Decoded  `json:"decoded,omitempty"` // decoded event, if the ABI of the contract known
 }
 
 func convertEvent(header *block.Header, tx *tx.Transaction, clauseIndex uint32, event *tx.Event, obsolete bool) (*EventMessage, error) {
@@ -136,12 +138,12 @@ func convertEvent(header *block.Header, tx *tx.Transaction, clauseIndex uint32,
 
 // EventFilter contains options for contract event filtering.
 type EventFilter struct {
-	Address *thor.Address // restricts matches to events created by specific contracts
-	Topic0  *thor.Bytes32
-	Topic1  *thor.Bytes32
-	Topic2  *thor.Bytes32
-	Topic3  *thor.Bytes32
-	Topic4  *thor.Bytes32
+	Address *thor.Address `json:"addr"` // restricts matches to events created by specific contracts
+	Topic0  *thor.Bytes32 `json:"t0"`
+	Topic1  *thor.Bytes32 `json:"t1"`
+	Topic2  *thor.Bytes32 `json:"t2"`
+	Topic3  *thor.Bytes32 `json:"t3"`
+	Topic4  *thor.Bytes32 `json:"t4"`
 }
 
 // Match returs whether event matches filter
@@ -172,9 +174,9 @@ func (ef *EventFilter) Match(event *tx.Event) bool {
 
 // TransferFilter contains options for contract transfer filtering.
 type TransferFilter struct {
-	TxOrigin  *thor.Address // who send transaction
-	Sender    *thor.Address // who transferred tokens
-	Recipient *thor.Address // who received tokens
+	TxOrigin  *thor.Address `json:"txOrigin"`  // who send transaction
+	Sender    *thor.Address `json:"sender"`    // who transferred tokens
+	Recipient *thor.Address `json:"recipient"` // who received tokens
 }
 
 // Match returs whether transfer matches filter
@@ -202,3 +204,58 @@ type BeatMessage struct {
 	K         uint32       `json:"k"`
 	Obsolete  bool         `json:"obsolete"`
 }
+
+// WatchFilter contains addresses to watch.
+type WatchFilter struct {
+	Addresses []thor.Address `json:"addresses"`
+}
+
+type WatchMeta struct {
+	BlockID        thor.Bytes32 `json:"blockID"`
+	BlockNumber    uint32       `json:"blockNumber"`
+	BlockTimestamp uint64       `json:"blockTimestamp"`
+}
+
+// WatchMessage changes of a watched address in a block, piped by websocket.
+type WatchMessage struct {
+	Address        thor.Address          `json:"address"`
+	Balance        *math.HexOrDecimal256 `json:"balance"` // VET balance after the block
+	Energy         *math.HexOrDecimal256 `json:"energy"`  // VTHO balance after the block
+	BalanceChanged bool                  `json:"balanceChanged"`
+	EnergyChanged  bool                  `json:"energyChanged"` // changed other than growth
+	TxIDs          []thor.Bytes32        `json:"txIDs"`         // txs interacting with the address
+	Meta           WatchMeta             `json:"meta"`
+	Obsolete       bool                  `json:"obsolete"`
+}
+
+// TxStatusMessage inclusion of a subscribed tx, piped by websocket.
+type TxStatusMessage struct {
+	TxID     thor.Bytes32 `json:"txID"`
+	Reverted bool         `json:"reverted"`
+	Meta     WatchMeta    `json:"meta"` // the block including the tx
+	Obsolete bool         `json:"obsolete"`
+}
+
+// Statuses of TxLifecycleMessage.
+const (
+	TxLifecyclePooled    = "pooled"    // in the pool, or back to the pool since the including block reverted
+	TxLifecycleIncluded  = "included"  // included by a block of the best chain
+	TxLifecycleConfirmed = "confirmed" // more blocks built on top of the including one
+	TxLifecycleFinalized = "finalized" // the including block is finalized
+	TxLifecycleDropped   = "dropped"   // neither on the best chain nor in the pool
+)
+
+// TxLifecycleMessage status transition of a subscribed tx, piped by websocket.
+type TxLifecycleMessage struct {
+	TxID          thor.Bytes32 `json:"txID"`
+	Status        string       `json:"status"`
+	Confirmations uint32       `json:"confirmations"` // count of blocks on top of the including one
+	Meta          *WatchMeta   `json:"meta"`          // the block including the tx, null if not included
+}
+
+// TruncateMessage removal of logs by reorgs, piped by websocket.
+type TruncateMessage struct {
+	From    uint32         `json:"from"`              // logs of blocks from it are removed
+	TxIDs   []thor.Bytes32 `json:"txIDs"`             // txs which had logs removed, null if tooMany
+	TooMany bool           `json:"tooMany,omitempty"` // too many txs, all logs from 'from' should be taken as removed
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// maxWatchAddresses limits count of addresses per watch list.
+const maxWatchAddresses = 10000
+
+type watchReader struct {
+	chain       *chain.Chain
+	stateC      *state.Creator
+	filter      *WatchFilter
+	blockReader chain.BlockReader
+}
+
+func newWatchReader(chain *chain.Chain, stateC *state.Creator, position thor.Bytes32, filter *WatchFilter) *watchReader {
+	return &watchReader{
+		chain:       chain,
+		stateC:      stateC,
+		filter:      filter,
+		blockReader: chain.NewBlockReader(position),
+	}
+}
+
+func (wr *watchReader) Read() ([]interface{}, bool, error) {
+	blocks, err := wr.blockReader.Read()
+	if err != nil {
+		return nil, false, err
+	}
+	var msgs []interface{}
+	for _, block := range blocks {
+		blockMsgs, err := watchMessages(wr.chain, wr.stateC, block, wr.filter)
+		if err != nil {
+			return nil, false, err
+		}
+		msgs = append(msgs, blockMsgs...)
+	}
+	return msgs, len(blocks) > 0, nil
+}
+
+// watchMessages returns messages of watched addresses, whose balances changed,
+// or interacted with by txs in the block.
+func watchMessages(chain *chain.Chain, stateC *state.Creator, block *chain.Block, filter *WatchFilter) ([]interface{}, error) {
+	header := block.Header()
+	if header.Number() == 0 || len(filter.Addresses) == 0 {
+		return nil, nil
+	}
+	receipts, err := chain.GetBlockReceipts(header.ID())
+	if err != nil {
+		return nil, err
+	}
+	parent, err := chain.GetBlockHeader(header.ParentID())
+	if err != nil {
+		return nil, err
+	}
+	parentState, err := stateC.NewState(parent.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	st, err := stateC.NewState(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[thor.Address]bool, len(filter.Addresses))
+	for _, addr := range filter.Addresses {
+		watched[addr] = true
+	}
+	interactions := make(map[thor.Address][]thor.Bytes32)
+	touch := func(addr thor.Address, txID thor.Bytes32) {
+		if !watched[addr] {
+			return
+		}
+		ids := interactions[addr]
+		if len(ids) == 0 || ids[len(ids)-1] != txID {
+			interactions[addr] = append(ids, txID)
+		}
+	}
+	txs := block.Transactions()
+	for i, receipt := range receipts {
+		txID := txs[i].ID()
+		origin, err := txs[i].Signer()
+		if err != nil {
+			return nil, err
+		}
+		touch(origin, txID)
+		touch(receipt.GasPayer, txID)
+		for j, clause := range txs[i].Clauses() {
+			if to := clause.To(); to != nil {
+				touch(*to, txID)
+			} else {
+				touch(thor.CreateContractAddress(txID, uint32(j), 0), txID)
+			}
+		}
+		for _, output := range receipt.Outputs {
+			for _, event := range output.Events {
+				touch(event.Address, txID)
+			}
+			for _, transfer := range output.Transfers {
+				touch(transfer.Sender, txID)
+				touch(transfer.Recipient, txID)
+			}
+		}
+	}
+
+	var msgs []interface{}
+	for _, addr := range filter.Addresses {
+		if !watched[addr] {
+			// duplicated
+			continue
+		}
+		delete(watched, addr)
+		balance := st.GetBalance(addr)
+		// energy grows with VET held, so compare with the grown one of parent
+		energy := st.GetEnergy(addr, header.Timestamp())
+		balanceChanged := balance.Cmp(parentState.GetBalance(addr)) != 0
+		energyChanged := energy.Cmp(parentState.GetEnergy(addr, header.Timestamp())) != 0
+		if !balanceChanged && !energyChanged && len(interactions[addr]) == 0 {
+			continue
+		}
+		msgs = append(msgs, &WatchMessage{
+			Address:        addr,
+			Balance:        (*math.HexOrDecimal256)(balance),
+			Energy:         (*math.HexOrDecimal256)(energy),
+			BalanceChanged: balanceChanged,
+			EnergyChanged:  energyChanged,
+			TxIDs:          interactions[addr],
+			Meta: WatchMeta{
+				BlockID:        header.ID(),
+				BlockNumber:    header.Number(),
+				BlockTimestamp: header.Timestamp(),
+			},
+			Obsolete: block.Obsolete,
+		})
+	}
+	if err := parentState.Err(); err != nil {
+		return nil, err
+	}
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
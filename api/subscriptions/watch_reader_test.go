@@ -0,0 +1,104 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions_test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestWatch(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	sender := genesis.DevAccounts()[1]
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	trx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		Clause(tx.NewClause(&recipient).WithValue(big.NewInt(100))).
+		Expiration(10).
+		Gas(21000).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), sender.PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, flow.Adopt(trx))
+	b1, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b1, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+	subs := subscriptions.New(chain, stateC, nil, nil, nil, utils.NewAllowedOrigins("*"), 100)
+	subs.Mount(router, "/subscriptions")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	defer subs.Close()
+
+	res, err := http.Get(ts.URL + "/subscriptions/watch?pos=" + b0.Header().ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode, "addr required")
+
+	stranger := thor.BytesToAddress([]byte("stranger"))
+	conn, _, err := websocket.DefaultDialer.Dial(
+		strings.Replace(ts.URL, "http", "ws", 1)+"/subscriptions/watch?pos="+b0.Header().ID().String()+
+			"&addr="+recipient.String()+"&addr="+sender.Address.String()+"&addr="+stranger.String(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var msg subscriptions.WatchMessage
+	assert.Nil(t, conn.ReadJSON(&msg))
+	assert.Equal(t, recipient, msg.Address)
+	assert.True(t, msg.BalanceChanged)
+	assert.Equal(t, big.NewInt(100), (*big.Int)(msg.Balance))
+	assert.Equal(t, []thor.Bytes32{trx.ID()}, msg.TxIDs)
+	assert.Equal(t, b1.Header().ID(), msg.Meta.BlockID)
+
+	msg = subscriptions.WatchMessage{}
+	assert.Nil(t, conn.ReadJSON(&msg))
+	assert.Equal(t, sender.Address, msg.Address)
+	assert.True(t, msg.BalanceChanged)
+	assert.True(t, msg.EnergyChanged, "gas paid")
+	assert.Equal(t, []thor.Bytes32{trx.ID()}, msg.TxIDs)
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+const (
+	webhookTimeout         = 10 * time.Second
+	webhookMinRetryBackoff = time.Second
+	webhookMaxRetryBackoff = 5 * time.Minute
+
+	// WebhookIDHeader header carries the webhook id.
+	WebhookIDHeader = "X-Thor-Webhook"
+	// WebhookSignatureHeader header carries hex encoded HMAC-SHA256 of the body, keyed by the webhook secret.
+	WebhookSignatureHeader = "X-Thor-Signature"
+)
+
+var webhookKeyPrefix = []byte("webhook")
+
+// Webhook is a registered http callback, to which messages of the subject are POSTed.
+// Messages are the same as those piped by websocket, and delivered at least once, in order.
+type Webhook struct {
+	ID       string          `json:"id"`
+	URL      string          `json:"url"`
+	Subject  string          `json:"subject"`            // block, event, transfer or watch
+	Event    *EventFilter    `json:"event,omitempty"`    // for event subject
+	Transfer *TransferFilter `json:"transfer,omitempty"` // for transfer subject
+	Watch    *WatchFilter    `json:"watch,omitempty"`    // for watch subject
+	Secret   string          `json:"secret,omitempty"`   // to sign the body, write only
+	Position thor.Bytes32    `json:"position"`           // id of the last block whose messages delivered
+}
+
+type webhookEntry struct {
+	hook   Webhook
+	cancel func()
+}
+
+// Webhooks manages webhooks and delivers messages to them.
+// Webhooks and their delivery positions are persisted, so delivery resumes after restart.
+type Webhooks struct {
+	chain  *chain.Chain
+	stateC *state.Creator
+	abis   *abis.Registry
+	store  kv.GetPutter
+	client *http.Client
+	ctx    context.Context
+	cancel func()
+	lock   sync.Mutex
+	hooks  map[string]*webhookEntry
+	wg     sync.WaitGroup
+}
+
+// NewWebhooks creates webhooks manager, and starts delivering to persisted webhooks.
+func NewWebhooks(chain *chain.Chain, stateC *state.Creator, abis *abis.Registry, store kv.GetPutter) (*Webhooks, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Webhooks{
+		chain:  chain,
+		stateC: stateC,
+		abis:   abis,
+		store:  store,
+		client: &http.Client{Timeout: webhookTimeout},
+		ctx:    ctx,
+		cancel: cancel,
+		hooks:  make(map[string]*webhookEntry),
+	}
+
+	it := store.NewIterator(*kv.NewRangeWithBytesPrefix(webhookKeyPrefix))
+	defer it.Release()
+	for it.Next() {
+		var hook Webhook
+		if err := json.Unmarshal(it.Value(), &hook); err != nil {
+			return nil, errors.WithMessage(err, "decode webhook")
+		}
+		w.start(&hook)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Add validates and registers the webhook. The registered one with id assigned is returned.
+// Delivery starts after the block of position, or the best block if position is zero.
+func (w *Webhooks) Add(hook *Webhook) (*Webhook, error) {
+	u, err := url.Parse(hook.URL)
+	if err != nil {
+		return nil, utils.BadRequest(errors.WithMessage(err, "url"))
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, utils.BadRequest(errors.New("url: unsupported scheme"))
+	}
+	switch hook.Subject {
+	case "block":
+		if hook.Event != nil || hook.Transfer != nil || hook.Watch != nil {
+			return nil, utils.BadRequest(errors.New("filter: not allowed for block subject"))
+		}
+	case "event":
+		if hook.Transfer != nil || hook.Watch != nil {
+			return nil, utils.BadRequest(errors.New("filter: only event allowed for event subject"))
+		}
+	case "transfer":
+		if hook.Event != nil || hook.Watch != nil {
+			return nil, utils.BadRequest(errors.New("filter: only transfer allowed for transfer subject"))
+		}
+	case "watch":
+		if hook.Event != nil || hook.Transfer != nil {
+			return nil, utils.BadRequest(errors.New("filter: only watch allowed for watch subject"))
+		}
+		if hook.Watch == nil || len(hook.Watch.Addresses) == 0 {
+			return nil, utils.BadRequest(errors.New("watch: addresses required"))
+		}
+		if len(hook.Watch.Addresses) > maxWatchAddresses {
+			return nil, utils.BadRequest(errors.Errorf("watch: exceeds %v addresses", maxWatchAddresses))
+		}
+	default:
+		return nil, utils.BadRequest(errors.New("subject: unsupported"))
+	}
+
+	added := *hook
+	if added.Position.IsZero() {
+		added.Position = w.chain.BestBlock().Header().ID()
+	} else if _, err := w.chain.GetBlockHeader(added.Position); err != nil {
+		if w.chain.IsNotFound(err) {
+			return nil, utils.BadRequest(errors.WithMessage(err, "position"))
+		}
+		return nil, err
+	}
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	added.ID = hex.EncodeToString(id[:])
+
+	if err := w.save(&added); err != nil {
+		return nil, err
+	}
+	w.start(&added)
+
+	added.Secret = ""
+	return &added, nil
+}
+
+// Remove stops delivery and removes the webhook. False returned if not found.
+func (w *Webhooks) Remove(id string) (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	entry, ok := w.hooks[id]
+	if !ok {
+		return false, nil
+	}
+	entry.cancel()
+	delete(w.hooks, id)
+	return true, w.store.Delete(webhookKey(id))
+}
+
+// List returns all registered webhooks, with secrets hidden.
+func (w *Webhooks) List() []*Webhook {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	list := make([]*Webhook, 0, len(w.hooks))
+	for _, entry := range w.hooks {
+		hook := entry.hook
+		hook.Secret = ""
+		list = append(list, &hook)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Close stops all deliveries.
+func (w *Webhooks) Close() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *Webhooks) save(hook *Webhook) error {
+	data, err := json.Marshal(hook)
+	if err != nil {
+		return err
+	}
+	return w.store.Put(webhookKey(hook.ID), data)
+}
+
+func webhookKey(id string) []byte {
+	return append(append([]byte(nil), webhookKeyPrefix...), id...)
+}
+
+func (w *Webhooks) start(hook *Webhook) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	w.lock.Lock()
+	w.hooks[hook.ID] = &webhookEntry{*hook, cancel}
+	w.lock.Unlock()
+
+	w.wg.Add(1)
+	go func(hook Webhook) {
+		defer w.wg.Done()
+		w.run(ctx, hook)
+	}(*hook)
+}
+
+func (w *Webhooks) run(ctx context.Context, hook Webhook) {
+	reader := w.chain.NewBlockReader(hook.Position)
+	ticker := w.chain.NewTicker()
+	for {
+		msgs, lastBlockID, err := w.read(&hook, reader)
+		if err != nil {
+			log.Warn("webhook failed to read", "id", hook.ID, "err", err)
+			// restart from the last delivered position
+			reader = w.chain.NewBlockReader(hook.Position)
+		}
+		if err != nil || lastBlockID == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+				continue
+			}
+		}
+
+		for _, msg := range msgs {
+			if !w.deliver(ctx, &hook, msg) {
+				return
+			}
+		}
+
+		hook.Position = *lastBlockID
+		w.lock.Lock()
+		if entry, ok := w.hooks[hook.ID]; ok && ctx.Err() == nil {
+			entry.hook.Position = hook.Position
+			if err := w.save(&hook); err != nil {
+				log.Warn("webhook failed to save position", "id", hook.ID, "err", err)
+			}
+		}
+		w.lock.Unlock()
+	}
+}
+
+// read reads messages of next blocks. Nil block id returned if no more block.
+func (w *Webhooks) read(hook *Webhook, reader chain.BlockReader) ([]interface{}, *thor.Bytes32, error) {
+	blocks, err := reader.Read()
+	if err != nil || len(blocks) == 0 {
+		return nil, nil, err
+	}
+	var msgs []interface{}
+	for _, block := range blocks {
+		blockMsgs, err := w.messages(hook, block)
+		if err != nil {
+			return nil, nil, err
+		}
+		msgs = append(msgs, blockMsgs...)
+	}
+	// the last one is always a trunk block
+	lastBlockID := blocks[len(blocks)-1].Header().ID()
+	return msgs, &lastBlockID, nil
+}
+
+func (w *Webhooks) messages(hook *Webhook, block *chain.Block) ([]interface{}, error) {
+	switch hook.Subject {
+	case "block":
+		msg, err := convertBlock(block)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{msg}, nil
+	case "event":
+		filter := hook.Event
+		if filter == nil {
+			filter = &EventFilter{}
+		}
+		return eventMessages(w.chain, w.stateC, w.abis, block, filter)
+	case "transfer":
+		filter := hook.Transfer
+		if filter == nil {
+			filter = &TransferFilter{}
+		}
+		return transferMessages(w.chain, block, filter)
+	case "watch":
+		return watchMessages(w.chain, w.stateC, block, hook.Watch)
+	default:
+		return nil, fmt.Errorf("unsupported subject %v", hook.Subject)
+	}
+}
+
+// deliver posts the message until succeeded. False returned if ctx done.
+// The message is skipped if failed to be encoded, so that the hook keeps going.
+func (w *Webhooks) deliver(ctx context.Context, hook *Webhook, msg interface{}) bool {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		log.Warn("webhook failed to encode message, skipped", "id", hook.ID, "err", err)
+		return true
+	}
+	backoff := webhookMinRetryBackoff
+	for {
+		err := w.post(ctx, hook, body)
+		if err == nil {
+			return true
+		}
+		log.Debug("webhook failed to deliver", "id", hook.ID, "url", hook.URL, "err", err, "retry", backoff)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > webhookMaxRetryBackoff {
+			backoff = webhookMaxRetryBackoff
+		}
+	}
+}
+
+func (w *Webhooks) post(ctx context.Context, hook *Webhook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, hook.ID)
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package subscriptions_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+type webhookRequest struct {
+	id        string
+	signature string
+	body      []byte
+}
+
+func TestWebhooks(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	requests := make(chan *webhookRequest, 10)
+	failures := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		requests <- &webhookRequest{
+			req.Header.Get(subscriptions.WebhookIDHeader),
+			req.Header.Get(subscriptions.WebhookSignatureHeader),
+			body,
+		}
+	}))
+	defer srv.Close()
+
+	webhooks, err := subscriptions.NewWebhooks(chain, stateC, nil, db)
+	assert.Nil(t, err)
+
+	_, err = webhooks.Add(&subscriptions.Webhook{URL: "ftp://localhost", Subject: "block"})
+	assert.NotNil(t, err, "bad scheme")
+	_, err = webhooks.Add(&subscriptions.Webhook{URL: srv.URL, Subject: "beat"})
+	assert.NotNil(t, err, "bad subject")
+	_, err = webhooks.Add(&subscriptions.Webhook{URL: srv.URL, Subject: "block", Event: &subscriptions.EventFilter{}})
+	assert.NotNil(t, err, "filter not allowed")
+
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	hook, err := webhooks.Add(&subscriptions.Webhook{
+		URL:      srv.URL,
+		Subject:  "transfer",
+		Transfer: &subscriptions.TransferFilter{Recipient: &recipient},
+		Secret:   "secret",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "", hook.Secret)
+	assert.Equal(t, b0.Header().ID(), hook.Position)
+	assert.Equal(t, []*subscriptions.Webhook{hook}, webhooks.List())
+
+	// pack a block with the transfer
+	trx := new(tx.Builder).
+		ChainTag(chain.Tag()).
+		Clause(tx.NewClause(&recipient).WithValue(big.NewInt(100))).
+		Expiration(10).
+		Gas(21000).
+		Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	trx = trx.WithSignature(sig)
+
+	flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	assert.Nil(t, err)
+	assert.Nil(t, flow.Adopt(trx))
+	b1, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	assert.Nil(t, err)
+	_, err = stage.Commit()
+	assert.Nil(t, err)
+	_, err = chain.AddBlock(b1, receipts)
+	assert.Nil(t, err)
+
+	// delivered after retry
+	select {
+	case req := <-requests:
+		assert.Equal(t, hook.ID, req.id)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(req.body)
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.signature)
+
+		var msg subscriptions.TransferMessage
+		assert.Nil(t, json.Unmarshal(req.body, &msg))
+		assert.Equal(t, recipient, msg.Recipient)
+		assert.Equal(t, trx.ID(), msg.Meta.TxID)
+		assert.Equal(t, b1.Header().ID(), msg.Meta.BlockID)
+	case <-time.After(10 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	// position persisted
+	for i := 0; i < 100 && webhooks.List()[0].Position != b1.Header().ID(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	webhooks.Close()
+
+	webhooks, err = subscriptions.NewWebhooks(chain, stateC, nil, db)
+	assert.Nil(t, err)
+	list := webhooks.List()
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, b1.Header().ID(), list[0].Position)
+
+	removed, err := webhooks.Remove(hook.ID)
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	removed, _ = webhooks.Remove(hook.ID)
+	assert.False(t, removed)
+	webhooks.Close()
+
+	webhooks, _ = subscriptions.NewWebhooks(chain, stateC, nil, db)
+	assert.Equal(t, 0, len(webhooks.List()))
+	webhooks.Close()
+}
@@ -7,30 +7,83 @@ package transactions
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/api/txtracker"
 	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/cache"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/proof"
+	"github.com/vechain/thor/runtime"
+	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/txpool"
+	"github.com/vechain/thor/xenv"
+)
+
+const (
+	// IdempotencyKeyHeader header carries client-provided key to make tx submission retries safe.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// maxIdempotencyKeyLen limits length of idempotency key.
+	maxIdempotencyKeyLen = 256
+	// idempotencyKeyCacheSize limits count of remembered idempotency keys.
+	idempotencyKeyCacheSize = 10000
+
+	// maxBatchSize limits count of txs submitted in a batch.
+	maxBatchSize = 100
+
+	// maxOriginTxsLimit limits count of txs returned by a request listing txs of an origin, which is also the default.
+	maxOriginTxsLimit = 100
+
+	// maxOriginTxsOffset limits count of txs skipped by a request listing txs of an origin. Larger pages
+	// should be requested with the after cursor.
+	maxOriginTxsOffset = 1000
+
+	// maxReceiptWait limits duration to wait for receipt.
+	maxReceiptWait = time.Minute
+
+	// maxReceiptsRangeSize limits blocks covered by a bulk receipts request.
+	maxReceiptsRangeSize = 1000
+
+	// NextBlockHeader header carries number of the block to continue from, if the bulk receipts range is truncated.
+	NextBlockHeader = "X-Thor-Next-Block"
 )
 
 type Transactions struct {
-	chain *chain.Chain
-	pool  *txpool.TxPool
+	chain        *chain.Chain
+	stateCreator *state.Creator
+	pool         *txpool.TxPool
+	tracker      *txtracker.Tracker // nil if lifecycles not tracked
+	abis         *abis.Registry     // nil if clauses not decoded
+	// idempotency key => tx id
+	sentTxs     *cache.RandCache
+	sentTxsLock sync.Mutex
 }
 
-func New(chain *chain.Chain, pool *txpool.TxPool) *Transactions {
+func New(chain *chain.Chain, stateCreator *state.Creator, pool *txpool.TxPool, tracker *txtracker.Tracker, abis *abis.Registry) *Transactions {
 	return &Transactions{
-		chain,
-		pool,
+		chain:        chain,
+		stateCreator: stateCreator,
+		pool:         pool,
+		tracker:      tracker,
+		abis:         abis,
+		// entries live long enough to cover retries
+		sentTxs: cache.NewRandCache(idempotencyKeyCacheSize),
 	}
 }
 
@@ -56,7 +109,7 @@ func (t *Transactions) getRawTransaction(txID thor.Bytes32, blockID thor.Bytes32
 	}
 	return &rawTransaction{
 		RawTx: RawTx{hexutil.Encode(raw)},
-		Meta: TxMeta{
+		Meta: &TxMeta{
 			BlockID:        block.Header().ID(),
 			BlockNumber:    block.Header().Number(),
 			BlockTimestamp: block.Header().Timestamp(),
@@ -80,7 +133,50 @@ func (t *Transactions) getTransactionByID(txID thor.Bytes32, blockID thor.Bytes3
 	if err != nil {
 		return nil, err
 	}
-	return convertTransaction(tx, h, txMeta.Index)
+	converted, err := convertTransaction(tx, h, txMeta.Index)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.decodeClauses(converted, tx, h); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// decodeClauses decodes calls of the converted tx to contracts of known ABIs, with code hashes
+// of contracts read from the state of the block.
+func (t *Transactions) decodeClauses(converted *Transaction, tx *tx.Transaction, header *block.Header) error {
+	if t.abis == nil {
+		return nil
+	}
+	var st *state.State
+	codeHashFunc := func(address thor.Address) (thor.Bytes32, error) {
+		if st == nil {
+			var err error
+			if st, err = t.stateCreator.NewState(header.StateRoot()); err != nil {
+				return thor.Bytes32{}, err
+			}
+		}
+		return abis.StateCodeHash(st)(address)
+	}
+	for i, c := range tx.Clauses() {
+		if c.To() != nil && len(c.Data()) > 0 {
+			decoded, err := t.abis.DecodeCall(*c.To(), codeHashFunc, c.Data())
+			if err != nil {
+				return err
+			}
+			converted.Clauses[i].Decoded = decoded
+		}
+	}
+	return nil
+}
+
+// getPendingTransaction returns the tx in the pool, nil if not found.
+func (t *Transactions) getPendingTransaction(txID thor.Bytes32) *tx.Transaction {
+	if t.pool == nil {
+		return nil
+	}
+	return t.pool.Get(txID)
 }
 
 //GetTransactionReceiptByID get tx's receipt
@@ -107,6 +203,9 @@ func (t *Transactions) getTransactionReceiptByID(txID thor.Bytes32, blockID thor
 	return convertReceipt(receipt, h, tx)
 }
 func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Request) error {
+	if t.pool == nil {
+		return utils.Forbidden(errors.New("tx pool unavailable"))
+	}
 	data, err := ioutil.ReadAll(req.Body)
 	if err != nil {
 		return err
@@ -118,19 +217,42 @@ func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Re
 	if m == nil {
 		return utils.BadRequest(errors.New("body: empty body"))
 	}
+	idempotencyKey := req.Header.Get(IdempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLen {
+		return utils.BadRequest(errors.New("idempotency key: too long"))
+	}
 	var sendTx = func(tx *tx.Transaction) error {
-		if err := t.pool.Add(tx); err != nil {
-			if txpool.IsBadTx(err) {
-				return utils.BadRequest(err)
-			}
-			if txpool.IsTxRejected(err) {
-				return utils.Forbidden(err)
+		if idempotencyKey != "" {
+			// serialize submissions with keys, to make check-and-add atomic
+			t.sentTxsLock.Lock()
+			defer t.sentTxsLock.Unlock()
+
+			if sentID, ok := t.sentTxs.Get(idempotencyKey); ok {
+				if sentID.(thor.Bytes32) != tx.ID() {
+					return utils.HTTPError(errors.New("idempotency key: used by another tx"), http.StatusConflict)
+				}
+				// retried, respond as the original
+				return utils.WriteJSON(w, map[string]string{
+					"id": tx.ID().String(),
+				})
 			}
-			return err
 		}
-		return utils.WriteJSON(w, map[string]string{
+		if err := verifySignatures(tx); err != nil {
+			return utils.BadRequest(err)
+		}
+		result := map[string]string{
 			"id": tx.ID().String(),
-		})
+		}
+		// already in the pool, respond its state rather than adding again
+		if status := t.getPendingStatus(tx.ID()); status != "" {
+			result["status"] = status
+		} else if err := t.addTx(tx); err != nil {
+			return convertAddTxError(err)
+		}
+		if idempotencyKey != "" {
+			t.sentTxs.Set(idempotencyKey, tx.ID())
+		}
+		return utils.WriteJSON(w, result)
 	}
 	reader := bytes.NewReader(data)
 	if hasKey(m, "raw") {
@@ -154,7 +276,10 @@ func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Re
 		}
 		return sendTx(tx)
 	} else {
-		var ustx *UnSignedTx
+		var ustx *struct {
+			UnSignedTx
+			Origin *thor.Address `json:"origin"`
+		}
 		if err := utils.ParseJSON(reader, &ustx); err != nil {
 			return utils.BadRequest(errors.WithMessage(err, "body"))
 		}
@@ -162,10 +287,273 @@ func (t *Transactions) handleSendTransaction(w http.ResponseWriter, req *http.Re
 		if err != nil {
 			return utils.BadRequest(err)
 		}
-		return utils.WriteJSON(w, map[string]string{
+		hashes := map[string]string{
 			"signingHash": tx.SigningHash().String(),
+		}
+		// the delegator signs the hash bound to the origin
+		if ustx.Delegated && ustx.Origin != nil {
+			hashes["delegatorSigningHash"] = tx.DelegatorSigningHash(*ustx.Origin).String()
+		}
+		return utils.WriteJSON(w, hashes)
+	}
+}
+
+// verifySignatures checks signatures of the origin, and the delegator if the tx is delegated.
+func verifySignatures(tx *tx.Transaction) error {
+	if _, err := tx.Signer(); err != nil {
+		return errors.WithMessage(err, "signature")
+	}
+	if _, err := tx.Delegator(); err != nil {
+		return errors.WithMessage(err, "delegator signature")
+	}
+	return nil
+}
+
+// addTx adds the tx into the pool, and tracks it if lifecycles tracked.
+func (t *Transactions) addTx(tx *tx.Transaction) error {
+	if t.tracker != nil {
+		t.tracker.Received(tx)
+	}
+	if err := t.pool.Add(tx); err != nil {
+		if t.tracker != nil {
+			t.tracker.Rejected(tx.ID(), err.Error())
+		}
+		return err
+	}
+	return nil
+}
+
+// convertAddTxError converts the error of adding tx into pool to http error.
+func convertAddTxError(err error) error {
+	if txpool.IsBadTx(err) {
+		return utils.BadRequest(err)
+	}
+	if txpool.IsTxRejected(err) {
+		return utils.Forbidden(err)
+	}
+	return err
+}
+
+// getPendingStatus returns status of the tx in the pool, or empty if not in the pool.
+func (t *Transactions) getPendingStatus(txID thor.Bytes32) string {
+	if t.getPendingTransaction(txID) == nil {
+		return ""
+	}
+	for _, tx := range t.pool.Executables() {
+		if tx.ID() == txID {
+			return PendingStatusExecutable
+		}
+	}
+	return PendingStatusQueued
+}
+
+// handleReplaceTransaction replaces the pending tx of the same origin and block ref, with the raw tx
+// paying higher gas price coef. It's to speed up a tx stuck in the pool.
+// Replacing is best-effort and node-local. The replaced tx may have been relayed to peers, so both can
+// be packed, and it's reported superseded rather than dropped.
+func (t *Transactions) handleReplaceTransaction(w http.ResponseWriter, req *http.Request) error {
+	if t.pool == nil {
+		return utils.Forbidden(errors.New("tx pool unavailable"))
+	}
+	var rawTx *RawTx
+	if err := utils.ParseJSON(req.Body, &rawTx); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if rawTx == nil {
+		return utils.BadRequest(errors.New("body: empty body"))
+	}
+	newTx, err := rawTx.decode()
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "raw"))
+	}
+	if err := verifySignatures(newTx); err != nil {
+		return utils.BadRequest(err)
+	}
+	origin, _ := newTx.Signer()
+
+	var replaced []*tx.Transaction
+	for _, pending := range t.pool.Dump() {
+		if pending.ID() == newTx.ID() {
+			return utils.BadRequest(errors.New("tx already in the pool"))
+		}
+		if pending.BlockRef() != newTx.BlockRef() {
+			continue
+		}
+		if signer, err := pending.Signer(); err == nil && signer == origin {
+			replaced = append(replaced, pending)
+		}
+	}
+	switch {
+	case len(replaced) == 0:
+		return utils.HTTPError(errors.New("no pending tx of the same origin and block ref"), http.StatusNotFound)
+	case len(replaced) > 1:
+		return utils.HTTPError(fmt.Errorf("ambiguous: %v pending txs of the same origin and block ref", len(replaced)), http.StatusConflict)
+	}
+	oldTx := replaced[0]
+	if newTx.GasPriceCoef() <= oldTx.GasPriceCoef() {
+		return utils.BadRequest(errors.New("gasPriceCoef: should be higher than the replaced one"))
+	}
+	// add before removing, so that the replaced one kept if rejected
+	if err := t.addTx(newTx); err != nil {
+		return convertAddTxError(err)
+	}
+	t.pool.Remove(oldTx.Hash(), oldTx.ID())
+	if t.tracker != nil {
+		t.tracker.Superseded(oldTx.ID(), newTx.ID())
+	}
+	return utils.WriteJSON(w, map[string]string{
+		"id":       newTx.ID().String(),
+		"replaced": oldTx.ID().String(),
+	})
+}
+
+// handleSendTransactions adds a batch of raw txs into the pool, and responds results in the same order.
+// A tx failed to be decoded or added doesn't fail others.
+func (t *Transactions) handleSendTransactions(w http.ResponseWriter, req *http.Request) error {
+	if t.pool == nil {
+		return utils.Forbidden(errors.New("tx pool unavailable"))
+	}
+	var rawTxs []*RawTx
+	if err := utils.ParseJSON(req.Body, &rawTxs); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if len(rawTxs) == 0 {
+		return utils.BadRequest(errors.New("body: empty batch"))
+	}
+	if len(rawTxs) > maxBatchSize {
+		return utils.BadRequest(fmt.Errorf("body: batch size exceeds %v", maxBatchSize))
+	}
+	results := make([]*BatchResult, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		if rawTx == nil {
+			results[i] = &BatchResult{Error: "raw: empty"}
+			continue
+		}
+		tx, err := rawTx.decode()
+		if err != nil {
+			results[i] = &BatchResult{Error: errors.WithMessage(err, "raw").Error()}
+			continue
+		}
+		id := tx.ID()
+		results[i] = &BatchResult{ID: &id}
+		if err := verifySignatures(tx); err != nil {
+			results[i].Error = err.Error()
+		} else if err := t.addTx(tx); err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+	return utils.WriteJSON(w, results)
+}
+
+// simulate executes the resolved tx on top of the best block, as if it's packed into the next block.
+// The state is discarded after execution.
+func (t *Transactions) simulate(resolvedTx *runtime.ResolvedTransaction, tx *tx.Transaction) (*SimulateResult, error) {
+	best := t.chain.BestBlock().Header()
+	if tx.ChainTag() != t.chain.Tag() {
+		return nil, utils.BadRequest(errors.New("chainTag: mismatch"))
+	}
+	if tx.Gas() > best.GasLimit() {
+		return nil, utils.BadRequest(errors.New("gas: exceeds block gas limit"))
+	}
+	state, err := t.stateCreator.NewState(best.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	rt := runtime.New(t.chain.NewSeeker(best.ID()), state,
+		&xenv.BlockContext{
+			Number:     best.Number() + 1,
+			Time:       best.Timestamp() + thor.BlockInterval,
+			GasLimit:   best.GasLimit(),
+			TotalScore: best.TotalScore() + 1,
 		})
+	executor, err := rt.PrepareResolvedTransaction(resolvedTx)
+	if err != nil {
+		if err := state.Err(); err != nil {
+			return nil, err
+		}
+		return nil, utils.BadRequest(errors.WithMessage(err, "tx"))
 	}
+	outputs := []*SimulatedOutput{}
+	for executor.HasNextClause() {
+		gasUsed, output, err := executor.NextClause()
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, convertSimulatedOutput(output, gasUsed))
+	}
+	receipt, err := executor.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	if err := rt.Seeker().Err(); err != nil {
+		return nil, err
+	}
+	if err := state.Err(); err != nil {
+		return nil, err
+	}
+	return convertSimulateResult(receipt, outputs), nil
+}
+
+// handleSimulateTransaction simulates a raw, signed or unsigned tx, which is not sent.
+// The origin is required for an unsigned tx.
+func (t *Transactions) handleSimulateTransaction(w http.ResponseWriter, req *http.Request) error {
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if m == nil {
+		return utils.BadRequest(errors.New("body: empty body"))
+	}
+	var (
+		reader     = bytes.NewReader(data)
+		trx        *tx.Transaction
+		resolvedTx *runtime.ResolvedTransaction
+	)
+	if hasKey(m, "raw") || hasKey(m, "signature") {
+		if hasKey(m, "raw") {
+			var rawTx *RawTx
+			if err := utils.ParseJSON(reader, &rawTx); err != nil {
+				return utils.BadRequest(errors.WithMessage(err, "body"))
+			}
+			if trx, err = rawTx.decode(); err != nil {
+				return utils.BadRequest(errors.WithMessage(err, "raw"))
+			}
+		} else {
+			var stx *SignedTx
+			if err := utils.ParseJSON(reader, &stx); err != nil {
+				return utils.BadRequest(errors.WithMessage(err, "body"))
+			}
+			if trx, err = stx.decode(); err != nil {
+				return utils.BadRequest(err)
+			}
+		}
+		if resolvedTx, err = runtime.ResolveTransaction(trx); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "tx"))
+		}
+	} else {
+		var stx *SimulateTx
+		if err := utils.ParseJSON(reader, &stx); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "body"))
+		}
+		if stx.Origin == nil {
+			return utils.BadRequest(errors.New("origin: required for unsigned tx"))
+		}
+		if trx, err = stx.decode(); err != nil {
+			return utils.BadRequest(err)
+		}
+		if resolvedTx, err = runtime.ResolveUnsignedTransaction(trx, *stx.Origin, stx.Delegator); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "tx"))
+		}
+	}
+	result, err := t.simulate(resolvedTx, trx)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, result)
 }
 
 func (t *Transactions) handleGetTransactionByID(w http.ResponseWriter, req *http.Request) error {
@@ -174,6 +562,13 @@ func (t *Transactions) handleGetTransactionByID(w http.ResponseWriter, req *http
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "id"))
 	}
+	pending := req.URL.Query().Get("pending")
+	if pending != "" && pending != "false" && pending != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "pending"))
+	}
+	if pending == "true" && req.URL.Query().Get("head") != "" {
+		return utils.BadRequest(errors.New("pending: not allowed with head"))
+	}
 	head, err := t.parseHead(req.URL.Query().Get("head"))
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "head"))
@@ -185,17 +580,69 @@ func (t *Transactions) handleGetTransactionByID(w http.ResponseWriter, req *http
 		}
 		return err
 	}
+	// falls back to the pool if not found on the chain
+	var pendingTx *tx.Transaction
+	if pending == "true" {
+		if _, err := t.chain.GetTransactionMeta(txID, h.ID()); err != nil {
+			if !t.chain.IsNotFound(err) {
+				return err
+			}
+			pendingTx = t.getPendingTransaction(txID)
+		}
+	}
+	wantsRLP, err := utils.WantsRLP(req)
+	if err != nil {
+		return err
+	}
+	if wantsRLP {
+		tx := pendingTx
+		if tx == nil {
+			txMeta, err := t.chain.GetTransactionMeta(txID, h.ID())
+			if err != nil {
+				if t.chain.IsNotFound(err) {
+					return utils.HTTPError(errors.New("transaction not found"), http.StatusNotFound)
+				}
+				return err
+			}
+			if tx, err = t.chain.GetTransaction(txMeta.BlockID, txMeta.Index); err != nil {
+				return err
+			}
+		}
+		data, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return err
+		}
+		return utils.WriteRLP(w, data)
+	}
 	raw := req.URL.Query().Get("raw")
 	if raw != "" && raw != "false" && raw != "true" {
 		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "raw"))
 	}
 	if raw == "true" {
+		if pendingTx != nil {
+			data, err := rlp.EncodeToBytes(pendingTx)
+			if err != nil {
+				return err
+			}
+			return utils.WriteJSON(w, &rawTransaction{RawTx: RawTx{hexutil.Encode(data)}})
+		}
 		tx, err := t.getRawTransaction(txID, h.ID())
 		if err != nil {
 			return err
 		}
 		return utils.WriteJSON(w, tx)
 	}
+	if pendingTx != nil {
+		tx, err := convertTransaction(pendingTx, nil, 0)
+		if err != nil {
+			return err
+		}
+		// pending tx decoded against the best state
+		if err := t.decodeClauses(tx, pendingTx, t.chain.BestBlock().Header()); err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, tx)
+	}
 	tx, err := t.getTransactionByID(txID, h.ID())
 	if err != nil {
 		return err
@@ -210,6 +657,32 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "id"))
 	}
+	var wait time.Duration
+	if waitStr := req.URL.Query().Get("wait"); waitStr != "" {
+		if req.URL.Query().Get("head") != "" {
+			return utils.BadRequest(errors.New("wait: not allowed with head"))
+		}
+		if wait, err = time.ParseDuration(waitStr); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "wait"))
+		}
+		if wait < 0 || wait > maxReceiptWait {
+			return utils.BadRequest(fmt.Errorf("wait: out of range [0, %v]", maxReceiptWait))
+		}
+	}
+	wantsRLP, err := utils.WantsRLP(req)
+	if err != nil {
+		return err
+	}
+	if wantsRLP && wait > 0 {
+		return utils.BadRequest(errors.New("wait: not allowed with rlp format"))
+	}
+	raw := req.URL.Query().Get("raw")
+	if raw != "" && raw != "false" && raw != "true" {
+		return utils.BadRequest(errors.WithMessage(errors.New("should be boolean"), "raw"))
+	}
+	if raw == "true" && wait > 0 {
+		return utils.BadRequest(errors.New("wait: not allowed with raw"))
+	}
 	head, err := t.parseHead(req.URL.Query().Get("head"))
 	if err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "head"))
@@ -221,13 +694,384 @@ func (t *Transactions) handleGetTransactionReceiptByID(w http.ResponseWriter, re
 		}
 		return err
 	}
+	if wantsRLP {
+		data, err := t.getReceiptRLP(txID, h.ID())
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			return utils.HTTPError(errors.New("receipt not found"), http.StatusNotFound)
+		}
+		return utils.WriteRLP(w, data)
+	}
+	if raw == "true" {
+		receipt, err := t.getRawReceipt(txID, h.ID())
+		if err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, receipt)
+	}
 	receipt, err := t.getTransactionReceiptByID(txID, h.ID())
 	if err != nil {
 		return err
 	}
+	if receipt == nil && wait > 0 {
+		if receipt, err = t.waitTransactionReceipt(req.Context(), txID, wait); err != nil {
+			return err
+		}
+	}
 	return utils.WriteJSON(w, receipt)
 }
 
+// waitTransactionReceipt waits until the receipt appears on the best chain, or timeout.
+// Nil returned if timeout.
+func (t *Transactions) waitTransactionReceipt(ctx context.Context, txID thor.Bytes32, timeout time.Duration) (*Receipt, error) {
+	ticker := t.chain.NewTicker()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		// check after subscribed to avoid missing
+		receipt, err := t.getTransactionReceiptByID(txID, t.chain.BestBlock().Header().ID())
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-timer.C:
+			return nil, nil
+		case <-ticker.C():
+		}
+	}
+}
+
+// handleGetReceipts streams receipts of trunk blocks in range [from, to] as NDJSON, ordered by block and tx index.
+// The range is truncated to maxReceiptsRangeSize blocks, and the next block number is set in NextBlockHeader.
+func (t *Transactions) handleGetReceipts(w http.ResponseWriter, req *http.Request) error {
+	from, err := parseBlockNumber(req.URL.Query().Get("from"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "from"))
+	}
+	to := uint32(math.MaxUint32)
+	if toStr := req.URL.Query().Get("to"); toStr != "" {
+		if to, err = parseBlockNumber(toStr); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "to"))
+		}
+		if from > to {
+			return utils.BadRequest(errors.New("from: greater than to"))
+		}
+	}
+	if best := t.chain.BestBlock().Header().Number(); to > best {
+		to = best
+	}
+	if from <= to && to-from >= maxReceiptsRangeSize {
+		to = from + maxReceiptsRangeSize - 1
+		w.Header().Set(NextBlockHeader, strconv.FormatUint(uint64(to)+1, 10))
+	}
+
+	// empty if from is beyond the best block
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for num := uint64(from); num <= uint64(to); num++ {
+		select {
+		case <-req.Context().Done():
+			return nil
+		default:
+		}
+		block, err := t.chain.GetTrunkBlock(uint32(num))
+		if err != nil {
+			return err
+		}
+		txs := block.Transactions()
+		if len(txs) == 0 {
+			continue
+		}
+		receipts, err := t.chain.GetBlockReceipts(block.Header().ID())
+		if err != nil {
+			return err
+		}
+		for i, tx := range txs {
+			receipt, err := convertReceipt(receipts[i], block.Header(), tx)
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(receipt); err != nil {
+				return err
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return nil
+}
+
+// handleGetTransactionsByOrigin pages through trunk txs sent by the origin, ordered by block number.
+// Pages can be continued after the last returned tx, by passing its ID as the after cursor.
+func (t *Transactions) handleGetTransactionsByOrigin(w http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	origin, err := thor.ParseAddress(query.Get("origin"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "origin"))
+	}
+	var after *thor.Bytes32
+	if s := query.Get("after"); s != "" {
+		id, err := thor.ParseBytes32(s)
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "after"))
+		}
+		after = &id
+	}
+	offset, err := parseUint(query.Get("offset"), 0)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "offset"))
+	}
+	if offset > maxOriginTxsOffset {
+		return utils.BadRequest(fmt.Errorf("offset: exceeds %v", maxOriginTxsOffset))
+	}
+	limit, err := parseUint(query.Get("limit"), maxOriginTxsLimit)
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "limit"))
+	}
+	if limit > maxOriginTxsLimit {
+		return utils.BadRequest(fmt.Errorf("limit: exceeds %v", maxOriginTxsLimit))
+	}
+	best := t.chain.BestBlock().Header().ID()
+	ids, err := t.chain.GetTrunkTransactionsByOrigin(origin, after, offset, limit)
+	if err != nil {
+		if t.chain.IsNotFound(err) {
+			return utils.BadRequest(errors.New("after: tx not found on trunk"))
+		}
+		return err
+	}
+	txs := make([]*Transaction, 0, len(ids))
+	for _, id := range ids {
+		tx, err := t.getTransactionByID(id, best)
+		if err != nil {
+			return err
+		}
+		// the best block may have changed
+		if tx != nil {
+			txs = append(txs, tx)
+		}
+	}
+	return utils.WriteJSON(w, txs)
+}
+
+// parseUint parses the unsigned integer in decimal or hex, def returned if empty.
+func parseUint(s string, def uint64) (uint64, error) {
+	if s == "" {
+		return def, nil
+	}
+	return strconv.ParseUint(s, 0, 64)
+}
+
+func parseBlockNumber(s string) (uint32, error) {
+	if s == "" {
+		return 0, errors.New("required")
+	}
+	n, err := strconv.ParseUint(s, 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxUint32 {
+		return 0, errors.New("block number out of max uint32")
+	}
+	return uint32(n), nil
+}
+
+// getInclusionProof proves inclusion of the tx, or its receipt, in the block it was packed.
+func (t *Transactions) getInclusionProof(txID thor.Bytes32, blockID thor.Bytes32, ofReceipt bool) (*Proof, error) {
+	txMeta, err := t.chain.GetTransactionMeta(txID, blockID)
+	if err != nil {
+		if t.chain.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	block, err := t.chain.GetBlock(txMeta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	header := block.Header()
+
+	var (
+		p    *proof.Proof
+		root thor.Bytes32
+	)
+	if ofReceipt {
+		receipts, err := t.chain.GetBlockReceipts(txMeta.BlockID)
+		if err != nil {
+			return nil, err
+		}
+		if p, err = proof.ProveReceipt(receipts, int(txMeta.Index)); err != nil {
+			return nil, err
+		}
+		root = header.ReceiptsRoot()
+	} else {
+		if p, err = proof.ProveTransaction(block.Transactions(), int(txMeta.Index)); err != nil {
+			return nil, err
+		}
+		root = header.TxsRoot()
+	}
+	return convertProof(p, root, header), nil
+}
+
+// getReceiptRLP returns the rlp encoded receipt of the tx on the chain of head, nil if the tx not found.
+func (t *Transactions) getReceiptRLP(txID thor.Bytes32, headID thor.Bytes32) ([]byte, error) {
+	txMeta, err := t.chain.GetTransactionMeta(txID, headID)
+	if err != nil {
+		if t.chain.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	receipt, err := t.chain.GetTransactionReceipt(txMeta.BlockID, txMeta.Index)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(receipt)
+}
+
+// getRawReceipt returns the rlp encoded receipt with its inclusion proof, nil if the tx not found.
+func (t *Transactions) getRawReceipt(txID thor.Bytes32, headID thor.Bytes32) (*rawReceipt, error) {
+	p, err := t.getInclusionProof(txID, headID, true)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	data, err := t.getReceiptRLP(txID, headID)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return &rawReceipt{Raw: hexutil.Encode(data), Proof: p}, nil
+}
+
+func (t *Transactions) handleGetInclusionProof(ofReceipt bool) utils.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) error {
+		txID, err := thor.ParseBytes32(mux.Vars(req)["id"])
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "id"))
+		}
+		head, err := t.parseHead(req.URL.Query().Get("head"))
+		if err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "head"))
+		}
+		if _, err := t.chain.GetBlockHeader(head); err != nil {
+			if t.chain.IsNotFound(err) {
+				return utils.BadRequest(errors.WithMessage(err, "head"))
+			}
+			return err
+		}
+		p, err := t.getInclusionProof(txID, head, ofReceipt)
+		if err != nil {
+			return err
+		}
+		return utils.WriteJSON(w, p)
+	}
+}
+
+// getTransactionStatus returns the status of the tx on the chain of head.
+// It falls back to the pool only if head is the best block.
+func (t *Transactions) getTransactionStatus(txID thor.Bytes32, head *block.Header) (*TxStatus, error) {
+	txMeta, err := t.chain.GetTransactionMeta(txID, head.ID())
+	if err != nil {
+		if !t.chain.IsNotFound(err) {
+			return nil, err
+		}
+		if head.ID() == t.chain.BestBlock().Header().ID() && t.getPendingTransaction(txID) != nil {
+			return &TxStatus{Status: TxStatusPending}, nil
+		}
+		return &TxStatus{Status: TxStatusUnknown}, nil
+	}
+	h, err := t.chain.GetBlockHeader(txMeta.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	status := &TxStatus{
+		Status:        TxStatusIncluded,
+		Confirmations: head.Number() - h.Number(),
+		Meta: &TxMeta{
+			BlockID:        h.ID(),
+			BlockNumber:    h.Number(),
+			BlockTimestamp: h.Timestamp(),
+		},
+	}
+	if status.Confirmations >= utils.FinalizedDepth {
+		status.Status = TxStatusFinalized
+	}
+	return status, nil
+}
+
+func (t *Transactions) handleGetTransactionStatus(w http.ResponseWriter, req *http.Request) error {
+	txID, err := thor.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	head, err := t.parseHead(req.URL.Query().Get("head"))
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "head"))
+	}
+	h, err := t.chain.GetBlockHeader(head)
+	if err != nil {
+		if t.chain.IsNotFound(err) {
+			return utils.BadRequest(errors.WithMessage(err, "head"))
+		}
+		return err
+	}
+	status, err := t.getTransactionStatus(txID, h)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, status)
+}
+
+func (t *Transactions) handleGetLifecycle(w http.ResponseWriter, req *http.Request) error {
+	txID, err := thor.ParseBytes32(mux.Vars(req)["id"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "id"))
+	}
+	record, err := t.tracker.Get(txID)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, record)
+}
+
+func (t *Transactions) handleGetPendingByAccount(w http.ResponseWriter, req *http.Request) error {
+	addr, err := thor.ParseAddress(mux.Vars(req)["address"])
+	if err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "address"))
+	}
+	if t.pool == nil {
+		return utils.Forbidden(errors.New("tx pool unavailable"))
+	}
+	executables := make(map[thor.Bytes32]bool)
+	for _, tx := range t.pool.Executables() {
+		executables[tx.ID()] = true
+	}
+	pendings := []*PendingTx{}
+	for _, tx := range t.pool.Dump() {
+		origin, err := tx.Signer()
+		if err != nil {
+			continue
+		}
+		delegator, err := tx.Delegator()
+		if err != nil {
+			continue
+		}
+		if origin != addr && (delegator == nil || *delegator != addr) {
+			continue
+		}
+		status := PendingStatusQueued
+		if executables[tx.ID()] {
+			status = PendingStatusExecutable
+		}
+		pendings = append(pendings, convertPendingTx(tx, origin, delegator, status))
+	}
+	return utils.WriteJSON(w, pendings)
+}
+
 func (t *Transactions) parseHead(head string) (thor.Bytes32, error) {
 	if head == "" {
 		return t.chain.BestBlock().Header().ID(), nil
@@ -243,6 +1087,22 @@ func (t *Transactions) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransaction))
+	sub.Path("").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionsByOrigin))
+	sub.Path("/batch").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleSendTransactions))
+	sub.Path("/replace").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleReplaceTransaction))
+	sub.Path("/simulate").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleSimulateTransaction))
+	sub.Path("/receipts").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetReceipts))
 	sub.Path("/{id}").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionByID))
 	sub.Path("/{id}/receipt").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionReceiptByID))
+	sub.Path("/{id}/status").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetTransactionStatus))
+	sub.Path("/{id}/proof").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetInclusionProof(false)))
+	if t.tracker != nil {
+		sub.Path("/{id}/lifecycle").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetLifecycle))
+	}
+	sub.Path("/{id}/receipt/proof").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetInclusionProof(true)))
+}
+
+// MountPending mounts the endpoint listing pending txs of an account at path, which contains the {address} variable.
+func (t *Transactions) MountPending(root *mux.Router, path string) {
+	root.Path(path).Methods("GET").HandlerFunc(utils.WrapHandlerFunc(t.handleGetPendingByAccount))
 }
@@ -12,6 +12,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,13 +21,17 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/api/transactions"
+	"github.com/vechain/thor/api/txtracker"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/proof"
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
@@ -34,15 +39,56 @@ import (
 )
 
 var c *chain.Chain
+var stateC *state.Creator
 var ts *httptest.Server
 var transaction *tx.Transaction
+var registry *abis.Registry
 
 func TestTransaction(t *testing.T) {
 	initTransactionServer(t)
 	defer ts.Close()
 	getTx(t)
 	getTxReceipt(t)
+	getRLP(t)
+	getInclusionProofs(t)
+	getReceipts(t)
+	waitTxReceipt(t)
 	senTx(t)
+	sendTxIdempotently(t)
+	sendTxBatch(t)
+	getPendingTxs(t)
+	getPendingTx(t)
+	getTxStatus(t)
+	getTxsByOrigin(t)
+	getDecodedClauses(t)
+	simulateTx(t)
+	sendDelegatedTx(t)
+	getByHead(t)
+	replaceTx(t)
+}
+
+func TestWithoutPool(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	router := mux.NewRouter()
+	txs := transactions.New(chain, stateC, nil, nil, nil)
+	txs.Mount(router, "/transactions")
+	txs.MountPending(router, "/accounts/{address}/transactions/pending")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/accounts/" + genesis.DevAccounts()[0].Address.String() + "/transactions/pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
 }
 
 func getTx(t *testing.T) {
@@ -72,6 +118,671 @@ func getTxReceipt(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, uint64(receipt.GasUsed), transaction.Gas(), "gas should be equal")
+	if assert.Len(t, receipt.Outputs, 1) && assert.Len(t, receipt.Outputs[0].Transfers, 1) {
+		transfer := receipt.Outputs[0].Transfers[0]
+		assert.Equal(t, genesis.DevAccounts()[0].Address, transfer.Sender)
+		assert.Equal(t, *transaction.Clauses()[0].To(), transfer.Recipient)
+		assert.Equal(t, *transaction.Clauses()[0].Value(), big.Int(*transfer.Amount))
+	}
+}
+
+func getRLP(t *testing.T) {
+	get := func(url string, accept string) *http.Response {
+		req, _ := http.NewRequest("GET", url, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := get(ts.URL+"/transactions/"+transaction.ID().String()+"?format=rlp", "")
+	assert.Equal(t, "application/rlp", res.Header.Get("Content-Type"))
+	var trx tx.Transaction
+	if err := rlp.Decode(res.Body, &trx); err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, transaction.ID(), trx.ID())
+
+	res = get(ts.URL+"/transactions/"+transaction.ID().String()+"/receipt", "application/rlp")
+	assert.Equal(t, "application/rlp", res.Header.Get("Content-Type"))
+	var receipt tx.Receipt
+	if err := rlp.Decode(res.Body, &receipt); err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, transaction.Gas(), receipt.GasUsed)
+
+	res = get(ts.URL+"/transactions/"+thor.Bytes32{}.String(), "application/rlp")
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	res = get(ts.URL+"/transactions/"+transaction.ID().String()+"/receipt?format=rlp&wait=1s", "")
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func getPendingTxs(t *testing.T) {
+	origin := genesis.DevAccounts()[0].Address
+	var pendings []*transactions.PendingTx
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/accounts/"+origin.String()+"/transactions/pending"), &pendings); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, pendings)
+	for _, p := range pendings {
+		assert.Equal(t, origin, p.Origin)
+		assert.Nil(t, p.Delegator)
+		assert.Contains(t, []string{transactions.PendingStatusExecutable, transactions.PendingStatusQueued}, p.Status)
+	}
+
+	assert.Equal(t, "[]", string(httpGet(t, ts.URL+"/accounts/"+thor.Address{}.String()+"/transactions/pending")))
+
+	res, err := http.Get(ts.URL + "/accounts/0x01/transactions/pending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func getPendingTx(t *testing.T) {
+	var pendings []*transactions.PendingTx
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/accounts/"+genesis.DevAccounts()[0].Address.String()+"/transactions/pending"), &pendings); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.NotEmpty(t, pendings) {
+		return
+	}
+	id := pendings[0].ID.String()
+	assert.Equal(t, "null", strings.TrimSpace(string(httpGet(t, ts.URL+"/transactions/"+id))), "not packed")
+
+	var rtx *transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+id+"?pending=true"), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, rtx) {
+		assert.Equal(t, pendings[0].ID, rtx.ID)
+		assert.Nil(t, rtx.Meta)
+	}
+
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"?pending=true"), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotNil(t, rtx.Meta, "packed tx preferred")
+
+	res, err := http.Get(ts.URL + "/transactions/" + id + "?pending=true&head=" + c.BestBlock().Header().ID().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func getTxStatus(t *testing.T) {
+	getStatus := func(id thor.Bytes32) *transactions.TxStatus {
+		var status *transactions.TxStatus
+		if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+id.String()+"/status"), &status); err != nil {
+			t.Fatal(err)
+		}
+		return status
+	}
+
+	status := getStatus(transaction.ID())
+	assert.Equal(t, transactions.TxStatusIncluded, status.Status)
+	assert.Equal(t, c.BestBlock().Header().Number()-1, status.Confirmations)
+	if assert.NotNil(t, status.Meta) {
+		assert.Equal(t, uint32(1), status.Meta.BlockNumber)
+	}
+
+	var pendings []*transactions.PendingTx
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/accounts/"+genesis.DevAccounts()[0].Address.String()+"/transactions/pending"), &pendings); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotEmpty(t, pendings) {
+		status = getStatus(pendings[0].ID)
+		assert.Equal(t, transactions.TxStatusPending, status.Status)
+		assert.Nil(t, status.Meta)
+	}
+
+	status = getStatus(thor.Bytes32{})
+	assert.Equal(t, transactions.TxStatusUnknown, status.Status)
+	assert.Nil(t, status.Meta)
+}
+
+func getTxsByOrigin(t *testing.T) {
+	origin := genesis.DevAccounts()[0].Address
+	var txs []*transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions?origin="+origin.String()), &txs); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, txs, 2, "one packed on init, another on waiting receipt") {
+		return
+	}
+	checkTx(t, transaction, txs[0])
+	assert.Equal(t, uint32(1), txs[0].Meta.BlockNumber)
+
+	var paged []*transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions?origin="+origin.String()+"&offset=1&limit=1"), &paged); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, paged, 1) {
+		assert.Equal(t, txs[1].ID, paged[0].ID)
+	}
+
+	paged = nil
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions?origin="+origin.String()+"&after="+txs[0].ID.String()), &paged); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, paged, 1) {
+		assert.Equal(t, txs[1].ID, paged[0].ID)
+	}
+
+	for _, query := range []string{
+		"origin=0x01",
+		"origin=" + origin.String() + "&limit=101",
+		"origin=" + origin.String() + "&offset=x",
+		"origin=" + origin.String() + "&offset=1001",
+		"origin=" + origin.String() + "&after=" + thor.Bytes32{}.String(),
+	} {
+		res, err := http.Get(ts.URL + "/transactions?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
+func getDecodedClauses(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	transfer, _ := builtin.Energy.ABI.MethodByName("transfer")
+	data, err := transfer.EncodeInput(to, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// bound to code hash of the energy contract
+	codeHash := thor.Bytes32(crypto.Keccak256Hash(builtin.Energy.RuntimeBytecodes()))
+	assert.Nil(t, registry.Add(&abis.Entry{
+		CodeHash: &codeHash,
+		Name:     "probe",
+		ABI:      json.RawMessage(`[{"type":"function","name":"probe","inputs":[{"name":"x","type":"uint256"}],"outputs":[]}]`),
+	}))
+	probe := append(crypto.Keccak256([]byte("probe(uint256)"))[:4], make([]byte, 32)...)
+
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(100).
+		Gas(100000).
+		Nonce(300).
+		Clause(tx.NewClause(&builtin.Energy.Address).WithData(data)).
+		Clause(tx.NewClause(&to)).
+		Clause(tx.NewClause(&builtin.Energy.Address).WithData(probe)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+	raw, err := rlp.EncodeToBytes(trx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpPost(t, ts.URL+"/transactions", transactions.RawTx{Raw: hexutil.Encode(raw)})
+
+	var rtx *transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+trx.ID().String()+"?pending=true"), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, rtx) && assert.Len(t, rtx.Clauses, 3) {
+		if assert.NotNil(t, rtx.Clauses[0].Decoded) {
+			assert.Equal(t, "transfer", rtx.Clauses[0].Decoded.Name)
+			assert.Len(t, rtx.Clauses[0].Decoded.Args, 2)
+		}
+		assert.Nil(t, rtx.Clauses[1].Decoded, "no data")
+		if assert.NotNil(t, rtx.Clauses[2].Decoded, "by code hash") {
+			assert.Equal(t, "probe", rtx.Clauses[2].Decoded.Name)
+		}
+	}
+}
+
+func simulateTx(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	origin := genesis.DevAccounts()[0].Address
+	simulate := func(body interface{}) (*transactions.SimulateResult, int) {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.Post(ts.URL+"/transactions/simulate", "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var result *transactions.SimulateResult
+		if res.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return result, res.StatusCode
+	}
+
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(100).
+		Gas(21000).
+		Nonce(400).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10))).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := rlp.EncodeToBytes(trx.WithSignature(sig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, status := simulate(transactions.RawTx{Raw: hexutil.Encode(raw)})
+	assert.Equal(t, http.StatusOK, status)
+	if assert.NotNil(t, result) {
+		assert.False(t, result.Reverted)
+		assert.Equal(t, uint64(21000), result.GasUsed)
+		assert.Equal(t, origin, result.GasPayer)
+		if assert.Len(t, result.Outputs, 1) && assert.Len(t, result.Outputs[0].Transfers, 1) {
+			assert.Equal(t, to, result.Outputs[0].Transfers[0].Recipient)
+		}
+	}
+	assert.Equal(t, "null", strings.TrimSpace(string(httpGet(t, ts.URL+"/transactions/"+trx.WithSignature(sig).ID().String()+"?pending=true"))), "not sent")
+
+	setParam, _ := builtin.Params.ABI.MethodByName("set")
+	data, err := setParam.EncodeInput(thor.BytesToBytes32([]byte("key")), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := transactions.UnSignedTx{
+		ChainTag: c.Tag(),
+		BlockRef: "0x0000000000000000",
+		Gas:      100000,
+		Clauses: transactions.Clauses{
+			{To: &to, Data: "0x"},
+			{To: &builtin.Params.Address, Data: hexutil.Encode(data)},
+			{To: &to, Data: "0x"},
+		},
+	}
+	// not the executor
+	other := genesis.DevAccounts()[1].Address
+	result, status = simulate(transactions.SimulateTx{UnSignedTx: unsigned, Origin: &other})
+	assert.Equal(t, http.StatusOK, status)
+	if assert.NotNil(t, result) {
+		assert.True(t, result.Reverted)
+		if assert.Len(t, result.Outputs, 2, "clauses after reverted one not executed") {
+			assert.False(t, result.Outputs[0].Reverted)
+			assert.True(t, result.Outputs[1].Reverted)
+			assert.Equal(t, "builtin: executor required", result.Outputs[1].RevertReason)
+		}
+	}
+
+	_, status = simulate(unsigned)
+	assert.Equal(t, http.StatusBadRequest, status, "origin required")
+
+	poor := thor.BytesToAddress([]byte("poor"))
+	_, status = simulate(transactions.SimulateTx{UnSignedTx: unsigned, Origin: &poor})
+	assert.Equal(t, http.StatusBadRequest, status, "insufficient energy")
+}
+
+func sendDelegatedTx(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	origin, delegator := genesis.DevAccounts()[0], genesis.DevAccounts()[1]
+	var features tx.Features
+	features.SetDelegated(true)
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(100).
+		Gas(21000).
+		Nonce(500).
+		Features(features).
+		Clause(tx.NewClause(&to)).
+		Build()
+
+	res := httpPost(t, ts.URL+"/transactions", struct {
+		transactions.UnSignedTx
+		Origin *thor.Address `json:"origin"`
+	}{
+		transactions.UnSignedTx{
+			ChainTag:   c.Tag(),
+			BlockRef:   "0x0000000000000000",
+			Expiration: 100,
+			Gas:        21000,
+			Nonce:      500,
+			Clauses:    transactions.Clauses{{To: &to, Data: "0x"}},
+			Delegated:  true,
+		},
+		&origin.Address,
+	})
+	var hashes map[string]string
+	if err := json.Unmarshal(res, &hashes); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, trx.SigningHash().String(), hashes["signingHash"])
+	assert.Equal(t, trx.DelegatorSigningHash(origin.Address).String(), hashes["delegatorSigningHash"])
+
+	originSig, err := crypto.Sign(trx.SigningHash().Bytes(), origin.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegatorSig, err := crypto.Sign(trx.DelegatorSigningHash(origin.Address).Bytes(), delegator.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only signed by the origin
+	data, err := rlp.EncodeToBytes(trx.WithSignature(originSig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(transactions.RawTx{Raw: hexutil.Encode(data)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := http.Post(ts.URL+"/transactions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, r.StatusCode)
+
+	trx = trx.WithSignature(append(originSig, delegatorSig...))
+	if data, err = rlp.EncodeToBytes(trx); err != nil {
+		t.Fatal(err)
+	}
+	var sent map[string]string
+	if err := json.Unmarshal(httpPost(t, ts.URL+"/transactions", transactions.RawTx{Raw: hexutil.Encode(data)}), &sent); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, trx.ID().String(), sent["id"])
+
+	var rtx *transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+trx.ID().String()+"?pending=true"), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, rtx) {
+		assert.Equal(t, origin.Address, rtx.Origin)
+		assert.True(t, rtx.Delegated)
+		assert.Equal(t, &delegator.Address, rtx.Delegator)
+	}
+}
+
+func getByHead(t *testing.T) {
+	// a side branch forked from genesis, which excludes the packed tx
+	branch := new(block.Builder).ParentID(c.GenesisBlock().Header().ID()).Build()
+	sig, err := crypto.Sign(branch.Header().SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch = branch.WithSignature(sig)
+	if _, err := c.AddBlock(branch, nil); err != nil {
+		t.Fatal(err)
+	}
+	head := "?head=" + branch.Header().ID().String()
+
+	var rtx *transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+head), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, rtx)
+	var receipt *transactions.Receipt
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/receipt"+head), &receipt); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, receipt)
+
+	var status *transactions.TxStatus
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/status"+head), &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, transactions.TxStatusUnknown, status.Status)
+
+	status = nil
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/status?head="+c.GenesisBlock().Header().ID().String()), &status); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, transactions.TxStatusUnknown, status.Status, "not included on the chain of genesis")
+
+	res, err := http.Get(ts.URL + "/transactions/" + transaction.ID().String() + "/status?head=" + thor.Bytes32{}.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode, "unknown head")
+}
+
+func replaceTx(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	newRawTx := func(origin genesis.DevAccount, nonce uint64, gasPriceCoef uint8) (*tx.Transaction, *transactions.RawTx) {
+		trx := new(tx.Builder).
+			ChainTag(c.Tag()).
+			Expiration(100).
+			Gas(21000).
+			GasPriceCoef(gasPriceCoef).
+			Nonce(nonce).
+			Clause(tx.NewClause(&to)).
+			Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), origin.PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trx = trx.WithSignature(sig)
+		data, err := rlp.EncodeToBytes(trx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return trx, &transactions.RawTx{Raw: hexutil.Encode(data)}
+	}
+	replace := func(rawTx *transactions.RawTx) (map[string]string, int) {
+		data, err := json.Marshal(rawTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.Post(ts.URL+"/transactions/replace", "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var body map[string]string
+		json.NewDecoder(res.Body).Decode(&body)
+		return body, res.StatusCode
+	}
+
+	origin := genesis.DevAccounts()[3]
+	stuck, rawTx := newRawTx(origin, 1, 0)
+	httpPost(t, ts.URL+"/transactions", rawTx)
+
+	_, status := replace(rawTx)
+	assert.Equal(t, http.StatusBadRequest, status, "already in the pool")
+	_, rawTx = newRawTx(origin, 2, 0)
+	_, status = replace(rawTx)
+	assert.Equal(t, http.StatusBadRequest, status, "gas price coef not bumped")
+	_, rawTx = newRawTx(genesis.DevAccounts()[4], 2, 10)
+	_, status = replace(rawTx)
+	assert.Equal(t, http.StatusNotFound, status, "nothing to replace")
+
+	bumped, rawTx := newRawTx(origin, 2, 10)
+	body, status := replace(rawTx)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, bumped.ID().String(), body["id"])
+	assert.Equal(t, stuck.ID().String(), body["replaced"])
+
+	var rtx *transactions.Transaction
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+stuck.ID().String()+"?pending=true"), &rtx); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, rtx, "removed from the pool")
+	var record *txtracker.Record
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+stuck.ID().String()+"/lifecycle"), &record); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, txtracker.StatusSuperseded, record.Status, "may still be packed")
+
+	_, rawTx = newRawTx(origin, 3, 0)
+	httpPost(t, ts.URL+"/transactions", rawTx)
+	_, rawTx = newRawTx(origin, 4, 20)
+	_, status = replace(rawTx)
+	assert.Equal(t, http.StatusConflict, status, "ambiguous")
+}
+
+func getInclusionProofs(t *testing.T) {
+	header := c.BestBlock().Header()
+	toProof := func(p *transactions.Proof) *proof.Proof {
+		nodes := make([][]byte, len(p.Nodes))
+		for i, node := range p.Nodes {
+			nodes[i] = node
+		}
+		return &proof.Proof{Index: p.Index, Nodes: nodes}
+	}
+
+	var p *transactions.Proof
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/proof"), &p); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, header.ID(), p.Meta.BlockID)
+	assert.Equal(t, header.TxsRoot(), p.Root)
+	trx, err := proof.VerifyTransaction(header.TxsRoot(), toProof(p))
+	assert.Nil(t, err)
+	assert.Equal(t, transaction.ID(), trx.ID())
+
+	p = nil
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/receipt/proof"), &p); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, header.ReceiptsRoot(), p.Root)
+	receipt, err := proof.VerifyReceipt(header.ReceiptsRoot(), toProof(p))
+	assert.Nil(t, err)
+	assert.Equal(t, transaction.Gas(), receipt.GasUsed)
+
+	var raw struct {
+		Raw   string              `json:"raw"`
+		Proof *transactions.Proof `json:"proof"`
+	}
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+transaction.ID().String()+"/receipt?raw=true"), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, raw.Proof) {
+		proved, err := proof.VerifyReceipt(header.ReceiptsRoot(), toProof(raw.Proof))
+		assert.Nil(t, err)
+		data, err := rlp.EncodeToBytes(proved)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, hexutil.Encode(data), raw.Raw, "raw receipt proved")
+	}
+
+	assert.Equal(t, "null", strings.TrimSpace(string(httpGet(t, ts.URL+"/transactions/"+thor.Bytes32{}.String()+"/proof"))))
+	assert.Equal(t, "null", strings.TrimSpace(string(httpGet(t, ts.URL+"/transactions/"+thor.Bytes32{}.String()+"/receipt?raw=true"))))
+}
+
+func getReceipts(t *testing.T) {
+	res, err := http.Get(ts.URL + "/transactions/receipts?from=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "", res.Header.Get(transactions.NextBlockHeader))
+
+	var receipts []*transactions.Receipt
+	dec := json.NewDecoder(res.Body)
+	for dec.More() {
+		var receipt *transactions.Receipt
+		if err := dec.Decode(&receipt); err != nil {
+			t.Fatal(err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	assert.Equal(t, 1, len(receipts))
+	assert.Equal(t, transaction.ID(), receipts[0].Meta.TxID)
+	assert.Equal(t, uint32(1), receipts[0].Meta.BlockNumber)
+
+	r := httpGet(t, ts.URL+"/transactions/receipts?from=2")
+	assert.Equal(t, "", string(r))
+
+	for _, query := range []string{"", "from=x", "from=1&to=0", "from=4294967296"} {
+		res, err := http.Get(ts.URL + "/transactions/receipts?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+}
+
+func waitTxReceipt(t *testing.T) {
+	r := httpGet(t, ts.URL+"/transactions/"+thor.Bytes32{}.String()+"/receipt?wait=10ms")
+	assert.Equal(t, "null", string(r))
+
+	for _, query := range []string{"wait=x", "wait=-1s", "wait=1h", "wait=1s&head=" + c.BestBlock().Header().ID().String()} {
+		res, err := http.Get(ts.URL + "/transactions/" + thor.Bytes32{}.String() + "/receipt?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode, query)
+	}
+
+	to := thor.BytesToAddress([]byte("to"))
+	trx := new(tx.Builder).
+		ChainTag(c.Tag()).
+		Expiration(10).
+		Gas(21000).
+		Nonce(2).
+		Clause(tx.NewClause(&to)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trx = trx.WithSignature(sig)
+
+	done := make(chan []byte)
+	go func() {
+		done <- httpGet(t, ts.URL+"/transactions/"+trx.ID().String()+"/receipt?wait=10s")
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	best := c.BestBlock()
+	flow, err := packer.New(c, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(best.Header(), best.Header().Timestamp()+thor.BlockInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := flow.Adopt(trx); err != nil {
+		t.Fatal(err)
+	}
+	b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.AddBlock(b, receipts); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case r := <-done:
+		var receipt *transactions.Receipt
+		if err := json.Unmarshal(r, &receipt); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, trx.ID(), receipt.Meta.TxID)
+		assert.Equal(t, b.Header().ID(), receipt.Meta.BlockID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("receipt not returned")
+	}
 }
 
 func senTx(t *testing.T) {
@@ -124,6 +835,130 @@ func senTx(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, tx.ID().String(), txObj["id"], "should be the same transaction id")
+	assert.NotEmpty(t, txObj["status"], "already in the pool")
+
+	var record *txtracker.Record
+	if err := json.Unmarshal(httpGet(t, ts.URL+"/transactions/"+tx.ID().String()+"/lifecycle"), &record); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tx.ID(), record.TxID)
+	assert.Equal(t, txtracker.StatusReceived, record.History[0].Status)
+}
+
+func sendTxIdempotently(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	newRawTx := func(nonce uint64) *transactions.RawTx {
+		trx := new(tx.Builder).
+			ChainTag(c.Tag()).
+			Expiration(100).
+			Gas(21000).
+			Nonce(nonce).
+			Clause(tx.NewClause(&to)).
+			Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := rlp.EncodeToBytes(trx.WithSignature(sig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &transactions.RawTx{Raw: hexutil.Encode(data)}
+	}
+	send := func(rawTx *transactions.RawTx, key string) (map[string]string, int) {
+		data, err := json.Marshal(rawTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("POST", ts.URL+"/transactions", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "" {
+			req.Header.Set(transactions.IdempotencyKeyHeader, key)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var body map[string]string
+		json.NewDecoder(res.Body).Decode(&body)
+		return body, res.StatusCode
+	}
+
+	rawTx := newRawTx(100)
+	body, statusCode := send(rawTx, "key")
+	assert.Equal(t, http.StatusOK, statusCode)
+	id := body["id"]
+
+	// retried
+	body, statusCode = send(rawTx, "key")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, id, body["id"])
+
+	// key reused
+	_, statusCode = send(newRawTx(101), "key")
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	_, statusCode = send(newRawTx(101), strings.Repeat("k", 257))
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func sendTxBatch(t *testing.T) {
+	to := thor.BytesToAddress([]byte("to"))
+	newRawTx := func(chainTag byte, nonce uint64) *transactions.RawTx {
+		trx := new(tx.Builder).
+			ChainTag(chainTag).
+			Expiration(100).
+			Gas(21000).
+			Nonce(nonce).
+			Clause(tx.NewClause(&to)).
+			Build()
+		sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := rlp.EncodeToBytes(trx.WithSignature(sig))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &transactions.RawTx{Raw: hexutil.Encode(data)}
+	}
+
+	var results []*transactions.BatchResult
+	res := httpPost(t, ts.URL+"/transactions/batch", []*transactions.RawTx{
+		newRawTx(c.Tag(), 200),
+		newRawTx(c.Tag()+1, 201),
+		{Raw: "0x01"},
+		newRawTx(c.Tag(), 202),
+	})
+	if err := json.Unmarshal(res, &results); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, results, 4) {
+		assert.NotNil(t, results[0].ID)
+		assert.Empty(t, results[0].Error)
+		assert.NotNil(t, results[1].ID)
+		assert.Equal(t, "bad tx: chain tag mismatch", results[1].Error)
+		assert.Nil(t, results[2].ID)
+		assert.NotEmpty(t, results[2].Error, "undecodable")
+		assert.NotNil(t, results[3].ID)
+		assert.Empty(t, results[3].Error, "not failed by others")
+	}
+
+	for _, body := range []interface{}{[]*transactions.RawTx{}, make([]*transactions.RawTx, 101)} {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := http.Post(ts.URL+"/transactions/batch", "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	}
 }
 
 func httpPost(t *testing.T, url string, obj interface{}) []byte {
@@ -166,7 +1001,7 @@ func initTransactionServer(t *testing.T) {
 		}
 	}
 	db, _ := lvldb.NewMem()
-	stateC := state.NewCreator(db)
+	stateC = state.NewCreator(db)
 	gene := genesis.NewDevnet()
 
 	b, _, err := gene.Build(stateC)
@@ -197,7 +1032,7 @@ func initTransactionServer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	b, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -208,7 +1043,18 @@ func initTransactionServer(t *testing.T) {
 		t.Fatal(err)
 	}
 	router := mux.NewRouter()
-	transactions.New(c, txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})).Mount(router, "/transactions")
+	pool := txpool.New(c, stateC, txpool.Options{Limit: 10000, LimitPerAccount: 16, MaxLifetime: 10 * time.Minute})
+	tracker, err := txtracker.New(c, pool, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err = abis.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := transactions.New(c, stateC, pool, tracker, registry)
+	txs.Mount(router, "/transactions")
+	txs.MountPending(router, "/accounts/{address}/transactions/pending")
 	ts = httptest.NewServer(router)
 
 }
@@ -14,16 +14,21 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/abis"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/proof"
+	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
 )
 
 // Clause for json marshal
 type Clause struct {
-	To    *thor.Address        `json:"to"`
-	Value math.HexOrDecimal256 `json:"value"`
-	Data  string               `json:"data"`
+	To      *thor.Address        `json:"to"`
+	Value   math.HexOrDecimal256 `json:"value"`
+	Data    string               `json:"data"`
+	Decoded *abis.Decoded        `json:"decoded,omitempty"` // decoded call, if the ABI of the contract known
 }
 
 //Clauses array of clauses.
@@ -32,9 +37,9 @@ type Clauses []Clause
 //ConvertClause convert a raw clause into a json format clause
 func convertClause(c *tx.Clause) Clause {
 	return Clause{
-		c.To(),
-		math.HexOrDecimal256(*c.Value()),
-		hexutil.Encode(c.Data()),
+		To:    c.To(),
+		Value: math.HexOrDecimal256(*c.Value()),
+		Data:  hexutil.Encode(c.Data()),
 	}
 }
 
@@ -67,10 +72,12 @@ type Transaction struct {
 	GasPriceCoef uint8               `json:"gasPriceCoef"`
 	Gas          uint64              `json:"gas"`
 	Origin       thor.Address        `json:"origin"`
+	Delegated    bool                `json:"delegated"` // VIP-191 fee delegation
+	Delegator    *thor.Address       `json:"delegator"` // the gas payer, null if not delegated
 	Nonce        math.HexOrDecimal64 `json:"nonce"`
 	DependsOn    *thor.Bytes32       `json:"dependsOn"`
 	Size         uint32              `json:"size"`
-	Meta         TxMeta              `json:"meta"`
+	Meta         *TxMeta             `json:"meta"` // null if pending
 }
 type UnSignedTx struct {
 	ChainTag     uint8               `json:"chainTag"`
@@ -81,6 +88,7 @@ type UnSignedTx struct {
 	Gas          uint64              `json:"gas"`
 	DependsOn    *thor.Bytes32       `json:"dependsOn"`
 	Nonce        math.HexOrDecimal64 `json:"nonce"`
+	Delegated    bool                `json:"delegated"` // VIP-191 fee delegation, signed by both origin and delegator
 }
 
 func (ustx *UnSignedTx) decode() (*tx.Transaction, error) {
@@ -99,8 +107,11 @@ func (ustx *UnSignedTx) decode() (*tx.Transaction, error) {
 	}
 	var bf tx.BlockRef
 	copy(bf[:], blockRef[:])
+	var features tx.Features
+	features.SetDelegated(ustx.Delegated)
 
 	return txBuilder.ChainTag(ustx.ChainTag).
+		Features(features).
 		BlockRef(bf).
 		Expiration(ustx.Expiration).
 		Gas(ustx.Gas).
@@ -143,18 +154,96 @@ func (rtx *RawTx) decode() (*tx.Transaction, error) {
 	return tx, nil
 }
 
+// SimulateTx is an unsigned tx to simulate, as sent by the origin, and paid by the delegator if set.
+type SimulateTx struct {
+	UnSignedTx
+	Origin    *thor.Address `json:"origin"`
+	Delegator *thor.Address `json:"delegator"`
+}
+
+// SimulateResult is the result of a simulated tx, with outputs of clauses executed.
+// Clauses after the reverted one are not executed.
+type SimulateResult struct {
+	GasUsed  uint64                `json:"gasUsed"`
+	GasPayer thor.Address          `json:"gasPayer"`
+	Paid     *math.HexOrDecimal256 `json:"paid"`
+	Reverted bool                  `json:"reverted"`
+	Outputs  []*SimulatedOutput    `json:"outputs"`
+}
+
+func convertSimulateResult(receipt *tx.Receipt, outputs []*SimulatedOutput) *SimulateResult {
+	return &SimulateResult{
+		GasUsed:  receipt.GasUsed,
+		GasPayer: receipt.GasPayer,
+		Paid:     (*math.HexOrDecimal256)(receipt.Paid),
+		Reverted: receipt.Reverted,
+		Outputs:  outputs,
+	}
+}
+
+// SimulatedOutput is the output of a simulated clause.
+type SimulatedOutput struct {
+	Data         string      `json:"data"`
+	Events       []*Event    `json:"events"`
+	Transfers    []*Transfer `json:"transfers"`
+	GasUsed      uint64      `json:"gasUsed"`
+	Reverted     bool        `json:"reverted"`
+	VMError      string      `json:"vmError"`
+	RevertReason string      `json:"revertReason,omitempty"` // decoded from data if reverted with reason
+}
+
+func convertSimulatedOutput(output *runtime.Output, gasUsed uint64) *SimulatedOutput {
+	converted := &SimulatedOutput{
+		Data:      hexutil.Encode(output.Data),
+		Events:    make([]*Event, len(output.Events)),
+		Transfers: make([]*Transfer, len(output.Transfers)),
+		GasUsed:   gasUsed,
+	}
+	if output.VMErr != nil {
+		converted.Reverted = true
+		converted.VMError = output.VMErr.Error()
+		converted.RevertReason, _ = vm.UnpackRevertReason(output.Data)
+	}
+	for i, event := range output.Events {
+		converted.Events[i] = &Event{
+			Address: event.Address,
+			Topics:  append([]thor.Bytes32{}, event.Topics...),
+			Data:    hexutil.Encode(event.Data),
+		}
+	}
+	for i, transfer := range output.Transfers {
+		converted.Transfers[i] = &Transfer{
+			Sender:    transfer.Sender,
+			Recipient: transfer.Recipient,
+			Amount:    (*math.HexOrDecimal256)(transfer.Amount),
+		}
+	}
+	return converted
+}
+
+// BatchResult is the result of a tx in a batch submission, the error is empty if the tx is added.
+// The id is null if the tx can't be decoded.
+type BatchResult struct {
+	ID    *thor.Bytes32 `json:"id"`
+	Error string        `json:"error,omitempty"`
+}
+
 type rawTransaction struct {
 	RawTx
-	Meta TxMeta `json:"meta"`
+	Meta *TxMeta `json:"meta"` // null if pending
 }
 
-//convertTransaction convert a raw transaction into a json format transaction
+//convertTransaction convert a raw transaction into a json format transaction, header is nil if pending
 func convertTransaction(tx *tx.Transaction, header *block.Header, txIndex uint64) (*Transaction, error) {
 	//tx signer
 	signer, err := tx.Signer()
 	if err != nil {
 		return nil, err
 	}
+	delegator, err := tx.Delegator()
+	if err != nil {
+		return nil, err
+	}
 	cls := make(Clauses, len(tx.Clauses()))
 	for i, c := range tx.Clauses() {
 		cls[i] = convertClause(c)
@@ -164,6 +253,8 @@ func convertTransaction(tx *tx.Transaction, header *block.Header, txIndex uint64
 		ChainTag:     tx.ChainTag(),
 		ID:           tx.ID(),
 		Origin:       signer,
+		Delegated:    tx.Features().IsDelegated(),
+		Delegator:    delegator,
 		BlockRef:     hexutil.Encode(br[:]),
 		Expiration:   tx.Expiration(),
 		Nonce:        math.HexOrDecimal64(tx.Nonce()),
@@ -172,15 +263,78 @@ func convertTransaction(tx *tx.Transaction, header *block.Header, txIndex uint64
 		Gas:          tx.Gas(),
 		DependsOn:    tx.DependsOn(),
 		Clauses:      cls,
-		Meta: TxMeta{
+	}
+	if header != nil {
+		t.Meta = &TxMeta{
 			BlockID:        header.ID(),
 			BlockNumber:    header.Number(),
 			BlockTimestamp: header.Timestamp(),
-		},
+		}
 	}
 	return t, nil
 }
 
+// statuses of pending txs
+const (
+	PendingStatusExecutable = "executable" // to be packed into the next block
+	PendingStatusQueued     = "queued"     // waiting for its block ref, dependency or balance
+)
+
+// PendingTx is a tx in the pool, not yet packed.
+type PendingTx struct {
+	ID           thor.Bytes32        `json:"id"`
+	ChainTag     byte                `json:"chainTag"`
+	BlockRef     string              `json:"blockRef"`
+	Expiration   uint32              `json:"expiration"`
+	Clauses      Clauses             `json:"clauses"`
+	GasPriceCoef uint8               `json:"gasPriceCoef"`
+	Gas          uint64              `json:"gas"`
+	Origin       thor.Address        `json:"origin"`
+	Delegator    *thor.Address       `json:"delegator"`
+	Nonce        math.HexOrDecimal64 `json:"nonce"`
+	DependsOn    *thor.Bytes32       `json:"dependsOn"`
+	Size         uint32              `json:"size"`
+	Status       string              `json:"status"`
+}
+
+func convertPendingTx(tx *tx.Transaction, origin thor.Address, delegator *thor.Address, status string) *PendingTx {
+	cls := make(Clauses, len(tx.Clauses()))
+	for i, c := range tx.Clauses() {
+		cls[i] = convertClause(c)
+	}
+	br := tx.BlockRef()
+	return &PendingTx{
+		ID:           tx.ID(),
+		ChainTag:     tx.ChainTag(),
+		BlockRef:     hexutil.Encode(br[:]),
+		Expiration:   tx.Expiration(),
+		Clauses:      cls,
+		GasPriceCoef: tx.GasPriceCoef(),
+		Gas:          tx.Gas(),
+		Origin:       origin,
+		Delegator:    delegator,
+		Nonce:        math.HexOrDecimal64(tx.Nonce()),
+		DependsOn:    tx.DependsOn(),
+		Size:         uint32(tx.Size()),
+		Status:       status,
+	}
+}
+
+// statuses of txs, on the best chain
+const (
+	TxStatusPending   = "pending"   // in the pool
+	TxStatusIncluded  = "included"  // packed in a block, not yet finalized
+	TxStatusFinalized = "finalized" // packed in a finalized block
+	TxStatusUnknown   = "unknown"   // neither packed nor in the pool
+)
+
+// TxStatus is the status of a tx, with the block it's packed in.
+type TxStatus struct {
+	Status        string  `json:"status"`
+	Confirmations uint32  `json:"confirmations"` // count of blocks on top of the packing block
+	Meta          *TxMeta `json:"meta"`          // null if not packed
+}
+
 type TxMeta struct {
 	BlockID        thor.Bytes32 `json:"blockID"`
 	BlockNumber    uint32       `json:"blockNumber"`
@@ -285,3 +439,34 @@ func convertReceipt(txReceipt *tx.Receipt, header *block.Header, tx *tx.Transact
 	}
 	return receipt, nil
 }
+
+// rawReceipt is the rlp encoded receipt, with the proof of its inclusion against receipts root of the block.
+type rawReceipt struct {
+	Raw   string `json:"raw"`
+	Proof *Proof `json:"proof"`
+}
+
+// Proof merkle proof of tx or receipt inclusion, against the root in block header.
+type Proof struct {
+	Meta  TxMeta          `json:"meta"`
+	Root  thor.Bytes32    `json:"root"`
+	Index uint            `json:"index"`
+	Nodes []hexutil.Bytes `json:"nodes"`
+}
+
+func convertProof(p *proof.Proof, root thor.Bytes32, header *block.Header) *Proof {
+	nodes := make([]hexutil.Bytes, len(p.Nodes))
+	for i, node := range p.Nodes {
+		nodes[i] = node
+	}
+	return &Proof{
+		Meta: TxMeta{
+			BlockID:        header.ID(),
+			BlockNumber:    header.Number(),
+			BlockTimestamp: header.Timestamp(),
+		},
+		Root:  root,
+		Index: p.Index,
+		Nodes: nodes,
+	}
+}
@@ -15,27 +15,33 @@ import (
 	"github.com/vechain/thor/logdb"
 )
 
+// maxCountGroups limits count of groups returned by count API.
+const maxCountGroups = 1000
+
 type Transfers struct {
-	db *logdb.LogDB
+	db logdb.Store
 }
 
-func New(db *logdb.LogDB) *Transfers {
+func New(db logdb.Store) *Transfers {
 	return &Transfers{
 		db,
 	}
 }
 
-//Filter query logs with option
-func (t *Transfers) filter(ctx context.Context, filter *logdb.TransferFilter) ([]*FilteredTransfer, error) {
+//Filter query logs with option. The cursor of the last transfer is returned if the page is full.
+func (t *Transfers) filter(ctx context.Context, filter *logdb.TransferFilter) ([]*FilteredTransfer, *logdb.Cursor, error) {
 	transfers, err := t.db.FilterTransfers(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	tLogs := make([]*FilteredTransfer, len(transfers))
 	for i, trans := range transfers {
 		tLogs[i] = convertTransfer(trans)
 	}
-	return tLogs, nil
+	if len(filter.Sort) == 0 && !filter.IncludeRemoved && filter.Options != nil && filter.Options.Limit > 0 && uint64(len(transfers)) == filter.Options.Limit {
+		return tLogs, transfers[len(transfers)-1].Cursor(), nil
+	}
+	return tLogs, nil, nil
 }
 
 func (t *Transfers) handleFilterTransferLogs(w http.ResponseWriter, req *http.Request) error {
@@ -43,15 +49,68 @@ func (t *Transfers) handleFilterTransferLogs(w http.ResponseWriter, req *http.Re
 	if err := utils.ParseJSON(req.Body, &filter); err != nil {
 		return utils.BadRequest(errors.WithMessage(err, "body"))
 	}
-	tLogs, err := t.filter(req.Context(), &filter)
+	tLogs, next, err := t.filter(req.Context(), &filter)
+	if _, ok := err.(*logdb.ResultTooLargeError); ok {
+		return utils.BadRequest(err)
+	}
+	if err == logdb.ErrUnsupportedSortKey || err == logdb.ErrCursorWithSort || err == logdb.ErrCursorWithRemoved {
+		return utils.BadRequest(err)
+	}
 	if err != nil {
 		return err
 	}
+	if next != nil {
+		w.Header().Set(utils.NextCursorHeader, next.String())
+	}
 	return utils.WriteJSON(w, tLogs)
 }
 
+// handleCount counts transfers matching the filter, and groups them if 'groupBy' is 'txOrigin', 'sender' or 'recipient'.
+func (t *Transfers) handleCount(w http.ResponseWriter, req *http.Request) error {
+	var filter logdb.TransferFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	count, err := t.db.CountTransfers(req.Context(), &filter)
+	if err != nil {
+		return err
+	}
+	result := &CountResult{Count: count}
+	if by := req.URL.Query().Get("groupBy"); by != "" {
+		groups, err := t.db.CountTransfersBy(req.Context(), &filter, logdb.GroupBy(by), maxCountGroups)
+		if err == logdb.ErrUnsupportedGroupBy {
+			return utils.BadRequest(errors.WithMessage(err, "groupBy"))
+		}
+		if err != nil {
+			return err
+		}
+		result.Groups = convertGroupCounts(groups)
+	}
+	return utils.WriteJSON(w, result)
+}
+
+// handleAggregate counts transfers matching the filter and sums their amounts in buckets of block time,
+// by 'bucket' of 'hour' or 'day'.
+func (t *Transfers) handleAggregate(w http.ResponseWriter, req *http.Request) error {
+	var filter logdb.TransferFilter
+	if err := utils.ParseJSON(req.Body, &filter); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	bucket := req.URL.Query().Get("bucket")
+	aggs, err := t.db.AggregateTransfers(req.Context(), &filter, logdb.Bucket(bucket))
+	if err == logdb.ErrUnsupportedBucket {
+		return utils.BadRequest(errors.WithMessage(err, "bucket"))
+	}
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, convertAggregates(aggs))
+}
+
 func (t *Transfers) Mount(root *mux.Router, pathPrefix string) {
 	sub := root.PathPrefix(pathPrefix).Subrouter()
 
 	sub.Path("").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleFilterTransferLogs))
+	sub.Path("/count").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleCount))
+	sub.Path("/aggregate").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(t.handleAggregate))
 }
@@ -6,6 +6,7 @@
 package transfers
 
 import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/thor"
@@ -18,6 +19,7 @@ type LogMeta struct {
 	TxID           thor.Bytes32 `json:"txID"`
 	TxOrigin       thor.Address `json:"txOrigin"`
 	ClauseIndex    uint32       `json:"clauseIndex"`
+	Removed        bool         `json:"removed,omitempty"` // the block is orphaned
 }
 
 type FilteredTransfer struct {
@@ -40,6 +42,47 @@ func convertTransfer(transfer *logdb.Transfer) *FilteredTransfer {
 			TxID:           transfer.TxID,
 			TxOrigin:       transfer.TxOrigin,
 			ClauseIndex:    transfer.ClauseIndex,
+			Removed:        transfer.Removed,
 		},
 	}
 }
+
+// CountResult count of logs matching the filter, with the largest groups if grouped.
+type CountResult struct {
+	Count  uint64        `json:"count"`
+	Groups []*GroupCount `json:"groups,omitempty"`
+}
+
+// GroupCount count of logs sharing the key, which is null if the column is empty.
+type GroupCount struct {
+	Key   *hexutil.Bytes `json:"key"`
+	Count uint64         `json:"count"`
+}
+
+func convertGroupCounts(groups []*logdb.GroupCount) []*GroupCount {
+	converted := make([]*GroupCount, len(groups))
+	for i, g := range groups {
+		converted[i] = &GroupCount{Count: g.Count}
+		if g.Key != nil {
+			key := hexutil.Bytes(g.Key)
+			converted[i].Key = &key
+		}
+	}
+	return converted
+}
+
+// Aggregate count and sum of amounts of transfers in the bucket starting at the block time.
+type Aggregate struct {
+	Time   uint64                `json:"time"`
+	Count  uint64                `json:"count"`
+	Amount *math.HexOrDecimal256 `json:"amount"`
+}
+
+func convertAggregates(aggs []*logdb.Aggregate) []*Aggregate {
+	converted := make([]*Aggregate, len(aggs))
+	for i, agg := range aggs {
+		amount := math.HexOrDecimal256(*agg.Amount)
+		converted[i] = &Aggregate{Time: agg.Time, Count: agg.Count, Amount: &amount}
+	}
+	return converted
+}
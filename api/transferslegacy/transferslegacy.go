@@ -16,10 +16,10 @@ import (
 )
 
 type TransfersLegacy struct {
-	db *logdb.LogDB
+	db logdb.Store
 }
 
-func New(db *logdb.LogDB) *TransfersLegacy {
+func New(db logdb.Store) *TransfersLegacy {
 	return &TransfersLegacy{
 		db,
 	}
@@ -50,6 +50,9 @@ func (t *TransfersLegacy) handleFilterTransferLogs(w http.ResponseWriter, req *h
 		filter.Order = logdb.DESC
 	}
 	tLogs, err := t.filter(req.Context(), convertTransferFilter(&filter))
+	if _, ok := err.(*logdb.ResultTooLargeError); ok {
+		return utils.BadRequest(err)
+	}
 	if err != nil {
 		return err
 	}
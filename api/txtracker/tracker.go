@@ -0,0 +1,302 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package txtracker tracks lifecycles of locally submitted txs, and persists them as audit trails.
+package txtracker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/co"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txpool"
+)
+
+var log = log15.New("pkg", "txtracker")
+
+// Lifecycle statuses of a tracked tx.
+const (
+	StatusReceived  = "received"  // submitted, not yet accepted by pool
+	StatusPooled    = "pooled"    // accepted by pool, but not executable
+	StatusBroadcast = "broadcast" // executable, and relayed to peers
+	StatusPacked    = "packed"    // packed in a trunk block
+	StatusFinalized = "finalized" // packed block is finalized
+	StatusDropped   = "dropped"   // rejected or washed out of pool
+	// replaced in the local pool, but still followed on the chain, since it may have been relayed and be packed
+	StatusSuperseded = "superseded"
+)
+
+// supersededTimeout how long superseded txs are followed on the chain, before taken as dropped.
+// It's far beyond the lifetime of txs in pools of peers.
+const supersededTimeout = time.Hour
+
+// retention how long records of finalized or dropped txs are kept.
+const retention = 7 * 24 * time.Hour
+
+var recordKeyPrefix = []byte("txlife")
+
+// Transition a status change of tracked tx.
+type Transition struct {
+	Status      string        `json:"status"`
+	Timestamp   int64         `json:"timestamp"`
+	BlockID     *thor.Bytes32 `json:"blockID,omitempty"`     // for packed or finalized
+	BlockNumber *uint32       `json:"blockNumber,omitempty"` // for packed or finalized
+	Reason      string        `json:"reason,omitempty"`
+}
+
+// Record lifecycle record of tracked tx.
+type Record struct {
+	TxID    thor.Bytes32  `json:"txID"`
+	Status  string        `json:"status"`
+	History []*Transition `json:"history"`
+}
+
+func (r *Record) isDone() bool {
+	return r.Status == StatusFinalized || r.Status == StatusDropped
+}
+
+// Tracker tracks lifecycles of txs following pool and chain events.
+// Records of txs not yet finalized or dropped are kept in memory, and all records are persisted.
+type Tracker struct {
+	chain  *chain.Chain
+	store  kv.GetPutter
+	lock   sync.Mutex
+	active map[thor.Bytes32]*Record
+	cancel func()
+	goes   co.Goes
+}
+
+// New creates tracker, loads unfinished records and prunes outdated ones.
+func New(chain *chain.Chain, pool *txpool.TxPool, store kv.GetPutter) (*Tracker, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &Tracker{
+		chain:  chain,
+		store:  store,
+		active: make(map[thor.Bytes32]*Record),
+		cancel: cancel,
+	}
+
+	var outdated [][]byte
+	deadline := time.Now().Add(-retention).Unix()
+	it := store.NewIterator(*kv.NewRangeWithBytesPrefix(recordKeyPrefix))
+	for it.Next() {
+		var record Record
+		if err := json.Unmarshal(it.Value(), &record); err != nil {
+			it.Release()
+			return nil, errors.WithMessage(err, "decode tx record")
+		}
+		if !record.isDone() {
+			t.active[record.TxID] = &record
+		} else if record.History[len(record.History)-1].Timestamp < deadline {
+			outdated = append(outdated, append([]byte(nil), it.Key()...))
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	for _, key := range outdated {
+		if err := store.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+
+	// subscribe before returning, to not miss events of txs added right after
+	var scope event.SubscriptionScope
+	txCh := make(chan *txpool.TxEvent, 10)
+	dropCh := make(chan *txpool.TxDropEvent, 10)
+	if pool != nil {
+		scope.Track(pool.SubscribeTxEvent(txCh))
+		scope.Track(pool.SubscribeTxDropEvent(dropCh))
+	}
+	ticker := chain.NewTicker()
+
+	t.goes.Go(func() {
+		defer scope.Close()
+		t.run(ctx, txCh, dropCh, ticker)
+	})
+	return t, nil
+}
+
+// Close stops tracking.
+func (t *Tracker) Close() {
+	t.cancel()
+	t.goes.Wait()
+}
+
+// Received starts tracking the tx, which is about to be added into pool.
+func (t *Tracker) Received(trx *tx.Transaction) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.active[trx.ID()]; ok {
+		return
+	}
+	record := &Record{TxID: trx.ID()}
+	t.active[trx.ID()] = record
+	t.transit(record, &Transition{Status: StatusReceived})
+}
+
+// Rejected marks the tracked tx dropped, since pool refused it.
+func (t *Tracker) Rejected(txID thor.Bytes32, reason string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if record := t.active[txID]; record != nil && record.Status == StatusReceived {
+		t.transit(record, &Transition{Status: StatusDropped, Reason: reason})
+	}
+}
+
+// Superseded marks the tracked tx superseded by another one in the local pool. It's only node-local, as the
+// tx may have been relayed to peers, so it's still followed on the chain.
+func (t *Tracker) Superseded(txID thor.Bytes32, by thor.Bytes32) {
+	t.update(txID, func(record *Record) *Transition {
+		if record.Status == StatusPacked {
+			return nil
+		}
+		return &Transition{Status: StatusSuperseded, Reason: "superseded locally by " + by.String()}
+	})
+}
+
+// Get returns lifecycle record of the tx. Nil returned if not tracked.
+func (t *Tracker) Get(txID thor.Bytes32) (*Record, error) {
+	t.lock.Lock()
+	if record := t.active[txID]; record != nil {
+		copied := *record
+		copied.History = append([]*Transition(nil), record.History...)
+		t.lock.Unlock()
+		return &copied, nil
+	}
+	t.lock.Unlock()
+
+	data, err := t.store.Get(recordKey(txID))
+	if err != nil {
+		if t.store.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (t *Tracker) run(ctx context.Context, txCh <-chan *txpool.TxEvent, dropCh <-chan *txpool.TxDropEvent, ticker co.Waiter) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-txCh:
+			status := StatusPooled
+			if ev.Executable != nil && *ev.Executable {
+				status = StatusBroadcast
+			}
+			t.update(ev.Tx.ID(), func(record *Record) *Transition {
+				if record.Status == StatusReceived || (record.Status == StatusPooled && status == StatusBroadcast) {
+					return &Transition{Status: status}
+				}
+				return nil
+			})
+		case ev := <-dropCh:
+			t.update(ev.Tx.ID(), func(record *Record) *Transition {
+				// packed txs are washed out as known
+				if record.Status == StatusPacked {
+					return nil
+				}
+				return &Transition{Status: StatusDropped, Reason: ev.Reason}
+			})
+		case <-ticker.C():
+			if err := t.followChain(); err != nil {
+				log.Warn("failed to follow chain", "err", err)
+			}
+		}
+	}
+}
+
+// followChain checks active records against the best block, for packing, reverting and finality.
+func (t *Tracker) followChain() error {
+	best := t.chain.BestBlock().Header()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for id, record := range t.active {
+		meta, err := t.chain.GetTransactionMeta(id, best.ID())
+		if err != nil {
+			if !t.chain.IsNotFound(err) {
+				return err
+			}
+			switch record.Status {
+			case StatusPacked:
+				// packed block is no longer trunk, and the tx will be re-added into pool
+				t.transit(record, &Transition{Status: StatusPooled, Reason: "block reverted"})
+			case StatusSuperseded:
+				if time.Now().Unix() > record.History[len(record.History)-1].Timestamp+int64(supersededTimeout/time.Second) {
+					t.transit(record, &Transition{Status: StatusDropped, Reason: "superseded and not packed"})
+				}
+			}
+			continue
+		}
+		header, err := t.chain.GetBlockHeader(meta.BlockID)
+		if err != nil {
+			return err
+		}
+		blockID, blockNum := header.ID(), header.Number()
+		if record.Status != StatusPacked || *record.History[len(record.History)-1].BlockID != blockID {
+			t.transit(record, &Transition{Status: StatusPacked, BlockID: &blockID, BlockNumber: &blockNum})
+		}
+		if best.Number() >= blockNum+utils.FinalizedDepth {
+			t.transit(record, &Transition{Status: StatusFinalized, BlockID: &blockID, BlockNumber: &blockNum})
+		}
+	}
+	return nil
+}
+
+// update applies the transition returned by fn to the active record, if any.
+func (t *Tracker) update(txID thor.Bytes32, fn func(*Record) *Transition) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if record := t.active[txID]; record != nil {
+		if tr := fn(record); tr != nil {
+			t.transit(record, tr)
+		}
+	}
+}
+
+// transit appends the transition to the record and persists it. Must be called with lock held.
+func (t *Tracker) transit(record *Record, tr *Transition) {
+	tr.Timestamp = time.Now().Unix()
+	record.Status = tr.Status
+	record.History = append(record.History, tr)
+	if record.isDone() {
+		delete(t.active, record.TxID)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Warn("failed to encode tx record", "id", record.TxID, "err", err)
+		return
+	}
+	if err := t.store.Put(recordKey(record.TxID), data); err != nil {
+		log.Warn("failed to save tx record", "id", record.TxID, "err", err)
+	}
+}
+
+func recordKey(txID thor.Bytes32) []byte {
+	return append(append([]byte(nil), recordKeyPrefix...), txID[:]...)
+}
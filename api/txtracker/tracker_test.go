@@ -0,0 +1,152 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txtracker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/txtracker"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txpool"
+)
+
+func TestTracker(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	// chain is not synced (genesis is far in the past), so txs are pooled without executability checked
+	pool := txpool.New(chain, stateC, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Hour})
+	defer pool.Close()
+	tracker, err := txtracker.New(chain, pool, db)
+	assert.Nil(t, err)
+
+	trx := newTx(t, chain.Tag(), 1)
+	tracker.Received(trx)
+	assert.Nil(t, pool.Add(trx))
+	waitStatus(t, tracker, trx.ID(), txtracker.StatusPooled)
+
+	rejected := newTx(t, chain.Tag()+1, 2)
+	tracker.Received(rejected)
+	tracker.Rejected(rejected.ID(), "bad tx: chain tag mismatch")
+	record, err := tracker.Get(rejected.ID())
+	assert.Nil(t, err)
+	assert.Equal(t, txtracker.StatusDropped, record.Status)
+	assert.Equal(t, "bad tx: chain tag mismatch", record.History[1].Reason)
+
+	// superseded locally, but relayed before and packed
+	superseded := newTx(t, chain.Tag(), 3)
+	tracker.Received(superseded)
+	assert.Nil(t, pool.Add(superseded))
+	waitStatus(t, tracker, superseded.ID(), txtracker.StatusPooled)
+	pool.Remove(superseded.Hash(), superseded.ID())
+	tracker.Superseded(superseded.ID(), trx.ID())
+	record, _ = tracker.Get(superseded.ID())
+	assert.Equal(t, txtracker.StatusSuperseded, record.Status)
+
+	b1 := pack(t, chain, stateC, b0.Header(), trx, superseded)
+	waitStatus(t, tracker, trx.ID(), txtracker.StatusPacked)
+	waitStatus(t, tracker, superseded.ID(), txtracker.StatusPacked)
+	record, _ = tracker.Get(trx.ID())
+	assert.Equal(t, b1.Header().ID(), *record.History[len(record.History)-1].BlockID)
+
+	parent := b1.Header()
+	for i := uint32(0); i < utils.FinalizedDepth; i++ {
+		parent = pack(t, chain, stateC, parent).Header()
+	}
+	waitStatus(t, tracker, trx.ID(), txtracker.StatusFinalized)
+	tracker.Close()
+
+	// persisted
+	tracker, err = txtracker.New(chain, pool, db)
+	assert.Nil(t, err)
+	defer tracker.Close()
+	record, err = tracker.Get(trx.ID())
+	assert.Nil(t, err)
+	var statuses []string
+	for _, tr := range record.History {
+		statuses = append(statuses, tr.Status)
+	}
+	assert.Equal(t, []string{
+		txtracker.StatusReceived,
+		txtracker.StatusPooled,
+		txtracker.StatusPacked,
+		txtracker.StatusFinalized,
+	}, statuses)
+
+	record, err = tracker.Get(thor.Bytes32{})
+	assert.Nil(t, err)
+	assert.Nil(t, record)
+}
+
+func waitStatus(t *testing.T, tracker *txtracker.Tracker, txID thor.Bytes32, status string) {
+	for i := 0; i < 100; i++ {
+		record, err := tracker.Get(txID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record != nil && record.Status == status {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("status %v not reached", status)
+}
+
+func newTx(t *testing.T, chainTag byte, nonce uint64) *tx.Transaction {
+	to := thor.BytesToAddress([]byte("to"))
+	trx := new(tx.Builder).
+		ChainTag(chainTag).
+		Clause(tx.NewClause(&to)).
+		Expiration(1000).
+		Gas(21000).
+		Nonce(nonce).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trx.WithSignature(sig)
+}
+
+func pack(t *testing.T, chain *chain.Chain, stateC *state.Creator, parent *block.Header, txs ...*tx.Transaction) *block.Block {
+	acc := genesis.DevAccounts()[0]
+	flow, err := packer.New(chain, stateC, acc.Address, nil).Schedule(parent, parent.Timestamp()+thor.BlockInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, trx := range txs {
+		if err := flow.Adopt(trx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, stage, receipts, _, err := flow.Pack(acc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.AddBlock(b, receipts); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -8,7 +8,11 @@ package utils
 import (
 	"encoding/json"
 	"io"
+	"mime"
 	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 type httpError struct {
@@ -71,8 +75,12 @@ func WrapHandlerFunc(f HandlerFunc) http.HandlerFunc {
 const (
 	JSONContentType        = "application/json; charset=utf-8"
 	OctetStreamContentType = "application/octet-stream"
+	RLPContentType         = "application/rlp"
 )
 
+// NextCursorHeader header carries the cursor to continue from, if a page of logs is full.
+const NextCursorHeader = "X-Thor-Next-Cursor"
+
 // ParseJSON parse a JSON object using strict mode.
 func ParseJSON(r io.Reader, v interface{}) error {
 	decoder := json.NewDecoder(r)
@@ -91,5 +99,32 @@ func WriteJSON(w http.ResponseWriter, obj interface{}) error {
 	return nil
 }
 
+// WantsRLP returns whether the request asks for a RLP encoded response, by 'Accept: application/rlp'
+// header or 'format=rlp' query.
+func WantsRLP(req *http.Request) (bool, error) {
+	switch format := req.URL.Query().Get("format"); format {
+	case "rlp":
+		return true, nil
+	case "", "json":
+	default:
+		return false, BadRequest(errors.New("format: should be json or rlp"))
+	}
+	for _, accept := range req.Header["Accept"] {
+		for _, t := range strings.Split(accept, ",") {
+			if mt, _, err := mime.ParseMediaType(strings.TrimSpace(t)); err == nil && mt == RLPContentType {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// WriteRLP reponse RLP encoded data.
+func WriteRLP(w http.ResponseWriter, data []byte) error {
+	w.Header().Set("Content-Type", RLPContentType)
+	w.Write(data)
+	return nil
+}
+
 // M shortcut for type map[string]interface{}.
 type M map[string]interface{}
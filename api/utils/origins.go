@@ -0,0 +1,43 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// AllowedOrigins origins from which cross origin requests are accepted.
+// It's safe to be updated at runtime.
+type AllowedOrigins struct {
+	origins atomic.Value
+}
+
+// NewAllowedOrigins create allowed origins from comma separated list of domains.
+func NewAllowedOrigins(s string) *AllowedOrigins {
+	o := &AllowedOrigins{}
+	o.Set(s)
+	return o
+}
+
+// Set replaces origins with comma separated list of domains.
+func (o *AllowedOrigins) Set(s string) {
+	origins := strings.Split(strings.TrimSpace(s), ",")
+	for i, origin := range origins {
+		origins[i] = strings.ToLower(strings.TrimSpace(origin))
+	}
+	o.origins.Store(origins)
+}
+
+// Allowed returns whether the origin is allowed.
+func (o *AllowedOrigins) Allowed(origin string) bool {
+	for _, allowedOrigin := range o.origins.Load().([]string) {
+		if allowedOrigin == origin || allowedOrigin == "*" {
+			return true
+		}
+	}
+	return false
+}
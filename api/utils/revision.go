@@ -0,0 +1,100 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/thor"
+)
+
+// FinalizedDepth is the count of blocks on top of a block to treat it as finalized.
+// There's no finality gadget, so a block is treated as finalized once more than 2/3
+// of max block proposers have built on it.
+const FinalizedDepth = uint32(thor.MaxBlockProposers*2/3 + 1)
+
+// ParseRevision parses revision string, which is one of:
+//
+//   - "best" or empty: the best block
+//   - "finalized": the trunk block at FinalizedDepth below the best block
+//   - block number in decimal or hex
+//   - block id
+//   - "@<timestamp>": the latest trunk block with timestamp not after given unix time
+//
+// The parsed revision is of type nil (best), string ("finalized"), uint32 (number),
+// thor.Bytes32 (id) or uint64 (timestamp).
+func ParseRevision(revision string) (interface{}, error) {
+	switch {
+	case revision == "" || revision == "best":
+		return nil, nil
+	case revision == "finalized":
+		return revision, nil
+	case strings.HasPrefix(revision, "@"):
+		ts, err := strconv.ParseUint(revision[1:], 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return ts, nil
+	case len(revision) == 66 || len(revision) == 64:
+		return thor.ParseBytes32(revision)
+	}
+	n, err := strconv.ParseUint(revision, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n > math.MaxUint32 {
+		return nil, errors.New("block number out of max uint32")
+	}
+	return uint32(n), nil
+}
+
+// GetRevisionHeader returns header of the block which the parsed revision refers to.
+// Errors from chain are returned as is, which can be checked by chain.IsNotFound.
+func GetRevisionHeader(chain *chain.Chain, revision interface{}) (*block.Header, error) {
+	best := chain.BestBlock().Header()
+	switch rev := revision.(type) {
+	case thor.Bytes32:
+		return chain.GetBlockHeader(rev)
+	case uint32:
+		return chain.GetTrunkBlockHeader(rev)
+	case uint64:
+		if rev >= best.Timestamp() {
+			return best, nil
+		}
+		// the first block after the timestamp
+		n := sort.Search(int(best.Number()), func(i int) bool {
+			h, err := chain.GetTrunkBlockHeader(uint32(i))
+			return err != nil || h.Timestamp() > rev
+		})
+		if n == 0 {
+			return nil, BadRequest(errors.New("revision: timestamp before genesis"))
+		}
+		return chain.GetTrunkBlockHeader(uint32(n - 1))
+	case string:
+		if best.Number() < FinalizedDepth {
+			return chain.GenesisBlock().Header(), nil
+		}
+		return chain.GetTrunkBlockHeader(best.Number() - FinalizedDepth)
+	default:
+		return best, nil
+	}
+}
+
+// ResolveRevision parses revision string and returns header of the block it refers to.
+// Bad revision string results in BadRequest error, and errors from chain are returned as is.
+func ResolveRevision(chain *chain.Chain, revision string) (*block.Header, error) {
+	rev, err := ParseRevision(revision)
+	if err != nil {
+		return nil, BadRequest(errors.WithMessage(err, "revision"))
+	}
+	return GetRevisionHeader(chain, rev)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package utils_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+func TestParseRevision(t *testing.T) {
+	id := thor.BytesToBytes32([]byte("id"))
+	for revision, expected := range map[string]interface{}{
+		"":              nil,
+		"best":          nil,
+		"finalized":     "finalized",
+		"10":            uint32(10),
+		"0xa":           uint32(10),
+		"@1530000000":   uint64(1530000000),
+		id.String():     id,
+		id.String()[2:]: id,
+	} {
+		rev, err := utils.ParseRevision(revision)
+		assert.Nil(t, err, revision)
+		assert.Equal(t, expected, rev, revision)
+	}
+
+	for _, revision := range []string{"4294967296", "bad", "@", "@x", "0x" + id.String()[4:] + "zz"} {
+		_, err := utils.ParseRevision(revision)
+		assert.NotNil(t, err, revision)
+	}
+}
+
+func TestResolveRevision(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	headers := []*block.Header{b0.Header()}
+	for i := 0; i < 3; i++ {
+		parent := headers[len(headers)-1]
+		flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).Schedule(parent, parent.Timestamp()+thor.BlockInterval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stage.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.AddBlock(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+		headers = append(headers, b.Header())
+	}
+
+	resolve := func(revision string) *block.Header {
+		h, err := utils.ResolveRevision(chain, revision)
+		assert.Nil(t, err, revision)
+		return h
+	}
+	ts := func(h *block.Header) string {
+		return strconv.FormatUint(h.Timestamp(), 10)
+	}
+
+	assert.Equal(t, headers[3].ID(), resolve("best").ID())
+	assert.Equal(t, headers[1].ID(), resolve("1").ID())
+	assert.Equal(t, headers[2].ID(), resolve(headers[2].ID().String()).ID())
+	// not enough blocks to finalize
+	assert.Equal(t, headers[0].ID(), resolve("finalized").ID())
+
+	assert.Equal(t, headers[1].ID(), resolve("@"+ts(headers[1])).ID())
+	assert.Equal(t, headers[1].ID(), resolve("@"+strconv.FormatUint(headers[2].Timestamp()-1, 10)).ID())
+	assert.Equal(t, headers[0].ID(), resolve("@"+ts(headers[0])).ID())
+	assert.Equal(t, headers[3].ID(), resolve("@"+strconv.FormatUint(headers[3].Timestamp()+100, 10)).ID())
+
+	_, err = utils.ResolveRevision(chain, "@"+strconv.FormatUint(headers[0].Timestamp()-1, 10))
+	assert.NotNil(t, err, "before genesis")
+	_, err = utils.ResolveRevision(chain, "4")
+	assert.True(t, chain.IsNotFound(err))
+	_, err = utils.ResolveRevision(chain, "bad")
+	assert.NotNil(t, err)
+	assert.False(t, chain.IsNotFound(err))
+}
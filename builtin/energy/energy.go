@@ -8,6 +8,8 @@ package energy
 import (
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
@@ -127,3 +129,16 @@ func (e *Energy) Sub(addr thor.Address, amount *big.Int) bool {
 	e.state.SetEnergy(addr, new(big.Int).Sub(eng, amount), e.blockTime)
 	return true
 }
+
+// allowanceKey returns storage key of allowed[owner][spender], where the mapping 'allowed' is
+// at slot 0 of the energy contract.
+func allowanceKey(owner, spender thor.Address) thor.Bytes32 {
+	slot := crypto.Keccak256(common.LeftPadBytes(owner[:], 32), make([]byte, 32))
+	return thor.BytesToBytes32(crypto.Keccak256(common.LeftPadBytes(spender[:], 32), slot))
+}
+
+// GetAllowance returns amount of energy the spender is approved to transfer from the owner.
+func (e *Energy) GetAllowance(owner, spender thor.Address) *big.Int {
+	v := e.state.GetStorage(e.addr, allowanceKey(owner, spender))
+	return new(big.Int).SetBytes(v[:])
+}
@@ -461,6 +461,8 @@ func TestEnergyNative(t *testing.T) {
 	test.Case("allowance", addr, to).
 		ShouldOutput(big.NewInt(10)).
 		Assert(t)
+	assert.Equal(t, big.NewInt(10), builtin.Energy.Native(st, 0).GetAllowance(addr, to))
+	assert.Equal(t, 0, builtin.Energy.Native(st, 0).GetAllowance(to, addr).Sign())
 
 	test.Case("transferFrom", addr, thor.BytesToAddress([]byte("some one")), big.NewInt(10)).
 		Caller(to).
@@ -7,6 +7,7 @@ package chain
 
 import (
 	"bytes"
+	"encoding/binary"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/rlp"
@@ -203,6 +204,12 @@ func (c *Chain) AddBlock(newBlock *block.Block, receipts tx.Receipts) (*Fork, er
 		if err := saveTxMeta(batch, tx.ID(), meta); err != nil {
 			return nil, err
 		}
+		// txs of verified blocks are always signed
+		if origin, err := tx.Signer(); err == nil {
+			if err := batch.Put(txOriginKey(origin, newBlock.Header().Number(), tx.ID()), nil); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	var fork *Fork
@@ -374,6 +381,58 @@ func (c *Chain) GetTrunkTransaction(txID thor.Bytes32) (*tx.Transaction, *TxMeta
 	return tx, meta, nil
 }
 
+// GetTrunkTransactionsByOrigin returns ids of trunk txs sent by the origin, ordered by block number.
+// Paging starts after the trunk tx of the given id if not nil, then skips offset txs and returns at most limit.
+// Txs are indexed since the index introduced, and those of earlier blocks are not covered.
+func (c *Chain) GetTrunkTransactionsByOrigin(origin thor.Address, after *thor.Bytes32, offset, limit uint64) ([]thor.Bytes32, error) {
+	best := c.BestBlock().Header().ID()
+
+	prefix := append(append([]byte(nil), txOriginPrefix...), origin[:]...)
+	keyLen := len(prefix) + 4 + 32
+	rng := kv.NewRangeWithBytesPrefix(prefix)
+	if after != nil {
+		meta, err := c.GetTransactionMeta(*after, best)
+		if err != nil {
+			return nil, err
+		}
+		// seek to the key next to the one of the tx
+		rng.From = append(txOriginKey(origin, block.Number(meta.BlockID), *after), 0)
+	}
+
+	// the chain lock is not held while iterating, as the iterator reads a snapshot
+	var ids []thor.Bytes32
+	it := c.kv.NewIterator(*rng)
+	defer it.Release()
+	for uint64(len(ids)) < limit && it.Next() {
+		key := it.Key()
+		if len(key) != keyLen {
+			continue
+		}
+		num := binary.BigEndian.Uint32(key[len(prefix):])
+		txID := thor.BytesToBytes32(key[len(prefix)+4:])
+		// indexed for blocks of all branches
+		meta, err := c.GetTransactionMeta(txID, best)
+		if err != nil {
+			if c.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if block.Number(meta.BlockID) != num {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		ids = append(ids, txID)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // NewSeeker returns a new seeker instance.
 func (c *Chain) NewSeeker(headBlockID thor.Bytes32) *Seeker {
 	return newSeeker(c, headBlockID)
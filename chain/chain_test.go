@@ -15,6 +15,8 @@ import (
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
 )
 
 func initChain() *chain.Chain {
@@ -74,3 +76,76 @@ func TestAdd(t *testing.T) {
 		}
 	}
 }
+
+func TestAddBlockWriteFault(t *testing.T) {
+	kv, _ := lvldb.NewMemFaulty(lvldb.Faults{}, 1)
+	b0, _, _ := genesis.NewDevnet().Build(state.NewCreator(kv))
+	ch, err := chain.New(kv, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b1 := newBlock(b0, 1)
+	kv.SetFaults(lvldb.Faults{WriteErrorRate: 1})
+	_, err = ch.AddBlock(b1, nil)
+	assert.Equal(t, lvldb.ErrInjected, err)
+	assert.Equal(t, b0.Header().ID(), ch.BestBlock().Header().ID())
+	_, err = ch.GetBlockHeader(b1.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+
+	kv.SetFaults(lvldb.Faults{})
+	_, err = ch.AddBlock(b1, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, b1.Header().ID(), ch.BestBlock().Header().ID())
+}
+
+func TestTrunkTransactionsByOrigin(t *testing.T) {
+	ch := initChain()
+	tx1 := newTx(ch.Tag(), 1)
+	tx2 := newTx(ch.Tag(), 2)
+	tx3 := newTx(ch.Tag(), 3)
+	orphan := newTx(ch.Tag(), 4)
+
+	b0 := ch.GenesisBlock()
+	b1 := newBlockWithTxs(b0, 2, tx1)
+	b1x := newBlockWithTxs(b0, 1, tx2, orphan)
+	b2 := newBlockWithTxs(b1, 2, tx2, tx3)
+
+	for _, b := range []*block.Block{b1, b1x, b2} {
+		receipts := make(tx.Receipts, len(b.Transactions()))
+		for i := range receipts {
+			receipts[i] = &tx.Receipt{}
+		}
+		if _, err := ch.AddBlock(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	origin, _ := tx1.Signer()
+	ids, err := ch.GetTrunkTransactionsByOrigin(origin, nil, 0, 10)
+	assert.Nil(t, err)
+	if assert.Len(t, ids, 3, "txs of side branches excluded") {
+		assert.Equal(t, tx1.ID(), ids[0])
+		assert.Contains(t, ids[1:], tx2.ID())
+		assert.Contains(t, ids[1:], tx3.ID())
+	}
+
+	paged, err := ch.GetTrunkTransactionsByOrigin(origin, nil, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, ids[1:2], paged)
+
+	paged, err = ch.GetTrunkTransactionsByOrigin(origin, &ids[0], 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, ids[1:], paged, "after the cursor")
+	paged, err = ch.GetTrunkTransactionsByOrigin(origin, &ids[1], 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, ids[2:], paged, "after the cursor")
+
+	orphanID := orphan.ID()
+	_, err = ch.GetTrunkTransactionsByOrigin(origin, &orphanID, 0, 10)
+	assert.True(t, ch.IsNotFound(err), "cursor not on trunk")
+
+	ids, err = ch.GetTrunkTransactionsByOrigin(thor.Address{}, nil, 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, ids)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain
+
+import (
+	"encoding/binary"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+// count of block numbers pruned per batch, to limit the duration of holding the lock
+const pruneBatchNumbers = 256
+
+var branchGCPosKey = []byte("gc-branch-pos") // -> the number from which side branches not yet pruned
+
+// PruneBranches deletes blocks, receipts and indexes of side branches which are at least horizon blocks
+// below the best block. It continues from where the last call stopped, and returns the count of blocks deleted.
+// Nodes of block number index tries are shared with trunk blocks, and kept.
+func (c *Chain) PruneBranches(horizon uint32) (int, error) {
+	pos, err := loadBranchGCPos(c.kv)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for {
+		n, next, err := c.pruneBranchesBatch(pos, horizon)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if next == pos {
+			return total, nil
+		}
+		pos = next
+	}
+}
+
+// pruneBranchesBatch prunes side branch blocks with number in [pos, pos+pruneBatchNumbers), and returns
+// the position to continue.
+func (c *Chain) pruneBranchesBatch(pos uint32, horizon uint32) (int, uint32, error) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	best := c.bestBlock.Header()
+	if best.Number() <= horizon || pos > best.Number()-horizon {
+		return 0, pos, nil
+	}
+	end := best.Number() - horizon + 1
+	if end-pos > pruneBatchNumbers {
+		end = pos + pruneBatchNumbers
+	}
+
+	type staleBlock struct {
+		id  thor.Bytes32
+		raw block.Raw
+	}
+	var stales []staleBlock
+	it := c.kv.NewIterator(*kv.NewRange(
+		append(append([]byte(nil), blockPrefix...), numberAsKey(pos)...),
+		append(append([]byte(nil), blockPrefix...), numberAsKey(end)...)))
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(blockPrefix)+32 {
+			continue
+		}
+		id := thor.BytesToBytes32(key[len(blockPrefix):])
+		trunkID, err := c.ancestorTrie.GetAncestor(best.ID(), block.Number(id))
+		if err != nil {
+			it.Release()
+			return 0, 0, err
+		}
+		if trunkID != id {
+			stales = append(stales, staleBlock{id, append(block.Raw(nil), it.Value()...)})
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return 0, 0, err
+	}
+
+	batch := c.kv.NewBatch()
+	// tx metas updated in this batch
+	metas := make(map[thor.Bytes32][]TxMeta)
+	// origin index keys of txs in stale blocks
+	originKeys := make(map[thor.Bytes32][][]byte)
+	for _, stale := range stales {
+		body, err := (&rawBlock{raw: stale.raw}).Body()
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, tx := range body.Txs {
+			meta, ok := metas[tx.ID()]
+			if !ok {
+				if meta, err = loadTxMeta(c.kv, tx.ID()); err != nil && !c.IsNotFound(err) {
+					return 0, 0, err
+				}
+			}
+			remained := meta[:0:0]
+			for _, m := range meta {
+				if m.BlockID != stale.id {
+					remained = append(remained, m)
+				}
+			}
+			metas[tx.ID()] = remained
+			if origin, err := tx.Signer(); err == nil {
+				originKeys[tx.ID()] = append(originKeys[tx.ID()], txOriginKey(origin, block.Number(stale.id), tx.ID()))
+			}
+		}
+		if err := batch.Delete(append(append([]byte(nil), blockPrefix...), stale.id[:]...)); err != nil {
+			return 0, 0, err
+		}
+		if err := batch.Delete(append(append([]byte(nil), blockReceiptsPrefix...), stale.id[:]...)); err != nil {
+			return 0, 0, err
+		}
+		if err := batch.Delete(append(append([]byte(nil), indexTrieRootPrefix...), stale.id[:]...)); err != nil {
+			return 0, 0, err
+		}
+	}
+	for txID, meta := range metas {
+		if len(meta) == 0 {
+			if err := batch.Delete(append(append([]byte(nil), txMetaPrefix...), txID[:]...)); err != nil {
+				return 0, 0, err
+			}
+		} else if err := saveTxMeta(batch, txID, meta); err != nil {
+			return 0, 0, err
+		}
+		// keys are shared by blocks of the same number
+	keys:
+		for _, key := range originKeys[txID] {
+			num := binary.BigEndian.Uint32(key[len(key)-36:])
+			for _, m := range meta {
+				if block.Number(m.BlockID) == num {
+					continue keys
+				}
+			}
+			if err := batch.Delete(key); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	if err := saveBranchGCPos(batch, end); err != nil {
+		return 0, 0, err
+	}
+	if err := batch.Write(); err != nil {
+		return 0, 0, err
+	}
+	for _, stale := range stales {
+		c.caches.rawBlocks.Remove(stale.id)
+		c.caches.receipts.Remove(stale.id)
+		c.ancestorTrie.rootsCache.Remove(stale.id)
+	}
+	return len(stales), end, nil
+}
+
+func loadBranchGCPos(r kv.Getter) (uint32, error) {
+	data, err := r.Get(branchGCPosKey)
+	if err != nil {
+		if r.IsNotFound(err) {
+			// genesis never pruned
+			return 1, nil
+		}
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
+
+func saveBranchGCPos(w kv.Putter, pos uint32) error {
+	return w.Put(branchGCPosKey, numberAsKey(pos))
+}
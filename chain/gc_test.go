@@ -0,0 +1,97 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func newTx(chainTag byte, nonce uint64) *tx.Transaction {
+	trx := new(tx.Builder).ChainTag(chainTag).Nonce(nonce).Build()
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), privateKey)
+	return trx.WithSignature(sig)
+}
+
+func newBlockWithTxs(parent *block.Block, score uint64, txs ...*tx.Transaction) *block.Block {
+	builder := new(block.Builder).ParentID(parent.Header().ID()).TotalScore(parent.Header().TotalScore() + score)
+	for _, tx := range txs {
+		builder.Transaction(tx)
+	}
+	b := builder.Build()
+	sig, _ := crypto.Sign(b.Header().SigningHash().Bytes(), privateKey)
+	return b.WithSignature(sig)
+}
+
+func TestPruneBranches(t *testing.T) {
+	ch := initChain()
+	shared := newTx(ch.Tag(), 1)
+	orphan := newTx(ch.Tag(), 2)
+
+	b0 := ch.GenesisBlock()
+	b1 := newBlock(b0, 2)
+	b2 := newBlock(b1, 2)
+	b2x := newBlockWithTxs(b1, 1, shared, orphan)
+	b3 := newBlockWithTxs(b2, 2, shared)
+	b4 := newBlock(b3, 2)
+	b4x := newBlock(b3, 1)
+	b5 := newBlock(b4, 2)
+
+	for _, b := range []*block.Block{b1, b2, b2x, b3, b4, b4x, b5} {
+		receipts := make(tx.Receipts, len(b.Transactions()))
+		for i := range receipts {
+			receipts[i] = &tx.Receipt{}
+		}
+		if _, err := ch.AddBlock(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, b5.Header().ID(), ch.BestBlock().Header().ID())
+
+	// nothing below horizon
+	n, err := ch.PruneBranches(5)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = ch.PruneBranches(2)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	_, err = ch.GetBlockHeader(b2x.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+	_, err = ch.GetBlockReceipts(b2x.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+	_, err = ch.GetBlockHeader(b4x.Header().ID())
+	assert.Nil(t, err, "side block above horizon should be kept")
+
+	meta, err := ch.GetTrunkTransactionMeta(shared.ID())
+	assert.Nil(t, err)
+	assert.Equal(t, b3.Header().ID(), meta.BlockID)
+
+	origin, _ := shared.Signer()
+	ids, err := ch.GetTrunkTransactionsByOrigin(origin, nil, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []thor.Bytes32{shared.ID()}, ids)
+
+	// continue from last position
+	n, err = ch.PruneBranches(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	_, err = ch.GetBlockHeader(b4x.Header().ID())
+	assert.True(t, ch.IsNotFound(err))
+
+	for _, b := range []*block.Block{b0, b1, b2, b3, b4, b5} {
+		_, err := ch.GetBlockHeader(b.Header().ID())
+		assert.Nil(t, err)
+		id, err := ch.GetTrunkBlockID(b.Header().Number())
+		assert.Nil(t, err)
+		assert.Equal(t, b.Header().ID(), id)
+	}
+}
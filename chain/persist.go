@@ -19,6 +19,7 @@ var (
 	txMetaPrefix        = []byte("t") // (prefix, tx id) -> tx location
 	blockReceiptsPrefix = []byte("r") // (prefix, block id) -> receipts
 	indexTrieRootPrefix = []byte("i") // (prefix, block id) -> trie root
+	txOriginPrefix      = []byte("o") // (prefix, origin, block number, tx id) -> empty
 )
 
 // TxMeta contains information about a tx is settled.
@@ -99,6 +100,15 @@ func loadTxMeta(r kv.Getter, txID thor.Bytes32) ([]TxMeta, error) {
 	return meta, nil
 }
 
+// txOriginKey returns the key indexing the tx by its origin, sorted by block number.
+func txOriginKey(origin thor.Address, blockNum uint32, txID thor.Bytes32) []byte {
+	key := make([]byte, 0, len(txOriginPrefix)+20+4+32)
+	key = append(key, txOriginPrefix...)
+	key = append(key, origin[:]...)
+	key = append(key, numberAsKey(blockNum)...)
+	return append(key, txID[:]...)
+}
+
 // saveBlockReceipts save tx receipts of a block.
 func saveBlockReceipts(w kv.Putter, blockID thor.Bytes32, receipts tx.Receipts) error {
 	return saveRLP(w, append(blockReceiptsPrefix, blockID[:]...), receipts)
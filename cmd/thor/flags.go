@@ -50,11 +50,20 @@ var (
 		Value: 50000000,
 		Usage: "limit contract call gas",
 	}
+	apiBatchLimitFlag = cli.IntFlag{
+		Name:  "api-batch-limit",
+		Value: 50,
+		Usage: "limit count of sub requests per batch request (0 to disable batch API)",
+	}
 	apiBacktraceLimitFlag = cli.IntFlag{
 		Name:  "api-backtrace-limit",
 		Value: 1000,
 		Usage: "limit the distance between 'position' and best block for subscriptions APIs",
 	}
+	apiChecksumAddressFlag = cli.BoolFlag{
+		Name:  "api-checksum-address",
+		Usage: "output EIP-55 checksumed addresses in API responses",
+	}
 	verbosityFlag = cli.IntFlag{
 		Name:  "verbosity",
 		Value: int(log15.LvlInfo),
@@ -80,6 +89,10 @@ var (
 		Name:  "on-demand",
 		Usage: "create new block when there is pending transaction",
 	}
+	faucetFlag = cli.BoolFlag{
+		Name:  "faucet",
+		Usage: "pre-deploy faucet contract in genesis, which results in a different genesis id",
+	}
 	persistFlag = cli.BoolFlag{
 		Name:  "persist",
 		Usage: "blockchain data storage option, if set data will be saved to disk",
@@ -102,16 +115,116 @@ var (
 		Value: 0,
 		Usage: "target block gas limit (adaptive if set to 0)",
 	}
+	gcBranchesFlag = cli.IntFlag{
+		Name:  "gc-branches",
+		Value: 0,
+		Usage: "prune stale side branches older than the given count of blocks behind best (disabled if set to 0)",
+	}
 	bootNodeFlag = cli.StringFlag{
 		Name:  "bootnode",
 		Usage: "comma separated list of bootnode IDs",
 	}
+	trustedPeersFlag = cli.StringFlag{
+		Name:  "trusted-peers",
+		Usage: "comma separated list of enode URLs of trusted peers, which are always connected and never evicted",
+	}
+	txRelayFlag = cli.StringFlag{
+		Name:  "tx-relay",
+		Value: "normal",
+		Usage: "tx relay policy (normal|private|trusted), private means txs are only included by local packing",
+	}
 	pprofFlag = cli.BoolFlag{
 		Name:  "pprof",
 		Usage: "turn on go-pprof",
 	}
+	apiGasProfilingFlag = cli.BoolFlag{
+		Name:  "api-gas-profiling",
+		Usage: "aggregate gas usage of executions traced by /debug API per contract function, reported at /debug/gas-profile",
+	}
 	skipLogsFlag = cli.BoolFlag{
 		Name:  "skip-logs",
 		Usage: "skip writing event|transfer logs (/logs API will be disabled)",
 	}
+	apiSnapshotFlag = cli.BoolFlag{
+		Name:  "api-snapshot",
+		Usage: "serve API only from an offline snapshot of data dir, which is not refreshed (P2P, consensus and packer disabled); stop the node owning the data dir or use a copy of it, and use --upstream instead to follow a syncing node",
+	}
+	upstreamFlag = cli.StringFlag{
+		Name:  "upstream",
+		Usage: "run as read replica, syncing blocks from the trusted upstream node API URL instead of P2P",
+	}
+	runtimeConfigFlag = cli.StringFlag{
+		Name:  "runtime-config",
+		Usage: "path to JSON file of runtime tunable settings, reloaded on SIGHUP and updated by admin tuning API",
+	}
+	logDBKeyFlag = cli.StringFlag{
+		Name:   "logdb-key",
+		Usage:  "passphrase to encrypt log database, requires SQLCipher linked build (prefer env var to flag)",
+		EnvVar: "THOR_LOGDB_KEY",
+	}
+	logDBURLFlag = cli.StringFlag{
+		Name:  "logdb-url",
+		Usage: "URL of external log database in form of backend://source, the backend should be linked in build (local sqlite database used if not set)",
+	}
+	logDBSlowQueryFlag = cli.IntFlag{
+		Name:  "logdb-slow-query",
+		Usage: "log queries of log database slower than the threshold in milliseconds, with query plans (0 to disable)",
+	}
+	logDBMaxRowsFlag = cli.IntFlag{
+		Name:  "logdb-max-rows",
+		Usage: "max count of logs a filter query may return, queries exceeding it fail (0 for no limit)",
+	}
+	logDBMaxResultMBFlag = cli.IntFlag{
+		Name:  "logdb-max-result-mb",
+		Usage: "max size in MB of logs a filter query may return, queries exceeding it fail (0 for no limit)",
+	}
+	logDBKeepRemovedFlag = cli.BoolFlag{
+		Name:  "logdb-keep-removed",
+		Usage: "keep logs of orphaned blocks flagged as removed, rather than delete them",
+	}
+	logDBRetentionBlocksFlag = cli.IntFlag{
+		Name:  "logdb-retention-blocks",
+		Usage: "prune logs older than the given count of blocks behind best (disabled if set to 0)",
+	}
+	logDBRetentionDaysFlag = cli.IntFlag{
+		Name:  "logdb-retention-days",
+		Usage: "prune logs of blocks older than the given count of days (disabled if set to 0)",
+	}
+	tracingFlag = cli.StringFlag{
+		Name:  "tracing",
+		Usage: "export traces of API requests to OTLP/HTTP endpoint (e.g. http://localhost:4318/v1/traces), or 'log', disabled if not set",
+	}
+	telemetryFlag = cli.StringFlag{
+		Name:  "telemetry",
+		Usage: "opt in to report anonymous node statistics (version, best block, peer count and platform) to the URL hourly, disabled if not set",
+	}
+	adminAddrFlag = cli.StringFlag{
+		Name:  "admin-addr",
+		Usage: "admin API service listening address, disabled if not set (do not expose it to public)",
+	}
+	watchdogStallTimeoutFlag = cli.IntFlag{
+		Name:  "watchdog-stall-timeout",
+		Value: 300,
+		Usage: "seconds without block imported, before systemd watchdog stops being pinged (0 to ping as long as the process is responsive, e.g. on private networks)",
+	}
+	snapshotFileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "path to snapshot archive",
+	}
+	snapshotWithLogsFlag = cli.BoolFlag{
+		Name:  "with-logs",
+		Usage: "include log database in snapshot",
+	}
+	replayFromFlag = cli.UintFlag{
+		Name:  "from",
+		Usage: "number of the first block to replay",
+	}
+	replayToFlag = cli.UintFlag{
+		Name:  "to",
+		Usage: "number of the last block to replay (default best block)",
+	}
+	rebuildFromFlag = cli.UintFlag{
+		Name:  "from",
+		Usage: "number of the first block to rebuild logs from",
+	}
 )
@@ -20,9 +20,14 @@ import (
 	isatty "github.com/mattn/go-isatty"
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/analytics"
 	"github.com/vechain/thor/api"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/api/txtracker"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/cmd/thor/node"
+	"github.com/vechain/thor/cmd/thor/replica"
 	"github.com/vechain/thor/cmd/thor/solo"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/logdb"
@@ -67,18 +72,39 @@ func main() {
 			dataDirFlag,
 			beneficiaryFlag,
 			targetGasLimitFlag,
+			gcBranchesFlag,
 			apiAddrFlag,
 			apiCorsFlag,
 			apiTimeoutFlag,
 			apiCallGasLimitFlag,
+			apiBatchLimitFlag,
 			apiBacktraceLimitFlag,
+			apiChecksumAddressFlag,
 			verbosityFlag,
 			maxPeersFlag,
 			p2pPortFlag,
 			natFlag,
 			bootNodeFlag,
+			trustedPeersFlag,
+			txRelayFlag,
 			skipLogsFlag,
+			logDBKeyFlag,
+			logDBURLFlag,
+			logDBSlowQueryFlag,
+			logDBMaxRowsFlag,
+			logDBMaxResultMBFlag,
+			logDBKeepRemovedFlag,
+			logDBRetentionBlocksFlag,
+			logDBRetentionDaysFlag,
 			pprofFlag,
+			apiGasProfilingFlag,
+			apiSnapshotFlag,
+			upstreamFlag,
+			runtimeConfigFlag,
+			tracingFlag,
+			telemetryFlag,
+			adminAddrFlag,
+			watchdogStallTimeoutFlag,
 		},
 		Action: defaultAction,
 		Commands: []cli.Command{
@@ -91,12 +117,25 @@ func main() {
 					apiCorsFlag,
 					apiTimeoutFlag,
 					apiCallGasLimitFlag,
+					apiBatchLimitFlag,
 					apiBacktraceLimitFlag,
+					apiChecksumAddressFlag,
 					onDemandFlag,
 					persistFlag,
+					faucetFlag,
+					logDBKeyFlag,
+					logDBURLFlag,
+					logDBSlowQueryFlag,
+					logDBMaxRowsFlag,
+					logDBMaxResultMBFlag,
+					logDBKeepRemovedFlag,
 					gasLimitFlag,
 					verbosityFlag,
 					pprofFlag,
+					apiGasProfilingFlag,
+					runtimeConfigFlag,
+					tracingFlag,
+					adminAddrFlag,
 				},
 				Action: soloAction,
 			},
@@ -110,6 +149,61 @@ func main() {
 				},
 				Action: masterKeyAction,
 			},
+			{
+				Name:  "snapshot",
+				Usage: "create or restore snapshot of chain data (node should be stopped)",
+				Subcommands: []cli.Command{
+					{
+						Name:  "create",
+						Usage: "create snapshot archive at best block",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							verbosityFlag,
+							snapshotFileFlag,
+							snapshotWithLogsFlag,
+							logDBKeyFlag,
+						},
+						Action: snapshotCreateAction,
+					},
+					{
+						Name:  "restore",
+						Usage: "restore chain data from snapshot archive",
+						Flags: []cli.Flag{
+							networkFlag,
+							dataDirFlag,
+							verbosityFlag,
+							snapshotFileFlag,
+						},
+						Action: snapshotRestoreAction,
+					},
+				},
+			},
+			{
+				Name:  "replay",
+				Usage: "re-execute blocks against stored parent states and verify results (node should be stopped)",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					verbosityFlag,
+					replayFromFlag,
+					replayToFlag,
+				},
+				Action: replayAction,
+			},
+			{
+				Name:  "rebuild-logs",
+				Usage: "rebuild log database from receipts in chain data (node should be stopped)",
+				Flags: []cli.Flag{
+					networkFlag,
+					dataDirFlag,
+					verbosityFlag,
+					logDBKeyFlag,
+					logDBURLFlag,
+					rebuildFromFlag,
+				},
+				Action: rebuildLogsAction,
+			},
 		},
 	}
 
@@ -120,11 +214,18 @@ func main() {
 }
 
 func defaultAction(ctx *cli.Context) error {
+	if ctx.Bool(apiSnapshotFlag.Name) {
+		return apiSnapshotAction(ctx)
+	}
+	if ctx.String(upstreamFlag.Name) != "" {
+		return replicaAction(ctx)
+	}
 	exitSignal := handleExitSignal()
 
 	defer func() { log.Info("exited") }()
 
 	initLogger(ctx)
+	defer initTracing(ctx)()
 	gene := selectGenesis(ctx)
 	instanceDir := makeInstanceDir(ctx, gene)
 
@@ -147,20 +248,52 @@ func defaultAction(ctx *cli.Context) error {
 		}
 	}
 
+	abiRegistry := loadABIRegistry(mainDB)
+
+	webhooks, err := subscriptions.NewWebhooks(chain, state.NewCreator(mainDB), abiRegistry, mainDB)
+	if err != nil {
+		fatal(fmt.Sprintf("load webhooks: %v", err))
+	}
+	defer func() { log.Info("stopping webhooks..."); webhooks.Close() }()
+
 	txPool := txpool.New(chain, state.NewCreator(mainDB), defaultTxPoolOptions)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
 	p2pcom := newP2PComm(ctx, chain, txPool, instanceDir)
+
+	allowedOrigins := utils.NewAllowedOrigins(ctx.String(apiCorsFlag.Name))
+	tunables := &runtimeTunables{
+		allowedOrigins: allowedOrigins,
+		txPool:         txPool,
+		comm:           p2pcom.comm,
+		p2pSrv:         p2pcom.p2pSrv,
+		configPath:     ctx.String(runtimeConfigFlag.Name),
+	}
+	handleReloadSignal(exitSignal, tunables)
+	stats := analytics.New(chain, state.NewCreator(mainDB), mainDB)
+	defer func() { log.Info("stopping analytics..."); stats.Close() }()
+
+	txTracker, err := txtracker.New(chain, txPool, mainDB)
+	if err != nil {
+		fatal(fmt.Sprintf("load tx tracker: %v", err))
+	}
+	defer func() { log.Info("stopping tx tracker..."); txTracker.Close() }()
+
 	apiHandler, apiCloser := api.New(
 		chain,
 		state.NewCreator(mainDB),
 		txPool,
 		logDB,
 		p2pcom.comm,
-		ctx.String(apiCorsFlag.Name),
+		stats,
+		txTracker,
+		abiRegistry,
+		allowedOrigins,
 		uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
 		uint64(ctx.Int(apiCallGasLimitFlag.Name)),
+		ctx.Int(apiBatchLimitFlag.Name),
 		ctx.Bool(pprofFlag.Name),
+		ctx.Bool(apiGasProfilingFlag.Name),
 		skipLogs)
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
@@ -172,6 +305,18 @@ func defaultAction(ctx *cli.Context) error {
 	p2pcom.Start()
 	defer p2pcom.Stop()
 
+	startTelemetry(exitSignal, ctx, instanceDir, chain, p2pcom.comm.PeerCount)
+
+	if addr := ctx.String(adminAddrFlag.Name); addr != "" {
+		adminURL, adminSrvCloser := startAdminServer(addr, p2pcom, webhooks, abiRegistry, tunables)
+		defer func() { log.Info("stopping admin API server..."); adminSrvCloser() }()
+		log.Info("admin API started", "url", adminURL)
+	}
+
+	sdNotify("READY=1")
+	defer sdNotify("STOPPING=1")
+	runWatchdog(exitSignal, chain, p2pcom.comm.PeerCount, time.Duration(ctx.Int(watchdogStallTimeoutFlag.Name))*time.Second)
+
 	return node.New(
 		master,
 		chain,
@@ -180,7 +325,136 @@ func defaultAction(ctx *cli.Context) error {
 		txPool,
 		filepath.Join(instanceDir, "tx.stash"),
 		p2pcom.comm,
+		stats,
 		uint64(ctx.Int(targetGasLimitFlag.Name)),
+		branchGCHorizon(ctx),
+		logRetention(ctx),
+		skipLogs).
+		Run(exitSignal)
+}
+
+// apiSnapshotAction serves API from an offline snapshot of data dir. Read traffic is scaled by
+// read replicas following a syncing node (see replicaAction) instead.
+func apiSnapshotAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	defer func() { log.Info("exited") }()
+
+	initLogger(ctx)
+	defer initTracing(ctx)()
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openReadOnlyMainDB(ctx, instanceDir)
+	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+
+	skipLogs := ctx.Bool(skipLogsFlag.Name)
+
+	var logDB logdb.Store
+	if !skipLogs {
+		logDB = openReadOnlyLogDB(ctx, instanceDir)
+		defer func() { log.Info("closing log database..."); logDB.Close() }()
+	}
+
+	chain := initReadOnlyChain(gene, mainDB)
+
+	allowedOrigins := utils.NewAllowedOrigins(ctx.String(apiCorsFlag.Name))
+	handleReloadSignal(exitSignal, &runtimeTunables{allowedOrigins: allowedOrigins, configPath: ctx.String(runtimeConfigFlag.Name)})
+
+	// nothing recorded, since main db is read only
+	stats := analytics.New(chain, state.NewCreator(mainDB), nil)
+	defer stats.Close()
+
+	// no tx pool, since txs can't be broadcast without P2P
+	apiHandler, apiCloser := api.New(
+		chain,
+		state.NewCreator(mainDB),
+		nil,
+		logDB,
+		solo.Communicator{},
+		stats,
+		nil,
+		loadABIRegistry(mainDB),
+		allowedOrigins,
+		uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
+		uint64(ctx.Int(apiCallGasLimitFlag.Name)),
+		ctx.Int(apiBatchLimitFlag.Name),
+		ctx.Bool(pprofFlag.Name),
+		ctx.Bool(apiGasProfilingFlag.Name),
+		skipLogs)
+	defer func() { log.Info("closing API..."); apiCloser() }()
+
+	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
+	defer func() { log.Info("stopping API server..."); srvCloser() }()
+
+	printAPISnapshotStartupMessage(gene, chain, instanceDir, apiURL)
+
+	<-exitSignal.Done()
+	return nil
+}
+
+func replicaAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	defer func() { log.Info("exited") }()
+
+	initLogger(ctx)
+	defer initTracing(ctx)()
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+	upstream := ctx.String(upstreamFlag.Name)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer func() { log.Info("closing main database..."); mainDB.Close() }()
+
+	skipLogs := ctx.Bool(skipLogsFlag.Name)
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer func() { log.Info("closing log database..."); logDB.Close() }()
+
+	chain := initChain(gene, mainDB, logDB)
+
+	if !skipLogs {
+		if err := syncLogDB(exitSignal, chain, logDB); err != nil {
+			return err
+		}
+	}
+
+	allowedOrigins := utils.NewAllowedOrigins(ctx.String(apiCorsFlag.Name))
+	handleReloadSignal(exitSignal, &runtimeTunables{allowedOrigins: allowedOrigins, configPath: ctx.String(runtimeConfigFlag.Name)})
+
+	stats := analytics.New(chain, state.NewCreator(mainDB), mainDB)
+	defer func() { log.Info("stopping analytics..."); stats.Close() }()
+
+	// no tx pool, since txs can't be broadcast without P2P
+	apiHandler, apiCloser := api.New(
+		chain,
+		state.NewCreator(mainDB),
+		nil,
+		logDB,
+		solo.Communicator{},
+		stats,
+		nil,
+		loadABIRegistry(mainDB),
+		allowedOrigins,
+		uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
+		uint64(ctx.Int(apiCallGasLimitFlag.Name)),
+		ctx.Int(apiBatchLimitFlag.Name),
+		ctx.Bool(pprofFlag.Name),
+		ctx.Bool(apiGasProfilingFlag.Name),
+		skipLogs)
+	defer func() { log.Info("closing API..."); apiCloser() }()
+
+	apiURL, srvCloser := startAPIServer(ctx, apiHandler, chain.GenesisBlock().Header().ID())
+	defer func() { log.Info("stopping API server..."); srvCloser() }()
+
+	printReplicaStartupMessage(gene, chain, instanceDir, upstream, apiURL)
+
+	return replica.New(
+		chain,
+		state.NewCreator(mainDB),
+		logDB,
+		upstream,
 		skipLogs).
 		Run(exitSignal)
 }
@@ -190,10 +464,11 @@ func soloAction(ctx *cli.Context) error {
 	defer func() { log.Info("exited") }()
 
 	initLogger(ctx)
-	gene := genesis.NewDevnet()
+	defer initTracing(ctx)()
+	gene := genesis.NewDevnetWithOptions(genesis.DevnetOptions{Faucet: ctx.Bool(faucetFlag.Name)})
 
 	var mainDB *lvldb.LevelDB
-	var logDB *logdb.LogDB
+	var logDB logdb.Store
 	var instanceDir string
 
 	if ctx.Bool("persist") {
@@ -203,7 +478,7 @@ func soloAction(ctx *cli.Context) error {
 	} else {
 		instanceDir = "Memory"
 		mainDB = openMemMainDB()
-		logDB = openMemLogDB()
+		logDB = openMemLogDB(ctx)
 	}
 
 	defer func() { log.Info("closing main database..."); mainDB.Close() }()
@@ -217,16 +492,33 @@ func soloAction(ctx *cli.Context) error {
 	txPool := txpool.New(chain, state.NewCreator(mainDB), defaultTxPoolOptions)
 	defer func() { log.Info("closing tx pool..."); txPool.Close() }()
 
+	allowedOrigins := utils.NewAllowedOrigins(ctx.String(apiCorsFlag.Name))
+	handleReloadSignal(exitSignal, &runtimeTunables{allowedOrigins: allowedOrigins, txPool: txPool, configPath: ctx.String(runtimeConfigFlag.Name)})
+
+	stats := analytics.New(chain, state.NewCreator(mainDB), mainDB)
+	defer func() { log.Info("stopping analytics..."); stats.Close() }()
+
+	txTracker, err := txtracker.New(chain, txPool, mainDB)
+	if err != nil {
+		fatal(fmt.Sprintf("load tx tracker: %v", err))
+	}
+	defer func() { log.Info("stopping tx tracker..."); txTracker.Close() }()
+
 	apiHandler, apiCloser := api.New(
 		chain,
 		state.NewCreator(mainDB),
 		txPool,
 		logDB,
 		solo.Communicator{},
-		ctx.String(apiCorsFlag.Name),
+		stats,
+		txTracker,
+		loadABIRegistry(mainDB),
+		allowedOrigins,
 		uint32(ctx.Int(apiBacktraceLimitFlag.Name)),
 		uint64(ctx.Int(apiCallGasLimitFlag.Name)),
+		ctx.Int(apiBatchLimitFlag.Name),
 		ctx.Bool(pprofFlag.Name),
+		ctx.Bool(apiGasProfilingFlag.Name),
 		false)
 	defer func() { log.Info("closing API..."); apiCloser() }()
 
@@ -235,12 +527,20 @@ func soloAction(ctx *cli.Context) error {
 
 	printSoloStartupMessage(gene, chain, instanceDir, apiURL)
 
-	return solo.New(chain,
+	s := solo.New(chain,
 		state.NewCreator(mainDB),
 		logDB,
 		txPool,
 		uint64(ctx.Int("gas-limit")),
-		ctx.Bool("on-demand")).Run(exitSignal)
+		ctx.Bool("on-demand"))
+
+	if addr := ctx.String(adminAddrFlag.Name); addr != "" {
+		adminURL, adminSrvCloser := startSoloAdminServer(addr, s)
+		defer func() { log.Info("stopping admin API server..."); adminSrvCloser() }()
+		log.Info("admin API started", "url", adminURL)
+	}
+
+	return s.Run(exitSignal)
 }
 
 func masterKeyAction(ctx *cli.Context) error {
@@ -327,18 +627,19 @@ func masterKeyAction(ctx *cli.Context) error {
 	return nil
 }
 
-func syncLogDB(ctx context.Context, chain *chain.Chain, logDB *logdb.LogDB) error {
+func syncLogDB(ctx context.Context, chain *chain.Chain, logDB logdb.Store) error {
 	bestBlockNum := chain.BestBlock().Header().Number()
 	if bestBlockNum == 0 {
 		return nil
 	}
 
-	pos, err := logDB.QueryLastBlockNumber()
+	// drop logs ahead of or diverged from the chain, left by crashes
+	pos, err := node.RepairLogDB(chain, logDB)
 	if err != nil {
-		return errors.Wrap(err, "get last synced block number")
+		return errors.Wrap(err, "repair logdb")
 	}
 
-	if pos >= bestBlockNum {
+	if pos > bestBlockNum {
 		return nil
 	}
 
@@ -354,29 +655,8 @@ func syncLogDB(ctx context.Context, chain *chain.Chain, logDB *logdb.LogDB) erro
 	defer func() { pb.NotPrint = true }()
 
 	for ; pos <= bestBlockNum; pos++ {
-		block, err := chain.GetTrunkBlock(pos)
-		if err != nil {
-			return errors.Wrap(err, "get trunk block")
-		}
-		txs := block.Transactions()
-		if len(txs) > 0 {
-			receipts, err := chain.GetBlockReceipts(block.Header().ID())
-			if err != nil {
-				return errors.Wrap(err, "get block receipts")
-			}
-
-			batch := logDB.Prepare(block.Header())
-
-			for i, tx := range txs {
-				origin, _ := tx.Signer()
-				txBatch := batch.ForTransaction(tx.ID(), origin)
-				for j, output := range receipts[i].Outputs {
-					txBatch.Insert(output.Events, output.Transfers, uint32(j))
-				}
-			}
-			if err := batch.Commit(); err != nil {
-				return errors.Wrap(err, "commit logs")
-			}
+		if err := node.CommitLogs(chain, logDB, pos); err != nil {
+			return err
 		}
 
 		pb.Set64(int64(pos))
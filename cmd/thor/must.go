@@ -6,12 +6,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -24,9 +27,14 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/p2p/nat"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/api/abis"
+	"github.com/vechain/thor/api/admin"
+	"github.com/vechain/thor/api/utils"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/cmd/thor/node"
+	"github.com/vechain/thor/cmd/thor/solo"
 	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/comm"
 	"github.com/vechain/thor/genesis"
@@ -34,20 +42,71 @@ import (
 	"github.com/vechain/thor/lvldb"
 	"github.com/vechain/thor/p2psrv"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/telemetry"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tracing"
 	"github.com/vechain/thor/txpool"
 	cli "gopkg.in/urfave/cli.v1"
 )
 
 func initLogger(ctx *cli.Context) {
-	logLevel := ctx.Int(verbosityFlag.Name)
-	log15.Root().SetHandler(log15.LvlFilterHandler(log15.Lvl(logLevel), log15.StderrHandler))
+	setLogLevel(ctx.Int(verbosityFlag.Name))
 	// set go-ethereum log lvl to Warn
 	ethLogHandler := ethlog.NewGlogHandler(ethlog.StreamHandler(os.Stderr, ethlog.TerminalFormat(true)))
 	ethLogHandler.Verbosity(ethlog.LvlWarn)
 	ethlog.Root().SetHandler(ethLogHandler)
 }
 
+// initTracing sets up the trace exporter, and returns the function to flush and stop it.
+func initTracing(ctx *cli.Context) func() {
+	switch endpoint := ctx.String(tracingFlag.Name); endpoint {
+	case "":
+		return func() {}
+	case "log":
+		tracing.SetExporter(tracing.LogExporter{})
+		return func() {}
+	default:
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			fatal(fmt.Sprintf("invalid tracing endpoint [%v]", endpoint))
+		}
+		exporter := tracing.NewOTLPExporter(endpoint, "thor")
+		tracing.SetExporter(exporter)
+		log.Info("tracing enabled", "endpoint", endpoint)
+		return func() {
+			tracing.SetExporter(nil)
+			exporter.Close()
+		}
+	}
+}
+
+// interval of telemetry reports
+const telemetryInterval = time.Hour
+
+// startTelemetry starts reporting node statistics in background until ctx done, if opted in.
+func startTelemetry(ctx context.Context, cliCtx *cli.Context, instanceDir string, chain *chain.Chain, peerCount func() int) {
+	endpoint := cliCtx.String(telemetryFlag.Name)
+	if endpoint == "" {
+		return
+	}
+	if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		fatal(fmt.Sprintf("invalid telemetry endpoint [%v]", endpoint))
+	}
+	instanceID, err := telemetry.LoadOrCreateInstanceID(filepath.Join(instanceDir, "telemetry.id"))
+	if err != nil {
+		fatal("load or create telemetry instance id:", err)
+	}
+	version := fullVersion()
+	reporter := telemetry.New(endpoint, telemetryInterval, func() *telemetry.Report {
+		return telemetry.NewReport(instanceID, version, chain, peerCount())
+	})
+	go reporter.Run(ctx)
+	log.Info("telemetry enabled", "endpoint", endpoint, "instance", instanceID)
+}
+
+func setLogLevel(logLevel int) {
+	log15.Root().SetHandler(log15.LvlFilterHandler(log15.Lvl(logLevel), log15.StderrHandler))
+}
+
 func selectGenesis(ctx *cli.Context) *genesis.Genesis {
 	network := ctx.String(networkFlag.Name)
 
@@ -120,6 +179,16 @@ func makeInstanceDir(ctx *cli.Context, gene *genesis.Genesis) string {
 }
 
 func openMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
+	return openLevelDB(filepath.Join(dataDir, "main.db"), false)
+}
+
+// openReadOnlyMainDB opens main db of an offline snapshot. The db lock is still taken, so it can't be
+// opened beside a running node, and blocks are not refreshed since nothing writes the snapshot.
+func openReadOnlyMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
+	return openLevelDB(filepath.Join(dataDir, "main.db"), true)
+}
+
+func openLevelDB(dir string, readOnly bool) *lvldb.LevelDB {
 	limit, err := fdlimit.Current()
 	if err != nil {
 		fatal("failed to get fd limit:", err)
@@ -133,27 +202,87 @@ func openMainDB(ctx *cli.Context, dataDir string) *lvldb.LevelDB {
 		fileCache = 1024
 	}
 
-	dir := filepath.Join(dataDir, "main.db")
 	db, err := lvldb.New(dir, lvldb.Options{
 		CacheSize:              256,
 		OpenFilesCacheCapacity: fileCache,
+		ReadOnly:               readOnly,
 	})
 	if err != nil {
+		if readOnly {
+			fatal(fmt.Sprintf("open chain database [%v]: %v (stop the node using it, or serve a copy of the data dir)", dir, err))
+		}
 		fatal(fmt.Sprintf("open chain database [%v]: %v", dir, err))
 	}
 	return db
 }
 
-func openLogDB(ctx *cli.Context, dataDir string) *logdb.LogDB {
+func openLogDB(ctx *cli.Context, dataDir string) logdb.Store {
+	if url := ctx.String(logDBURLFlag.Name); url != "" {
+		db, err := logdb.Open(url)
+		if err != nil {
+			fatal(fmt.Sprintf("open log database [%v]: %v", url, err))
+		}
+		return db
+	}
+	return openLocalLogDB(ctx, dataDir)
+}
+
+// openLocalLogDB opens the sqlite log db in the data dir.
+func openLocalLogDB(ctx *cli.Context, dataDir string) *logdb.LogDB {
 	dir := filepath.Join(dataDir, "logs-v2.db")
-	db, err := logdb.New(dir)
+	var (
+		db  *logdb.LogDB
+		err error
+	)
+	if key := ctx.String(logDBKeyFlag.Name); key != "" {
+		db, err = logdb.NewEncrypted(dir, key)
+	} else {
+		db, err = logdb.New(dir)
+	}
 	if err != nil {
 		fatal(fmt.Sprintf("open log database [%v]: %v", dir, err))
 	}
+	configureLogDB(ctx, db)
 	return db
 }
 
-func initChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, logDB *logdb.LogDB) *chain.Chain {
+func openReadOnlyLogDB(ctx *cli.Context, dataDir string) logdb.Store {
+	if url := ctx.String(logDBURLFlag.Name); url != "" {
+		// read-only is up to the privilege of the url
+		db, err := logdb.Open(url)
+		if err != nil {
+			fatal(fmt.Sprintf("open log database [%v]: %v", url, err))
+		}
+		return db
+	}
+	dir := filepath.Join(dataDir, "logs-v2.db")
+	var (
+		db  *logdb.LogDB
+		err error
+	)
+	if key := ctx.String(logDBKeyFlag.Name); key != "" {
+		db, err = logdb.NewReadOnlyEncrypted(dir, key)
+	} else {
+		db, err = logdb.NewReadOnly(dir)
+	}
+	if err != nil {
+		fatal(fmt.Sprintf("open log database [%v]: %v", dir, err))
+	}
+	configureLogDB(ctx, db)
+	return db
+}
+
+// configureLogDB applies options of log db queries.
+func configureLogDB(ctx *cli.Context, db *logdb.LogDB) {
+	db.SetSlowQueryThreshold(time.Duration(ctx.Int(logDBSlowQueryFlag.Name)) * time.Millisecond)
+	db.SetQueryLimits(logdb.QueryLimits{
+		MaxRows:  uint64(ctx.Int(logDBMaxRowsFlag.Name)),
+		MaxBytes: uint64(ctx.Int(logDBMaxResultMBFlag.Name)) * 1024 * 1024,
+	})
+	db.SetKeepRemoved(ctx.Bool(logDBKeepRemovedFlag.Name))
+}
+
+func initChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, logDB logdb.Store) *chain.Chain {
 	genesisBlock, genesisEvents, err := gene.Build(state.NewCreator(mainDB))
 	if err != nil {
 		fatal("build genesis block: ", err)
@@ -164,7 +293,7 @@ func initChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, logDB *logdb.LogDB)
 		fatal("initialize block chain:", err)
 	}
 
-	if err := logDB.Prepare(genesisBlock.Header()).
+	if err := logdb.NewBlockBatch(logDB, genesisBlock.Header()).
 		ForTransaction(thor.Bytes32{}, thor.Address{}).
 		Insert(genesisEvents, nil, 0).Commit(); err != nil {
 		fatal("write genesis events: ", err)
@@ -172,6 +301,35 @@ func initChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB, logDB *logdb.LogDB)
 	return chain
 }
 
+// initReadOnlyChain opens the existing chain in main db without writing anything.
+func initReadOnlyChain(gene *genesis.Genesis, mainDB *lvldb.LevelDB) *chain.Chain {
+	// genesis block is built on a scratch db, since main db is not writable
+	memDB, err := lvldb.NewMem()
+	if err != nil {
+		fatal("open scratch database:", err)
+	}
+	defer memDB.Close()
+
+	genesisBlock, _, err := gene.Build(state.NewCreator(memDB))
+	if err != nil {
+		fatal("build genesis block: ", err)
+	}
+
+	chain, err := chain.New(mainDB, genesisBlock)
+	if err != nil {
+		fatal("initialize block chain:", err)
+	}
+	return chain
+}
+
+func loadABIRegistry(mainDB *lvldb.LevelDB) *abis.Registry {
+	registry, err := abis.New(mainDB)
+	if err != nil {
+		fatal(fmt.Sprintf("load ABIs: %v", err))
+	}
+	return registry
+}
+
 func masterKeyPath(ctx *cli.Context) string {
 	configDir := makeConfigDir(ctx)
 	return filepath.Join(configDir, "master.key")
@@ -189,6 +347,37 @@ func beneficiary(ctx *cli.Context) *thor.Address {
 	return &addr
 }
 
+func branchGCHorizon(ctx *cli.Context) uint32 {
+	horizon := ctx.Int(gcBranchesFlag.Name)
+	if horizon == 0 {
+		return 0
+	}
+	// side branches within finalized depth may still be reorganized onto trunk
+	if horizon < int(utils.FinalizedDepth) || horizon > math.MaxUint32 {
+		fatal(fmt.Sprintf("invalid %v: should be in range [%v, %v]", gcBranchesFlag.Name, utils.FinalizedDepth, uint32(math.MaxUint32)))
+	}
+	return uint32(horizon)
+}
+
+func logRetention(ctx *cli.Context) node.LogRetention {
+	blocks := ctx.Int(logDBRetentionBlocksFlag.Name)
+	if blocks < 0 || blocks > math.MaxUint32 {
+		fatal(fmt.Sprintf("invalid %v: should be in range [0, %v]", logDBRetentionBlocksFlag.Name, uint32(math.MaxUint32)))
+	}
+	// logs within finalized depth may still be reverted and replayed
+	if blocks > 0 && blocks < int(utils.FinalizedDepth) {
+		fatal(fmt.Sprintf("invalid %v: should be 0 or not less than %v", logDBRetentionBlocksFlag.Name, utils.FinalizedDepth))
+	}
+	days := ctx.Int(logDBRetentionDaysFlag.Name)
+	if days < 0 {
+		fatal(fmt.Sprintf("invalid %v: should not be negative", logDBRetentionDaysFlag.Name))
+	}
+	return node.LogRetention{
+		Blocks: uint32(blocks),
+		Age:    time.Duration(days) * 24 * time.Hour,
+	}
+}
+
 func loadNodeMaster(ctx *cli.Context) *node.Master {
 	if ctx.String(networkFlag.Name) == "dev" {
 		i := rand.Intn(len(genesis.DevAccounts()))
@@ -211,6 +400,7 @@ type p2pComm struct {
 	comm           *comm.Communicator
 	p2pSrv         *p2psrv.Server
 	peersCachePath string
+	bansCachePath  string
 }
 
 func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, instanceDir string) *p2pComm {
@@ -231,6 +421,7 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 		MaxPeers:       ctx.Int(maxPeersFlag.Name),
 		ListenAddr:     fmt.Sprintf(":%v", ctx.Int(p2pPortFlag.Name)),
 		BootstrapNodes: bootstrapNodes,
+		TrustedNodes:   parseTrustedPeers(ctx),
 		NAT:            nat,
 	}
 
@@ -260,13 +451,55 @@ func newP2PComm(ctx *cli.Context, chain *chain.Chain, txPool *txpool.TxPool, ins
 		}
 	}
 
+	communicator := comm.New(chain, txPool)
+	txRelayPolicy, err := comm.ParseTxRelayPolicy(ctx.String(txRelayFlag.Name))
+	if err != nil {
+		fatal(fmt.Sprintf("parse -%v flag: %v", txRelayFlag.Name, err))
+	}
+	communicator.SetTxRelayPolicy(txRelayPolicy)
+
+	bansCachePath := filepath.Join(instanceDir, "bans.cache")
+	if data, err := ioutil.ReadFile(bansCachePath); err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("failed to load bans cache", "err", err)
+		}
+	} else {
+		var bans []*comm.Ban
+		if err := rlp.DecodeBytes(data, &bans); err != nil {
+			log.Warn("failed to load bans cache", "err", err)
+		} else {
+			communicator.RestoreBans(bans)
+		}
+	}
+
 	return &p2pComm{
-		comm:           comm.New(chain, txPool),
+		comm:           communicator,
 		p2pSrv:         p2psrv.New(opts),
 		peersCachePath: peersCachePath,
+		bansCachePath:  bansCachePath,
 	}
 }
 
+// PeersStats returns stats of connected peers.
+func (p *p2pComm) PeersStats() []*comm.PeerStats {
+	return p.comm.PeersStats()
+}
+
+// AddTrustedPeer adds the node as trusted peer and connects to it.
+func (p *p2pComm) AddTrustedPeer(node *discover.Node) {
+	p.p2pSrv.AddTrustedPeer(node)
+}
+
+// DisconnectPeer disconnects the connected peer.
+func (p *p2pComm) DisconnectPeer(nodeID discover.NodeID) bool {
+	return p.comm.DisconnectPeer(nodeID)
+}
+
+// BanPeer bans the node for the given duration.
+func (p *p2pComm) BanPeer(nodeID discover.NodeID, duration time.Duration) {
+	p.comm.BanPeer(nodeID, duration)
+}
+
 func (p *p2pComm) Start() {
 	log.Info("starting P2P networking")
 	if err := p.p2pSrv.Start(p.comm.Protocols()); err != nil {
@@ -287,11 +520,16 @@ func (p *p2pComm) Stop() {
 	data, err := rlp.EncodeToBytes(nodes)
 	if err != nil {
 		log.Warn("failed to encode cached peers", "err", err)
-		return
-	}
-	if err := ioutil.WriteFile(p.peersCachePath, data, 0600); err != nil {
+	} else if err := ioutil.WriteFile(p.peersCachePath, data, 0600); err != nil {
 		log.Warn("failed to write peers cache", "err", err)
 	}
+
+	log.Info("saving bans cache...")
+	if data, err := rlp.EncodeToBytes(p.comm.Bans()); err != nil {
+		log.Warn("failed to encode bans", "err", err)
+	} else if err := ioutil.WriteFile(p.bansCachePath, data, 0600); err != nil {
+		log.Warn("failed to write bans cache", "err", err)
+	}
 }
 
 func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID thor.Bytes32) (string, func()) {
@@ -300,6 +538,8 @@ func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID thor.Bytes
 	if err != nil {
 		fatal(fmt.Sprintf("listen API addr [%v]: %v", addr, err))
 	}
+	thor.SetChecksumedJSON(ctx.Bool(apiChecksumAddressFlag.Name))
+
 	timeout := ctx.Int(apiTimeoutFlag.Name)
 	if timeout > 0 {
 		handler = handleAPITimeout(handler, time.Duration(timeout)*time.Millisecond)
@@ -318,6 +558,34 @@ func startAPIServer(ctx *cli.Context, handler http.Handler, genesisID thor.Bytes
 	}
 }
 
+func startAdminServer(addr string, nw admin.Network, webhooks admin.Webhooks, abis admin.ABIs, tunables admin.Tunables) (string, func()) {
+	router := mux.NewRouter()
+	admin.New(nw, webhooks, abis, tunables).Mount(router, "/admin")
+	return serveAdmin(addr, router)
+}
+
+func startSoloAdminServer(addr string, s *solo.Solo) (string, func()) {
+	router := mux.NewRouter()
+	s.Mount(router, "/admin/solo")
+	return serveAdmin(addr, router)
+}
+
+func serveAdmin(addr string, router *mux.Router) (string, func()) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatal(fmt.Sprintf("listen admin API addr [%v]: %v", addr, err))
+	}
+	srv := &http.Server{Handler: requestBodyLimit(router)}
+	var goes co.Goes
+	goes.Go(func() {
+		srv.Serve(listener)
+	})
+	return "http://" + listener.Addr().String() + "/admin", func() {
+		srv.Close()
+		goes.Wait()
+	}
+}
+
 func printStartupMessage1(
 	gene *genesis.Genesis,
 	chain *chain.Chain,
@@ -367,14 +635,64 @@ func openMemMainDB() *lvldb.LevelDB {
 	return db
 }
 
-func openMemLogDB() *logdb.LogDB {
+func openMemLogDB(ctx *cli.Context) *logdb.LogDB {
 	db, err := logdb.NewMem()
 	if err != nil {
 		fatal(fmt.Sprintf("open log database: %v", err))
 	}
+	configureLogDB(ctx, db)
 	return db
 }
 
+func printAPISnapshotStartupMessage(
+	gene *genesis.Genesis,
+	chain *chain.Chain,
+	dataDir string,
+	apiURL string,
+) {
+	bestBlock := chain.BestBlock()
+
+	fmt.Printf(`Starting %v
+    Network      [ %v %v ]
+    Best block   [ %v #%v @%v ]
+    Forks        [ %v ]
+    Instance dir [ %v ]
+    API portal   [ %v ]
+`,
+		common.MakeName("Thor API-snapshot", fullVersion()),
+		gene.ID(), gene.Name(),
+		bestBlock.Header().ID(), bestBlock.Header().Number(), time.Unix(int64(bestBlock.Header().Timestamp()), 0),
+		thor.GetForkConfig(gene.ID()),
+		dataDir,
+		apiURL)
+}
+
+func printReplicaStartupMessage(
+	gene *genesis.Genesis,
+	chain *chain.Chain,
+	dataDir string,
+	upstream string,
+	apiURL string,
+) {
+	bestBlock := chain.BestBlock()
+
+	fmt.Printf(`Starting %v
+    Network      [ %v %v ]
+    Best block   [ %v #%v @%v ]
+    Forks        [ %v ]
+    Instance dir [ %v ]
+    Upstream     [ %v ]
+    API portal   [ %v ]
+`,
+		common.MakeName("Thor Replica", fullVersion()),
+		gene.ID(), gene.Name(),
+		bestBlock.Header().ID(), bestBlock.Header().Number(), time.Unix(int64(bestBlock.Header().Timestamp()), 0),
+		thor.GetForkConfig(gene.ID()),
+		dataDir,
+		upstream,
+		apiURL)
+}
+
 func printSoloStartupMessage(
 	gene *genesis.Genesis,
 	chain *chain.Chain,
@@ -428,6 +746,22 @@ func getNodeID(ctx *cli.Context) string {
 	return fmt.Sprintf("enode://%x@[extip]:%v", discover.PubkeyID(&key.PublicKey).Bytes(), ctx.Int(p2pPortFlag.Name))
 }
 
+func parseTrustedPeers(ctx *cli.Context) []*discover.Node {
+	s := strings.TrimSpace(ctx.String(trustedPeersFlag.Name))
+	if s == "" {
+		return nil
+	}
+	var nodes []*discover.Node
+	for _, url := range strings.Split(s, ",") {
+		node, err := discover.ParseNode(strings.TrimSpace(url))
+		if err != nil {
+			fatal(fmt.Sprintf("parse trusted peer [%v]: %v", url, err))
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
 func parseBootNode(ctx *cli.Context) []*discover.Node {
 	s := strings.TrimSpace(ctx.String(bootNodeFlag.Name))
 	if s == "" {
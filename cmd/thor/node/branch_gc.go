@@ -0,0 +1,38 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"time"
+)
+
+// interval to prune stale side branches
+const branchGCInterval = 10 * time.Minute
+
+// branchGCLoop periodically prunes side branches below the horizon.
+func (n *Node) branchGCLoop(ctx context.Context) {
+	log.Debug("enter branch gc loop")
+	defer log.Debug("leave branch gc loop")
+
+	ticker := time.NewTicker(branchGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			pruned, err := n.chain.PruneBranches(n.branchGCHorizon)
+			if err != nil {
+				log.Warn("failed to prune side branches", "err", err)
+			} else if pruned > 0 {
+				log.Info("pruned side branches", "blocks", pruned, "elapsed", time.Since(start))
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/tx"
+)
+
+// interval to check consistency between chain and logdb at runtime
+const logDBGuardInterval = time.Minute
+
+// RepairLogDB truncates logs which are ahead of the chain or not on the trunk, and returns
+// the number of the block from which logs should be replayed.
+// Only the latest logged block is checked against the trunk, which covers drifts left by crashes.
+func RepairLogDB(chain *chain.Chain, logDB logdb.Store) (uint32, error) {
+	best := chain.BestBlock().Header().Number()
+	pos, err := logDB.QueryLastBlockNumber()
+	if err != nil {
+		return 0, errors.WithMessage(err, "query last block number")
+	}
+	if pos > best {
+		log.Warn("logdb ahead of chain, truncating", "logdb", pos, "chain", best)
+		if err := logDB.Truncate(best); err != nil {
+			return 0, errors.WithMessage(err, "truncate logdb")
+		}
+		pos = best
+	}
+
+	for {
+		num, id, found, err := logDB.QueryLastLoggedBlock()
+		if err != nil {
+			return 0, errors.WithMessage(err, "query last logged block")
+		}
+		if !found {
+			break
+		}
+		trunkID, err := chain.GetTrunkBlockID(num)
+		if err != nil {
+			return 0, errors.WithMessage(err, "get trunk block id")
+		}
+		// genesis always matches
+		if trunkID == id {
+			break
+		}
+		log.Warn("logdb diverged from chain, truncating", "number", num, "logdb", id, "chain", trunkID)
+		if err := logDB.Truncate(num - 1); err != nil {
+			return 0, errors.WithMessage(err, "truncate logdb")
+		}
+		if pos > num-1 {
+			pos = num - 1
+		}
+	}
+	return pos + 1, nil
+}
+
+// CommitLogs writes logs of the trunk block with given number into logdb.
+func CommitLogs(chain *chain.Chain, logDB logdb.Store, num uint32) error {
+	if err := logdb.CommitTrunkBlock(logDB, chain, num); err != nil {
+		return errors.WithMessage(err, "commit logs")
+	}
+	return nil
+}
+
+func prepareLogs(logDB logdb.Store, blk *block.Block, receipts tx.Receipts) *logdb.BlockBatch {
+	batch := logdb.NewBlockBatch(logDB, blk.Header())
+	for i, tx := range blk.Transactions() {
+		origin, _ := tx.Signer()
+		txBatch := batch.ForTransaction(tx.ID(), origin)
+		for j, output := range receipts[i].Outputs {
+			txBatch.Insert(output.Events, output.Transfers, uint32(j))
+		}
+	}
+	return batch
+}
+
+// logDBGuardLoop periodically repairs drift between chain and logdb.
+func (n *Node) logDBGuardLoop(ctx context.Context) {
+	log.Debug("enter logdb guard loop")
+	defer log.Debug("leave logdb guard loop")
+
+	ticker := time.NewTicker(logDBGuardInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := n.guardLogDB(); err != nil {
+				log.Warn("failed to guard logdb", "err", err)
+			}
+		}
+	}
+}
+
+func (n *Node) guardLogDB() error {
+	n.commitLock.Lock()
+	defer n.commitLock.Unlock()
+
+	from, err := RepairLogDB(n.chain, n.logDB)
+	if err != nil {
+		return err
+	}
+	best := n.chain.BestBlock().Header().Number()
+	if from <= best {
+		log.Warn("logdb behind chain, replaying", "from", from, "to", best)
+	}
+	for num := from; num <= best; num++ {
+		if err := CommitLogs(n.chain, n.logDB, num); err != nil {
+			return err
+		}
+	}
+	return nil
+}
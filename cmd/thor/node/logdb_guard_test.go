@@ -0,0 +1,98 @@
+package node
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestRepairLogDB(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	logDB, _ := logdb.NewMem()
+	defer logDB.Close()
+
+	// pack 3 blocks with a transfer in each
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	parent := b0.Header()
+	for i := 0; i < 3; i++ {
+		trx := new(tx.Builder).
+			ChainTag(chain.Tag()).
+			Clause(tx.NewClause(&recipient).WithValue(big.NewInt(1))).
+			Expiration(100).
+			Gas(21000).
+			Nonce(uint64(i)).
+			Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[1].PrivateKey)
+		trx = trx.WithSignature(sig)
+
+		flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).
+			Schedule(parent, parent.Timestamp()+thor.BlockInterval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Nil(t, flow.Adopt(trx))
+		b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stage.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.AddBlock(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+		parent = b.Header()
+	}
+
+	from, err := RepairLogDB(chain, logDB)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), from, "empty logdb")
+
+	for num := uint32(1); num <= 3; num++ {
+		assert.Nil(t, CommitLogs(chain, logDB, num))
+	}
+	from, err = RepairLogDB(chain, logDB)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(4), from, "consistent")
+
+	// logs of a block not on the trunk, which is also ahead of the chain
+	b1, _ := chain.GetTrunkBlockID(1)
+	foreign := new(block.Builder).ParentID(b1).Build().Header()
+	assert.Nil(t, logDB.Prepare(foreign).
+		ForTransaction(thor.Bytes32{}, thor.Address{}).
+		Insert(nil, tx.Transfers{{Amount: big.NewInt(1)}}, 0).Commit())
+	ahead := new(block.Builder).ParentID(parent.ID()).Build().Header()
+	assert.Nil(t, logDB.Prepare(ahead).Commit())
+
+	from, err = RepairLogDB(chain, logDB)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), from)
+	num, id, _, _ := logDB.QueryLastLoggedBlock()
+	assert.Equal(t, uint32(1), num)
+	assert.Equal(t, b1, id)
+
+	for num := from; num <= 3; num++ {
+		assert.Nil(t, CommitLogs(chain, logDB, num))
+	}
+	transfers, err := logDB.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(transfers))
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"time"
+
+	"github.com/vechain/thor/logdb"
+)
+
+// interval to checkpoint and compact logdb
+const logDBMaintainInterval = time.Hour
+
+// logDBMaintainLoop periodically maintains logdb, if the store needs it.
+func (n *Node) logDBMaintainLoop(ctx context.Context, m logdb.Maintainer) {
+	log.Debug("enter logdb maintain loop")
+	defer log.Debug("leave logdb maintain loop")
+
+	ticker := time.NewTicker(logDBMaintainInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := m.Maintain(ctx); err != nil {
+				log.Warn("failed to maintain logdb", "err", err)
+			} else {
+				log.Debug("maintained logdb", "elapsed", time.Since(start))
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package node
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+)
+
+// interval to prune logs out of retention
+const logDBPruneInterval = 10 * time.Minute
+
+// LogRetention limits logs kept in logdb. Zero fields mean no limit.
+type LogRetention struct {
+	Blocks uint32        // count of latest blocks to keep logs of
+	Age    time.Duration // max age of blocks to keep logs of
+}
+
+// Enabled returns whether any limit is set.
+func (r LogRetention) Enabled() bool {
+	return r.Blocks > 0 || r.Age > 0
+}
+
+// logPruneCutoff returns the number of the lowest block whose logs should be kept.
+func logPruneCutoff(chain *chain.Chain, retention LogRetention, now time.Time) (uint32, error) {
+	best := chain.BestBlock().Header()
+	var cutoff uint32
+	if retention.Blocks > 0 && best.Number()+1 > retention.Blocks {
+		cutoff = best.Number() + 1 - retention.Blocks
+	}
+	if retention.Age > 0 {
+		since := now.Add(-retention.Age).Unix()
+		if since > 0 {
+			// the first trunk block not older than the age
+			var err error
+			num := uint32(sort.Search(int(best.Number())+1, func(i int) bool {
+				if err != nil {
+					return true
+				}
+				var header *block.Header
+				header, err = chain.GetTrunkBlockHeader(uint32(i))
+				return err != nil || header.Timestamp() >= uint64(since)
+			}))
+			if err != nil {
+				return 0, errors.WithMessage(err, "get trunk block header")
+			}
+			if num > cutoff {
+				cutoff = num
+			}
+		}
+	}
+	return cutoff, nil
+}
+
+// logDBPruneLoop periodically removes logs out of retention.
+func (n *Node) logDBPruneLoop(ctx context.Context) {
+	log.Debug("enter logdb prune loop")
+	defer log.Debug("leave logdb prune loop")
+
+	ticker := time.NewTicker(logDBPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff, err := logPruneCutoff(n.chain, n.logRetention, time.Now())
+			if err != nil {
+				log.Warn("failed to prune logs", "err", err)
+				continue
+			}
+			if cutoff == 0 {
+				continue
+			}
+			start := time.Now()
+			pruned, err := n.logDB.Prune(cutoff)
+			if err != nil {
+				log.Warn("failed to prune logs", "err", err)
+			} else if pruned > 0 {
+				log.Info("pruned logs", "before", cutoff, "logs", pruned, "elapsed", time.Since(start))
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+func TestLogPruneCutoff(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+
+	parent := b0.Header()
+	for i := 0; i < 10; i++ {
+		flow, err := packer.New(chain, stateC, genesis.DevAccounts()[0].Address, nil).
+			Schedule(parent, parent.Timestamp()+thor.BlockInterval)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stage.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := chain.AddBlock(b, receipts); err != nil {
+			t.Fatal(err)
+		}
+		parent = b.Header()
+	}
+	// as if now is one interval after best
+	now := time.Unix(int64(parent.Timestamp()+thor.BlockInterval), 0)
+	interval := time.Duration(thor.BlockInterval) * time.Second
+
+	tests := []struct {
+		retention LogRetention
+		want      uint32
+	}{
+		{LogRetention{}, 0},
+		{LogRetention{Blocks: 3}, 8},
+		{LogRetention{Blocks: 11}, 0},
+		{LogRetention{Blocks: 100}, 0},
+		{LogRetention{Age: 4 * interval}, 7},
+		{LogRetention{Age: 100 * interval}, 0},
+		{LogRetention{Blocks: 5, Age: 4 * interval}, 7},
+		{LogRetention{Blocks: 2, Age: 4 * interval}, 9},
+	}
+	for _, tt := range tests {
+		cutoff, err := logPruneCutoff(chain, tt.retention, now)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.want, cutoff, "%+v", tt.retention)
+	}
+}
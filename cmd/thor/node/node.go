@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/vechain/thor/analytics"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/cache"
 	"github.com/vechain/thor/chain"
@@ -40,39 +41,48 @@ type Node struct {
 	packer *packer.Packer
 	cons   *consensus.Consensus
 
-	master         *Master
-	chain          *chain.Chain
-	logDB          *logdb.LogDB
-	txPool         *txpool.TxPool
-	txStashPath    string
-	comm           *comm.Communicator
-	commitLock     sync.Mutex
-	targetGasLimit uint64
-	skipLogs       bool
+	master          *Master
+	chain           *chain.Chain
+	logDB           logdb.Store
+	txPool          *txpool.TxPool
+	txStashPath     string
+	comm            *comm.Communicator
+	stats           *analytics.Analytics
+	commitLock      sync.Mutex
+	targetGasLimit  uint64
+	branchGCHorizon uint32
+	logRetention    LogRetention
+	skipLogs        bool
 }
 
 func New(
 	master *Master,
 	chain *chain.Chain,
 	stateCreator *state.Creator,
-	logDB *logdb.LogDB,
+	logDB logdb.Store,
 	txPool *txpool.TxPool,
 	txStashPath string,
 	comm *comm.Communicator,
+	stats *analytics.Analytics,
 	targetGasLimit uint64,
+	branchGCHorizon uint32,
+	logRetention LogRetention,
 	skipLogs bool,
 ) *Node {
 	return &Node{
-		packer:         packer.New(chain, stateCreator, master.Address(), master.Beneficiary),
-		cons:           consensus.New(chain, stateCreator),
-		master:         master,
-		chain:          chain,
-		logDB:          logDB,
-		txPool:         txPool,
-		txStashPath:    txStashPath,
-		comm:           comm,
-		targetGasLimit: targetGasLimit,
-		skipLogs:       skipLogs,
+		packer:          packer.New(chain, stateCreator, master.Address(), master.Beneficiary),
+		cons:            consensus.New(chain, stateCreator),
+		master:          master,
+		chain:           chain,
+		logDB:           logDB,
+		txPool:          txPool,
+		txStashPath:     txStashPath,
+		comm:            comm,
+		stats:           stats,
+		targetGasLimit:  targetGasLimit,
+		branchGCHorizon: branchGCHorizon,
+		logRetention:    logRetention,
+		skipLogs:        skipLogs,
 	}
 }
 
@@ -82,6 +92,18 @@ func (n *Node) Run(ctx context.Context) error {
 	n.goes.Go(func() { n.houseKeeping(ctx) })
 	n.goes.Go(func() { n.txStashLoop(ctx) })
 	n.goes.Go(func() { n.packerLoop(ctx) })
+	if !n.skipLogs {
+		n.goes.Go(func() { n.logDBGuardLoop(ctx) })
+		if n.logRetention.Enabled() {
+			n.goes.Go(func() { n.logDBPruneLoop(ctx) })
+		}
+		if m, ok := n.logDB.(logdb.Maintainer); ok {
+			n.goes.Go(func() { n.logDBMaintainLoop(ctx, m) })
+		}
+	}
+	if n.branchGCHorizon > 0 {
+		n.goes.Go(func() { n.branchGCLoop(ctx) })
+	}
 
 	n.goes.Wait()
 	return nil
@@ -288,16 +310,7 @@ func (n *Node) commitBlock(newBlock *block.Block, receipts tx.Receipts) (*chain.
 		return nil, err
 	}
 	if !n.skipLogs {
-		batch := n.logDB.Prepare(newBlock.Header())
-		for i, tx := range newBlock.Transactions() {
-			origin, _ := tx.Signer()
-			txBatch := batch.ForTransaction(tx.ID(), origin)
-			for j, output := range receipts[i].Outputs {
-				txBatch.Insert(output.Events, output.Transfers, uint32(j))
-			}
-		}
-
-		if err := batch.Commit(); err != nil {
+		if err := prepareLogs(n.logDB, newBlock, receipts).Commit(); err != nil {
 			return nil, errors.Wrap(err, "commit logs")
 		}
 	}
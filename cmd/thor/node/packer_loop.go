@@ -104,7 +104,7 @@ func (n *Node) pack(flow *packer.Flow) error {
 		}
 	}
 
-	newBlock, stage, receipts, err := flow.Pack(n.master.PrivateKey)
+	newBlock, stage, receipts, breakdown, err := flow.Pack(n.master.PrivateKey)
 	if err != nil {
 		return err
 	}
@@ -121,6 +121,7 @@ func (n *Node) pack(flow *packer.Flow) error {
 	commitElapsed := mclock.Now() - startTime - execElapsed
 
 	n.processFork(fork)
+	n.stats.RecordPacked(breakdown)
 
 	if len(fork.Trunk) > 0 {
 		n.comm.BroadcastBlock(newBlock)
@@ -0,0 +1,37 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/vechain/thor/logdb"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func rebuildLogsAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openReadOnlyMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLogDB(ctx, instanceDir)
+	defer logDB.Close()
+
+	chain := initReadOnlyChain(gene, mainDB)
+	from := uint32(ctx.Uint(rebuildFromFlag.Name))
+
+	log.Info("rebuilding logs", "from", from, "best", chain.BestBlock().Header().Number())
+	if err := logdb.Rebuild(exitSignal, logDB, chain, from); err != nil {
+		return err
+	}
+	fmt.Printf("rebuilt logs from block #%v to #%v\n", from, chain.BestBlock().Header().Number())
+	return nil
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/admin"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/p2psrv"
+	"github.com/vechain/thor/txpool"
+)
+
+// runtimeConfig settings that can be reloaded without restarting.
+// Absent fields are left unchanged.
+type runtimeConfig struct {
+	Verbosity *int    `json:"verbosity"`
+	APICors   *string `json:"apiCors"`
+	admin.Tuning
+}
+
+func loadRuntimeConfig(path string) (*runtimeConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	var config runtimeConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, errors.WithMessage(err, "decode")
+	}
+	return &config, nil
+}
+
+func saveRuntimeConfig(path string, config *runtimeConfig) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// runtimeTunables components affected by runtime config.
+type runtimeTunables struct {
+	allowedOrigins *utils.AllowedOrigins
+	txPool         *txpool.TxPool     // nil if no tx pool
+	comm           *comm.Communicator // nil if no p2p
+	p2pSrv         *p2psrv.Server     // nil if no p2p
+	configPath     string             // empty if no runtime config file
+	lock           sync.Mutex
+}
+
+func (t *runtimeTunables) apply(config *runtimeConfig) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if config.Verbosity != nil {
+		setLogLevel(*config.Verbosity)
+	}
+	if config.APICors != nil {
+		t.allowedOrigins.Set(*config.APICors)
+	}
+	if t.p2pSrv != nil && config.MaxPeers != nil {
+		if err := t.p2pSrv.SetMaxPeers(*config.MaxPeers); err != nil {
+			log.Warn("invalid max peers", "err", err)
+		}
+	}
+	if t.comm != nil && config.TxRelay != nil {
+		if policy, err := comm.ParseTxRelayPolicy(*config.TxRelay); err != nil {
+			log.Warn("invalid tx relay policy", "err", err)
+		} else {
+			t.comm.SetTxRelayPolicy(policy)
+		}
+	}
+	t.applyTxPool(&config.Tuning)
+}
+
+func (t *runtimeTunables) applyTxPool(tuning *admin.Tuning) {
+	if t.txPool == nil {
+		return
+	}
+	options := t.txPool.Options()
+	if tuning.TxPoolLimit != nil {
+		options.Limit = *tuning.TxPoolLimit
+	}
+	if tuning.TxPoolLimitPerAccount != nil {
+		options.LimitPerAccount = *tuning.TxPoolLimitPerAccount
+	}
+	if tuning.TxPoolMaxLifetime != nil {
+		options.MaxLifetime = time.Duration(*tuning.TxPoolMaxLifetime) * time.Second
+	}
+	t.txPool.SetOptions(options)
+}
+
+// Tuning implements admin.Tunables.
+func (t *runtimeTunables) Tuning() *admin.Tuning {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var tuning admin.Tuning
+	if t.txPool != nil {
+		options := t.txPool.Options()
+		lifetime := uint64(options.MaxLifetime / time.Second)
+		tuning.TxPoolLimit = &options.Limit
+		tuning.TxPoolLimitPerAccount = &options.LimitPerAccount
+		tuning.TxPoolMaxLifetime = &lifetime
+	}
+	if t.p2pSrv != nil {
+		maxPeers := t.p2pSrv.MaxPeers()
+		tuning.MaxPeers = &maxPeers
+	}
+	if t.comm != nil {
+		relay := t.comm.TxRelayPolicy().String()
+		tuning.TxRelay = &relay
+	}
+	return &tuning
+}
+
+// Tune implements admin.Tunables. Nothing is applied if any field is invalid, and a bad request error returned.
+// Applied fields are merged into the runtime config file, so that they survive restarts.
+// Other errors mean fields applied but failed to be persisted.
+func (t *runtimeTunables) Tune(tuning *admin.Tuning) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, v := range []*int{tuning.TxPoolLimit, tuning.TxPoolLimitPerAccount} {
+		if v != nil && *v < 1 {
+			return utils.BadRequest(errors.New("tx pool limits should be positive"))
+		}
+	}
+	if tuning.TxPoolMaxLifetime != nil && *tuning.TxPoolMaxLifetime == 0 {
+		return utils.BadRequest(errors.New("tx pool max lifetime should be positive"))
+	}
+	var policy *comm.TxRelayPolicy
+	if tuning.TxRelay != nil {
+		p, err := comm.ParseTxRelayPolicy(*tuning.TxRelay)
+		if err != nil {
+			return utils.BadRequest(err)
+		}
+		policy = &p
+	}
+	if tuning.MaxPeers != nil {
+		if t.p2pSrv == nil {
+			return utils.BadRequest(errors.New("max peers: no p2p"))
+		}
+		if err := t.p2pSrv.SetMaxPeers(*tuning.MaxPeers); err != nil {
+			return utils.BadRequest(err)
+		}
+	}
+	if policy != nil && t.comm != nil {
+		t.comm.SetTxRelayPolicy(*policy)
+	}
+	t.applyTxPool(tuning)
+
+	if t.configPath == "" {
+		log.Warn("runtime tuning not persisted, use --" + runtimeConfigFlag.Name)
+		return nil
+	}
+	config, err := loadRuntimeConfig(t.configPath)
+	if err != nil {
+		if !os.IsNotExist(errors.Cause(err)) {
+			return errors.WithMessage(err, "applied but not persisted")
+		}
+		config = &runtimeConfig{}
+	}
+	mergeTuning(&config.Tuning, tuning)
+	if err := saveRuntimeConfig(t.configPath, config); err != nil {
+		return errors.WithMessage(err, "applied but not persisted")
+	}
+	return nil
+}
+
+// mergeTuning overrides fields of dst with non-nil fields of src.
+func mergeTuning(dst, src *admin.Tuning) {
+	if src.TxPoolLimit != nil {
+		dst.TxPoolLimit = src.TxPoolLimit
+	}
+	if src.TxPoolLimitPerAccount != nil {
+		dst.TxPoolLimitPerAccount = src.TxPoolLimitPerAccount
+	}
+	if src.TxPoolMaxLifetime != nil {
+		dst.TxPoolMaxLifetime = src.TxPoolMaxLifetime
+	}
+	if src.MaxPeers != nil {
+		dst.MaxPeers = src.MaxPeers
+	}
+	if src.TxRelay != nil {
+		dst.TxRelay = src.TxRelay
+	}
+}
+
+// handleReloadSignal applies runtime config from file at once and on each SIGHUP, until ctx done.
+func handleReloadSignal(ctx context.Context, tunables *runtimeTunables) {
+	path := tunables.configPath
+	reload := func() {
+		if path == "" {
+			log.Warn("no runtime config file specified, use --" + runtimeConfigFlag.Name)
+			return
+		}
+		config, err := loadRuntimeConfig(path)
+		if err != nil {
+			log.Warn("failed to load runtime config", "path", path, "err", err)
+			return
+		}
+		tunables.apply(config)
+		log.Info("runtime config applied", "path", path)
+	}
+
+	if path != "" {
+		reload()
+	}
+
+	go func() {
+		reloadSignalCh := make(chan os.Signal, 1)
+		signal.Notify(reloadSignalCh, syscall.SIGHUP)
+		defer signal.Stop(reloadSignalCh)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reloadSignalCh:
+				reload()
+			}
+		}
+	}()
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/consensus"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+func replayAction(ctx *cli.Context) error {
+	exitSignal := handleExitSignal()
+
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	mainDB := openReadOnlyMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	chain := initReadOnlyChain(gene, mainDB)
+	best := chain.BestBlock().Header().Number()
+
+	from := uint32(ctx.Uint(replayFromFlag.Name))
+	to := uint32(ctx.Uint(replayToFlag.Name))
+	if !ctx.IsSet(replayToFlag.Name) || to > best {
+		to = best
+	}
+	// genesis is built, not executed
+	if from == 0 {
+		from = 1
+	}
+	if from > to {
+		return fmt.Errorf("invalid range [%v, %v], best block #%v", from, to, best)
+	}
+
+	cons := consensus.New(chain, state.NewCreator(mainDB))
+	diverged := 0
+	for num := from; num <= to; num++ {
+		if exitSignal.Err() != nil {
+			return exitSignal.Err()
+		}
+		diffs, err := replayBlock(chain, cons, num)
+		if err != nil {
+			return err
+		}
+		if len(diffs) > 0 {
+			diverged++
+			fmt.Printf("block #%v diverged:\n", num)
+			for _, diff := range diffs {
+				fmt.Println("   ", diff)
+			}
+		}
+		if (num-from+1)%1000 == 0 {
+			log.Info("replaying", "number", num, "diverged", diverged)
+		}
+		if num == to {
+			break
+		}
+	}
+
+	fmt.Printf("replayed blocks [%v, %v], %v diverged\n", from, to, diverged)
+	if diverged > 0 {
+		return fmt.Errorf("%v blocks diverged", diverged)
+	}
+	return nil
+}
+
+// replayBlock re-executes the trunk block, and returns divergences against stored values.
+func replayBlock(chain *chain.Chain, cons *consensus.Consensus, num uint32) ([]string, error) {
+	blk, err := chain.GetTrunkBlock(num)
+	if err != nil {
+		return nil, err
+	}
+	header := blk.Header()
+	stored, err := chain.GetBlockReceipts(header.ID())
+	if err != nil {
+		if !chain.IsNotFound(err) {
+			return nil, err
+		}
+		// blocks without txs may have no receipts stored
+		stored = tx.Receipts{}
+	}
+
+	stateRoot, receipts, err := cons.Replay(blk)
+	if err != nil {
+		return []string{fmt.Sprintf("execution failed: %v", err)}, nil
+	}
+	return compareReplay(header, stored, stateRoot, receipts), nil
+}
+
+func compareReplay(header *block.Header, stored tx.Receipts, stateRoot thor.Bytes32, receipts tx.Receipts) []string {
+	var diffs []string
+	diff := func(what string, want, have interface{}) {
+		diffs = append(diffs, fmt.Sprintf("%v: want %v, have %v", what, want, have))
+	}
+
+	if header.StateRoot() != stateRoot {
+		diff("state root", header.StateRoot(), stateRoot)
+	}
+	if root := receipts.RootHash(); header.ReceiptsRoot() != root {
+		diff("receipts root", header.ReceiptsRoot(), root)
+	}
+	var gasUsed uint64
+	for _, r := range receipts {
+		gasUsed += r.GasUsed
+	}
+	if header.GasUsed() != gasUsed {
+		diff("gas used", header.GasUsed(), gasUsed)
+	}
+
+	if len(stored) != len(receipts) {
+		diff("receipts count", len(stored), len(receipts))
+		return diffs
+	}
+	for i, want := range stored {
+		have := receipts[i]
+		what := func(field string) string { return fmt.Sprintf("tx #%v %v", i, field) }
+		if want.GasUsed != have.GasUsed {
+			diff(what("gas used"), want.GasUsed, have.GasUsed)
+		}
+		if want.GasPayer != have.GasPayer {
+			diff(what("gas payer"), want.GasPayer, have.GasPayer)
+		}
+		if want.Paid.Cmp(have.Paid) != 0 {
+			diff(what("paid"), want.Paid, have.Paid)
+		}
+		if want.Reward.Cmp(have.Reward) != 0 {
+			diff(what("reward"), want.Reward, have.Reward)
+		}
+		if want.Reverted != have.Reverted {
+			diff(what("reverted"), want.Reverted, have.Reverted)
+		}
+		if wantHash, haveHash := outputsHash(want), outputsHash(have); wantHash != haveHash {
+			diff(what("outputs hash"), wantHash, haveHash)
+		}
+	}
+	return diffs
+}
+
+func outputsHash(r *tx.Receipt) thor.Bytes32 {
+	data, _ := rlp.EncodeToBytes(r.Outputs)
+	return thor.Blake2b(data)
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package replica
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/blocks"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/consensus"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/state"
+)
+
+var log = log15.New("pkg", "replica")
+
+const (
+	pollInterval = time.Second
+	// maxForkDepth limits how far back to walk upstream when its trunk was reorganized.
+	maxForkDepth = 1000
+)
+
+// Replica ingests blocks from a trusted upstream node's API instead of P2P.
+// Blocks are verified and executed locally as they would be when received from peers.
+type Replica struct {
+	chain    *chain.Chain
+	cons     *consensus.Consensus
+	logDB    logdb.Store
+	upstream string
+	client   *http.Client
+	skipLogs bool
+}
+
+// New creates a replica syncing from the upstream API URL.
+func New(
+	chain *chain.Chain,
+	stateCreator *state.Creator,
+	logDB logdb.Store,
+	upstream string,
+	skipLogs bool,
+) *Replica {
+	return &Replica{
+		chain:    chain,
+		cons:     consensus.New(chain, stateCreator),
+		logDB:    logDB,
+		upstream: strings.TrimRight(upstream, "/"),
+		client:   &http.Client{Timeout: 30 * time.Second},
+		skipLogs: skipLogs,
+	}
+}
+
+// Run keeps syncing until ctx done.
+func (r *Replica) Run(ctx context.Context) error {
+	if err := r.checkGenesis(ctx); err != nil {
+		return err
+	}
+	r.loop(ctx)
+	return nil
+}
+
+func (r *Replica) loop(ctx context.Context) {
+	log.Debug("enter replica loop")
+	defer log.Debug("leave replica loop")
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.sync(ctx); err != nil && ctx.Err() == nil {
+			log.Warn("failed to sync from upstream", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkGenesis ensures the upstream node is on the same network.
+func (r *Replica) checkGenesis(ctx context.Context) error {
+	blk, err := r.fetchBlock(ctx, "0")
+	if err != nil {
+		return errors.WithMessage(err, "fetch upstream genesis")
+	}
+	if blk == nil {
+		return errors.New("upstream genesis not found")
+	}
+	if id := r.chain.GenesisBlock().Header().ID(); blk.Header().ID() != id {
+		return fmt.Errorf("upstream genesis mismatch, want %v, got %v", id, blk.Header().ID())
+	}
+	return nil
+}
+
+// sync imports upstream blocks following the local best block, until none left.
+func (r *Replica) sync(ctx context.Context) error {
+	startNum := r.chain.BestBlock().Header().Number()
+	defer func() {
+		best := r.chain.BestBlock().Header()
+		if best.Number() != startNum {
+			log.Info("imported blocks from upstream", "number", best.Number(), "id", best.ID())
+		}
+	}()
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		next := r.chain.BestBlock().Header().Number() + 1
+		blk, err := r.fetchBlock(ctx, fmt.Sprint(next))
+		if err != nil {
+			return err
+		}
+		if blk == nil {
+			return nil
+		}
+		if err := r.importBlock(ctx, blk); err != nil {
+			return err
+		}
+	}
+}
+
+// importBlock processes the block, and its missing ancestors fetched from upstream.
+func (r *Replica) importBlock(ctx context.Context, blk *block.Block) error {
+	pending := []*block.Block{blk}
+	for {
+		err := r.processBlock(pending[len(pending)-1])
+		if err == nil {
+			pending = pending[:len(pending)-1]
+			if len(pending) == 0 {
+				return nil
+			}
+			continue
+		}
+		if !consensus.IsParentMissing(err) {
+			return err
+		}
+		if len(pending) >= maxForkDepth {
+			return errors.New("fork too deep")
+		}
+		parentID := pending[len(pending)-1].Header().ParentID()
+		parent, err := r.fetchBlock(ctx, parentID.String())
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return fmt.Errorf("upstream block %v not found", parentID)
+		}
+		pending = append(pending, parent)
+	}
+}
+
+func (r *Replica) processBlock(blk *block.Block) error {
+	stage, receipts, err := r.cons.Process(blk, uint64(time.Now().Unix()))
+	if err != nil {
+		if consensus.IsKnownBlock(err) {
+			return nil
+		}
+		if consensus.IsCritical(err) {
+			log.Error(fmt.Sprintf("failed to process upstream block due to consensus failure \n%v\n", blk.Header()), "err", err)
+		}
+		return err
+	}
+	if _, err := stage.Commit(); err != nil {
+		return errors.WithMessage(err, "commit state")
+	}
+	fork, err := r.chain.AddBlock(blk, receipts)
+	if err != nil {
+		return errors.WithMessage(err, "add block")
+	}
+	if !r.skipLogs {
+		batch := logdb.NewBlockBatch(r.logDB, blk.Header())
+		for i, tx := range blk.Transactions() {
+			origin, _ := tx.Signer()
+			txBatch := batch.ForTransaction(tx.ID(), origin)
+			for j, output := range receipts[i].Outputs {
+				txBatch.Insert(output.Events, output.Transfers, uint32(j))
+			}
+		}
+		if err := batch.Commit(); err != nil {
+			return errors.WithMessage(err, "commit logs")
+		}
+	}
+	log.Debug("imported block", "number", blk.Header().Number(), "id", blk.Header().ID(), "trunk", len(fork.Trunk) > 0)
+	return nil
+}
+
+// fetchBlock fetches raw block at the revision from upstream. Nil returned if not found.
+func (r *Replica) fetchBlock(ctx context.Context, revision string) (*block.Block, error) {
+	req, err := http.NewRequest("GET", r.upstream+"/blocks/"+revision+"?raw=true", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream responded %v", resp.Status)
+	}
+
+	var raw *blocks.RawBlock
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, errors.WithMessage(err, "decode response")
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	data, err := hexutil.Decode(raw.Raw)
+	if err != nil {
+		return nil, errors.WithMessage(err, "decode raw block")
+	}
+	var blk block.Block
+	if err := rlp.DecodeBytes(data, &blk); err != nil {
+		return nil, errors.WithMessage(err, "decode raw block")
+	}
+	return &blk, nil
+}
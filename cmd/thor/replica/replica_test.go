@@ -0,0 +1,107 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package replica
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/api/blocks"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/packer"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+type testChain struct {
+	chain  *chain.Chain
+	stateC *state.Creator
+}
+
+func newTestChain(t *testing.T) *testChain {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := chain.New(db, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testChain{c, stateC}
+}
+
+// pack packs an empty block on the parent, skipping the given count of slots.
+func (tc *testChain) pack(t *testing.T, parent *block.Header, skipped uint64) *block.Block {
+	acc := genesis.DevAccounts()[0]
+	p := packer.New(tc.chain, tc.stateC, acc.Address, &acc.Address)
+	flow, err := p.Schedule(parent, parent.Timestamp()+thor.BlockInterval*(skipped+1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk, stage, receipts, _, err := flow.Pack(acc.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tc.chain.AddBlock(blk, receipts); err != nil {
+		t.Fatal(err)
+	}
+	return blk
+}
+
+func TestReplica(t *testing.T) {
+	upstream := newTestChain(t)
+	b1 := upstream.pack(t, upstream.chain.GenesisBlock().Header(), 0)
+	upstream.pack(t, b1.Header(), 0)
+
+	router := mux.NewRouter()
+	blocks.New(upstream.chain).Mount(router, "/blocks")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	local := newTestChain(t)
+	r := New(local.chain, local.stateC, nil, ts.URL+"/", true)
+	ctx := context.Background()
+
+	assert.Nil(t, r.checkGenesis(ctx))
+	assert.Nil(t, r.sync(ctx))
+	assert.Equal(t, upstream.chain.BestBlock().Header().ID(), local.chain.BestBlock().Header().ID())
+
+	// upstream switches to a longer branch forked from b1
+	c2 := upstream.pack(t, b1.Header(), 1)
+	c3 := upstream.pack(t, c2.Header(), 0)
+	upstream.pack(t, c3.Header(), 0)
+	assert.Nil(t, r.sync(ctx))
+	assert.Equal(t, upstream.chain.BestBlock().Header().ID(), local.chain.BestBlock().Header().ID())
+
+	// different network
+	other := New(local.chain, local.stateC, nil, ts.URL, true)
+	other.chain = newMainnetChain(t)
+	assert.NotNil(t, other.checkGenesis(ctx))
+}
+
+func newMainnetChain(t *testing.T) *chain.Chain {
+	db, _ := lvldb.NewMem()
+	b0, _, err := genesis.NewMainnet().Build(state.NewCreator(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := chain.New(db, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
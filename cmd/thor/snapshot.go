@@ -0,0 +1,315 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+// snapshot archive layout (gzip compressed rlp stream):
+//
+//	header, record..., trailer
+//
+// trailer carries sha256 over all raw rlp items before it.
+const (
+	snapshotVersion = 1
+
+	snapshotRecordMain = 0 // key-value pair of main db
+	snapshotRecordLogs = 1 // chunk of log db file
+	snapshotRecordEnd  = 2 // trailer, value is the checksum
+
+	snapshotLogsChunkSize = 1024 * 1024
+)
+
+type snapshotHeader struct {
+	Version     uint
+	GenesisID   thor.Bytes32
+	BestBlockID thor.Bytes32
+	WithLogs    bool
+}
+
+type snapshotRecord struct {
+	Kind  uint
+	Key   []byte
+	Value []byte
+}
+
+type snapshotWriter struct {
+	w      io.Writer
+	hasher hash.Hash
+}
+
+func (sw *snapshotWriter) write(val interface{}) error {
+	data, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	if _, err := sw.w.Write(data); err != nil {
+		return err
+	}
+	sw.hasher.Write(data)
+	return nil
+}
+
+func (sw *snapshotWriter) finish() error {
+	data, err := rlp.EncodeToBytes(&snapshotRecord{Kind: snapshotRecordEnd, Value: sw.hasher.Sum(nil)})
+	if err != nil {
+		return err
+	}
+	_, err = sw.w.Write(data)
+	return err
+}
+
+func snapshotCreateAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+	instanceDir := makeInstanceDir(ctx, gene)
+
+	out := ctx.String(snapshotFileFlag.Name)
+	if out == "" {
+		return fmt.Errorf("flag %s required", snapshotFileFlag.Name)
+	}
+	withLogs := ctx.Bool(snapshotWithLogsFlag.Name)
+
+	mainDB := openMainDB(ctx, instanceDir)
+	defer mainDB.Close()
+
+	logDB := openLocalLogDB(ctx, instanceDir)
+	chain := initChain(gene, mainDB, logDB)
+	// close log db to have WAL checkpointed into the db file
+	logDB.Close()
+
+	file, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zw := gzip.NewWriter(file)
+	sw := &snapshotWriter{zw, sha256.New()}
+
+	best := chain.BestBlock().Header()
+	if err := sw.write(&snapshotHeader{
+		snapshotVersion,
+		gene.ID(),
+		best.ID(),
+		withLogs,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("creating snapshot at block #%v %v\n", best.Number(), best.ID())
+
+	it := mainDB.NewIterator(kv.Range{})
+	for it.Next() {
+		if err := sw.write(&snapshotRecord{snapshotRecordMain, it.Key(), it.Value()}); err != nil {
+			it.Release()
+			return err
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return errors.WithMessage(err, "iterate main db")
+	}
+
+	if withLogs {
+		logs, err := os.Open(logDB.Path())
+		if err != nil {
+			return err
+		}
+		defer logs.Close()
+
+		buf := make([]byte, snapshotLogsChunkSize)
+		for {
+			n, err := io.ReadFull(logs, buf)
+			if n > 0 {
+				if err := sw.write(&snapshotRecord{Kind: snapshotRecordLogs, Value: buf[:n]}); err != nil {
+					return err
+				}
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			if err != nil {
+				return errors.WithMessage(err, "read log db")
+			}
+		}
+	}
+
+	if err := sw.finish(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	fmt.Println("snapshot created:", out)
+	return nil
+}
+
+func snapshotRestoreAction(ctx *cli.Context) error {
+	initLogger(ctx)
+	gene := selectGenesis(ctx)
+
+	in := ctx.String(snapshotFileFlag.Name)
+	if in == "" {
+		return fmt.Errorf("flag %s required", snapshotFileFlag.Name)
+	}
+
+	instanceDir := makeInstanceDir(ctx, gene)
+	mainDBDir := filepath.Join(instanceDir, "main.db")
+	logDBPath := filepath.Join(instanceDir, "logs-v2.db")
+	for _, path := range []string{mainDBDir, logDBPath} {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%v already exists, remove it before restoring", path)
+		}
+	}
+	if err := os.MkdirAll(instanceDir, 0700); err != nil {
+		return err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			os.RemoveAll(mainDBDir)
+			os.Remove(logDBPath)
+		}
+	}()
+
+	file, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.WithMessage(err, "open snapshot")
+	}
+
+	var (
+		stream = rlp.NewStream(zr, 0)
+		hasher = sha256.New()
+		header snapshotHeader
+	)
+
+	raw, err := stream.Raw()
+	if err != nil {
+		return errors.WithMessage(err, "read snapshot header")
+	}
+	hasher.Write(raw)
+	if err := rlp.DecodeBytes(raw, &header); err != nil {
+		return errors.WithMessage(err, "decode snapshot header")
+	}
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %v", header.Version)
+	}
+	if header.GenesisID != gene.ID() {
+		return errors.New("genesis id mismatch")
+	}
+
+	mainDB, err := lvldb.New(mainDBDir, lvldb.Options{})
+	if err != nil {
+		return err
+	}
+	defer mainDB.Close()
+
+	var logs *os.File
+	if header.WithLogs {
+		if logs, err = os.OpenFile(logDBPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600); err != nil {
+			return err
+		}
+		defer logs.Close()
+	}
+
+	batch := mainDB.NewBatch()
+	for {
+		raw, err := stream.Raw()
+		if err != nil {
+			return errors.WithMessage(err, "read snapshot record")
+		}
+		var rec snapshotRecord
+		if err := rlp.DecodeBytes(raw, &rec); err != nil {
+			return errors.WithMessage(err, "decode snapshot record")
+		}
+
+		if rec.Kind == snapshotRecordEnd {
+			if !bytes.Equal(rec.Value, hasher.Sum(nil)) {
+				return errors.New("snapshot checksum mismatch")
+			}
+			break
+		}
+		hasher.Write(raw)
+
+		switch rec.Kind {
+		case snapshotRecordMain:
+			if err := batch.Put(rec.Key, rec.Value); err != nil {
+				return err
+			}
+			if batch.Len() >= 4096 {
+				if err := batch.Write(); err != nil {
+					return err
+				}
+				batch = mainDB.NewBatch()
+			}
+		case snapshotRecordLogs:
+			if logs == nil {
+				return errors.New("unexpected log db record")
+			}
+			if _, err := logs.Write(rec.Value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown snapshot record kind %v", rec.Kind)
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if logs != nil {
+		if err := logs.Sync(); err != nil {
+			return err
+		}
+	}
+
+	genesisBlock, _, err := gene.Build(state.NewCreator(mainDB))
+	if err != nil {
+		return errors.WithMessage(err, "build genesis block")
+	}
+	chain, err := chain.New(mainDB, genesisBlock)
+	if err != nil {
+		return errors.WithMessage(err, "verify restored chain")
+	}
+	best := chain.BestBlock().Header()
+	if best.ID() != header.BestBlockID {
+		return errors.New("best block mismatch")
+	}
+	if _, err := state.NewCreator(mainDB).NewState(best.StateRoot()); err != nil {
+		return errors.WithMessage(err, "verify restored state")
+	}
+
+	succeeded = true
+	fmt.Printf("snapshot restored at block #%v %v\n", best.Number(), best.ID())
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package solo
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/utils"
+	"github.com/vechain/thor/thor"
+)
+
+// maxMineCount limits count of blocks mined per request.
+const maxMineCount = 1000
+
+// Mine is the body of mine request.
+type Mine struct {
+	Count int `json:"count"`
+}
+
+// Mined is the result of mine request.
+type Mined struct {
+	Blocks []thor.Bytes32 `json:"blocks"`
+}
+
+// TimeTravel is the body of time request, either to increase seconds or to set the timestamp of next block.
+type TimeTravel struct {
+	Increase  uint64 `json:"increase"`
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// Time is the current chain time.
+type Time struct {
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// Faucet is the body of faucet request.
+type Faucet struct {
+	To thor.Address `json:"to"`
+}
+
+// Dripped is the result of faucet request.
+type Dripped struct {
+	ID thor.Bytes32 `json:"id"`
+}
+
+func (s *Solo) handleMine(w http.ResponseWriter, req *http.Request) error {
+	body := Mine{Count: 1}
+	if req.ContentLength != 0 {
+		if err := utils.ParseJSON(req.Body, &body); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "body"))
+		}
+	}
+	if body.Count < 1 || body.Count > maxMineCount {
+		return utils.BadRequest(errors.Errorf("count: should be in [1, %v]", maxMineCount))
+	}
+	ids, err := s.Mine(body.Count)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, &Mined{ids})
+}
+
+func (s *Solo) handleGetTime(w http.ResponseWriter, req *http.Request) error {
+	return utils.WriteJSON(w, &Time{s.Now()})
+}
+
+func (s *Solo) handleTimeTravel(w http.ResponseWriter, req *http.Request) error {
+	var body TimeTravel
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	switch {
+	case body.Increase > 0 && body.Timestamp > 0:
+		return utils.BadRequest(errors.New("body: increase and timestamp are exclusive"))
+	case body.Increase > 0:
+		s.IncreaseTime(body.Increase)
+	case body.Timestamp > 0:
+		if err := s.SetNextTimestamp(body.Timestamp); err != nil {
+			return utils.BadRequest(errors.WithMessage(err, "timestamp"))
+		}
+	default:
+		return utils.BadRequest(errors.New("body: increase or timestamp required"))
+	}
+	return utils.WriteJSON(w, &Time{s.Now()})
+}
+
+func (s *Solo) handleFaucet(w http.ResponseWriter, req *http.Request) error {
+	var body Faucet
+	if err := utils.ParseJSON(req.Body, &body); err != nil {
+		return utils.BadRequest(errors.WithMessage(err, "body"))
+	}
+	if body.To.IsZero() {
+		return utils.BadRequest(errors.New("to: required"))
+	}
+	if !s.hasFaucet() {
+		return utils.Forbidden(errors.New("faucet not deployed, solo should be started with faucet"))
+	}
+	id, err := s.Drip(body.To)
+	if err != nil {
+		return err
+	}
+	return utils.WriteJSON(w, &Dripped{id})
+}
+
+// Mount mounts controls of solo chain, to mine blocks on demand, to travel in time and to request funds from faucet.
+func (s *Solo) Mount(root *mux.Router, pathPrefix string) {
+	sub := root.PathPrefix(pathPrefix).Subrouter()
+
+	sub.Path("/mine").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(s.handleMine))
+	sub.Path("/time").Methods("GET").HandlerFunc(utils.WrapHandlerFunc(s.handleGetTime))
+	sub.Path("/time").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(s.handleTimeTravel))
+	sub.Path("/faucet").Methods("POST").HandlerFunc(utils.WrapHandlerFunc(s.handleFaucet))
+}
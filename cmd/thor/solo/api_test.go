@@ -0,0 +1,225 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package solo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/cmd/thor/solo"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txpool"
+)
+
+func TestTimeTravel(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	logDB, _ := logdb.NewMem()
+	defer logDB.Close()
+	pool := txpool.New(chain, stateC, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Minute})
+	defer pool.Close()
+
+	router := mux.NewRouter()
+	solo.New(chain, stateC, logDB, pool, 10000000, true).Mount(router, "/solo")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	post := func(path string, body interface{}) ([]byte, int) {
+		data, _ := json.Marshal(body)
+		res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		r, _ := ioutil.ReadAll(res.Body)
+		return r, res.StatusCode
+	}
+
+	target := uint64(time.Now().Unix()) + 3600*24
+	_, status := post("/solo/time", &solo.TimeTravel{Timestamp: target})
+	assert.Equal(t, http.StatusOK, status)
+
+	res, status := post("/solo/mine", &solo.Mine{Count: 3})
+	assert.Equal(t, http.StatusOK, status)
+	var mined solo.Mined
+	assert.Nil(t, json.Unmarshal(res, &mined))
+	assert.Equal(t, 3, len(mined.Blocks))
+	assert.Equal(t, mined.Blocks[2], chain.BestBlock().Header().ID())
+
+	b1, _ := chain.GetTrunkBlockHeader(1)
+	b3 := chain.BestBlock().Header()
+	assert.True(t, b1.Timestamp() >= target)
+	assert.True(t, b3.Timestamp() > b1.Timestamp())
+
+	// energy grows with the travelled time
+	acc := genesis.DevAccounts()[5].Address
+	energyAt := func(h interface{ StateRoot() thor.Bytes32 }, ts uint64) *big.Int {
+		st, _ := stateC.NewState(h.StateRoot())
+		return st.GetEnergy(acc, ts)
+	}
+	assert.True(t, energyAt(b1, b1.Timestamp()).Cmp(energyAt(b0.Header(), b0.Header().Timestamp())) > 0)
+
+	_, status = post("/solo/time", &solo.TimeTravel{Increase: 100})
+	assert.Equal(t, http.StatusOK, status)
+	_, status = post("/solo/mine", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, chain.BestBlock().Header().Timestamp() >= b3.Timestamp()+100)
+
+	_, status = post("/solo/time", &solo.TimeTravel{Timestamp: b3.Timestamp()})
+	assert.Equal(t, http.StatusBadRequest, status, "not after best block")
+	_, status = post("/solo/time", &solo.TimeTravel{})
+	assert.Equal(t, http.StatusBadRequest, status)
+	_, status = post("/solo/mine", &solo.Mine{Count: 0})
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestTxAfterTimeTravel(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b0, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b0)
+	logDB, _ := logdb.NewMem()
+	defer logDB.Close()
+	pool := txpool.New(chain, stateC, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Minute})
+	defer pool.Close()
+	s := solo.New(chain, stateC, logDB, pool, 10000000, true)
+
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	newTx := func(nonce uint64) *tx.Transaction {
+		trx := new(tx.Builder).
+			ChainTag(chain.Tag()).
+			BlockRef(tx.NewBlockRef(chain.BestBlock().Header().Number())).
+			Expiration(100).
+			Gas(21000).
+			Nonce(nonce).
+			Clause(tx.NewClause(&recipient).WithValue(big.NewInt(1))).
+			Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		return trx.WithSignature(sig)
+	}
+	// mineTx submits the tx, and mines a block once it's executable
+	mineTx := func(trx *tx.Transaction) {
+		assert.Nil(t, pool.Add(trx))
+		executable := func() bool {
+			for _, e := range pool.Executables() {
+				if e.ID() == trx.ID() {
+					return true
+				}
+			}
+			return false
+		}
+		for i := 0; i < 50 && !executable(); i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if _, err := s.Mine(1); err != nil {
+			t.Fatal(err)
+		}
+		txs := chain.BestBlock().Transactions()
+		if assert.Len(t, txs, 1) {
+			assert.Equal(t, trx.ID(), txs[0].ID())
+		}
+	}
+
+	// submitted after the time travel and a block mined
+	s.IncreaseTime(3600)
+	if _, err := s.Mine(1); err != nil {
+		t.Fatal(err)
+	}
+	mineTx(newTx(1))
+
+	// submitted right after the time travel
+	s.IncreaseTime(3600 * 24)
+	mineTx(newTx(2))
+}
+
+func TestFaucet(t *testing.T) {
+	newServer := func(gene *genesis.Genesis) (*httptest.Server, *chain.Chain, *state.Creator, *txpool.TxPool, func()) {
+		db, _ := lvldb.NewMem()
+		stateC := state.NewCreator(db)
+		b0, _, err := gene.Build(stateC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain, _ := chain.New(db, b0)
+		logDB, _ := logdb.NewMem()
+		pool := txpool.New(chain, stateC, txpool.Options{Limit: 100, LimitPerAccount: 16, MaxLifetime: time.Minute})
+
+		router := mux.NewRouter()
+		solo.New(chain, stateC, logDB, pool, 10000000, true).Mount(router, "/solo")
+		ts := httptest.NewServer(router)
+		return ts, chain, stateC, pool, func() {
+			ts.Close()
+			pool.Close()
+			logDB.Close()
+		}
+	}
+	post := func(url string, body interface{}) ([]byte, int) {
+		data, _ := json.Marshal(body)
+		res, err := http.Post(url, "application/json", bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		r, _ := ioutil.ReadAll(res.Body)
+		return r, res.StatusCode
+	}
+
+	recipient := thor.BytesToAddress([]byte("recipient"))
+
+	ts, _, _, _, closer := newServer(genesis.NewDevnet())
+	_, status := post(ts.URL+"/solo/faucet", &solo.Faucet{To: recipient})
+	assert.Equal(t, http.StatusForbidden, status)
+	closer()
+
+	ts, chain, stateC, pool, closer := newServer(genesis.NewDevnetWithOptions(genesis.DevnetOptions{Faucet: true}))
+	defer closer()
+	_, status = post(ts.URL+"/solo/faucet", &solo.Faucet{})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	res, status := post(ts.URL+"/solo/faucet", &solo.Faucet{To: recipient})
+	assert.Equal(t, http.StatusOK, status, string(res))
+	var dripped solo.Dripped
+	assert.Nil(t, json.Unmarshal(res, &dripped))
+
+	// wait for the pool to turn the tx executable
+	for i := 0; i < 50 && len(pool.Executables()) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	_, status = post(ts.URL+"/solo/mine", nil)
+	assert.Equal(t, http.StatusOK, status)
+	best := chain.BestBlock()
+	assert.Equal(t, 1, len(best.Transactions()))
+	assert.Equal(t, dripped.ID, best.Transactions()[0].ID())
+	receipts, _ := chain.GetBlockReceipts(best.Header().ID())
+	assert.False(t, receipts[0].Reverted)
+
+	st, _ := stateC.NewState(best.Header().StateRoot())
+	assert.Equal(t, genesis.FaucetDripVET, st.GetBalance(recipient))
+	assert.Equal(t, genesis.FaucetDripEnergy, st.GetEnergy(recipient, best.Header().Timestamp()))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package solo
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+var (
+	faucetGenesisID     thor.Bytes32
+	faucetGenesisIDOnce sync.Once
+)
+
+// hasFaucet returns whether the chain is launched from devnet genesis with faucet.
+func (s *Solo) hasFaucet() bool {
+	faucetGenesisIDOnce.Do(func() {
+		faucetGenesisID = genesis.NewDevnetWithOptions(genesis.DevnetOptions{Faucet: true}).ID()
+	})
+	return s.chain.GenesisBlock().Header().ID() == faucetGenesisID
+}
+
+// Drip requests funds from the faucet for the address, by a tx sent by the solo block signer.
+// It returns the id of the tx.
+func (s *Solo) Drip(to thor.Address) (thor.Bytes32, error) {
+	if !s.hasFaucet() {
+		return thor.Bytes32{}, errors.New("faucet not deployed")
+	}
+	data := append(append([]byte(nil), genesis.FaucetDripSelector...), abiWord(to)...)
+	best := s.chain.BestBlock().Header()
+	trx := new(tx.Builder).
+		ChainTag(s.chain.Tag()).
+		BlockRef(tx.NewBlockRef(best.Number())).
+		Expiration(720).
+		Gas(200000).
+		Nonce(rand.Uint64()).
+		Clause(tx.NewClause(&genesis.FaucetAddress).WithData(data)).
+		Build()
+	sig, err := crypto.Sign(trx.SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+	if err != nil {
+		return thor.Bytes32{}, err
+	}
+	trx = trx.WithSignature(sig)
+	if err := s.txPool.Add(trx); err != nil {
+		return thor.Bytes32{}, err
+	}
+	return trx.ID(), nil
+}
+
+// abiWord left pads the address into a 32 bytes abi word.
+func abiWord(addr thor.Address) []byte {
+	word := make([]byte, 32)
+	copy(word[12:], addr[:])
+	return word
+}
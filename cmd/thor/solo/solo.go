@@ -8,6 +8,7 @@ package solo
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -22,6 +23,7 @@ import (
 	"github.com/vechain/thor/logdb"
 	"github.com/vechain/thor/packer"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/txpool"
 )
@@ -33,21 +35,27 @@ type Solo struct {
 	chain       *chain.Chain
 	txPool      *txpool.TxPool
 	packer      *packer.Packer
-	logDB       *logdb.LogDB
+	logDB       logdb.Store
 	bestBlockCh chan *block.Block
 	gasLimit    uint64
 	onDemand    bool
+
+	mu         sync.Mutex
+	timeOffset int64 // seconds the chain time is ahead of wall clock
 }
 
 // New returns Solo instance
 func New(
 	chain *chain.Chain,
 	stateCreator *state.Creator,
-	logDB *logdb.LogDB,
+	logDB logdb.Store,
 	txPool *txpool.TxPool,
 	gasLimit uint64,
 	onDemand bool,
 ) *Solo {
+	// The solo chain is never behind, as blocks are packed only by itself, so the pool is told the time
+	// of the best block, to keep washing txs after time travels or idle periods in on-demand mode.
+	txPool.SetClock(func() uint64 { return chain.BestBlock().Header().Timestamp() })
 	return &Solo{
 		chain:    chain,
 		txPool:   txPool,
@@ -86,7 +94,7 @@ func (s *Solo) loop(ctx context.Context) {
 	txEvCh := make(chan *txpool.TxEvent, 10)
 	scope.Track(s.txPool.SubscribeTxEvent(txEvCh))
 
-	if err := s.packing(nil); err != nil {
+	if err := s.packing(nil, false); err != nil {
 		log.Error("failed to pack block", "err", err)
 	}
 
@@ -100,7 +108,7 @@ func (s *Solo) loop(ctx context.Context) {
 			singer, _ := newTx.Signer()
 			log.Info("new Tx", "id", newTx.ID(), "signer", singer)
 			if s.onDemand {
-				if err := s.packing(tx.Transactions{newTx}); err != nil {
+				if err := s.packing(tx.Transactions{newTx}, false); err != nil {
 					log.Error("failed to pack block", "err", err)
 				}
 			}
@@ -108,14 +116,66 @@ func (s *Solo) loop(ctx context.Context) {
 			if s.onDemand {
 				continue
 			}
-			if err := s.packing(s.txPool.Executables()); err != nil {
+			if err := s.packing(s.txPool.Executables(), false); err != nil {
 				log.Error("failed to pack block", "err", err)
 			}
 		}
 	}
 }
 
-func (s *Solo) packing(pendingTxs tx.Transactions) error {
+// Mine packs count blocks immediately with executable txs in pool, even if empty.
+// It returns IDs of packed blocks.
+func (s *Solo) Mine(count int) ([]thor.Bytes32, error) {
+	ids := make([]thor.Bytes32, 0, count)
+	for i := 0; i < count; i++ {
+		if err := s.packing(s.txPool.Executables(), true); err != nil {
+			return ids, err
+		}
+		ids = append(ids, s.chain.BestBlock().Header().ID())
+	}
+	return ids, nil
+}
+
+// IncreaseTime advances the chain time by seconds, which takes effect from the next block.
+// Blocks mined ahead of the chain time are taken into account.
+func (s *Solo) IncreaseTime(seconds uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	base := s.now()
+	if best := s.chain.BestBlock().Header().Timestamp(); base < best {
+		base = best
+	}
+	s.timeOffset = int64(base+seconds) - time.Now().Unix()
+}
+
+// SetNextTimestamp sets the chain time, so that the next block is packed at the timestamp.
+func (s *Solo) SetNextTimestamp(timestamp uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if best := s.chain.BestBlock().Header().Timestamp(); timestamp <= best {
+		return errors.Errorf("timestamp should be greater than best block timestamp %v", best)
+	}
+	s.timeOffset = int64(timestamp) - time.Now().Unix()
+	return nil
+}
+
+// Now returns the current chain time.
+func (s *Solo) Now() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now()
+}
+
+func (s *Solo) now() uint64 {
+	return uint64(time.Now().Unix() + s.timeOffset)
+}
+
+func (s *Solo) packing(pendingTxs tx.Transactions, force bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	best := s.chain.BestBlock()
 	var txsToRemove []*tx.Transaction
 	defer func() {
@@ -124,7 +184,12 @@ func (s *Solo) packing(pendingTxs tx.Transactions) error {
 		}
 	}()
 
-	flow, err := s.packer.Mock(best.Header(), uint64(time.Now().Unix()), s.gasLimit)
+	// blocks mined on demand may be faster than the wall clock
+	timestamp := s.now()
+	if min := best.Header().Timestamp() + 1; timestamp < min {
+		timestamp = min
+	}
+	flow, err := s.packer.Mock(best.Header(), timestamp, s.gasLimit)
 	if err != nil {
 		return errors.WithMessage(err, "mock packer")
 	}
@@ -145,14 +210,14 @@ func (s *Solo) packing(pendingTxs tx.Transactions) error {
 		}
 	}
 
-	b, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+	b, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
 	if err != nil {
 		return errors.WithMessage(err, "pack")
 	}
 	execElapsed := mclock.Now() - startTime
 
 	// If there is no tx packed in the on-demand mode then skip
-	if s.onDemand && len(b.Transactions()) == 0 {
+	if s.onDemand && !force && len(b.Transactions()) == 0 {
 		return nil
 	}
 
@@ -166,7 +231,7 @@ func (s *Solo) packing(pendingTxs tx.Transactions) error {
 		return errors.WithMessage(err, "commit block")
 	}
 
-	batch := s.logDB.Prepare(b.Header())
+	batch := logdb.NewBlockBatch(s.logDB, b.Header())
 	for i, tx := range b.Transactions() {
 		origin, _ := tx.Signer()
 		txBatch := batch.ForTransaction(tx.ID(), origin)
@@ -0,0 +1,118 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/thor"
+)
+
+// grace period after startup for finding peers
+const watchdogStartupGrace = 5 * time.Minute
+
+// sdNotify sends state to systemd through $NOTIFY_SOCKET.
+// It's no-op if not started by systemd with notify support.
+func sdNotify(state string) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		log.Warn("failed to connect notify socket", "err", err)
+		return
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		log.Warn("failed to notify systemd", "state", state, "err", err)
+	}
+}
+
+// sdWatchdogInterval returns watchdog interval required by systemd.
+// Zero returned if watchdog not enabled.
+func sdWatchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseUint(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec == 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// runWatchdog keeps pinging systemd watchdog as long as the node is alive, which means blocks
+// are importing and peers are connected. A stuck node stops pinging and will then be restarted
+// by systemd. Zero stallTimeout disables checking blocks and peers, e.g. on private networks,
+// then the watchdog is pinged as long as the process is responsive.
+func runWatchdog(ctx context.Context, chain *chain.Chain, peerCount func() int, stallTimeout time.Duration) {
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	bestID := func() thor.Bytes32 { return chain.BestBlock().Header().ID() }
+	go watchdogLoop(ctx, interval/2, stallTimeout, bestID, peerCount, sdNotify)
+}
+
+// watchdogLoop checks liveness of the node on every tick, and notifies the watchdog ping if alive.
+func watchdogLoop(
+	ctx context.Context,
+	tick time.Duration,
+	stallTimeout time.Duration,
+	bestID func() thor.Bytes32,
+	peerCount func() int,
+	notify func(state string),
+) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var (
+		startTime       = time.Now()
+		lastBestID      = bestID()
+		bestChangedTime = startTime
+		status          string
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			if id := bestID(); id != lastBestID {
+				lastBestID = id
+				bestChangedTime = now
+			}
+
+			newStatus := ""
+			if stallTimeout > 0 {
+				if now.Sub(bestChangedTime) > stallTimeout {
+					newStatus = "no block imported since " + bestChangedTime.Format(time.RFC3339)
+				} else if peerCount() == 0 && now.Sub(startTime) > watchdogStartupGrace {
+					newStatus = "no peer connected"
+				}
+			}
+			if newStatus != status {
+				status = newStatus
+				if status != "" {
+					log.Warn("watchdog: " + status)
+					notify("STATUS=" + status)
+				} else {
+					notify("STATUS=running")
+				}
+			}
+			// stop pinging if stuck
+			if status == "" {
+				notify("WATCHDOG=1")
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/thor"
+)
+
+func TestWatchdogLoop(t *testing.T) {
+	var best atomic.Value
+	best.Store(thor.Bytes32{1})
+	bestID := func() thor.Bytes32 { return best.Load().(thor.Bytes32) }
+	peerCount := func() int { return 1 }
+
+	states := make(chan string, 100)
+	notify := func(state string) { states <- state }
+	// next returns the next state other than pings, and count of pings before it
+	next := func() (string, int) {
+		pings := 0
+		for {
+			select {
+			case state := <-states:
+				if state != "WATCHDOG=1" {
+					return state, pings
+				}
+				pings++
+			case <-time.After(time.Second):
+				return "", pings
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchdogLoop(ctx, 5*time.Millisecond, 50*time.Millisecond, bestID, peerCount, notify)
+
+	state, pings := next()
+	assert.True(t, strings.HasPrefix(state, "STATUS=no block imported"), state)
+	assert.NotZero(t, pings, "pinged before stalled")
+
+	// stalled
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, states, 0, "no ping once stalled")
+
+	best.Store(thor.Bytes32{2})
+	state, _ = next()
+	assert.Equal(t, "STATUS=running", state)
+	assert.Equal(t, "WATCHDOG=1", <-states, "ping resumed")
+}
+
+func TestWatchdogLoopStallDisabled(t *testing.T) {
+	bestID := func() thor.Bytes32 { return thor.Bytes32{} }
+	peerCount := func() int { return 0 }
+
+	states := make(chan string, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchdogLoop(ctx, 5*time.Millisecond, 0, bestID, peerCount, func(state string) { states <- state })
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "WATCHDOG=1", <-states)
+	}
+}
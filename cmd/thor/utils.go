@@ -17,6 +17,7 @@ import (
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
@@ -154,6 +155,11 @@ func handleXThorestVersion(h http.Handler) http.Handler {
 // middleware for http request timeout.
 func handleAPITimeout(h http.Handler, timeout time.Duration) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// long-poll for tx receipt is bounded by its own wait duration
+		if isReceiptLongPoll(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
 		ctx, cancel := context.WithTimeout(r.Context(), timeout)
 		defer cancel()
 		r = r.WithContext(ctx)
@@ -161,6 +167,15 @@ func handleAPITimeout(h http.Handler, timeout time.Duration) http.Handler {
 	})
 }
 
+// isReceiptLongPoll returns whether the request is 'GET /transactions/{id}/receipt?wait='.
+func isReceiptLongPoll(r *http.Request) bool {
+	if r.Method != http.MethodGet || r.URL.Query().Get("wait") == "" {
+		return false
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	return len(parts) == 3 && parts[0] == "transactions" && parts[2] == "receipt"
+}
+
 func readPasswordFromNewTTY(prompt string) (string, error) {
 	t, err := tty.Open()
 	if err != nil {
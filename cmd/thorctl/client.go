@@ -0,0 +1,134 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// client requests the node's HTTP API, and outputs response bodies.
+type client struct {
+	baseURL string
+	http    *http.Client
+	out     io.Writer
+}
+
+func newClient(baseURL string, timeout time.Duration, out io.Writer) *client {
+	return &client{
+		strings.TrimSuffix(baseURL, "/"),
+		&http.Client{Timeout: timeout},
+		out,
+	}
+}
+
+func (c *client) get(path string, query url.Values) error {
+	return c.do(http.MethodGet, path, query, nil)
+}
+
+func (c *client) post(path string, body interface{}) error {
+	return c.do(http.MethodPost, path, nil, body)
+}
+
+func (c *client) delete(path string) error {
+	return c.do(http.MethodDelete, path, nil, nil)
+}
+
+func (c *client) do(method, path string, query url.Values, body interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%v: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return c.output(data)
+}
+
+// output writes the json data indented.
+func (c *client) output(data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		// not json
+		buf.Reset()
+		buf.Write(data)
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(c.out)
+	return err
+}
+
+// subscribe connects the websocket subscription, and outputs messages until error.
+func (c *client) subscribe(subject string, query url.Values) error {
+	u, err := url.Parse(c.baseURL + "/subscriptions/" + subject)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.RawQuery = query.Encode()
+
+	conn, resp, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		if resp != nil {
+			data, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			return errors.WithMessage(err, strings.TrimSpace(string(data)))
+		}
+		return err
+	}
+	defer conn.Close()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if err := c.output(data); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,434 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// thorctl is a command-line client for the node API.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/api/subscriptions"
+	"github.com/vechain/thor/thor"
+	cli "gopkg.in/urfave/cli.v1"
+)
+
+var (
+	version   string
+	gitCommit string
+	gitTag    string
+
+	apiURLFlag = cli.StringFlag{
+		Name:  "api-url",
+		Value: "http://localhost:8669",
+		Usage: "URL of node API",
+	}
+	adminURLFlag = cli.StringFlag{
+		Name:  "admin-url",
+		Usage: "URL of node admin API, as printed by node at startup (e.g. http://localhost:2113/admin)",
+	}
+	timeoutFlag = cli.IntFlag{
+		Name:  "timeout",
+		Value: 10000,
+		Usage: "request timeout in milliseconds",
+	}
+	revisionFlag = cli.StringFlag{
+		Name:  "revision",
+		Usage: "block id, number, 'best', 'finalized' or '@<timestamp>', best block if not set",
+	}
+	headFlag = cli.StringFlag{
+		Name:  "head",
+		Usage: "id of the head block, best block if not set",
+	}
+	posFlag = cli.StringFlag{
+		Name:  "pos",
+		Usage: "id of the block to start after, best block if not set",
+	}
+)
+
+func main() {
+	versionMeta := "release"
+	if gitTag == "" {
+		versionMeta = "dev"
+	}
+	app := cli.App{
+		Version:   fmt.Sprintf("%s-%s-%s", version, gitCommit, versionMeta),
+		Name:      "Thorctl",
+		Usage:     "VeChain Thor node API client",
+		Copyright: "2018 VeChain Foundation <https://vechain.org/>",
+		Flags:     []cli.Flag{apiURLFlag, adminURLFlag, timeoutFlag},
+		Commands: []cli.Command{
+			{
+				Name:      "block",
+				Usage:     "get block by id, number, 'best', 'finalized' or '@<timestamp>'",
+				ArgsUsage: "<revision>",
+				Action:    blockAction,
+			},
+			{
+				Name:      "account",
+				Usage:     "get account balance, energy and whether it has code",
+				ArgsUsage: "<address>",
+				Flags:     []cli.Flag{revisionFlag},
+				Action:    accountAction,
+			},
+			{
+				Name:      "code",
+				Usage:     "get account code",
+				ArgsUsage: "<address>",
+				Flags:     []cli.Flag{revisionFlag},
+				Action:    codeAction,
+			},
+			{
+				Name:      "storage",
+				Usage:     "get account storage value",
+				ArgsUsage: "<address> <key>",
+				Flags:     []cli.Flag{revisionFlag},
+				Action:    storageAction,
+			},
+			{
+				Name:      "tx",
+				Usage:     "get transaction by id",
+				ArgsUsage: "<id>",
+				Flags:     []cli.Flag{headFlag},
+				Action:    txAction,
+			},
+			{
+				Name:      "receipt",
+				Usage:     "get transaction receipt by id",
+				ArgsUsage: "<id>",
+				Flags:     []cli.Flag{headFlag},
+				Action:    receiptAction,
+			},
+			{
+				Name:      "send",
+				Usage:     "send raw transaction in hex, read from stdin if '-'",
+				ArgsUsage: "<raw>",
+				Action:    sendAction,
+			},
+			{
+				Name:   "peers",
+				Usage:  "list connected peers",
+				Action: peersAction,
+			},
+			{
+				Name:   "gasprice",
+				Usage:  "get base gas price and suggested gas price coef",
+				Action: gasPriceAction,
+			},
+			{
+				Name:  "subscribe",
+				Usage: "subscribe and print messages until interrupted",
+				Subcommands: []cli.Command{
+					{
+						Name:   "block",
+						Usage:  "subscribe new blocks",
+						Flags:  []cli.Flag{posFlag},
+						Action: subscribeAction("block"),
+					},
+					{
+						Name:  "event",
+						Usage: "subscribe events",
+						Flags: []cli.Flag{
+							posFlag,
+							cli.StringFlag{Name: "addr", Usage: "contract address"},
+							cli.StringFlag{Name: "t0", Usage: "topic0"},
+							cli.StringFlag{Name: "t1", Usage: "topic1"},
+							cli.StringFlag{Name: "t2", Usage: "topic2"},
+							cli.StringFlag{Name: "t3", Usage: "topic3"},
+							cli.StringFlag{Name: "t4", Usage: "topic4"},
+						},
+						Action: subscribeAction("event", "addr", "t0", "t1", "t2", "t3", "t4"),
+					},
+					{
+						Name:  "transfer",
+						Usage: "subscribe VET transfers",
+						Flags: []cli.Flag{
+							posFlag,
+							cli.StringFlag{Name: "txOrigin", Usage: "tx origin"},
+							cli.StringFlag{Name: "sender", Usage: "sender"},
+							cli.StringFlag{Name: "recipient", Usage: "recipient"},
+						},
+						Action: subscribeAction("transfer", "txOrigin", "sender", "recipient"),
+					},
+					{
+						Name:   "beat",
+						Usage:  "subscribe block beats",
+						Flags:  []cli.Flag{posFlag},
+						Action: subscribeAction("beat"),
+					},
+				},
+			},
+			{
+				Name:  "admin",
+				Usage: "node administration, requires --" + adminURLFlag.Name,
+				Subcommands: []cli.Command{
+					{
+						Name:   "peers",
+						Usage:  "list connected peers",
+						Action: adminPeersAction,
+					},
+					{
+						Name:      "add-peer",
+						Usage:     "add trusted peer",
+						ArgsUsage: "<enode>",
+						Action:    adminAddPeerAction,
+					},
+					{
+						Name:      "disconnect-peer",
+						Usage:     "disconnect peer",
+						ArgsUsage: "<node id>",
+						Action:    adminDisconnectPeerAction,
+					},
+					{
+						Name:      "ban-peer",
+						Usage:     "disconnect peer and refuse it for a duration",
+						ArgsUsage: "<node id>",
+						Flags: []cli.Flag{
+							cli.Uint64Flag{Name: "duration", Value: 3600, Usage: "ban duration in seconds"},
+						},
+						Action: adminBanPeerAction,
+					},
+					{
+						Name:   "webhooks",
+						Usage:  "list webhooks",
+						Action: adminWebhooksAction,
+					},
+					{
+						Name:      "add-webhook",
+						Usage:     "add webhook to receive messages of subject (block|event|transfer)",
+						ArgsUsage: "<url> <subject>",
+						Flags: []cli.Flag{
+							posFlag,
+							cli.StringFlag{Name: "secret", Usage: "secret to sign message bodies"},
+						},
+						Action: adminAddWebhookAction,
+					},
+					{
+						Name:      "remove-webhook",
+						Usage:     "remove webhook",
+						ArgsUsage: "<id>",
+						Action:    adminRemoveWebhookAction,
+					},
+				},
+			},
+		},
+	}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func apiClient(ctx *cli.Context) *client {
+	return newClient(ctx.GlobalString(apiURLFlag.Name), time.Duration(ctx.GlobalInt(timeoutFlag.Name))*time.Millisecond, os.Stdout)
+}
+
+func adminClient(ctx *cli.Context) (*client, error) {
+	adminURL := ctx.GlobalString(adminURLFlag.Name)
+	if adminURL == "" {
+		return nil, errors.New("--" + adminURLFlag.Name + " required")
+	}
+	return newClient(adminURL, time.Duration(ctx.GlobalInt(timeoutFlag.Name))*time.Millisecond, os.Stdout), nil
+}
+
+// args returns exactly n positional args.
+func args(ctx *cli.Context, n int) ([]string, error) {
+	if ctx.NArg() != n {
+		return nil, fmt.Errorf("expect %v argument(s), usage: %v %v", n, ctx.Command.Name, ctx.Command.ArgsUsage)
+	}
+	return ctx.Args(), nil
+}
+
+func revisionQuery(ctx *cli.Context) url.Values {
+	query := url.Values{}
+	if revision := ctx.String(revisionFlag.Name); revision != "" {
+		query.Set("revision", revision)
+	}
+	return query
+}
+
+func headQuery(ctx *cli.Context) url.Values {
+	query := url.Values{}
+	if head := ctx.String(headFlag.Name); head != "" {
+		query.Set("head", head)
+	}
+	return query
+}
+
+func blockAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	return apiClient(ctx).get("/blocks/"+url.PathEscape(a[0]), nil)
+}
+
+func accountAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	return apiClient(ctx).get("/accounts/"+url.PathEscape(a[0]), revisionQuery(ctx))
+}
+
+func codeAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	return apiClient(ctx).get("/accounts/"+url.PathEscape(a[0])+"/code", revisionQuery(ctx))
+}
+
+func storageAction(ctx *cli.Context) error {
+	a, err := args(ctx, 2)
+	if err != nil {
+		return err
+	}
+	return apiClient(ctx).get("/accounts/"+url.PathEscape(a[0])+"/storage/"+url.PathEscape(a[1]), revisionQuery(ctx))
+}
+
+func txAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	return apiClient(ctx).get("/transactions/"+url.PathEscape(a[0]), headQuery(ctx))
+}
+
+func receiptAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	return apiClient(ctx).get("/transactions/"+url.PathEscape(a[0])+"/receipt", headQuery(ctx))
+}
+
+func sendAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	raw := a[0]
+	if raw == "-" {
+		data, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return errors.WithMessage(err, "read stdin")
+		}
+		raw = strings.TrimSpace(string(data))
+	}
+	return apiClient(ctx).post("/transactions", map[string]string{"raw": raw})
+}
+
+func peersAction(ctx *cli.Context) error {
+	return apiClient(ctx).get("/node/network/peers", nil)
+}
+
+func gasPriceAction(ctx *cli.Context) error {
+	return apiClient(ctx).get("/node/gasprice", nil)
+}
+
+// subscribeAction returns action to subscribe the subject, with flags of names as query params.
+func subscribeAction(subject string, names ...string) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		query := url.Values{}
+		for _, name := range append([]string{posFlag.Name}, names...) {
+			if v := ctx.String(name); v != "" {
+				query.Set(name, v)
+			}
+		}
+		return apiClient(ctx).subscribe(subject, query)
+	}
+}
+
+func adminPeersAction(ctx *cli.Context) error {
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	return c.get("/network/peers", nil)
+}
+
+func adminAddPeerAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	return c.post("/network/peers", map[string]string{"enode": a[0]})
+}
+
+func adminDisconnectPeerAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	return c.delete("/network/peers/" + url.PathEscape(a[0]))
+}
+
+func adminBanPeerAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	return c.post("/network/peers/"+url.PathEscape(a[0])+"/ban", map[string]uint64{"duration": ctx.Uint64("duration")})
+}
+
+func adminWebhooksAction(ctx *cli.Context) error {
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	return c.get("/webhooks", nil)
+}
+
+func adminAddWebhookAction(ctx *cli.Context) error {
+	a, err := args(ctx, 2)
+	if err != nil {
+		return err
+	}
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	hook := subscriptions.Webhook{
+		URL:     a[0],
+		Subject: a[1],
+		Secret:  ctx.String("secret"),
+	}
+	if pos := ctx.String(posFlag.Name); pos != "" {
+		if hook.Position, err = thor.ParseBytes32(pos); err != nil {
+			return errors.WithMessage(err, "pos")
+		}
+	}
+	return c.post("/webhooks", &hook)
+}
+
+func adminRemoveWebhookAction(ctx *cli.Context) error {
+	a, err := args(ctx, 1)
+	if err != nil {
+		return err
+	}
+	c, err := adminClient(ctx)
+	if err != nil {
+		return err
+	}
+	return c.delete("/webhooks/" + url.PathEscape(a[0]))
+}
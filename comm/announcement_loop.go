@@ -70,6 +70,7 @@ func (c *Communicator) fetchBlockByID(peer *Peer, newBlockID thor.Bytes32) {
 
 	result, err := proto.GetBlockByID(c.ctx, peer, newBlockID)
 	if err != nil {
+		c.scoreCallError(peer, err)
 		peer.logger.Debug("failed to get block by id", "err", err)
 		return
 	}
@@ -80,9 +81,11 @@ func (c *Communicator) fetchBlockByID(peer *Peer, newBlockID thor.Bytes32) {
 
 	var blk block.Block
 	if err := rlp.DecodeBytes(result, &blk); err != nil {
+		c.punishPeer(peer, "invalid block")
 		peer.logger.Debug("failed to decode block got by id", "err", err)
 		return
 	}
+	c.scorePeer(peer, scoreBlockDelivered)
 
 	c.newBlockFeed.Send(&NewBlockEvent{
 		Block: &blk,
@@ -0,0 +1,104 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// Ban describes a banned node.
+type Ban struct {
+	NodeID discover.NodeID
+	Expiry uint64 // unix timestamp
+}
+
+// banList records banned nodes with expiry.
+type banList struct {
+	m    map[discover.NodeID]time.Time
+	lock sync.Mutex
+}
+
+func newBanList() *banList {
+	return &banList{
+		m: make(map[discover.NodeID]time.Time),
+	}
+}
+
+// Add bans the node for the given duration.
+func (bl *banList) Add(nodeID discover.NodeID, duration time.Duration) {
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+	bl.m[nodeID] = time.Now().Add(duration)
+}
+
+// Contains returns whether the node is banned. Expired entry is removed.
+func (bl *banList) Contains(nodeID discover.NodeID) bool {
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+	expiry, ok := bl.m[nodeID]
+	if !ok {
+		return false
+	}
+	if !time.Now().Before(expiry) {
+		delete(bl.m, nodeID)
+		return false
+	}
+	return true
+}
+
+// Slice dumps unexpired bans.
+func (bl *banList) Slice() []*Ban {
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+
+	now := time.Now()
+	bans := make([]*Ban, 0, len(bl.m))
+	for nodeID, expiry := range bl.m {
+		if !now.Before(expiry) {
+			delete(bl.m, nodeID)
+			continue
+		}
+		bans = append(bans, &Ban{nodeID, uint64(expiry.Unix())})
+	}
+	return bans
+}
+
+// DisconnectPeer disconnects the peer with given node ID.
+// False returned if no such peer connected.
+func (c *Communicator) DisconnectPeer(nodeID discover.NodeID) bool {
+	peer := c.peerSet.Find(nodeID)
+	if peer == nil {
+		return false
+	}
+	peer.Disconnect(p2p.DiscRequested)
+	return true
+}
+
+// BanPeer bans the node for the given duration, and disconnects it if connected.
+// Banned node will be refused when trying to connect.
+func (c *Communicator) BanPeer(nodeID discover.NodeID, duration time.Duration) {
+	c.banList.Add(nodeID, duration)
+	c.DisconnectPeer(nodeID)
+}
+
+// Bans returns all unexpired bans, for persistence.
+func (c *Communicator) Bans() []*Ban {
+	return c.banList.Slice()
+}
+
+// RestoreBans restores previously saved bans. Expired ones are ignored.
+func (c *Communicator) RestoreBans(bans []*Ban) {
+	now := time.Now()
+	for _, ban := range bans {
+		if expiry := time.Unix(int64(ban.Expiry), 0); now.Before(expiry) {
+			c.banList.Add(ban.NodeID, expiry.Sub(now))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/comm"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/txpool"
+)
+
+func newCommunicator(t *testing.T) *comm.Communicator {
+	db, _ := lvldb.NewMem()
+	stateC := state.NewCreator(db)
+	b, _, err := genesis.NewDevnet().Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := chain.New(db, b)
+	return comm.New(chain, txpool.New(chain, stateC, txpool.Options{
+		Limit:           10000,
+		LimitPerAccount: 16,
+		MaxLifetime:     10 * time.Minute,
+	}))
+}
+
+func TestBans(t *testing.T) {
+	c := newCommunicator(t)
+	id1 := discover.NodeID{1}
+	id2 := discover.NodeID{2}
+
+	c.BanPeer(id1, time.Hour)
+	c.BanPeer(id2, -time.Second)
+
+	bans := c.Bans()
+	assert.Equal(t, 1, len(bans), "expired ban should be excluded")
+	assert.Equal(t, id1, bans[0].NodeID)
+
+	restored := newCommunicator(t)
+	restored.RestoreBans(append(bans, &comm.Ban{NodeID: id2, Expiry: uint64(time.Now().Unix()) - 1}))
+	assert.Equal(t, bans, restored.Bans())
+}
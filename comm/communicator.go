@@ -15,7 +15,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/co"
@@ -35,6 +37,9 @@ type Communicator struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	peerSet        *PeerSet
+	banList        *banList
+	peerScores     *lru.Cache
+	txRelayPolicy  int32
 	syncedCh       chan struct{}
 	newBlockFeed   event.Feed
 	announcementCh chan *announcement
@@ -46,12 +51,16 @@ type Communicator struct {
 // New create a new Communicator instance.
 func New(chain *chain.Chain, txPool *txpool.TxPool) *Communicator {
 	ctx, cancel := context.WithCancel(context.Background())
+	// keep scores of recently disconnected peers, to be restored on reconnection
+	peerScores, _ := lru.New(1024)
 	return &Communicator{
 		chain:          chain,
 		txPool:         txPool,
 		ctx:            ctx,
 		cancel:         cancel,
 		peerSet:        newPeerSet(),
+		banList:        newBanList(),
+		peerScores:     peerScores,
 		syncedCh:       make(chan struct{}),
 		announcementCh: make(chan *announcement),
 	}
@@ -95,8 +104,13 @@ func (c *Communicator) Sync(handler HandleBlockStream) {
 				log.Debug("synchronization start")
 
 				best := c.chain.BestBlock().Header()
+				// peers with higher usefulness score are preferred
+				peers := c.peerSet.Slice()
+				sort.SliceStable(peers, func(i, j int) bool {
+					return peers[i].Score() > peers[j].Score()
+				})
 				// choose peer which has the head block with higher total score
-				peer := c.peerSet.Slice().Find(func(peer *Peer) bool {
+				peer := peers.Find(func(peer *Peer) bool {
 					_, totalScore := peer.Head()
 					return totalScore >= best.TotalScore()
 				})
@@ -130,16 +144,24 @@ func (c *Communicator) Sync(handler HandleBlockStream) {
 // Protocols returns all supported protocols.
 func (c *Communicator) Protocols() []*p2psrv.Protocol {
 	genesisID := c.chain.GenesisBlock().Header().ID()
-	return []*p2psrv.Protocol{
-		&p2psrv.Protocol{
+	discTopic := fmt.Sprintf("%v%v@%x", proto.Name, proto.DiscoveryVersion, genesisID[24:])
+
+	var protocols []*p2psrv.Protocol
+	for _, spec := range proto.Specs() {
+		spec := spec
+		protocols = append(protocols, &p2psrv.Protocol{
 			Protocol: p2p.Protocol{
 				Name:    proto.Name,
-				Version: proto.Version,
-				Length:  proto.Length,
-				Run:     c.servePeer,
+				Version: spec.Version,
+				Length:  spec.Length(),
+				Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+					return c.servePeer(p, rw, spec)
+				},
 			},
-			DiscTopic: fmt.Sprintf("%v%v@%x", proto.Name, proto.Version, genesisID[24:]),
-		}}
+			DiscTopic: discTopic,
+		})
+	}
+	return protocols
 }
 
 // Start start the communicator.
@@ -156,12 +178,21 @@ func (c *Communicator) Stop() {
 }
 
 type txsToSync struct {
-	txs    tx.Transactions
-	synced bool
+	txs     tx.Transactions
+	synced  bool
+	digests map[proto.TxDigest]bool // offered by MsgGetTxDigests, and not yet requested
 }
 
-func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := newPeer(p, rw)
+func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter, spec *proto.Spec) error {
+	if c.banList.Contains(p.ID()) {
+		return errors.New("peer banned")
+	}
+	peer := newPeer(p, rw, spec)
+	if score, ok := c.peerScores.Get(peer.ID()); ok {
+		peer.loadScore(score.(peerScore))
+	}
+	defer func() { c.peerScores.Add(peer.ID(), peer.savedScore()) }()
+
 	c.goes.Go(func() {
 		c.runPeer(peer)
 	})
@@ -169,7 +200,11 @@ func (c *Communicator) servePeer(p *p2p.Peer, rw p2p.MsgReadWriter) error {
 	var txsToSync txsToSync
 
 	return peer.Serve(func(msg *p2p.Msg, w func(interface{})) error {
-		return c.handleRPC(peer, msg, w, &txsToSync)
+		if err := c.handleRPC(peer, msg, w, &txsToSync); err != nil {
+			c.punishPeer(peer, "invalid message")
+			return err
+		}
+		return nil
 	}, proto.MaxMsgSize)
 }
 
@@ -182,6 +217,7 @@ func (c *Communicator) runPeer(peer *Peer) {
 
 	status, err := proto.GetStatus(ctx, peer)
 	if err != nil {
+		c.scoreCallError(peer, err)
 		peer.logger.Debug("failed to get status", "err", err)
 		return
 	}
@@ -277,6 +313,7 @@ func (c *Communicator) PeersStats() []*PeerStats {
 			NetAddr:     peer.RemoteAddr().String(),
 			Inbound:     peer.Inbound(),
 			Duration:    uint64(time.Duration(peer.Duration()) / time.Second),
+			Latency:     uint64(peer.Latency() / time.Millisecond),
 		})
 	}
 	sort.Slice(stats, func(i, j int) bool {
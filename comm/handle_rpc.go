@@ -19,10 +19,15 @@ import (
 	"github.com/vechain/thor/tx"
 )
 
+const (
+	maxTxSyncSize = 100 * 1024
+	maxTxDigests  = 20000
+)
+
 // peer will be disconnected if error returned
 func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{}), txsToSync *txsToSync) (err error) {
 
-	log := peer.logger.New("msg", proto.MsgName(msg.Code))
+	log := peer.logger.New("msg", peer.spec.MsgName(msg.Code))
 	log.Debug("received RPC call")
 	defer func() {
 		if err != nil {
@@ -51,6 +56,7 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 
 		peer.MarkBlock(newBlock.Header().ID())
 		peer.UpdateHead(newBlock.Header().ID(), newBlock.Header().TotalScore())
+		c.scorePeer(peer, scoreBlockDelivered)
 		c.newBlockFeed.Send(&NewBlockEvent{Block: newBlock})
 		write(&struct{}{})
 	case proto.MsgNewBlockID:
@@ -70,7 +76,7 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 			return errors.WithMessage(err, "decode msg")
 		}
 		peer.MarkTransaction(newTx.Hash())
-		c.txPool.Add(newTx)
+		c.scoreTxDelivered(peer, c.txPool.Add(newTx))
 		write(&struct{}{})
 	case proto.MsgGetBlockByID:
 		var blockID thor.Bytes32
@@ -126,12 +132,11 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 		}
 		write(result)
 	case proto.MsgGetTxs:
-		const maxTxSyncSize = 100 * 1024
 		if err := msg.Decode(&struct{}{}); err != nil {
 			return errors.WithMessage(err, "decode msg")
 		}
 
-		if txsToSync.synced {
+		if txsToSync.synced || !c.shouldRelayTxsTo(peer) {
 			write(tx.Transactions(nil))
 		} else {
 			if len(txsToSync.txs) == 0 {
@@ -164,8 +169,62 @@ func (c *Communicator) handleRPC(peer *Peer, msg *p2p.Msg, write func(interface{
 			}
 			write(toSend)
 		}
+	case proto.MsgGetTxDigests:
+		if err := msg.Decode(&struct{}{}); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		var digests []proto.TxDigest
+		// digests offered once per connection, as txs by MsgGetTxs
+		if !txsToSync.synced && c.shouldRelayTxsTo(peer) {
+			txsToSync.digests = make(map[proto.TxDigest]bool)
+			for _, tx := range c.txPool.Executables() {
+				if len(digests) >= maxTxDigests {
+					break
+				}
+				d := proto.DigestOf(tx.Hash())
+				digests = append(digests, d)
+				txsToSync.digests[d] = true
+			}
+		}
+		txsToSync.synced = true
+		write(digests)
+	case proto.MsgGetTxsByDigest:
+		var digests []proto.TxDigest
+		if err := msg.Decode(&digests); err != nil {
+			return errors.WithMessage(err, "decode msg")
+		}
+		if len(digests) > maxTxDigests {
+			return errors.New("too many digests")
+		}
+		// only offered digests served, and each at most once
+		requested := make(map[proto.TxDigest]bool, len(digests))
+		for _, d := range digests {
+			if txsToSync.digests[d] {
+				requested[d] = true
+				delete(txsToSync.digests, d)
+			}
+		}
+		var toSend tx.Transactions
+		if len(requested) > 0 && c.shouldRelayTxsTo(peer) {
+			var size metric.StorageSize
+			for _, tx := range c.txPool.Executables() {
+				d := proto.DigestOf(tx.Hash())
+				if !requested[d] {
+					continue
+				}
+				if size >= maxTxSyncSize {
+					// to be requested again
+					txsToSync.digests[d] = true
+					continue
+				}
+				peer.MarkTransaction(tx.Hash())
+				toSend = append(toSend, tx)
+				size += tx.Size()
+			}
+		}
+		write(toSend)
 	default:
-		return fmt.Errorf("unknown message (%v)", msg.Code)
+		return fmt.Errorf("unknown message (%v) for version %v", msg.Code, peer.spec.Version)
 	}
 	return nil
 }
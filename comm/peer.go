@@ -15,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/comm/proto"
 	"github.com/vechain/thor/p2psrv/rpc"
 	"github.com/vechain/thor/thor"
 )
@@ -34,6 +35,7 @@ type Peer struct {
 	*p2p.Peer
 	*rpc.RPC
 	logger log15.Logger
+	spec   *proto.Spec
 
 	createdTime mclock.AbsTime
 	knownTxs    *lru.Cache
@@ -43,9 +45,13 @@ type Peer struct {
 		id         thor.Bytes32
 		totalScore uint64
 	}
+	score struct {
+		sync.Mutex
+		peerScore
+	}
 }
 
-func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter, spec *proto.Spec) *Peer {
 	dir := "outbound"
 	if peer.Inbound() {
 		dir = "inbound"
@@ -53,6 +59,7 @@ func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 	ctx := []interface{}{
 		"peer", peer,
 		"dir", dir,
+		"ver", spec.Version,
 	}
 	knownTxs, _ := lru.New(maxKnownTxs)
 	knownBlocks, _ := lru.New(maxKnownBlocks)
@@ -60,12 +67,24 @@ func newPeer(peer *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 		Peer:        peer,
 		RPC:         rpc.New(peer, rw),
 		logger:      log.New(ctx...),
+		spec:        spec,
 		createdTime: mclock.Now(),
 		knownTxs:    knownTxs,
 		knownBlocks: knownBlocks,
 	}
 }
 
+// ProtocolVersion returns the protocol version negotiated with the peer.
+func (p *Peer) ProtocolVersion() uint {
+	return p.spec.Version
+}
+
+// IsTrusted returns whether the peer is trusted.
+// Trusted peer is never evicted for low score.
+func (p *Peer) IsTrusted() bool {
+	return p.Info().Network.Trusted
+}
+
 // Head returns head block ID and total score.
 func (p *Peer) Head() (id thor.Bytes32, totalScore uint64) {
 	p.head.Lock()
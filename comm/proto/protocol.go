@@ -5,16 +5,18 @@
 
 package proto
 
-import (
-	"fmt"
-)
-
 // Constants
+//
+// There's no need to compress messages in this protocol, since they are already snappy
+// compressed by the underlying devp2p transport, if both sides advertise base protocol
+// version 5 or higher in the handshake. Messages to older peers are sent uncompressed.
 const (
-	Name              = "thor"
-	Version    uint   = 1
-	Length     uint64 = 8
-	MaxMsgSize        = 10 * 1024 * 1024
+	Name       = "thor"
+	MaxMsgSize = 10 * 1024 * 1024
+
+	// DiscoveryVersion version used in discovery topic, shared by all protocol versions
+	// to let nodes of different versions find each other.
+	DiscoveryVersion = 1
 )
 
 // Protocol messages of thor
@@ -27,28 +29,12 @@ const (
 	MsgGetBlockIDByNumber
 	MsgGetBlocksFromNumber // fetch blocks from given number (including given number)
 	MsgGetTxs
+	MsgGetTxDigests   // fetch digests of txs in pool, since version 2
+	MsgGetTxsByDigest // fetch txs in pool by given digests, since version 2
 )
 
 // MsgName convert msg code to string.
 func MsgName(msgCode uint64) string {
-	switch msgCode {
-	case MsgGetStatus:
-		return "MsgGetStatus"
-	case MsgNewBlockID:
-		return "MsgNewBlockID"
-	case MsgNewBlock:
-		return "MsgNewBlock"
-	case MsgNewTx:
-		return "MsgNewTx"
-	case MsgGetBlockByID:
-		return "MsgGetBlockByID"
-	case MsgGetBlockIDByNumber:
-		return "MsgGetBlockIDByNumber"
-	case MsgGetBlocksFromNumber:
-		return "MsgGetBlocksFromNumber"
-	case MsgGetTxs:
-		return "MsgGetTxs"
-	default:
-		return fmt.Sprintf("unknown msg code(%v)", msgCode)
-	}
+	specs := Specs()
+	return specs[len(specs)-1].MsgName(msgCode)
 }
@@ -0,0 +1,96 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proto
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Spec specification of a protocol version.
+// Versions are negotiated in devp2p handshake, where the highest version supported by both sides is chosen.
+// So new message types can be introduced by a new version, without dropping peers of old versions.
+type Spec struct {
+	Version uint
+	Msgs    []string // names of messages, indexed by message code
+}
+
+// Length returns count of message codes.
+func (s *Spec) Length() uint64 {
+	return uint64(len(s.Msgs))
+}
+
+// Supports returns whether the message code is defined in this version.
+func (s *Spec) Supports(msgCode uint64) bool {
+	return msgCode < s.Length()
+}
+
+// MsgName returns name of the message code.
+func (s *Spec) MsgName(msgCode uint64) string {
+	if s.Supports(msgCode) {
+		return s.Msgs[msgCode]
+	}
+	return fmt.Sprintf("unknown msg code(%v)", msgCode)
+}
+
+var specs = make(map[uint]*Spec)
+
+// Register registers spec of a protocol version.
+// It panics if the version already registered.
+func Register(spec *Spec) {
+	if _, ok := specs[spec.Version]; ok {
+		panic(fmt.Sprintf("protocol version %v already registered", spec.Version))
+	}
+	specs[spec.Version] = spec
+}
+
+// Lookup returns spec of given version, or nil if not registered.
+func Lookup(version uint) *Spec {
+	return specs[version]
+}
+
+// Specs returns all registered specs in ascending order of version.
+func Specs() []*Spec {
+	all := make([]*Spec, 0, len(specs))
+	for _, spec := range specs {
+		all = append(all, spec)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Version < all[j].Version
+	})
+	return all
+}
+
+func init() {
+	Register(&Spec{
+		Version: 1,
+		Msgs: []string{
+			MsgGetStatus:           "MsgGetStatus",
+			MsgNewBlockID:          "MsgNewBlockID",
+			MsgNewBlock:            "MsgNewBlock",
+			MsgNewTx:               "MsgNewTx",
+			MsgGetBlockByID:        "MsgGetBlockByID",
+			MsgGetBlockIDByNumber:  "MsgGetBlockIDByNumber",
+			MsgGetBlocksFromNumber: "MsgGetBlocksFromNumber",
+			MsgGetTxs:              "MsgGetTxs",
+		},
+	})
+	Register(&Spec{
+		Version: 2,
+		Msgs: []string{
+			MsgGetStatus:           "MsgGetStatus",
+			MsgNewBlockID:          "MsgNewBlockID",
+			MsgNewBlock:            "MsgNewBlock",
+			MsgNewTx:               "MsgNewTx",
+			MsgGetBlockByID:        "MsgGetBlockByID",
+			MsgGetBlockIDByNumber:  "MsgGetBlockIDByNumber",
+			MsgGetBlocksFromNumber: "MsgGetBlocksFromNumber",
+			MsgGetTxs:              "MsgGetTxs",
+			MsgGetTxDigests:        "MsgGetTxDigests",
+			MsgGetTxsByDigest:      "MsgGetTxsByDigest",
+		},
+	})
+}
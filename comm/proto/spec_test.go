@@ -0,0 +1,43 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/comm/proto"
+	"github.com/vechain/thor/thor"
+)
+
+func TestSpec(t *testing.T) {
+	spec := proto.Lookup(1)
+	assert.NotNil(t, spec)
+	assert.Equal(t, uint64(8), spec.Length())
+	assert.True(t, spec.Supports(proto.MsgGetTxs))
+	assert.False(t, spec.Supports(proto.MsgGetTxs+1))
+	assert.Equal(t, "MsgNewBlock", spec.MsgName(proto.MsgNewBlock))
+
+	spec = proto.Lookup(2)
+	assert.NotNil(t, spec)
+	assert.True(t, spec.Supports(proto.MsgGetTxs), "compatible with version 1")
+	assert.True(t, spec.Supports(proto.MsgGetTxsByDigest))
+	assert.Equal(t, "MsgGetTxDigests", spec.MsgName(proto.MsgGetTxDigests))
+
+	assert.Panics(t, func() { proto.Register(&proto.Spec{Version: 1}) }, "duplicated version")
+
+	specs := proto.Specs()
+	assert.True(t, len(specs) >= 2)
+	for i := 1; i < len(specs); i++ {
+		assert.True(t, specs[i-1].Version < specs[i].Version, "should be in ascending order")
+	}
+}
+
+func TestDigestOf(t *testing.T) {
+	hash := thor.Blake2b([]byte("tx"))
+	d := proto.DigestOf(hash)
+	assert.Equal(t, hash[:8], d[:])
+}
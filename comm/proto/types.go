@@ -23,8 +23,17 @@ type (
 		BestBlockID    thor.Bytes32
 		TotalScore     uint64
 	}
+
+	// TxDigest compact digest of tx, which is the prefix of tx hash.
+	TxDigest [8]byte
 )
 
+// DigestOf returns digest of the tx hash.
+func DigestOf(txHash thor.Bytes32) (d TxDigest) {
+	copy(d[:], txHash[:])
+	return
+}
+
 // RPC defines RPC interface.
 type RPC interface {
 	Notify(ctx context.Context, msgCode uint64, arg interface{}) error
@@ -94,3 +103,22 @@ func GetTxs(ctx context.Context, rpc RPC) (tx.Transactions, error) {
 	}
 	return txs, nil
 }
+
+// GetTxDigests get digests of txs in pool from remote peer.
+func GetTxDigests(ctx context.Context, rpc RPC) ([]TxDigest, error) {
+	var digests []TxDigest
+	if err := rpc.Call(ctx, MsgGetTxDigests, &struct{}{}, &digests); err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+// GetTxsByDigest get txs matching given digests from remote peer.
+// The result may be a part of requested txs, limited by size.
+func GetTxsByDigest(ctx context.Context, rpc RPC, digests []TxDigest) (tx.Transactions, error) {
+	var txs tx.Transactions
+	if err := rpc.Call(ctx, MsgGetTxsByDigest, digests, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
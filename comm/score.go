@@ -0,0 +1,127 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/vechain/thor/txpool"
+)
+
+// score deltas of peer behaviors
+const (
+	scoreBlockDelivered = 2
+	scoreTxDelivered    = 1
+	scoreBadTx          = -5
+	scoreTimeout        = -10
+	scoreInvalidMsg     = -50
+
+	minPeerScore = -100
+	maxPeerScore = 100
+)
+
+const (
+	// peer is banned once count of protocol violations reaches it.
+	// Low score, e.g. caused by timeouts on a slow link, never leads to ban.
+	maxPeerViolations = 5
+	// count of violations halves every period, so that rare faults are forgotten
+	violationHalfLife = 10 * time.Minute
+	// duration of ban caused by violations
+	misbehaviorBanDuration = time.Hour
+)
+
+// peerScore score and violations of a peer, kept across connections.
+type peerScore struct {
+	value         int
+	violations    float64 // decayed count of protocol violations
+	violationTime time.Time
+}
+
+// decayedViolations returns count of violations decayed to now.
+func (s *peerScore) decayedViolations(now time.Time) float64 {
+	if s.violations == 0 {
+		return 0
+	}
+	return s.violations * math.Exp2(-float64(now.Sub(s.violationTime))/float64(violationHalfLife))
+}
+
+// Score returns usefulness score of the peer.
+func (p *Peer) Score() int {
+	p.score.Lock()
+	defer p.score.Unlock()
+	return p.score.value
+}
+
+// addScore adds delta to the score and returns the updated value.
+func (p *Peer) addScore(delta int) int {
+	p.score.Lock()
+	defer p.score.Unlock()
+	p.score.value += delta
+	if p.score.value > maxPeerScore {
+		p.score.value = maxPeerScore
+	} else if p.score.value < minPeerScore {
+		p.score.value = minPeerScore
+	}
+	return p.score.value
+}
+
+// addViolation counts a protocol violation and returns the decayed count.
+func (p *Peer) addViolation() float64 {
+	p.score.Lock()
+	defer p.score.Unlock()
+	now := time.Now()
+	p.score.violations = p.score.decayedViolations(now) + 1
+	p.score.violationTime = now
+	return p.score.violations
+}
+
+// loadScore restores score of the previous connection.
+func (p *Peer) loadScore(s peerScore) {
+	p.score.Lock()
+	defer p.score.Unlock()
+	p.score.peerScore = s
+}
+
+// savedScore returns score to be restored by the next connection.
+func (p *Peer) savedScore() peerScore {
+	p.score.Lock()
+	defer p.score.Unlock()
+	return p.score.peerScore
+}
+
+// scorePeer updates peer's score.
+func (c *Communicator) scorePeer(peer *Peer, delta int) {
+	peer.addScore(delta)
+}
+
+// punishPeer penalizes the peer for protocol violation, and bans it if violations reach the limit.
+func (c *Communicator) punishPeer(peer *Peer, reason string) {
+	peer.addScore(scoreInvalidMsg)
+	if n := peer.addViolation(); n >= maxPeerViolations && !peer.IsTrusted() {
+		peer.logger.Debug("peer banned for misbehavior", "reason", reason)
+		c.banList.Add(peer.ID(), misbehaviorBanDuration)
+		peer.Disconnect(p2p.DiscUselessPeer)
+	}
+}
+
+// scoreTxDelivered scores the peer according to the result of adding tx into pool.
+func (c *Communicator) scoreTxDelivered(peer *Peer, err error) {
+	if err == nil {
+		c.scorePeer(peer, scoreTxDelivered)
+	} else if txpool.IsBadTx(err) {
+		c.scorePeer(peer, scoreBadTx)
+	}
+}
+
+// scoreCallError penalizes the peer according to the error of RPC call.
+func (c *Communicator) scoreCallError(peer *Peer, err error) {
+	if err == context.DeadlineExceeded {
+		c.scorePeer(peer, scoreTimeout)
+	}
+}
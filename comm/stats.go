@@ -24,4 +24,5 @@ type PeerStats struct {
 	NetAddr     string
 	Inbound     bool
 	Duration    uint64 // in seconds
+	Latency     uint64 // in milliseconds
 }
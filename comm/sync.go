@@ -45,6 +45,7 @@ func (c *Communicator) download(peer *Peer, fromNum uint32, handler HandleBlockS
 		for {
 			result, err := proto.GetBlocksFromNumber(ctx, peer, fromNum)
 			if err != nil {
+				c.scoreCallError(peer, err)
 				errCh <- err
 				return
 			}
@@ -56,10 +57,12 @@ func (c *Communicator) download(peer *Peer, fromNum uint32, handler HandleBlockS
 			for _, raw := range result {
 				var blk block.Block
 				if err := rlp.DecodeBytes(raw, &blk); err != nil {
+					c.punishPeer(peer, "invalid block")
 					errCh <- errors.Wrap(err, "invalid block")
 					return
 				}
 				if blk.Header().Number() != fromNum {
+					c.punishPeer(peer, "broken sequence")
 					errCh <- errors.New("broken sequence")
 					return
 				}
@@ -82,6 +85,7 @@ func (c *Communicator) download(peer *Peer, fromNum uint32, handler HandleBlockS
 				}
 			})
 
+			c.scorePeer(peer, scoreBlockDelivered)
 			for _, blk := range blocks {
 				peer.MarkBlock(blk.Header().ID())
 				select {
@@ -110,6 +114,7 @@ func (c *Communicator) findCommonAncestor(peer *Peer, headNum uint32) (uint32, e
 	isOverlapped := func(num uint32) (bool, error) {
 		result, err := proto.GetBlockIDByNumber(c.ctx, peer, num)
 		if err != nil {
+			c.scoreCallError(peer, err)
 			return false, err
 		}
 		id, err := c.chain.GetTrunkBlockID(num)
@@ -179,10 +184,15 @@ func (c *Communicator) findCommonAncestor(peer *Peer, headNum uint32) (uint32, e
 }
 
 func (c *Communicator) syncTxs(peer *Peer) {
+	if peer.spec.Supports(proto.MsgGetTxsByDigest) {
+		c.syncTxsByDigest(peer)
+		return
+	}
 	for i := 0; ; i++ {
 		peer.logger.Debug(fmt.Sprintf("sync txs loop %v", i))
 		result, err := proto.GetTxs(c.ctx, peer)
 		if err != nil {
+			c.scoreCallError(peer, err)
 			peer.logger.Debug("failed to request txs", "err", err)
 			return
 		}
@@ -194,7 +204,7 @@ func (c *Communicator) syncTxs(peer *Peer) {
 
 		for _, tx := range result {
 			peer.MarkTransaction(tx.Hash())
-			c.txPool.StrictlyAdd(tx)
+			c.scoreTxDelivered(peer, c.txPool.StrictlyAdd(tx))
 			select {
 			case <-c.ctx.Done():
 				return
@@ -209,3 +219,67 @@ func (c *Communicator) syncTxs(peer *Peer) {
 	}
 	peer.logger.Debug("sync txs done")
 }
+
+// syncTxsByDigest exchanges digests of pooled txs with the peer, and fetches missing ones.
+func (c *Communicator) syncTxsByDigest(peer *Peer) {
+	digests, err := proto.GetTxDigests(c.ctx, peer)
+	if err != nil {
+		c.scoreCallError(peer, err)
+		peer.logger.Debug("failed to request tx digests", "err", err)
+		return
+	}
+
+	known := make(map[proto.TxDigest]bool)
+	for _, tx := range c.txPool.Dump() {
+		known[proto.DigestOf(tx.Hash())] = true
+	}
+	var missing []proto.TxDigest
+	for _, d := range digests {
+		if !known[d] {
+			known[d] = true
+			missing = append(missing, d)
+		}
+	}
+
+	for i := 0; len(missing) > 0; i++ {
+		result, err := proto.GetTxsByDigest(c.ctx, peer, missing)
+		if err != nil {
+			c.scoreCallError(peer, err)
+			peer.logger.Debug("failed to request txs by digest", "err", err)
+			return
+		}
+		// txs may be gone from remote pool
+		if len(result) == 0 {
+			break
+		}
+
+		fetched := make(map[proto.TxDigest]bool, len(result))
+		for _, tx := range result {
+			fetched[proto.DigestOf(tx.Hash())] = true
+			peer.MarkTransaction(tx.Hash())
+			c.scoreTxDelivered(peer, c.txPool.StrictlyAdd(tx))
+			select {
+			case <-c.ctx.Done():
+				return
+			default:
+			}
+		}
+		remaining := missing[:0]
+		for _, d := range missing {
+			if !fetched[d] {
+				remaining = append(remaining, d)
+			}
+		}
+		if len(remaining) == len(missing) {
+			peer.logger.Debug("unrequested txs delivered, break")
+			return
+		}
+		missing = remaining
+
+		if i >= 100 {
+			peer.logger.Debug("too many loops to sync txs, break")
+			return
+		}
+	}
+	peer.logger.Debug("sync txs by digest done", "count", len(digests))
+}
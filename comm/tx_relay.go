@@ -0,0 +1,66 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// TxRelayPolicy controls to which peers txs in pool are propagated.
+type TxRelayPolicy int32
+
+const (
+	// TxRelayNormal txs are relayed to all peers.
+	TxRelayNormal TxRelayPolicy = iota
+	// TxRelayPrivate txs are never relayed, and only included by local packing.
+	TxRelayPrivate
+	// TxRelayTrusted txs are relayed to trusted peers only.
+	TxRelayTrusted
+)
+
+func (p TxRelayPolicy) String() string {
+	switch p {
+	case TxRelayNormal:
+		return "normal"
+	case TxRelayPrivate:
+		return "private"
+	case TxRelayTrusted:
+		return "trusted"
+	}
+	return fmt.Sprintf("TxRelayPolicy(%d)", int32(p))
+}
+
+// ParseTxRelayPolicy parses policy from its name (normal|private|trusted).
+func ParseTxRelayPolicy(s string) (TxRelayPolicy, error) {
+	for _, p := range []TxRelayPolicy{TxRelayNormal, TxRelayPrivate, TxRelayTrusted} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown tx relay policy %q", s)
+}
+
+// TxRelayPolicy returns current tx relay policy.
+func (c *Communicator) TxRelayPolicy() TxRelayPolicy {
+	return TxRelayPolicy(atomic.LoadInt32(&c.txRelayPolicy))
+}
+
+// SetTxRelayPolicy sets tx relay policy. It's safe to be called at runtime.
+func (c *Communicator) SetTxRelayPolicy(policy TxRelayPolicy) {
+	atomic.StoreInt32(&c.txRelayPolicy, int32(policy))
+}
+
+// shouldRelayTxsTo returns whether txs in pool can be propagated to the peer.
+func (c *Communicator) shouldRelayTxsTo(peer *Peer) bool {
+	switch c.TxRelayPolicy() {
+	case TxRelayPrivate:
+		return false
+	case TxRelayTrusted:
+		return peer.IsTrusted()
+	}
+	return true
+}
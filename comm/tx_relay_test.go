@@ -0,0 +1,28 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package comm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/comm"
+)
+
+func TestTxRelayPolicy(t *testing.T) {
+	for _, p := range []comm.TxRelayPolicy{comm.TxRelayNormal, comm.TxRelayPrivate, comm.TxRelayTrusted} {
+		parsed, err := comm.ParseTxRelayPolicy(p.String())
+		assert.Nil(t, err)
+		assert.Equal(t, p, parsed)
+	}
+	_, err := comm.ParseTxRelayPolicy("foo")
+	assert.NotNil(t, err)
+
+	c := newCommunicator(t)
+	assert.Equal(t, comm.TxRelayNormal, c.TxRelayPolicy())
+	c.SetTxRelayPolicy(comm.TxRelayPrivate)
+	assert.Equal(t, comm.TxRelayPrivate, c.TxRelayPolicy())
+}
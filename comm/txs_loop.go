@@ -24,7 +24,7 @@ func (c *Communicator) txsLoop() {
 			if txEv.Executable != nil && *txEv.Executable {
 				tx := txEv.Tx
 				peers := c.peerSet.Slice().Filter(func(p *Peer) bool {
-					return !p.IsTransactionKnown(tx.Hash())
+					return !p.IsTransactionKnown(tx.Hash()) && c.shouldRelayTxsTo(p)
 				})
 
 				for _, peer := range peers {
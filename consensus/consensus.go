@@ -8,8 +8,10 @@ package consensus
 import (
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/poa"
 	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 	"github.com/vechain/thor/xenv"
 )
@@ -19,13 +21,17 @@ import (
 type Consensus struct {
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	proposers    *poa.ProposerCache
+	forkConfig   thor.ForkConfig
 }
 
 // New create a Consensus instance.
 func New(chain *chain.Chain, stateCreator *state.Creator) *Consensus {
 	return &Consensus{
 		chain:        chain,
-		stateCreator: stateCreator}
+		stateCreator: stateCreator,
+		proposers:    poa.NewProposerCache(stateCreator),
+		forkConfig:   thor.GetForkConfig(chain.GenesisBlock().Header().ID())}
 }
 
 // Process process a block.
@@ -95,3 +101,31 @@ func (c *Consensus) NewRuntimeForReplay(header *block.Header, skipPoA bool) (*ru
 			TotalScore:  header.TotalScore(),
 		}), nil
 }
+
+// Replay re-executes the block against its stored parent state, regardless of whether the block is known.
+// The resulting state root and receipts are returned for comparison, and nothing is written.
+func (c *Consensus) Replay(blk *block.Block) (thor.Bytes32, tx.Receipts, error) {
+	rt, err := c.NewRuntimeForReplay(blk.Header(), false)
+	if err != nil {
+		return thor.Bytes32{}, nil, err
+	}
+
+	txs := blk.Transactions()
+	receipts := make(tx.Receipts, 0, len(txs))
+	for _, tx := range txs {
+		receipt, err := rt.ExecuteTransaction(tx)
+		if err != nil {
+			return thor.Bytes32{}, nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+	if err := rt.Seeker().Err(); err != nil {
+		return thor.Bytes32{}, nil, err
+	}
+
+	stateRoot, err := rt.State().Stage().Hash()
+	if err != nil {
+		return thor.Bytes32{}, nil, err
+	}
+	return stateRoot, receipts, nil
+}
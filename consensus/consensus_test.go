@@ -37,6 +37,17 @@ func TestConsensus(t *testing.T) {
 	}
 }
 
+func (tc *testConsensus) TestReplay() {
+	stateRoot, receipts, err := tc.con.Replay(tc.original)
+	tc.assert.Nil(err)
+	tc.assert.Equal(tc.original.Header().StateRoot(), stateRoot)
+	tc.assert.Equal(tc.original.Header().ReceiptsRoot(), receipts.RootHash())
+
+	// replay doesn't write
+	_, err = tc.con.chain.GetBlockHeader(tc.original.Header().ID())
+	tc.assert.True(tc.con.chain.IsNotFound(err))
+}
+
 func txBuilder(tag byte) *tx.Builder {
 	address := thor.BytesToAddress([]byte("addr"))
 	return new(tx.Builder).
@@ -105,7 +116,7 @@ func newTestConsensus(t *testing.T) *testConsensus {
 		t.Fatal(err)
 	}
 
-	original, _, _, err := flow.Pack(proposer.PrivateKey)
+	original, _, _, _, err := flow.Pack(proposer.PrivateKey)
 	if err != nil {
 		t.Fatal(err)
 	}
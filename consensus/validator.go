@@ -81,16 +81,9 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 		return consensusError(fmt.Sprintf("block signer unavailable: %v", err))
 	}
 
-	authority := builtin.Authority.Native(st)
-	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
-
-	candidates := authority.Candidates(endorsement, thor.MaxBlockProposers)
-	proposers := make([]poa.Proposer, 0, len(candidates))
-	for _, c := range candidates {
-		proposers = append(proposers, poa.Proposer{
-			Address: c.NodeMaster,
-			Active:  c.Active,
-		})
+	proposers, err := c.proposers.Proposers(parent)
+	if err != nil {
+		return err
 	}
 
 	sched, err := poa.NewScheduler(signer, proposers, parent.Number(), parent.Timestamp())
@@ -107,6 +100,7 @@ func (c *Consensus) validateProposer(header *block.Header, parent *block.Header,
 		return consensusError(fmt.Sprintf("block total score invalid: want %v, have %v", parent.TotalScore()+score, header.TotalScore()))
 	}
 
+	authority := builtin.Authority.Native(st)
 	for _, proposer := range updates {
 		authority.Update(proposer.Address, proposer.Active)
 	}
@@ -129,12 +123,14 @@ func (c *Consensus) validateBlockBody(blk *block.Block) error {
 		switch {
 		case tx.ChainTag() != c.chain.Tag():
 			return consensusError(fmt.Sprintf("tx chain tag mismatch: want %v, have %v", c.chain.Tag(), tx.ChainTag()))
-		case header.Number() < tx.BlockRef().Number():
+		case tx.IsRefFuture(header.Number()):
 			return consensusError(fmt.Sprintf("tx ref future block: ref %v, current %v", tx.BlockRef().Number(), header.Number()))
 		case tx.IsExpired(header.Number()):
 			return consensusError(fmt.Sprintf("tx expired: ref %v, current %v, expiration %v", tx.BlockRef().Number(), header.Number(), tx.Expiration()))
 		case tx.HasReservedFields():
 			return consensusError(fmt.Sprintf("tx reserved fields not empty"))
+		case !tx.Features().IsSupported(c.forkConfig, header.Number()):
+			return consensusError(fmt.Sprintf("tx features not supported: %v", tx.Features()))
 		}
 	}
 
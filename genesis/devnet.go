@@ -57,8 +57,20 @@ func DevAccounts() []DevAccount {
 	return accs
 }
 
+// DevnetOptions options to customize devnet genesis.
+type DevnetOptions struct {
+	// Faucet pre-deploys the faucet contract at FaucetAddress.
+	Faucet bool
+}
+
 // NewDevnet create genesis for solo mode.
 func NewDevnet() *Genesis {
+	return NewDevnetWithOptions(DevnetOptions{})
+}
+
+// NewDevnetWithOptions create genesis for solo mode with options.
+// The genesis id differs with options.
+func NewDevnetWithOptions(opts DevnetOptions) *Genesis {
 	launchTime := uint64(1526400000) // 'Wed May 16 2018 00:00:00 GMT+0800 (CST)'
 
 	executor := DevAccounts()[0].Address
@@ -89,6 +101,14 @@ func NewDevnet() *Genesis {
 				tokenSupply.Add(tokenSupply, bal)
 				energySupply.Add(energySupply, bal)
 			}
+			if opts.Faucet {
+				bal, _ := new(big.Int).SetString("1000000000000000000000000000", 10)
+				state.SetCode(FaucetAddress, faucetRuntimeBytecode())
+				state.SetBalance(FaucetAddress, bal)
+				state.SetEnergy(FaucetAddress, bal, launchTime)
+				tokenSupply.Add(tokenSupply, bal)
+				energySupply.Add(energySupply, bal)
+			}
 			builtin.Energy.Native(state, launchTime).SetInitialSupply(tokenSupply, energySupply)
 			return nil
 		}).
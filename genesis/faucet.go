@@ -0,0 +1,116 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package genesis
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/vm"
+)
+
+var (
+	// FaucetAddress is the address of the faucet contract pre-deployed in devnet with faucet option.
+	FaucetAddress = thor.BytesToAddress([]byte("Faucet"))
+	// FaucetDripVET is the amount of VET sent by the faucet per call.
+	FaucetDripVET = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	// FaucetDripEnergy is the amount of energy (VTHO) sent by the faucet per call.
+	FaucetDripEnergy = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+)
+
+// FaucetDripSelector is the selector of faucet method 'drip(address to)'.
+// The faucet sends FaucetDripVET and FaucetDripEnergy to the given address, or the caller if it's zero.
+var FaucetDripSelector = crypto.Keccak256([]byte("drip(address)"))[:4]
+
+// faucetRuntimeBytecode assembles runtime bytecode of the faucet contract.
+// The input is not checked against the selector, so any call drips.
+func faucetRuntimeBytecode() []byte {
+	var code []byte
+	op := func(ops ...vm.OpCode) {
+		for _, o := range ops {
+			code = append(code, byte(o))
+		}
+	}
+	push := func(data []byte) {
+		code = append(code, byte(vm.PUSH1)+byte(len(data)-1))
+		code = append(code, data...)
+	}
+	push1 := func(b byte) { push([]byte{b}) }
+	// jumps to be patched with label offsets
+	var skipCaller, toRevert []int
+	pushLabel := func(refs *[]int) {
+		push1(0)
+		*refs = append(*refs, len(code)-1)
+	}
+
+	addrMask := make([]byte, 20)
+	for i := range addrMask {
+		addrMask[i] = 0xff
+	}
+	transfer, _ := builtin.Energy.ABI.MethodByName("transfer")
+	transferID := transfer.ID()
+	transferSelector := make([]byte, 32)
+	copy(transferSelector, transferID[:])
+
+	// to = address(calldata[4:36]), or caller if zero
+	push1(4)
+	op(vm.CALLDATALOAD)
+	push(addrMask)
+	op(vm.AND, vm.DUP1)
+	pushLabel(&skipCaller)
+	op(vm.JUMPI, vm.POP, vm.CALLER)
+	resolvedLabel := len(code)
+	op(vm.JUMPDEST)
+
+	// energy.transfer(to, amount)
+	push(transferSelector)
+	push1(0)
+	op(vm.MSTORE, vm.DUP1)
+	push1(4)
+	op(vm.MSTORE)
+	push(math.PaddedBigBytes(FaucetDripEnergy, 32))
+	push1(0x24)
+	op(vm.MSTORE)
+	push1(0)    // ret size
+	push1(0)    // ret offset
+	push1(0x44) // args size
+	push1(0)    // args offset
+	push1(0)    // value
+	push(builtin.Energy.Address.Bytes())
+	op(vm.GAS, vm.CALL, vm.ISZERO)
+	pushLabel(&toRevert)
+	op(vm.JUMPI)
+
+	// transfer VET to
+	push1(0) // ret size
+	push1(0) // ret offset
+	push1(0) // args size
+	push1(0) // args offset
+	push(math.PaddedBigBytes(FaucetDripVET, 32))
+	op(vm.DUP6, vm.GAS, vm.CALL, vm.ISZERO)
+	pushLabel(&toRevert)
+	op(vm.JUMPI, vm.STOP)
+
+	revertLabel := len(code)
+	op(vm.JUMPDEST)
+	push1(0)
+	op(vm.DUP1, vm.REVERT)
+
+	if revertLabel > 0xff {
+		panic("faucet code too large for 1-byte jump labels")
+	}
+	// jump if to is non-zero, skipping replacing it with caller
+	for _, ref := range skipCaller {
+		code[ref] = byte(resolvedLabel)
+	}
+	for _, ref := range toRevert {
+		code[ref] = byte(revertLabel)
+	}
+	return code
+}
@@ -6,12 +6,19 @@
 package genesis_test
 
 import (
+	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/genesis"
 	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/runtime"
 	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/xenv"
 )
 
 func TestTestnetGenesis(t *testing.T) {
@@ -24,3 +31,44 @@ func TestTestnetGenesis(t *testing.T) {
 	_, err = state.New(b0.Header().StateRoot(), kv)
 	assert.Nil(t, err)
 }
+
+func TestDevnetFaucet(t *testing.T) {
+	assert.NotEqual(t, genesis.NewDevnet().ID(), genesis.NewDevnetWithOptions(genesis.DevnetOptions{Faucet: true}).ID())
+
+	kv, _ := lvldb.NewMem()
+	stateC := state.NewCreator(kv)
+	b0, _, err := genesis.NewDevnetWithOptions(genesis.DevnetOptions{Faucet: true}).Build(stateC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := chain.New(kv, b0)
+	st, _ := stateC.NewState(b0.Header().StateRoot())
+	now := b0.Header().Timestamp()
+	rt := runtime.New(ch.NewSeeker(b0.Header().ID()), st, &xenv.BlockContext{Time: now})
+
+	drip := func(data []byte, origin thor.Address) *runtime.Output {
+		return rt.ExecuteClause(tx.NewClause(&genesis.FaucetAddress).WithData(data), 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin})
+	}
+
+	// to the given address
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	data := append(append([]byte(nil), genesis.FaucetDripSelector...), make([]byte, 12)...)
+	data = append(data, recipient.Bytes()...)
+	out := drip(data, genesis.DevAccounts()[0].Address)
+	assert.Nil(t, out.VMErr)
+	assert.Equal(t, genesis.FaucetDripVET, st.GetBalance(recipient))
+	assert.Equal(t, genesis.FaucetDripEnergy, st.GetEnergy(recipient, now))
+
+	// to the caller if zero address
+	caller := thor.BytesToAddress([]byte("caller"))
+	out = drip(genesis.FaucetDripSelector, caller)
+	assert.Nil(t, out.VMErr)
+	assert.Equal(t, genesis.FaucetDripVET, st.GetBalance(caller))
+	assert.Equal(t, genesis.FaucetDripEnergy, st.GetEnergy(caller, now))
+
+	// drained
+	st.SetBalance(genesis.FaucetAddress, big.NewInt(1))
+	out = drip(genesis.FaucetDripSelector, caller)
+	assert.NotNil(t, out.VMErr)
+	assert.Equal(t, genesis.FaucetDripVET, st.GetBalance(caller))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/vechain/thor/tracing"
+)
+
+// Bucket is the span of block time to aggregate logs by.
+type Bucket string
+
+// Buckets of block time, aligned to UTC.
+const (
+	BucketHour Bucket = "hour"
+	BucketDay  Bucket = "day"
+)
+
+// ErrUnsupportedBucket is returned if the bucket is unknown.
+var ErrUnsupportedBucket = errors.New("unsupported bucket")
+
+var bucketSeconds = map[Bucket]uint64{
+	BucketHour: 3600,
+	BucketDay:  86400,
+}
+
+// Aggregate is the aggregation of logs in a bucket. Buckets without logs are omitted.
+type Aggregate struct {
+	Time   uint64   // block time the bucket starts at
+	Count  uint64   // count of logs
+	Amount *big.Int // sum of amounts, only for transfers
+}
+
+// AggregateEvents counts events matching the filter in buckets of block time, ordered by time.
+// Options and order of the filter are ignored.
+func (db *LogDB) AggregateEvents(ctx context.Context, filter *EventFilter, bucket Bucket) ([]*Aggregate, error) {
+	seconds, ok := bucketSeconds[bucket]
+	if !ok {
+		return nil, ErrUnsupportedBucket
+	}
+	var (
+		cond   string
+		args   []interface{}
+		source = "event"
+	)
+	if filter != nil {
+		cond, args = db.eventConditions(filter)
+		source = logSource(source, filter.IncludeRemoved)
+	}
+	return db.queryAggregates(ctx, source, seconds, false, cond, args)
+}
+
+// AggregateTransfers counts transfers matching the filter and sums their amounts, in buckets of
+// block time, ordered by time. Options and order of the filter are ignored.
+func (db *LogDB) AggregateTransfers(ctx context.Context, filter *TransferFilter, bucket Bucket) ([]*Aggregate, error) {
+	seconds, ok := bucketSeconds[bucket]
+	if !ok {
+		return nil, ErrUnsupportedBucket
+	}
+	var (
+		cond   string
+		args   []interface{}
+		source = "transfer"
+	)
+	if filter != nil {
+		cond, args = transferConditions(filter)
+		source = logSource(source, filter.IncludeRemoved)
+	}
+	return db.queryAggregates(ctx, source, seconds, true, cond, args)
+}
+
+func (db *LogDB) queryAggregates(ctx context.Context, table string, seconds uint64, withAmount bool, cond string, args []interface{}) (aggs []*Aggregate, err error) {
+	amount := "NULL"
+	if withAmount {
+		amount = "amount_sum(amount)"
+	}
+	stmt := "SELECT blockTime - blockTime % ? AS t, COUNT(*), " + amount + " FROM " + table + " WHERE 1" + cond +
+		" GROUP BY t ORDER BY t ASC"
+	args = append([]interface{}{seconds}, args...)
+
+	ctx, span := tracing.Start(ctx, "logdb.queryAggregates")
+	start := time.Now()
+	defer func() {
+		span.SetAttribute("rows", int64(len(aggs)))
+		span.End()
+		db.logSlowQuery(start, stmt, args, len(aggs), err)
+	}()
+
+	rows, err := db.reader.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			agg Aggregate
+			sum []byte
+		)
+		if err := rows.Scan(&agg.Time, &agg.Count, &sum); err != nil {
+			return nil, err
+		}
+		if withAmount {
+			agg.Amount = new(big.Int).SetBytes(sum)
+		}
+		aggs = append(aggs, &agg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return aggs, nil
+}
+
+// amountSum is the sqlite aggregator to sum amounts, stored as big-endian unsigned integers.
+type amountSum struct {
+	sum big.Int
+}
+
+func newAmountSum() *amountSum {
+	return &amountSum{}
+}
+
+func (s *amountSum) Step(amount []byte) {
+	s.sum.Add(&s.sum, new(big.Int).SetBytes(amount))
+}
+
+func (s *amountSum) Done() []byte {
+	return s.sum.Bytes()
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/vechain/thor/thor"
+)
+
+// Each block with events has a bloom over addresses and topics of its events, to eliminate blocks
+// before index scans for filters with several addresses or topics.
+// Blocks logged before blooms were introduced have none, so blooms only cover blocks from the
+// number recorded as "bloomFrom" in config.
+
+// count of hash functions of event blooms
+const eventBloomK = 3
+
+var configBloomFromKey = "bloomFrom"
+
+// bloomMatch returns whether all bits of the mask are set in the bloom.
+func bloomMatch(bloom, mask []byte) bool {
+	if len(bloom) != len(mask) {
+		return false
+	}
+	for i, b := range mask {
+		if bloom[i]&b != b {
+			return false
+		}
+	}
+	return true
+}
+
+// newEventBloom returns the bloom of events, nil if no event.
+func newEventBloom(events []*Event) []byte {
+	if len(events) == 0 {
+		return nil
+	}
+	bloom := thor.NewBloom(eventBloomK)
+	for _, event := range events {
+		bloom.Add(event.Address.Bytes())
+		for _, topic := range event.Topics {
+			if topic != nil {
+				bloom.Add(topic.Bytes())
+			}
+		}
+	}
+	return bloom.Bits[:]
+}
+
+// eventCriteriaMask returns the mask of the criteria, and count of items in it.
+func eventCriteriaMask(criteria *EventCriteria) ([]byte, int) {
+	var (
+		mask = thor.NewBloom(eventBloomK)
+		n    int
+	)
+	if criteria.Address != nil {
+		mask.Add(criteria.Address.Bytes())
+		n++
+	}
+	for _, topic := range criteria.Topics {
+		if topic != nil {
+			mask.Add(topic.Bytes())
+			n++
+		}
+	}
+	return mask.Bits[:], n
+}
+
+// bloomCondition returns the condition to eliminate blocks by blooms. It's empty if blooms don't
+// cover the range, or don't help, i.e. some criteria match any address and topic, or there's only a
+// single address or topic, which is served well by indexes. Removed logs are not covered, since blooms
+// are replaced with blocks of new branches.
+func bloomCondition(filter *EventFilter, bloomFrom uint32) (string, []interface{}) {
+	if len(filter.CriteriaSet) == 0 || filter.IncludeRemoved || filter.Range == nil || filter.Range.Unit == Time || filter.Range.From < uint64(bloomFrom) {
+		return "", nil
+	}
+	var (
+		conds = make([]string, 0, len(filter.CriteriaSet))
+		masks = make([]interface{}, 0, len(filter.CriteriaSet))
+		total int
+	)
+	for _, criteria := range filter.CriteriaSet {
+		mask, n := eventCriteriaMask(criteria)
+		if n == 0 {
+			return "", nil
+		}
+		total += n
+		conds = append(conds, "bloom_match(bloom, ?)")
+		masks = append(masks, mask)
+	}
+	if total < 2 {
+		return "", nil
+	}
+	rangeCond, args := rangeCondition(filter.Range)
+	stmt := " AND blockNumber IN (SELECT blockNumber FROM event_bloom WHERE 1" + rangeCond + " AND (" + strings.Join(conds, " OR ") + ")) "
+	return stmt, append(args, masks...)
+}
+
+// mergeEventBlooms adds events into blooms of their blocks.
+func mergeEventBlooms(tx *sql.Tx, events []*Event) error {
+	byBlock := make(map[uint32][]*Event)
+	var nums []uint32
+	for _, event := range events {
+		if _, ok := byBlock[event.BlockNumber]; !ok {
+			nums = append(nums, event.BlockNumber)
+		}
+		byBlock[event.BlockNumber] = append(byBlock[event.BlockNumber], event)
+	}
+	for _, num := range nums {
+		bloom := newEventBloom(byBlock[num])
+		var existing []byte
+		if err := tx.QueryRow("SELECT bloom FROM event_bloom WHERE blockNumber = ?", num).Scan(&existing); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		for i := 0; i < len(existing) && i < len(bloom); i++ {
+			bloom[i] |= existing[i]
+		}
+		if _, err := tx.Exec("INSERT OR REPLACE INTO event_bloom(blockNumber, bloom) VALUES(?,?)", num, bloom); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryBloomFrom returns the number of the first block covered by blooms.
+func queryBloomFrom(db *sql.DB) (uint32, error) {
+	var from uint32
+	if err := db.QueryRow("SELECT value FROM config WHERE key=?", configBloomFromKey).Scan(&from); err != nil {
+		return 0, err
+	}
+	return from, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vechain/thor/tracing"
+)
+
+// GroupBy is the column to group counts by.
+type GroupBy string
+
+// Columns to group events or transfers by.
+const (
+	GroupByAddress   GroupBy = "address"   // events
+	GroupByTopic0    GroupBy = "topic0"    // events
+	GroupByTxOrigin  GroupBy = "txOrigin"  // events and transfers
+	GroupBySender    GroupBy = "sender"    // transfers
+	GroupByRecipient GroupBy = "recipient" // transfers
+)
+
+// ErrUnsupportedGroupBy is returned if the column is not supported to group logs of the kind.
+var ErrUnsupportedGroupBy = errors.New("unsupported group column")
+
+var (
+	eventGroupColumns    = map[GroupBy]bool{GroupByAddress: true, GroupByTopic0: true, GroupByTxOrigin: true}
+	transferGroupColumns = map[GroupBy]bool{GroupByTxOrigin: true, GroupBySender: true, GroupByRecipient: true}
+)
+
+// GroupCount is the count of logs sharing the value of the grouping column.
+type GroupCount struct {
+	Key   []byte // nil if the column is empty, e.g. events without topics
+	Count uint64
+}
+
+// CountEvents counts events matching the filter. Options and order of the filter are ignored.
+func (db *LogDB) CountEvents(ctx context.Context, filter *EventFilter) (uint64, error) {
+	var (
+		cond   string
+		args   []interface{}
+		source = "event"
+	)
+	if filter != nil {
+		cond, args = db.eventConditions(filter)
+		source = logSource(source, filter.IncludeRemoved)
+	}
+	return db.queryCount(ctx, "SELECT COUNT(*) FROM "+source+" WHERE 1"+cond, args...)
+}
+
+// CountTransfers counts transfers matching the filter. Options and order of the filter are ignored.
+func (db *LogDB) CountTransfers(ctx context.Context, filter *TransferFilter) (uint64, error) {
+	var (
+		cond   string
+		args   []interface{}
+		source = "transfer"
+	)
+	if filter != nil {
+		cond, args = transferConditions(filter)
+		source = logSource(source, filter.IncludeRemoved)
+	}
+	return db.queryCount(ctx, "SELECT COUNT(*) FROM "+source+" WHERE 1"+cond, args...)
+}
+
+// CountEventsBy counts events matching the filter grouped by the column, at most limit groups with
+// largest counts are returned.
+func (db *LogDB) CountEventsBy(ctx context.Context, filter *EventFilter, by GroupBy, limit uint64) ([]*GroupCount, error) {
+	if !eventGroupColumns[by] {
+		return nil, ErrUnsupportedGroupBy
+	}
+	var (
+		cond   string
+		args   []interface{}
+		source = "event"
+	)
+	if filter != nil {
+		cond, args = db.eventConditions(filter)
+		source = logSource(source, filter.IncludeRemoved)
+	}
+	return db.queryGroupCounts(ctx, source, string(by), cond, args, limit)
+}
+
+// CountTransfersBy counts transfers matching the filter grouped by the column, at most limit groups with
+// largest counts are returned.
+func (db *LogDB) CountTransfersBy(ctx context.Context, filter *TransferFilter, by GroupBy, limit uint64) ([]*GroupCount, error) {
+	if !transferGroupColumns[by] {
+		return nil, ErrUnsupportedGroupBy
+	}
+	var (
+		cond   string
+		args   []interface{}
+		source = "transfer"
+	)
+	if filter != nil {
+		cond, args = transferConditions(filter)
+		source = logSource(source, filter.IncludeRemoved)
+	}
+	return db.queryGroupCounts(ctx, source, string(by), cond, args, limit)
+}
+
+func (db *LogDB) queryCount(ctx context.Context, stmt string, args ...interface{}) (count uint64, err error) {
+	ctx, span := tracing.Start(ctx, "logdb.queryCount")
+	start := time.Now()
+	defer func() {
+		span.End()
+		db.logSlowQuery(start, stmt, args, 1, err)
+	}()
+
+	if err := db.reader.QueryRowContext(ctx, stmt, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// queryGroupCounts queries counts of the table grouped by the column, ordered by count descending.
+// Table and column are put into the statement directly, so must be validated against group columns.
+func (db *LogDB) queryGroupCounts(ctx context.Context, table, column, cond string, args []interface{}, limit uint64) (groups []*GroupCount, err error) {
+	stmt := "SELECT " + column + ", COUNT(*) AS n FROM " + table + " WHERE 1" + cond +
+		" GROUP BY " + column + " ORDER BY n DESC, " + column + " ASC limit ?"
+	args = append(args, limit)
+
+	ctx, span := tracing.Start(ctx, "logdb.queryGroupCounts")
+	start := time.Now()
+	defer func() {
+		span.SetAttribute("rows", int64(len(groups)))
+		span.End()
+		db.logSlowQuery(start, stmt, args, len(groups), err)
+	}()
+
+	rows, err := db.reader.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var group GroupCount
+		if err := rows.Scan(&group.Key, &group.Count); err != nil {
+			return nil, err
+		}
+		groups = append(groups, &group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
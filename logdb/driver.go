@@ -0,0 +1,26 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// driverName is sqlite3 with functions used by logdb registered.
+const driverName = "sqlite3_logdb"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("bloom_match", bloomMatch, true); err != nil {
+				return err
+			}
+			return conn.RegisterAggregator("amount_sum", newAmountSum, true)
+		},
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+// ErrEncryptionNotSupported returned when opening encrypted log db, but the sqlite3 library is not SQLCipher.
+var ErrEncryptionNotSupported = errors.New("encryption not supported, sqlite3 library should be SQLCipher")
+
+// EncryptionSupported returns whether the linked sqlite3 library supports encryption.
+// That's the case if built with tag 'libsqlite3' and linked against SQLCipher.
+func EncryptionSupported() bool {
+	db, err := sql.Open("sqlite3", "file::memory:")
+	if err != nil {
+		return false
+	}
+	defer db.Close()
+
+	var ver string
+	if err := db.QueryRow("PRAGMA cipher_version").Scan(&ver); err != nil {
+		return false
+	}
+	return ver != ""
+}
+
+// NewEncrypted create or open log db at given path, which is encrypted by the key.
+// The key is a passphrase, which is compatible with 'PRAGMA key' of SQLCipher.
+func NewEncrypted(path string, key string) (*LogDB, error) {
+	return openEncrypted(path, "file:"+path+"?_journal=wal&cache=shared&_auto_vacuum=incremental", "file:"+path+"?mode=ro&_journal=wal", false, key)
+}
+
+// NewReadOnlyEncrypted open an existing encrypted log db at given path in read-only mode.
+func NewReadOnlyEncrypted(path string, key string) (*LogDB, error) {
+	return openEncrypted(path, "file:"+path+"?mode=ro&_journal=wal&cache=shared", "file:"+path+"?mode=ro&_journal=wal", true, key)
+}
+
+func openEncrypted(path string, dsn string, readerDSN string, readOnly bool, key string) (*LogDB, error) {
+	if key == "" {
+		return nil, errors.New("empty key")
+	}
+	// a library without codec ignores the key, and the db would be stored in plain
+	if !EncryptionSupported() {
+		return nil, ErrEncryptionNotSupported
+	}
+	// the key is passed by uri parameter, to be applied before any pragma executed by the driver
+	hexKey := "&hexkey=" + hex.EncodeToString([]byte(key))
+	return open(path, dsn+hexKey, readerDSN+hexKey, readOnly)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/vechain/thor/thor"
+)
+
+// count of logs imported in one transaction
+const importBatchSize = 1000
+
+// Kinds of exported logs.
+const (
+	KindEvent    = "event"
+	KindTransfer = "transfer"
+)
+
+// JSONLog is a line of exported logs, in JSON Lines.
+// Fields of the other kind are omitted.
+type JSONLog struct {
+	Kind        string       `json:"kind"`
+	BlockNumber uint32       `json:"blockNumber"`
+	Index       uint32       `json:"index"`
+	BlockID     thor.Bytes32 `json:"blockID"`
+	BlockTime   uint64       `json:"blockTime"`
+	TxID        thor.Bytes32 `json:"txID"`
+	TxOrigin    thor.Address `json:"txOrigin"`
+	ClauseIndex uint32       `json:"clauseIndex"`
+
+	Address  *thor.Address  `json:"address,omitempty"`
+	Topics   []thor.Bytes32 `json:"topics,omitempty"`
+	Data     hexutil.Bytes  `json:"data,omitempty"`
+	LogIndex uint32         `json:"logIndex,omitempty"`
+
+	Sender    *thor.Address `json:"sender,omitempty"`
+	Recipient *thor.Address `json:"recipient,omitempty"`
+	Amount    *hexutil.Big  `json:"amount,omitempty"`
+}
+
+// Export writes events then transfers in the range as JSON Lines, all logs if rng is nil.
+func (db *LogDB) Export(ctx context.Context, w io.Writer, rng *Range) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := StreamEvents(ctx, db, &EventFilter{Range: rng}, func(event *Event) error {
+		l := &JSONLog{
+			Kind:        KindEvent,
+			BlockNumber: event.BlockNumber,
+			Index:       event.Index,
+			BlockID:     event.BlockID,
+			BlockTime:   event.BlockTime,
+			TxID:        event.TxID,
+			TxOrigin:    event.TxOrigin,
+			ClauseIndex: event.ClauseIndex,
+			Address:     &event.Address,
+			Data:        event.Data,
+			LogIndex:    event.LogIndex,
+		}
+		for _, topic := range event.Topics {
+			if topic == nil {
+				break
+			}
+			l.Topics = append(l.Topics, *topic)
+		}
+		return enc.Encode(l)
+	}); err != nil {
+		return err
+	}
+	if err := StreamTransfers(ctx, db, &TransferFilter{Range: rng}, func(transfer *Transfer) error {
+		return enc.Encode(&JSONLog{
+			Kind:        KindTransfer,
+			BlockNumber: transfer.BlockNumber,
+			Index:       transfer.Index,
+			BlockID:     transfer.BlockID,
+			BlockTime:   transfer.BlockTime,
+			TxID:        transfer.TxID,
+			TxOrigin:    transfer.TxOrigin,
+			ClauseIndex: transfer.ClauseIndex,
+			Sender:      &transfer.Sender,
+			Recipient:   &transfer.Recipient,
+			Amount:      (*hexutil.Big)(transfer.Amount),
+		})
+	}); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Import reads logs in JSON Lines written by Export, and inserts them, replacing existing logs at
+// the same positions. The last block number is raised to the highest imported block.
+func (db *LogDB) Import(ctx context.Context, r io.Reader) error {
+	var (
+		dec       = json.NewDecoder(bufio.NewReader(r))
+		events    []*Event
+		transfers []*Transfer
+		last      uint32
+		line      int
+	)
+	flush := func() error {
+		err := db.execInTx(func(tx *sql.Tx) error {
+			if err := insertEvents(tx, events); err != nil {
+				return err
+			}
+			if err := mergeEventBlooms(tx, events); err != nil {
+				return err
+			}
+			if err := insertTransfers(tx, transfers); err != nil {
+				return err
+			}
+			var data []byte
+			if err := tx.QueryRow("SELECT value FROM config WHERE key=?", configBlockNumKey).Scan(&data); err != nil && err != sql.ErrNoRows {
+				return err
+			}
+			if len(data) == 4 && binary.BigEndian.Uint32(data) >= last {
+				return nil
+			}
+			var b4 [4]byte
+			binary.BigEndian.PutUint32(b4[:], last)
+			_, err := tx.Exec("INSERT OR REPLACE INTO config(key, value) VALUES(?,?)", configBlockNumKey, b4[:])
+			return err
+		})
+		events, transfers = events[:0], transfers[:0]
+		return err
+	}
+
+	for {
+		var l JSONLog
+		if err := dec.Decode(&l); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fmt.Errorf("line %v: %v", line+1, err)
+		}
+		line++
+		switch l.Kind {
+		case KindEvent:
+			if l.Address == nil || len(l.Topics) > 5 {
+				return fmt.Errorf("line %v: invalid event", line)
+			}
+			event := &Event{
+				BlockNumber: l.BlockNumber,
+				Index:       l.Index,
+				BlockID:     l.BlockID,
+				BlockTime:   l.BlockTime,
+				TxID:        l.TxID,
+				TxOrigin:    l.TxOrigin,
+				ClauseIndex: l.ClauseIndex,
+				Address:     *l.Address,
+				Data:        l.Data,
+				LogIndex:    l.LogIndex,
+			}
+			for i := range l.Topics {
+				event.Topics[i] = &l.Topics[i]
+			}
+			events = append(events, event)
+		case KindTransfer:
+			if l.Sender == nil || l.Recipient == nil || l.Amount == nil {
+				return fmt.Errorf("line %v: invalid transfer", line)
+			}
+			transfers = append(transfers, &Transfer{
+				BlockNumber: l.BlockNumber,
+				Index:       l.Index,
+				BlockID:     l.BlockID,
+				BlockTime:   l.BlockTime,
+				TxID:        l.TxID,
+				TxOrigin:    l.TxOrigin,
+				ClauseIndex: l.ClauseIndex,
+				Sender:      *l.Sender,
+				Recipient:   *l.Recipient,
+				Amount:      (*big.Int)(l.Amount),
+			})
+		default:
+			return fmt.Errorf("line %v: unknown kind %q", line, l.Kind)
+		}
+		if l.BlockNumber > last {
+			last = l.BlockNumber
+		}
+
+		if len(events)+len(transfers) >= importBatchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(events)+len(transfers) > 0 {
+		return flush()
+	}
+	return nil
+}
@@ -11,24 +11,68 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/event"
 	sqlite3 "github.com/mattn/go-sqlite3"
 	"github.com/vechain/thor/block"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tracing"
 	"github.com/vechain/thor/tx"
 )
 
 var configBlockNumKey = "blockNum"
 
+// max count of rows inserted in one statement, to keep variables under the sqlite limit of 999
+const insertBatchRows = 64
+
+// max count of logs removed in one statement when pruning
+const pruneBatchSize = 10000
+
+// max count of connections to read concurrently
+const maxReadConns = 8
+
+// LogDB is the Store backed by sqlite.
+// Writes go through a single connection, and queries through a separate pool of read-only connections,
+// so that slow queries don't block writes, as WAL allows readers to run along with the writer.
 type LogDB struct {
+	slowQueryThreshold int64  // in nanoseconds, accessed atomically
+	maxRows            uint64 // accessed atomically
+	maxBytes           uint64 // accessed atomically
+	keepRemoved        int32  // accessed atomically
+	truncateSubs       int32  // count of truncate event subscribers, accessed atomically
+
 	path          string
-	db            *sql.DB
+	db            *sql.DB // the writer
+	reader        *sql.DB // same as db if not separated
 	driverVersion string
+	bloomFrom     uint32 // the first block covered by event blooms
+
+	truncateFeed event.Feed
+	scope        event.SubscriptionScope
 }
 
 // New create or open log db at given path.
 func New(path string) (logDB *LogDB, err error) {
-	db, err := sql.Open("sqlite3", path+"?_journal=wal&cache=shared")
+	return open(path, path+"?_journal=wal&cache=shared&_auto_vacuum=incremental", "file:"+path+"?mode=ro&_journal=wal", false)
+}
+
+// NewReadOnly open an existing log db at given path in read-only mode.
+func NewReadOnly(path string) (logDB *LogDB, err error) {
+	return open(path, "file:"+path+"?mode=ro&_journal=wal&cache=shared", "file:"+path+"?mode=ro&_journal=wal", true)
+}
+
+// NewMem create a log db in ram.
+func NewMem() (*LogDB, error) {
+	// the in-memory db can't be shared by separated pools
+	return open(":memory:", ":memory:?_journal=wal&cache=shared&_auto_vacuum=incremental", "", false)
+}
+
+// open opens the db with the writer dsn, and the reader pool with readerDSN if not empty.
+// The schema is migrated to the latest, or checked if read-only.
+func open(path string, dsn string, readerDSN string, readOnly bool) (logDB *LogDB, err error) {
+	db, err := sql.Open(driverName, dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -41,25 +85,52 @@ func New(path string) (logDB *LogDB, err error) {
 	// to avoid 'database is locked' error
 	db.SetMaxOpenConns(1)
 
-	if _, err := db.Exec(configTableSchema + eventTableSchema + transferTableSchema); err != nil {
+	if readOnly {
+		err = checkSchemaVersion(db)
+	} else {
+		err = migrate(db)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reader := db
+	if readerDSN != "" {
+		// opened after the schema created by the writer
+		if reader, err = sql.Open(driverName, readerDSN); err != nil {
+			return nil, err
+		}
+		reader.SetMaxOpenConns(maxReadConns)
+		if err := reader.Ping(); err != nil {
+			reader.Close()
+			return nil, err
+		}
+	}
+
+	bloomFrom, err := queryBloomFrom(db)
+	if err != nil {
+		if reader != db {
+			reader.Close()
+		}
 		return nil, err
 	}
 
 	driverVer, _, _ := sqlite3.Version()
 	return &LogDB{
-		path,
-		db,
-		driverVer,
+		path:          path,
+		db:            db,
+		reader:        reader,
+		driverVersion: driverVer,
+		bloomFrom:     bloomFrom,
 	}, nil
 }
 
-// NewMem create a log db in ram.
-func NewMem() (*LogDB, error) {
-	return New(":memory:")
-}
-
 // Close close the log db.
 func (db *LogDB) Close() {
+	db.scope.Close()
+	if db.reader != db.db {
+		db.reader.Close()
+	}
 	db.db.Close()
 }
 
@@ -67,55 +138,33 @@ func (db *LogDB) Path() string {
 	return db.path
 }
 
+// Prepare creates a batch to collect logs of the block, same as NewBlockBatch(db, header).
 func (db *LogDB) Prepare(header *block.Header) *BlockBatch {
-	return &BlockBatch{
-		db:     db.db,
-		header: header,
-	}
+	return NewBlockBatch(db, header)
 }
 
 func (db *LogDB) FilterEvents(ctx context.Context, filter *EventFilter) ([]*Event, error) {
 	if filter == nil {
-		return db.queryEvents(ctx, "SELECT * FROM event")
-	}
-	var args []interface{}
-	stmt := "SELECT * FROM event WHERE 1"
-	condition := "blockNumber"
-	if filter.Range != nil {
-		if filter.Range.Unit == Time {
-			condition = "blockTime"
-		}
-		args = append(args, filter.Range.From)
-		stmt += " AND " + condition + " >= ? "
-		if filter.Range.To >= filter.Range.From {
-			args = append(args, filter.Range.To)
-			stmt += " AND " + condition + " <= ? "
-		}
-	}
-	for i, criteria := range filter.CriteriaSet {
-		if i == 0 {
-			stmt += " AND ( 1"
-		} else {
-			stmt += " OR ( 1"
-		}
-		if criteria.Address != nil {
-			args = append(args, criteria.Address.Bytes())
-			stmt += " AND address = ? "
-		}
-		for j, topic := range criteria.Topics {
-			if topic != nil {
-				args = append(args, topic.Bytes())
-				stmt += fmt.Sprintf(" AND topic%v = ?", j)
-			}
-		}
-		stmt += ")"
+		return db.queryEvents(ctx, "SELECT *, 0 FROM event")
 	}
-
-	if filter.Order == DESC {
-		stmt += " ORDER BY blockNumber DESC,eventIndex DESC "
-	} else {
-		stmt += " ORDER BY blockNumber ASC,eventIndex ASC "
+	orderBy, err := orderClause(filter.Sort, eventSortExprs, "eventIndex", filter.Order, filter.IncludeRemoved)
+	if err != nil {
+		return nil, err
 	}
+	cond, args := db.eventConditions(filter)
+	stmt := "SELECT " + logColumns(filter.IncludeRemoved) + " FROM " + logSource("event", filter.IncludeRemoved) + " WHERE 1" + cond
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if len(filter.Sort) > 0 {
+			return nil, ErrCursorWithSort
+		}
+		if filter.IncludeRemoved {
+			return nil, ErrCursorWithRemoved
+		}
+		cond, condArgs := seekCondition("eventIndex", filter.Options.Cursor, filter.Order)
+		stmt += cond
+		args = append(args, condArgs...)
+	}
+	stmt += orderBy
 
 	if filter.Options != nil {
 		stmt += " limit ?, ? "
@@ -126,79 +175,202 @@ func (db *LogDB) FilterEvents(ctx context.Context, filter *EventFilter) ([]*Even
 
 func (db *LogDB) FilterTransfers(ctx context.Context, filter *TransferFilter) ([]*Transfer, error) {
 	if filter == nil {
-		return db.queryTransfers(ctx, "SELECT * FROM transfer")
+		return db.queryTransfers(ctx, "SELECT *, 0 FROM transfer")
+	}
+	orderBy, err := orderClause(filter.Sort, transferSortExprs, "transferIndex", filter.Order, filter.IncludeRemoved)
+	if err != nil {
+		return nil, err
 	}
-	var args []interface{}
-	stmt := "SELECT * FROM transfer WHERE 1"
-	condition := "blockNumber"
-	if filter.Range != nil {
-		if filter.Range.Unit == Time {
-			condition = "blockTime"
+	cond, args := transferConditions(filter)
+	stmt := "SELECT " + logColumns(filter.IncludeRemoved) + " FROM " + logSource("transfer", filter.IncludeRemoved) + " WHERE 1" + cond
+	if filter.Options != nil && filter.Options.Cursor != nil {
+		if len(filter.Sort) > 0 {
+			return nil, ErrCursorWithSort
 		}
-		args = append(args, filter.Range.From)
-		stmt += " AND " + condition + " >= ? "
-		if filter.Range.To >= filter.Range.From {
-			args = append(args, filter.Range.To)
-			stmt += " AND " + condition + " <= ? "
+		if filter.IncludeRemoved {
+			return nil, ErrCursorWithRemoved
 		}
+		cond, condArgs := seekCondition("transferIndex", filter.Options.Cursor, filter.Order)
+		stmt += cond
+		args = append(args, condArgs...)
+	}
+	stmt += orderBy
+	if filter.Options != nil {
+		stmt += " limit ?, ? "
+		args = append(args, filter.Options.Offset, filter.Options.Limit)
+	}
+	return db.queryTransfers(ctx, stmt, args...)
+}
+
+// rangeCondition returns the condition of the range.
+func rangeCondition(rng *Range) (string, []interface{}) {
+	if rng == nil {
+		return "", nil
+	}
+	column := "blockNumber"
+	if rng.Unit == Time {
+		column = "blockTime"
+	}
+	stmt := " AND " + column + " >= ? "
+	args := []interface{}{rng.From}
+	if rng.To >= rng.From {
+		stmt += " AND " + column + " <= ? "
+		args = append(args, rng.To)
+	}
+	return stmt, args
+}
+
+// eventConditions returns conditions of the event filter, options and order excluded.
+func (db *LogDB) eventConditions(filter *EventFilter) (string, []interface{}) {
+	stmt, args := rangeCondition(filter.Range)
+	// the criteria set as a whole, to not break other conditions with OR
+	if len(filter.CriteriaSet) > 0 {
+		cond, condArgs := criteriaSetCondition(len(filter.CriteriaSet), func(i int, eq string) (string, []interface{}) {
+			return eventCriteriaCondition(filter.CriteriaSet[i], eq)
+		}, "=")
+		stmt += " AND " + cond
+		args = append(args, condArgs...)
+
+		cond, condArgs = bloomCondition(filter, db.bloomFrom)
+		stmt += cond
+		args = append(args, condArgs...)
+	}
+	if len(filter.ExclusionSet) > 0 {
+		cond, condArgs := criteriaSetCondition(len(filter.ExclusionSet), func(i int, eq string) (string, []interface{}) {
+			return eventCriteriaCondition(filter.ExclusionSet[i], eq)
+		}, "IS")
+		stmt += " AND NOT " + cond
+		args = append(args, condArgs...)
+	}
+	return stmt, args
+}
+
+func eventCriteriaCondition(criteria *EventCriteria, eq string) (string, []interface{}) {
+	var (
+		stmt = "( 1"
+		args []interface{}
+	)
+	if criteria.Address != nil {
+		args = append(args, criteria.Address.Bytes())
+		stmt += " AND address " + eq + " ? "
+	}
+	for j, topic := range criteria.Topics {
+		if topic != nil {
+			args = append(args, topic.Bytes())
+			stmt += fmt.Sprintf(" AND topic%v %v ?", j, eq)
+		}
+	}
+	if criteria.TxID != nil {
+		args = append(args, criteria.TxID.Bytes())
+		stmt += " AND txID " + eq + " ? "
 	}
+	if criteria.ClauseIndex != nil {
+		args = append(args, *criteria.ClauseIndex)
+		stmt += " AND clauseIndex " + eq + " ? "
+	}
+	if criteria.LogIndex != nil {
+		args = append(args, *criteria.LogIndex)
+		stmt += " AND logIndex " + eq + " ? "
+	}
+	return stmt + ")", args
+}
+
+// transferConditions returns conditions of the transfer filter, options and order excluded.
+func transferConditions(filter *TransferFilter) (string, []interface{}) {
+	stmt, args := rangeCondition(filter.Range)
 	if filter.TxID != nil {
 		args = append(args, filter.TxID.Bytes())
 		stmt += " AND txID = ? "
 	}
-	length := len(filter.CriteriaSet)
-	if length > 0 {
-		for i, criteria := range filter.CriteriaSet {
-			if i == 0 {
-				stmt += " AND (( 1 "
-			} else {
-				stmt += " OR ( 1 "
-			}
-			if criteria.TxOrigin != nil {
-				args = append(args, criteria.TxOrigin.Bytes())
-				stmt += " AND txOrigin = ? "
-			}
-			if criteria.Sender != nil {
-				args = append(args, criteria.Sender.Bytes())
-				stmt += " AND sender = ? "
-			}
-			if criteria.Recipient != nil {
-				args = append(args, criteria.Recipient.Bytes())
-				stmt += " AND recipient = ? "
-			}
-			if i == length-1 {
-				stmt += " )) "
-			} else {
-				stmt += " ) "
-			}
-		}
+	if len(filter.CriteriaSet) > 0 {
+		cond, condArgs := criteriaSetCondition(len(filter.CriteriaSet), func(i int, eq string) (string, []interface{}) {
+			return transferCriteriaCondition(filter.CriteriaSet[i], eq)
+		}, "=")
+		stmt += " AND " + cond
+		args = append(args, condArgs...)
 	}
-	if filter.Order == DESC {
-		stmt += " ORDER BY blockNumber DESC,transferIndex DESC "
-	} else {
-		stmt += " ORDER BY blockNumber ASC,transferIndex ASC "
+	if len(filter.ExclusionSet) > 0 {
+		cond, condArgs := criteriaSetCondition(len(filter.ExclusionSet), func(i int, eq string) (string, []interface{}) {
+			return transferCriteriaCondition(filter.ExclusionSet[i], eq)
+		}, "IS")
+		stmt += " AND NOT " + cond
+		args = append(args, condArgs...)
 	}
-	if filter.Options != nil {
-		stmt += " limit ?, ? "
-		args = append(args, filter.Options.Offset, filter.Options.Limit)
+	return stmt, args
+}
+
+func transferCriteriaCondition(criteria *TransferCriteria, eq string) (string, []interface{}) {
+	var (
+		stmt = "( 1"
+		args []interface{}
+	)
+	if criteria.TxOrigin != nil {
+		args = append(args, criteria.TxOrigin.Bytes())
+		stmt += " AND txOrigin " + eq + " ? "
 	}
-	return db.queryTransfers(ctx, stmt, args...)
+	if criteria.Sender != nil {
+		args = append(args, criteria.Sender.Bytes())
+		stmt += " AND sender " + eq + " ? "
+	}
+	if criteria.Recipient != nil {
+		args = append(args, criteria.Recipient.Bytes())
+		stmt += " AND recipient " + eq + " ? "
+	}
+	return stmt + ")", args
+}
+
+// criteriaSetCondition joins conditions of n criteria with OR, as a whole.
+// Criteria of exclusion sets compare with IS rather than =, so that NULL columns, e.g. absent topics,
+// are taken as not equal rather than unknown, which would exclude the row under NOT.
+func criteriaSetCondition(n int, criteria func(i int, eq string) (string, []interface{}), eq string) (string, []interface{}) {
+	var (
+		conds = make([]string, 0, n)
+		args  []interface{}
+	)
+	for i := 0; i < n; i++ {
+		cond, condArgs := criteria(i, eq)
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
 }
 
-func (db *LogDB) queryEvents(ctx context.Context, stmt string, args ...interface{}) ([]*Event, error) {
-	rows, err := db.db.QueryContext(ctx, stmt, args...)
+// seekCondition returns the condition to seek logs after the cursor in the order.
+// The leading range on blockNumber lets the index be used.
+func seekCondition(indexColumn string, cursor *Cursor, order Order) (string, []interface{}) {
+	args := []interface{}{cursor.BlockNumber, cursor.BlockNumber, cursor.Index}
+	if order == DESC {
+		return " AND blockNumber <= ? AND (blockNumber < ? OR " + indexColumn + " < ?) ", args
+	}
+	return " AND blockNumber >= ? AND (blockNumber > ? OR " + indexColumn + " > ?) ", args
+}
+
+func (db *LogDB) queryEvents(ctx context.Context, stmt string, args ...interface{}) (events []*Event, err error) {
+	ctx, span := tracing.Start(ctx, "logdb.queryEvents")
+	var (
+		start   = time.Now()
+		scanned int
+	)
+	defer func() {
+		span.SetAttribute("rows", int64(len(events)))
+		span.End()
+		db.logSlowQuery(start, stmt, args, scanned, err)
+	}()
+
+	rows, err := db.reader.QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var events []*Event
+	guard := db.newResultGuard()
 	for rows.Next() {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
+		scanned++
 		var (
 			blockNumber uint32
 			index       uint32
@@ -210,6 +382,8 @@ func (db *LogDB) queryEvents(ctx context.Context, stmt string, args ...interface
 			address     []byte
 			topics      [5][]byte
 			data        []byte
+			logIndex    uint32
+			removed     bool
 		)
 		if err := rows.Scan(
 			&blockNumber,
@@ -226,6 +400,8 @@ func (db *LogDB) queryEvents(ctx context.Context, stmt string, args ...interface
 			&topics[3],
 			&topics[4],
 			&data,
+			&logIndex,
+			&removed,
 		); err != nil {
 			return nil, err
 		}
@@ -239,13 +415,20 @@ func (db *LogDB) queryEvents(ctx context.Context, stmt string, args ...interface
 			ClauseIndex: clauseIndex,
 			Address:     thor.BytesToAddress(address),
 			Data:        data,
+			LogIndex:    logIndex,
+			Removed:     removed,
 		}
+		size := eventFixedSize + len(data)
 		for i, topic := range topics {
 			if len(topic) > 0 {
 				h := thor.BytesToBytes32(topic)
 				event.Topics[i] = &h
+				size += len(h)
 			}
 		}
+		if err := guard.add(size); err != nil {
+			return nil, err
+		}
 		events = append(events, event)
 	}
 	if err := rows.Err(); err != nil {
@@ -254,19 +437,32 @@ func (db *LogDB) queryEvents(ctx context.Context, stmt string, args ...interface
 	return events, nil
 }
 
-func (db *LogDB) queryTransfers(ctx context.Context, stmt string, args ...interface{}) ([]*Transfer, error) {
-	rows, err := db.db.QueryContext(ctx, stmt, args...)
+func (db *LogDB) queryTransfers(ctx context.Context, stmt string, args ...interface{}) (transfers []*Transfer, err error) {
+	ctx, span := tracing.Start(ctx, "logdb.queryTransfers")
+	var (
+		start   = time.Now()
+		scanned int
+	)
+	defer func() {
+		span.SetAttribute("rows", int64(len(transfers)))
+		span.End()
+		db.logSlowQuery(start, stmt, args, scanned, err)
+	}()
+
+	rows, err := db.reader.QueryContext(ctx, stmt, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var transfers []*Transfer
+
+	guard := db.newResultGuard()
 	for rows.Next() {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
+		scanned++
 		var (
 			blockNumber uint32
 			index       uint32
@@ -278,6 +474,7 @@ func (db *LogDB) queryTransfers(ctx context.Context, stmt string, args ...interf
 			sender      []byte
 			recipient   []byte
 			amount      []byte
+			removed     bool
 		)
 		if err := rows.Scan(
 			&blockNumber,
@@ -290,6 +487,7 @@ func (db *LogDB) queryTransfers(ctx context.Context, stmt string, args ...interf
 			&sender,
 			&recipient,
 			&amount,
+			&removed,
 		); err != nil {
 			return nil, err
 		}
@@ -304,6 +502,10 @@ func (db *LogDB) queryTransfers(ctx context.Context, stmt string, args ...interf
 			Sender:      thor.BytesToAddress(sender),
 			Recipient:   thor.BytesToAddress(recipient),
 			Amount:      new(big.Int).SetBytes(amount),
+			Removed:     removed,
+		}
+		if err := guard.add(transferFixedSize); err != nil {
+			return nil, err
 		}
 		transfers = append(transfers, trans)
 	}
@@ -325,6 +527,87 @@ func (db *LogDB) QueryLastBlockNumber() (uint32, error) {
 	return binary.BigEndian.Uint32(data), nil
 }
 
+// QueryLastLoggedBlock returns number and ID of the latest block which has logs recorded.
+func (db *LogDB) QueryLastLoggedBlock() (num uint32, id thor.Bytes32, found bool, err error) {
+	for _, table := range []string{"event", "transfer"} {
+		row := db.db.QueryRow("SELECT blockNumber, blockID FROM " + table + " ORDER BY blockNumber DESC LIMIT 1")
+		var (
+			n       uint32
+			blockID []byte
+		)
+		if err := row.Scan(&n, &blockID); err != nil {
+			if sql.ErrNoRows == err {
+				continue
+			}
+			return 0, thor.Bytes32{}, false, err
+		}
+		if !found || n > num {
+			num, id, found = n, thor.BytesToBytes32(blockID), true
+		}
+	}
+	return
+}
+
+// Truncate removes logs of blocks after the given block number, and resets the recorded last block number to it.
+func (db *LogDB) Truncate(num uint32) error {
+	var removed *TruncateEvent
+	if err := db.execInTx(func(tx *sql.Tx) (err error) {
+		if removed, err = db.removedLogs(tx, num+1); err != nil {
+			return err
+		}
+		if err := db.removeLogs(tx, num+1); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE from event where blockNumber > ?", num); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE from transfer where blockNumber > ?", num); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE from event_bloom where blockNumber > ?", num); err != nil {
+			return err
+		}
+		var b4 [4]byte
+		binary.BigEndian.PutUint32(b4[:], num)
+		_, err = tx.Exec("INSERT OR REPLACE INTO config(key, value) VALUES(?,?)", configBlockNumKey, b4[:])
+		return err
+	}); err != nil {
+		return err
+	}
+	if removed != nil {
+		db.truncateFeed.Send(removed)
+	}
+	return nil
+}
+
+// Prune removes logs of blocks before the given block number, and returns the count of removed logs.
+// Logs are removed in small batches, so that queries are not blocked for long.
+func (db *LogDB) Prune(num uint32) (int64, error) {
+	var total int64
+	for _, table := range []string{"event", "transfer", "removed_event", "removed_transfer"} {
+		stmt := "DELETE FROM " + table + " WHERE rowid IN (SELECT rowid FROM " + table + " WHERE blockNumber < ? LIMIT ?)"
+		for {
+			r, err := db.db.Exec(stmt, num, pruneBatchSize)
+			if err != nil {
+				return total, err
+			}
+			n, err := r.RowsAffected()
+			if err != nil {
+				return total, err
+			}
+			total += n
+			if n < pruneBatchSize {
+				break
+			}
+		}
+	}
+	// one row per block, few enough to be removed at once
+	if _, err := db.db.Exec("DELETE FROM event_bloom WHERE blockNumber < ?", num); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
 func topicValue(topic *thor.Bytes32) []byte {
 	if topic == nil {
 		return nil
@@ -332,15 +615,8 @@ func topicValue(topic *thor.Bytes32) []byte {
 	return topic.Bytes()
 }
 
-type BlockBatch struct {
-	db        *sql.DB
-	header    *block.Header
-	events    []*Event
-	transfers []*Transfer
-}
-
-func (bb *BlockBatch) execInTx(proc func(*sql.Tx) error) (err error) {
-	tx, err := bb.db.Begin()
+func (db *LogDB) execInTx(proc func(*sql.Tx) error) (err error) {
+	tx, err := db.db.Begin()
 	if err != nil {
 		return err
 	}
@@ -351,64 +627,152 @@ func (bb *BlockBatch) execInTx(proc func(*sql.Tx) error) (err error) {
 	return tx.Commit()
 }
 
-func (bb *BlockBatch) Commit() error {
-	return bb.execInTx(func(tx *sql.Tx) error {
+// WriteBlock writes logs of the block, replacing logs of blocks not lower than it.
+func (db *LogDB) WriteBlock(header *block.Header, events []*Event, transfers []*Transfer) error {
+	var removed *TruncateEvent
+	if err := db.execInTx(func(tx *sql.Tx) (err error) {
 		// skip on initializing genesis
-		if bb.header.Number() > 0 {
-			if _, err := tx.Exec("DELETE from event where blockNumber >= ?", bb.header.Number()); err != nil {
+		if header.Number() > 0 {
+			if removed, err = db.removedLogs(tx, header.Number()); err != nil {
+				return err
+			}
+			if err := db.removeLogs(tx, header.Number()); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("DELETE from event where blockNumber >= ?", header.Number()); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("DELETE from transfer where blockNumber >= ?", header.Number()); err != nil {
 				return err
 			}
-			if _, err := tx.Exec("DELETE from transfer where blockNumber >= ?", bb.header.Number()); err != nil {
+			if _, err := tx.Exec("DELETE from event_bloom where blockNumber >= ?", header.Number()); err != nil {
 				return err
 			}
 			var b4 [4]byte
-			binary.BigEndian.PutUint32(b4[:], bb.header.Number())
+			binary.BigEndian.PutUint32(b4[:], header.Number())
 
 			tx.Exec("INSERT OR REPLACE INTO config(key, value) VALUES(?,?)",
 				configBlockNumKey,
 				b4[:],
 			)
-		}
-
-		for _, event := range bb.events {
-			if _, err := tx.Exec("INSERT OR REPLACE INTO event(blockNumber, eventIndex, blockID, blockTime, txID, txOrigin, clauseIndex, address, topic0, topic1, topic2, topic3, topic4, data) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);",
-				event.BlockNumber,
-				event.Index,
-				event.BlockID.Bytes(),
-				event.BlockTime,
-				event.TxID.Bytes(),
-				event.TxOrigin.Bytes(),
-				event.ClauseIndex,
-				event.Address.Bytes(),
-				topicValue(event.Topics[0]),
-				topicValue(event.Topics[1]),
-				topicValue(event.Topics[2]),
-				topicValue(event.Topics[3]),
-				topicValue(event.Topics[4]),
-				event.Data,
-			); err != nil {
+			if err := restoreLogs(tx, header.Number(), header.ID()); err != nil {
 				return err
 			}
 		}
 
-		for _, transfer := range bb.transfers {
-			if _, err := tx.Exec("INSERT OR REPLACE INTO transfer(blockNumber, transferIndex, blockID, blockTime, txID, txOrigin, clauseIndex, sender, recipient, amount) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);",
-				transfer.BlockNumber,
-				transfer.Index,
-				transfer.BlockID.Bytes(),
-				transfer.BlockTime,
-				transfer.TxID.Bytes(),
-				transfer.TxOrigin.Bytes(),
-				transfer.ClauseIndex,
-				transfer.Sender.Bytes(),
-				transfer.Recipient.Bytes(),
-				transfer.Amount.Bytes(),
-			); err != nil {
+		if err := insertEvents(tx, events); err != nil {
+			return err
+		}
+		if err := mergeEventBlooms(tx, events); err != nil {
+			return err
+		}
+		return insertTransfers(tx, transfers)
+	}); err != nil {
+		return err
+	}
+	if removed != nil {
+		db.truncateFeed.Send(removed)
+	}
+	return nil
+}
+
+func insertEvents(tx *sql.Tx, events []*Event) error {
+	rows := make([][]interface{}, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, []interface{}{
+			event.BlockNumber,
+			event.Index,
+			event.BlockID.Bytes(),
+			event.BlockTime,
+			event.TxID.Bytes(),
+			event.TxOrigin.Bytes(),
+			event.ClauseIndex,
+			event.Address.Bytes(),
+			topicValue(event.Topics[0]),
+			topicValue(event.Topics[1]),
+			topicValue(event.Topics[2]),
+			topicValue(event.Topics[3]),
+			topicValue(event.Topics[4]),
+			event.Data,
+			event.LogIndex,
+		})
+	}
+	return insertRows(tx, "event(blockNumber, eventIndex, blockID, blockTime, txID, txOrigin, clauseIndex, address, topic0, topic1, topic2, topic3, topic4, data, logIndex)", 15, rows)
+}
+
+func insertTransfers(tx *sql.Tx, transfers []*Transfer) error {
+	rows := make([][]interface{}, 0, len(transfers))
+	for _, transfer := range transfers {
+		rows = append(rows, []interface{}{
+			transfer.BlockNumber,
+			transfer.Index,
+			transfer.BlockID.Bytes(),
+			transfer.BlockTime,
+			transfer.TxID.Bytes(),
+			transfer.TxOrigin.Bytes(),
+			transfer.ClauseIndex,
+			transfer.Sender.Bytes(),
+			transfer.Recipient.Bytes(),
+			transfer.Amount.Bytes(),
+		})
+	}
+	return insertRows(tx, "transfer(blockNumber, transferIndex, blockID, blockTime, txID, txOrigin, clauseIndex, sender, recipient, amount)", 10, rows)
+}
+
+// insertRows inserts rows into the table with multi-row statements. The statement of full batch is
+// prepared once and reused, with a second one for the remainder.
+func insertRows(tx *sql.Tx, table string, columns int, rows [][]interface{}) error {
+	for len(rows) > 0 {
+		n := insertBatchRows
+		if len(rows) < n {
+			n = len(rows)
+		}
+		stmt, err := tx.Prepare(insertStatement(table, columns, n))
+		if err != nil {
+			return err
+		}
+		args := make([]interface{}, 0, n*columns)
+		for len(rows) >= n {
+			args = args[:0]
+			for _, row := range rows[:n] {
+				args = append(args, row...)
+			}
+			if _, err := stmt.Exec(args...); err != nil {
+				stmt.Close()
 				return err
 			}
+			rows = rows[n:]
 		}
-		return nil
-	})
+		stmt.Close()
+	}
+	return nil
+}
+
+// insertStatement builds 'INSERT OR REPLACE INTO table VALUES (?, ...), ...' of n rows.
+func insertStatement(table string, columns, n int) string {
+	row := "(?" + strings.Repeat(",?", columns-1) + ")"
+	return "INSERT OR REPLACE INTO " + table + " VALUES " + row + strings.Repeat(","+row, n-1)
+}
+
+// BlockBatch collects logs of a block, and writes them into the store on commit.
+type BlockBatch struct {
+	store     Store
+	header    *block.Header
+	events    []*Event
+	transfers []*Transfer
+}
+
+// NewBlockBatch creates a batch to collect logs of the block.
+func NewBlockBatch(store Store, header *block.Header) *BlockBatch {
+	return &BlockBatch{
+		store:  store,
+		header: header,
+	}
+}
+
+// Commit writes collected logs into the store.
+func (bb *BlockBatch) Commit() error {
+	return bb.store.WriteBlock(bb.header, bb.events, bb.transfers)
 }
 
 func (bb *BlockBatch) ForTransaction(txID thor.Bytes32, txOrigin thor.Address) struct {
@@ -418,8 +782,8 @@ func (bb *BlockBatch) ForTransaction(txID thor.Bytes32, txOrigin thor.Address) s
 		Insert func(events tx.Events, transfers tx.Transfers, clauseIndex uint32) *BlockBatch
 	}{
 		func(events tx.Events, transfers tx.Transfers, clauseIndex uint32) *BlockBatch {
-			for _, event := range events {
-				bb.events = append(bb.events, newEvent(bb.header, uint32(len(bb.events)), txID, txOrigin, clauseIndex, event))
+			for i, event := range events {
+				bb.events = append(bb.events, newEvent(bb.header, uint32(len(bb.events)), txID, txOrigin, clauseIndex, uint32(i), event))
 			}
 			for _, transfer := range transfers {
 				bb.transfers = append(bb.transfers, newTransfer(bb.header, uint32(len(bb.transfers)), txID, txOrigin, clauseIndex, transfer))
@@ -6,15 +6,30 @@
 package logdb_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
 	"math/big"
 	"os"
 	"os/user"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/inconshreveable/log15"
 	"github.com/stretchr/testify/assert"
 	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
 	logdb "github.com/vechain/thor/logdb"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
 )
@@ -183,3 +198,1209 @@ func BenchmarkLog(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkCommit(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("logs-%v", n), func(b *testing.B) {
+			dir, err := ioutil.TempDir("", "logdb")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			db, err := logdb.New(filepath.Join(dir, "logs.db"))
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer db.Close()
+
+			txEvent := &tx.Event{
+				Address: thor.BytesToAddress([]byte("addr")),
+				Topics:  []thor.Bytes32{thor.BytesToBytes32([]byte("topic0")), thor.BytesToBytes32([]byte("topic1"))},
+				Data:    []byte("data"),
+			}
+			txTransfer := &tx.Transfer{Sender: thor.BytesToAddress([]byte("sender")), Amount: big.NewInt(1)}
+
+			header := new(block.Builder).Build().Header()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				header = new(block.Builder).ParentID(header.ID()).Build().Header()
+				batch := db.Prepare(header)
+				for j := 0; j < n; j++ {
+					batch.ForTransaction(thor.BytesToBytes32([]byte("txID")), thor.BytesToAddress([]byte("txOrigin"))).
+						Insert(tx.Events{txEvent}, tx.Transfers{txTransfer}, 0)
+				}
+				if err := batch.Commit(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, _, found, err := db.QueryLastLoggedBlock()
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	txEvent := &tx.Event{Address: thor.BytesToAddress([]byte("addr")), Topics: []thor.Bytes32{{}}}
+	txTransfer := &tx.Transfer{Amount: big.NewInt(1)}
+
+	header := new(block.Builder).Build().Header()
+	var headers []*block.Header
+	for i := 0; i < 10; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		headers = append(headers, header)
+		batch := db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{})
+		if i%2 == 0 {
+			batch.Insert(tx.Events{txEvent}, nil, 0)
+		} else {
+			batch.Insert(nil, tx.Transfers{txTransfer}, 0)
+		}
+		if err := batch.Insert(nil, nil, 0).Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	num, id, found, err := db.QueryLastLoggedBlock()
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, headers[9].Number(), num)
+	assert.Equal(t, headers[9].ID(), id)
+
+	assert.Nil(t, db.Truncate(headers[7].Number()))
+	last, err := db.QueryLastBlockNumber()
+	assert.Nil(t, err)
+	assert.Equal(t, headers[7].Number(), last)
+	num, id, _, _ = db.QueryLastLoggedBlock()
+	assert.Equal(t, headers[7].Number(), num)
+	assert.Equal(t, headers[7].ID(), id)
+
+	assert.Nil(t, db.Truncate(0))
+	_, _, found, _ = db.QueryLastLoggedBlock()
+	assert.False(t, found)
+}
+
+func TestEncryption(t *testing.T) {
+	if logdb.EncryptionSupported() {
+		t.Skip("encryption supported")
+	}
+	_, err := logdb.NewEncrypted(":memory:", "secret")
+	assert.Equal(t, logdb.ErrEncryptionNotSupported, err)
+}
+
+func TestSlowQuery(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var records []*log15.Record
+	defer log15.Root().SetHandler(log15.Root().GetHandler())
+	log15.Root().SetHandler(log15.FuncHandler(func(r *log15.Record) error {
+		if r.Msg == "slow query" {
+			records = append(records, r)
+		}
+		return nil
+	}))
+
+	addr := thor.BytesToAddress([]byte("addr"))
+	filter := &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{Address: &addr}},
+	}
+	_, err = db.FilterEvents(context.Background(), filter)
+	assert.Nil(t, err)
+	assert.Empty(t, records, "disabled by default")
+
+	db.SetSlowQueryThreshold(time.Nanosecond)
+	_, err = db.FilterEvents(context.Background(), filter)
+	assert.Nil(t, err)
+	_, err = db.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+	if assert.Len(t, records, 2) {
+		ctx := make(map[interface{}]interface{})
+		for i := 0; i < len(records[0].Ctx); i += 2 {
+			ctx[records[0].Ctx[i]] = records[0].Ctx[i+1]
+		}
+		assert.Equal(t, 0, ctx["scanned"])
+		assert.Contains(t, ctx["sql"], "address = ?")
+		assert.Equal(t, "[blob(20)]", ctx["args"])
+		assert.Contains(t, ctx["plan"], "event")
+	}
+}
+
+func TestCursor(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	addr1 := thor.BytesToAddress([]byte("addr1"))
+	addr2 := thor.BytesToAddress([]byte("addr2"))
+	transfer := &tx.Transfer{Sender: addr1, Recipient: addr2, Amount: big.NewInt(1)}
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 30; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		// 3 logs per block
+		if err := db.Prepare(header).ForTransaction(thor.Bytes32{}, addr1).
+			Insert(tx.Events{{Address: addr1}, {Address: addr2}, {Address: addr1}}, tx.Transfers{transfer, transfer, transfer}, 0).
+			Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var cursor logdb.Cursor
+	assert.NotNil(t, cursor.UnmarshalText([]byte("invalid")))
+	assert.Nil(t, cursor.UnmarshalText([]byte((&logdb.Cursor{BlockNumber: 1, Index: 2}).String())))
+	assert.Equal(t, logdb.Cursor{BlockNumber: 1, Index: 2}, cursor)
+
+	for _, order := range []logdb.Order{logdb.ASC, logdb.DESC} {
+		filter := &logdb.EventFilter{
+			// the range applies to all criteria
+			CriteriaSet: []*logdb.EventCriteria{{Address: &addr1}, {Address: &addr2}},
+			Range:       &logdb.Range{Unit: logdb.Block, From: 5, To: 24},
+			Order:       order,
+		}
+		all, err := db.FilterEvents(context.Background(), filter)
+		assert.Nil(t, err)
+		assert.Len(t, all, 60)
+
+		var walked []*logdb.Event
+		filter.Options = &logdb.Options{Limit: 7}
+		for {
+			page, err := db.FilterEvents(context.Background(), filter)
+			assert.Nil(t, err)
+			walked = append(walked, page...)
+			if len(page) < 7 {
+				break
+			}
+			filter.Options.Cursor = page[len(page)-1].Cursor()
+		}
+		assert.Equal(t, all, walked, order)
+
+		tf := &logdb.TransferFilter{Order: order}
+		allTransfers, err := db.FilterTransfers(context.Background(), tf)
+		assert.Nil(t, err)
+		var walkedTransfers []*logdb.Transfer
+		tf.Options = &logdb.Options{Limit: 10}
+		for {
+			page, err := db.FilterTransfers(context.Background(), tf)
+			assert.Nil(t, err)
+			walkedTransfers = append(walkedTransfers, page...)
+			if len(page) < 10 {
+				break
+			}
+			tf.Options.Cursor = page[len(page)-1].Cursor()
+		}
+		assert.Equal(t, allTransfers, walkedTransfers, order)
+	}
+}
+
+func TestCount(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	addr1 := thor.BytesToAddress([]byte("addr1"))
+	addr2 := thor.BytesToAddress([]byte("addr2"))
+	topic := thor.BytesToBytes32([]byte("topic"))
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 10; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		if err := db.Prepare(header).ForTransaction(thor.Bytes32{}, addr1).
+			Insert(
+				tx.Events{{Address: addr1, Topics: []thor.Bytes32{topic}}, {Address: addr2}, {Address: addr1}},
+				tx.Transfers{{Sender: addr1, Recipient: addr2, Amount: big.NewInt(1)}},
+				0).
+			Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	n, err := db.CountEvents(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(30), n)
+
+	filter := &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{Address: &addr1}},
+		Range:       &logdb.Range{Unit: logdb.Block, From: 2, To: 6},
+		Options:     &logdb.Options{Limit: 1}, // ignored
+	}
+	n, err = db.CountEvents(ctx, filter)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10), n)
+
+	groups, err := db.CountEventsBy(ctx, nil, logdb.GroupByAddress, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.GroupCount{
+		{Key: addr1.Bytes(), Count: 20},
+		{Key: addr2.Bytes(), Count: 10},
+	}, groups)
+
+	groups, err = db.CountEventsBy(ctx, nil, logdb.GroupByTopic0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.GroupCount{{Key: nil, Count: 20}}, groups, "limited")
+
+	_, err = db.CountEventsBy(ctx, nil, logdb.GroupBySender, 10)
+	assert.Equal(t, logdb.ErrUnsupportedGroupBy, err)
+
+	n, err = db.CountTransfers(ctx, &logdb.TransferFilter{
+		CriteriaSet: []*logdb.TransferCriteria{{Recipient: &addr1}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), n)
+
+	groups, err = db.CountTransfersBy(ctx, nil, logdb.GroupByRecipient, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.GroupCount{{Key: addr2.Bytes(), Count: 10}}, groups)
+}
+
+type memStore struct {
+	logdb.Store
+	events    map[uint32][]*logdb.Event
+	transfers map[uint32][]*logdb.Transfer
+}
+
+func (s *memStore) WriteBlock(header *block.Header, events []*logdb.Event, transfers []*logdb.Transfer) error {
+	s.events[header.Number()] = events
+	s.transfers[header.Number()] = transfers
+	return nil
+}
+
+func TestStore(t *testing.T) {
+	_, err := logdb.Open("mem")
+	assert.NotNil(t, err, "invalid url")
+	_, err = logdb.Open("nope://")
+	assert.NotNil(t, err, "unknown backend")
+
+	db, err := logdb.Open("sqlite://:memory:")
+	assert.Nil(t, err)
+	db.Close()
+
+	store := &memStore{events: make(map[uint32][]*logdb.Event), transfers: make(map[uint32][]*logdb.Transfer)}
+	logdb.Register("mem", func(string) (logdb.Store, error) { return store, nil })
+	assert.Panics(t, func() { logdb.Register("mem", nil) })
+	assert.Contains(t, logdb.Backends(), "mem")
+
+	db, err = logdb.Open("mem://")
+	assert.Nil(t, err)
+
+	header := new(block.Builder).Build().Header()
+	header = new(block.Builder).ParentID(header.ID()).Build().Header()
+	txEvent := &tx.Event{Address: thor.BytesToAddress([]byte("addr"))}
+	txTransfer := &tx.Transfer{Amount: big.NewInt(1)}
+	assert.Nil(t, logdb.NewBlockBatch(db, header).
+		ForTransaction(thor.BytesToBytes32([]byte("txID")), thor.BytesToAddress([]byte("txOrigin"))).
+		Insert(tx.Events{txEvent, txEvent}, tx.Transfers{txTransfer}, 0).
+		Commit())
+
+	events := store.events[header.Number()]
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, uint32(1), events[1].Index)
+		assert.Equal(t, header.ID(), events[1].BlockID)
+	}
+	assert.Len(t, store.transfers[header.Number()], 1)
+}
+
+func TestPrune(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	txEvent := &tx.Event{Address: thor.BytesToAddress([]byte("addr"))}
+	txTransfer := &tx.Transfer{Amount: big.NewInt(1)}
+
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 10; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		if err := db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).
+			Insert(tx.Events{txEvent, txEvent}, tx.Transfers{txTransfer}, 0).Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	last := header.Number()
+
+	n, err := db.Prune(last - 3)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6*3), n)
+
+	events, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	if assert.Len(t, events, 8) {
+		assert.Equal(t, last-3, events[0].BlockNumber)
+	}
+	transfers, err := db.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 4)
+
+	n, err = db.Prune(last - 3)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n, "nothing more to prune")
+
+	num, err := db.QueryLastBlockNumber()
+	assert.Nil(t, err)
+	assert.Equal(t, last, num, "last block number kept")
+}
+
+func TestStream(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// more than a page
+	txEvent := &tx.Event{Address: thor.BytesToAddress([]byte("addr"))}
+	txTransfer := &tx.Transfer{Amount: big.NewInt(1)}
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 5; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		batch := db.Prepare(header)
+		for j := 0; j < 500; j++ {
+			batch.ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(tx.Events{txEvent}, tx.Transfers{txTransfer}, 0)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, all, 2500)
+
+	var streamed []*logdb.Event
+	assert.Nil(t, logdb.StreamEvents(context.Background(), db, nil, func(e *logdb.Event) error {
+		streamed = append(streamed, e)
+		return nil
+	}))
+	assert.Equal(t, all, streamed)
+
+	streamed = nil
+	assert.Nil(t, logdb.StreamEvents(context.Background(), db, &logdb.EventFilter{
+		Options: &logdb.Options{Offset: 10, Limit: 1500},
+		Order:   logdb.DESC,
+	}, func(e *logdb.Event) error {
+		streamed = append(streamed, e)
+		return nil
+	}))
+	if assert.Len(t, streamed, 1500) {
+		assert.Equal(t, all[2500-11], streamed[0])
+		assert.Equal(t, all[2500-1510], streamed[1499])
+	}
+
+	stop := errors.New("stop")
+	n := 0
+	assert.Equal(t, stop, logdb.StreamTransfers(context.Background(), db, nil, func(*logdb.Transfer) error {
+		n++
+		if n == 1200 {
+			return stop
+		}
+		return nil
+	}))
+	assert.Equal(t, 1200, n)
+
+	n = 0
+	assert.Nil(t, logdb.StreamTransfers(context.Background(), db, &logdb.TransferFilter{
+		Range: &logdb.Range{Unit: logdb.Block, From: uint64(header.Number()), To: uint64(header.Number())},
+	}, func(*logdb.Transfer) error {
+		n++
+		return nil
+	}))
+	assert.Equal(t, 500, n)
+}
+
+func TestConcurrentRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := logdb.New(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	const logsPerBlock = 50
+	txEvent := &tx.Event{Address: thor.BytesToAddress([]byte("addr"))}
+
+	done := make(chan struct{})
+	errs := make(chan error, 4)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			for {
+				select {
+				case <-done:
+					errs <- nil
+					return
+				default:
+				}
+				events, err := db.FilterEvents(context.Background(), nil)
+				if err != nil {
+					errs <- err
+					return
+				}
+				// commits are atomic to readers
+				if len(events)%logsPerBlock != 0 {
+					errs <- fmt.Errorf("partial block read, %v events", len(events))
+					return
+				}
+			}
+		}()
+	}
+
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 100; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		batch := db.Prepare(header)
+		for j := 0; j < logsPerBlock; j++ {
+			batch.ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(tx.Events{txEvent}, nil, 0)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	for i := 0; i < cap(errs); i++ {
+		assert.Nil(t, <-errs)
+	}
+
+	events, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 100*logsPerBlock, "writes visible to readers")
+}
+
+func TestMigration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs.db")
+
+	db, err := logdb.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := new(block.Builder).Build().Header()
+	header = new(block.Builder).ParentID(header.ID()).Build().Header()
+	txID := thor.BytesToBytes32([]byte("txID"))
+	assert.Nil(t, db.Prepare(header).ForTransaction(txID, thor.Address{}).
+		Insert(tx.Events{{Address: thor.BytesToAddress([]byte("addr"))}, {Address: thor.BytesToAddress([]byte("addr"))}}, nil, 0).Commit())
+	db.Close()
+
+	raw, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+	version := func() (v []byte) {
+		raw.QueryRow("SELECT value FROM config WHERE key='schemaVersion'").Scan(&v)
+		return
+	}
+	latest := version()
+	assert.NotEmpty(t, latest)
+
+	// as a db created before versioning, of the initial schema
+	_, err = raw.Exec(`CREATE TABLE event_v1 AS SELECT blockNumber, eventIndex, blockID, blockTime, txID, txOrigin,
+		clauseIndex, address, topic0, topic1, topic2, topic3, topic4, data FROM event;
+	DROP TABLE event;
+	ALTER TABLE event_v1 RENAME TO event;
+	DROP TABLE event_bloom;
+	DROP TABLE removed_event;
+	DROP TABLE removed_transfer;
+	DROP INDEX transfer_i3;
+	DELETE FROM config WHERE key IN ('schemaVersion', 'bloomFrom');`)
+	assert.Nil(t, err)
+
+	_, err = logdb.NewReadOnly(path)
+	assert.NotNil(t, err, "read-only db can't be migrated")
+
+	db, err = logdb.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, latest, version())
+	events, err := db.FilterEvents(context.Background(), &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{TxID: &txID}},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2, "logs kept") {
+		assert.Equal(t, uint32(0), events[0].LogIndex)
+		assert.Equal(t, uint32(1), events[1].LogIndex, "log index derived")
+	}
+	db.Close()
+
+	db, err = logdb.NewReadOnly(path)
+	assert.Nil(t, err)
+	db.Close()
+
+	_, err = raw.Exec("UPDATE config SET value=? WHERE key='schemaVersion'", []byte{0, 0, 0xff, 0xff})
+	assert.Nil(t, err)
+	_, err = logdb.New(path)
+	assert.NotNil(t, err, "newer schema")
+}
+
+func TestExclusion(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		addr = thor.BytesToAddress([]byte("addr"))
+		t0   = thor.BytesToBytes32([]byte("topic0"))
+		t1   = thor.BytesToBytes32([]byte("topic1"))
+		t1x  = thor.BytesToBytes32([]byte("topic1x"))
+		a    = thor.BytesToAddress([]byte("a"))
+		b    = thor.BytesToAddress([]byte("b"))
+	)
+	header := new(block.Builder).Build().Header()
+	header = new(block.Builder).ParentID(header.ID()).Build().Header()
+	assert.Nil(t, db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(
+		tx.Events{
+			{Address: addr, Topics: []thor.Bytes32{t0, t1}},
+			{Address: addr, Topics: []thor.Bytes32{t0, t1x}},
+			{Address: addr, Topics: []thor.Bytes32{t0}}, // without topic1
+		},
+		tx.Transfers{
+			{Sender: a, Recipient: b, Amount: big.NewInt(1)},
+			{Sender: b, Recipient: a, Amount: big.NewInt(1)},
+			{Sender: a, Recipient: a, Amount: big.NewInt(1)},
+		}, 0).Commit())
+
+	events, err := db.FilterEvents(context.Background(), &logdb.EventFilter{
+		CriteriaSet:  []*logdb.EventCriteria{{Address: &addr}},
+		ExclusionSet: []*logdb.EventCriteria{{Topics: [5]*thor.Bytes32{nil, &t1}}},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2, "events without topic1 kept") {
+		assert.Equal(t, uint32(1), events[0].Index)
+		assert.Equal(t, uint32(2), events[1].Index)
+	}
+
+	count, err := db.CountEvents(context.Background(), &logdb.EventFilter{
+		ExclusionSet: []*logdb.EventCriteria{{Topics: [5]*thor.Bytes32{nil, &t1}}, {Topics: [5]*thor.Bytes32{nil, &t1x}}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), count)
+
+	transfers, err := db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+		ExclusionSet: []*logdb.TransferCriteria{{Sender: &a}},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, transfers, 1) {
+		assert.Equal(t, b, transfers[0].Sender)
+	}
+
+	transfers, err = db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+		CriteriaSet:  []*logdb.TransferCriteria{{Sender: &a}, {Recipient: &a}},
+		ExclusionSet: []*logdb.TransferCriteria{{Sender: &a, Recipient: &a}},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 2)
+}
+
+func TestExportImport(t *testing.T) {
+	src, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 30; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Timestamp(uint64(i)).Build().Header()
+		batch := src.Prepare(header)
+		for j := 0; j < 50; j++ {
+			batch.ForTransaction(thor.BytesToBytes32([]byte{byte(j)}), thor.BytesToAddress([]byte("origin"))).Insert(
+				tx.Events{{
+					Address: thor.BytesToAddress([]byte("addr")),
+					Topics:  []thor.Bytes32{thor.BytesToBytes32([]byte("topic0")), thor.BytesToBytes32([]byte{byte(j)})},
+					Data:    []byte{byte(i), byte(j)},
+				}},
+				tx.Transfers{{Sender: thor.BytesToAddress([]byte("s")), Recipient: thor.BytesToAddress([]byte{byte(j)}), Amount: big.NewInt(int64(j))}},
+				uint32(j%3))
+		}
+		assert.Nil(t, batch.Commit())
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, src.Export(context.Background(), &buf, nil))
+	assert.Equal(t, 30*50*2, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	dst, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	assert.Nil(t, dst.Import(context.Background(), &buf))
+
+	srcEvents, _ := src.FilterEvents(context.Background(), nil)
+	dstEvents, err := dst.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, srcEvents, dstEvents)
+	srcTransfers, _ := src.FilterTransfers(context.Background(), nil)
+	dstTransfers, err := dst.FilterTransfers(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, srcTransfers, dstTransfers)
+	last, err := dst.QueryLastBlockNumber()
+	assert.Nil(t, err)
+	assert.Equal(t, header.Number(), last)
+
+	buf.Reset()
+	assert.Nil(t, src.Export(context.Background(), &buf, &logdb.Range{Unit: logdb.Block, From: uint64(header.Number()), To: uint64(header.Number())}))
+	assert.Equal(t, 50*2, bytes.Count(buf.Bytes(), []byte("\n")))
+
+	assert.NotNil(t, dst.Import(context.Background(), strings.NewReader(`{"kind":"receipt"}`)))
+	assert.NotNil(t, dst.Import(context.Background(), strings.NewReader(`{"kind":"transfer"}`)))
+}
+
+func TestMaintain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := logdb.New(filepath.Join(dir, "logs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	txEvent := &tx.Event{Address: thor.BytesToAddress([]byte("addr")), Data: make([]byte, 100)}
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 20; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		batch := db.Prepare(header)
+		for j := 0; j < 100; j++ {
+			batch.ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(tx.Events{txEvent}, nil, 0)
+		}
+		assert.Nil(t, batch.Commit())
+	}
+	_, err = db.Prune(header.Number())
+	assert.Nil(t, err)
+
+	before, err := db.Stats()
+	assert.Nil(t, err)
+	assert.True(t, before.AutoVacuum, "enabled for new db")
+	assert.True(t, before.WALSize > 0)
+
+	assert.Nil(t, db.Maintain(context.Background()))
+	after, err := db.Stats()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), after.WALSize, "wal truncated")
+	assert.Equal(t, uint64(0), after.FreePages, "free pages released")
+	assert.True(t, after.PageCount < before.PageCount)
+
+	assert.Nil(t, db.Vacuum())
+	events, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 100)
+}
+
+func TestBloom(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		addrs  = []thor.Address{thor.BytesToAddress([]byte("a0")), thor.BytesToAddress([]byte("a1"))}
+		topics = []thor.Bytes32{thor.BytesToBytes32([]byte("t0")), thor.BytesToBytes32([]byte("t1")), thor.BytesToBytes32([]byte("t2"))}
+		rare   = thor.BytesToBytes32([]byte("rare"))
+	)
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 30; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		events := tx.Events{
+			{Address: addrs[i%2], Topics: []thor.Bytes32{topics[i%3]}},
+			{Address: addrs[(i+1)%2], Topics: []thor.Bytes32{topics[(i+1)%3], topics[i%3]}},
+		}
+		if i == 17 {
+			events = append(events, &tx.Event{Address: addrs[0], Topics: []thor.Bytes32{rare}})
+		}
+		assert.Nil(t, db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(events, nil, 0).Commit())
+	}
+
+	for _, criteriaSet := range [][]*logdb.EventCriteria{
+		{{Address: &addrs[0], Topics: [5]*thor.Bytes32{&rare}}},
+		{{Address: &addrs[0], Topics: [5]*thor.Bytes32{&topics[1]}}},
+		// address and topics in the same block, but not the same event
+		{{Address: &addrs[1], Topics: [5]*thor.Bytes32{&topics[2], &topics[2]}}},
+		{{Topics: [5]*thor.Bytes32{&topics[0], &topics[1]}}, {Address: &addrs[1], Topics: [5]*thor.Bytes32{&topics[2]}}},
+		// bloom not applicable
+		{{Topics: [5]*thor.Bytes32{&topics[0], &topics[1]}}, {}},
+		{{Topics: [5]*thor.Bytes32{&rare}}},
+	} {
+		// no bloom used without range
+		expected, err := db.FilterEvents(context.Background(), &logdb.EventFilter{CriteriaSet: criteriaSet})
+		assert.Nil(t, err)
+
+		events, err := db.FilterEvents(context.Background(), &logdb.EventFilter{
+			CriteriaSet: criteriaSet,
+			Range:       &logdb.Range{Unit: logdb.Block, From: 0, To: math.MaxUint32},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, expected, events)
+
+		count, err := db.CountEvents(context.Background(), &logdb.EventFilter{
+			CriteriaSet: criteriaSet,
+			Range:       &logdb.Range{Unit: logdb.Block, From: 1, To: math.MaxUint32},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(len(expected)), count)
+	}
+
+	rareFilter := &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{Address: &addrs[0], Topics: [5]*thor.Bytes32{&rare}}},
+		Range:       &logdb.Range{Unit: logdb.Block, From: 0, To: math.MaxUint32},
+	}
+	events, err := db.FilterEvents(context.Background(), rareFilter)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+
+	// blooms follow truncating and importing
+	var buf bytes.Buffer
+	assert.Nil(t, db.Export(context.Background(), &buf, nil))
+	assert.Nil(t, db.Truncate(10))
+	events, err = db.FilterEvents(context.Background(), rareFilter)
+	assert.Nil(t, err)
+	assert.Len(t, events, 0)
+
+	assert.Nil(t, db.Import(context.Background(), &buf))
+	events, err = db.FilterEvents(context.Background(), rareFilter)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestRebuild(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	b0, _, err := genesis.NewDevnet().Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := chain.New(kv, b0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := thor.BytesToAddress([]byte("addr"))
+	parent := b0.Header()
+	for i := 1; i <= 5; i++ {
+		trx := new(tx.Builder).ChainTag(chain.Tag()).Nonce(uint64(i)).Build()
+		blk := new(block.Builder).
+			ParentID(parent.ID()).
+			Timestamp(parent.Timestamp() + thor.BlockInterval).
+			TotalScore(parent.TotalScore() + 1).
+			Transaction(trx).
+			Build()
+		receipts := tx.Receipts{{Outputs: []*tx.Output{{
+			Events:    tx.Events{{Address: addr}},
+			Transfers: tx.Transfers{{Sender: addr, Recipient: addr, Amount: big.NewInt(int64(i))}},
+		}}}}
+		if _, err := chain.AddBlock(blk, receipts); err != nil {
+			t.Fatal(err)
+		}
+		parent = blk.Header()
+	}
+
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	counts := func() (uint64, uint64) {
+		events, err := db.CountEvents(context.Background(), nil)
+		assert.Nil(t, err)
+		transfers, err := db.CountTransfers(context.Background(), nil)
+		assert.Nil(t, err)
+		return events, transfers
+	}
+
+	assert.Nil(t, logdb.Rebuild(context.Background(), db, chain, 0))
+	events, transfers := counts()
+	assert.Equal(t, uint64(5), events)
+	assert.Equal(t, uint64(5), transfers)
+	last, err := db.QueryLastBlockNumber()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(5), last)
+
+	// lost logs of some blocks, and logs ahead of the chain
+	assert.Nil(t, db.Truncate(2))
+	ahead := new(block.Builder).ParentID(parent.ID()).Build().Header()
+	assert.Nil(t, db.Prepare(ahead).ForTransaction(thor.Bytes32{}, thor.Address{}).
+		Insert(tx.Events{{Address: addr}}, nil, 0).Commit())
+
+	assert.Nil(t, logdb.Rebuild(context.Background(), db, chain, 3))
+	events, transfers = counts()
+	assert.Equal(t, uint64(5), events)
+	assert.Equal(t, uint64(5), transfers)
+
+	assert.NotNil(t, logdb.Rebuild(context.Background(), db, chain, 7))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, logdb.Rebuild(ctx, db, chain, 1))
+}
+
+func TestAggregate(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		addr = thor.BytesToAddress([]byte("addr"))
+		a    = thor.BytesToAddress([]byte("a"))
+		b    = thor.BytesToAddress([]byte("b"))
+		base = uint64(1600000000 - 1600000000%86400)
+		// two blocks in the first hour, one in the next, and one the next day
+		times = []uint64{base + 10, base + 3599, base + 3600, base + 86400 + 10}
+	)
+	// amounts beyond 64 bits
+	e30 := func(n int64) *big.Int {
+		v, _ := new(big.Int).SetString("1000000000000000000000000000000", 10)
+		return v.Mul(v, big.NewInt(n))
+	}
+	header := new(block.Builder).Build().Header()
+	for i, ts := range times {
+		header = new(block.Builder).ParentID(header.ID()).Timestamp(ts).Build().Header()
+		assert.Nil(t, db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(
+			tx.Events{{Address: addr}},
+			tx.Transfers{
+				{Sender: a, Recipient: b, Amount: e30(int64(i + 1))},
+				{Sender: b, Recipient: a, Amount: big.NewInt(1)},
+			}, 0).Commit())
+	}
+
+	aggs, err := db.AggregateTransfers(context.Background(), &logdb.TransferFilter{
+		CriteriaSet: []*logdb.TransferCriteria{{Sender: &a}},
+	}, logdb.BucketHour)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.Aggregate{
+		{Time: base, Count: 2, Amount: e30(3)},
+		{Time: base + 3600, Count: 1, Amount: e30(3)},
+		{Time: base + 86400, Count: 1, Amount: e30(4)},
+	}, aggs)
+
+	aggs, err = db.AggregateTransfers(context.Background(), &logdb.TransferFilter{
+		Range: &logdb.Range{Unit: logdb.Time, From: base, To: base + 86399},
+	}, logdb.BucketDay)
+	assert.Nil(t, err)
+	assert.Equal(t, []*logdb.Aggregate{
+		{Time: base, Count: 6, Amount: new(big.Int).Add(e30(6), big.NewInt(3))},
+	}, aggs)
+
+	aggs, err = db.AggregateEvents(context.Background(), nil, logdb.BucketDay)
+	assert.Nil(t, err)
+	if assert.Len(t, aggs, 2) {
+		assert.Equal(t, logdb.Aggregate{Time: base, Count: 3}, *aggs[0])
+		assert.Equal(t, logdb.Aggregate{Time: base + 86400, Count: 1}, *aggs[1])
+	}
+
+	_, err = db.AggregateEvents(context.Background(), nil, logdb.Bucket("week"))
+	assert.Equal(t, logdb.ErrUnsupportedBucket, err)
+}
+
+func TestTruncateEvent(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ch := make(chan *logdb.TruncateEvent, 10)
+	sub := db.SubscribeTruncateEvent(ch)
+
+	var headers []*block.Header
+	header := new(block.Builder).Build().Header()
+	for i := 1; i <= 4; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		headers = append(headers, header)
+		txID := thor.BytesToBytes32([]byte{byte(i)})
+		batch := db.Prepare(header)
+		if i%2 == 0 {
+			batch.ForTransaction(txID, thor.Address{}).Insert(tx.Events{{Address: thor.BytesToAddress([]byte("addr"))}}, nil, 0)
+		} else {
+			batch.ForTransaction(txID, thor.Address{}).Insert(nil, tx.Transfers{{Amount: big.NewInt(1)}}, 0)
+		}
+		assert.Nil(t, batch.Commit())
+	}
+	assert.Len(t, ch, 0, "no removal on appending")
+
+	assert.Nil(t, db.Truncate(headers[1].Number()))
+	if assert.Len(t, ch, 1) {
+		ev := <-ch
+		assert.Equal(t, headers[2].Number(), ev.From)
+		assert.Len(t, ev.TxIDs, 2)
+		assert.Contains(t, ev.TxIDs, thor.BytesToBytes32([]byte{3}))
+		assert.Contains(t, ev.TxIDs, thor.BytesToBytes32([]byte{4}))
+	}
+
+	// replaced on reorg
+	assert.Nil(t, db.Prepare(headers[1]).Commit())
+	if assert.Len(t, ch, 1) {
+		ev := <-ch
+		assert.Equal(t, headers[1].Number(), ev.From)
+		assert.Equal(t, []thor.Bytes32{thor.BytesToBytes32([]byte{2})}, ev.TxIDs)
+	}
+
+	assert.Nil(t, db.Truncate(headers[3].Number()))
+	assert.Len(t, ch, 0, "nothing removed")
+
+	sub.Unsubscribe()
+	assert.Nil(t, db.Truncate(0))
+	assert.Len(t, ch, 0, "no subscriber")
+}
+
+func TestSort(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var (
+		a = thor.BytesToAddress([]byte("a"))
+		b = thor.BytesToAddress([]byte("b"))
+	)
+	header := new(block.Builder).Build().Header()
+	// amounts of various lengths
+	for _, amount := range []int64{256, 1, 0, 70000, 255} {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		assert.Nil(t, db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(
+			tx.Events{{Address: b}, {Address: a}},
+			tx.Transfers{{Sender: a, Recipient: b, Amount: big.NewInt(amount)}}, 0).Commit())
+	}
+
+	transfers, err := db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+		Sort: []*logdb.Sort{{Key: logdb.SortByAmount, Order: logdb.DESC}},
+	})
+	assert.Nil(t, err)
+	var amounts []int64
+	for _, transfer := range transfers {
+		amounts = append(amounts, transfer.Amount.Int64())
+	}
+	assert.Equal(t, []int64{70000, 256, 255, 1, 0}, amounts)
+
+	events, err := db.FilterEvents(context.Background(), &logdb.EventFilter{
+		Sort:    []*logdb.Sort{{Key: logdb.SortByAddress}},
+		Order:   logdb.DESC,
+		Options: &logdb.Options{Limit: 6},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 6) {
+		for i, event := range events {
+			if i < 5 {
+				assert.Equal(t, a, event.Address)
+			} else {
+				assert.Equal(t, b, event.Address)
+			}
+			if i > 0 && i != 5 {
+				assert.True(t, event.BlockNumber < events[i-1].BlockNumber, "positions in order")
+			}
+		}
+	}
+
+	var streamed []*logdb.Event
+	assert.Nil(t, logdb.StreamEvents(context.Background(), db, &logdb.EventFilter{
+		Sort: []*logdb.Sort{{Key: logdb.SortByBlockTime, Order: logdb.DESC}},
+	}, func(event *logdb.Event) error {
+		streamed = append(streamed, event)
+		return nil
+	}))
+	assert.Len(t, streamed, 10)
+
+	_, err = db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+		Sort: []*logdb.Sort{{Key: logdb.SortByAddress}},
+	})
+	assert.Equal(t, logdb.ErrUnsupportedSortKey, err)
+	_, err = db.FilterEvents(context.Background(), &logdb.EventFilter{
+		Sort:    []*logdb.Sort{{Key: logdb.SortByAddress}},
+		Options: &logdb.Options{Limit: 1, Cursor: events[0].Cursor()},
+	})
+	assert.Equal(t, logdb.ErrCursorWithSort, err)
+}
+
+func TestQueryLimits(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	header := new(block.Builder).Build().Header()
+	for i := 0; i < 10; i++ {
+		header = new(block.Builder).ParentID(header.ID()).Build().Header()
+		assert.Nil(t, db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(
+			tx.Events{{Address: thor.BytesToAddress([]byte("addr")), Data: make([]byte, 1000)}},
+			tx.Transfers{{Amount: big.NewInt(1)}}, 0).Commit())
+	}
+
+	db.SetQueryLimits(logdb.QueryLimits{MaxRows: 5})
+	_, err = db.FilterEvents(context.Background(), nil)
+	assert.Equal(t, &logdb.ResultTooLargeError{Limit: "rows", Max: 5}, err)
+	_, err = db.FilterTransfers(context.Background(), &logdb.TransferFilter{})
+	assert.Equal(t, &logdb.ResultTooLargeError{Limit: "rows", Max: 5}, err)
+
+	transfers, err := db.FilterTransfers(context.Background(), &logdb.TransferFilter{Options: &logdb.Options{Limit: 5}})
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 5)
+
+	db.SetQueryLimits(logdb.QueryLimits{MaxBytes: 5000})
+	_, err = db.FilterEvents(context.Background(), nil)
+	assert.Equal(t, &logdb.ResultTooLargeError{Limit: "bytes", Max: 5000}, err)
+	events, err := db.FilterEvents(context.Background(), &logdb.EventFilter{Options: &logdb.Options{Limit: 4}})
+	assert.Nil(t, err)
+	assert.Len(t, events, 4)
+
+	db.SetQueryLimits(logdb.QueryLimits{})
+	events, err = db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 10)
+}
+
+func TestRemoved(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetKeepRemoved(true)
+
+	var (
+		addr = thor.BytesToAddress([]byte("addr"))
+		a    = thor.BytesToAddress([]byte("a"))
+		b    = thor.BytesToAddress([]byte("b"))
+	)
+	write := func(header *block.Header, sender thor.Address) {
+		assert.Nil(t, db.Prepare(header).ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(
+			tx.Events{{Address: addr}},
+			tx.Transfers{{Sender: sender, Amount: big.NewInt(1)}}, 0).Commit())
+	}
+	// signed to have distinct IDs
+	sign := func(blk *block.Block) *block.Header {
+		sig, err := crypto.Sign(blk.Header().SigningHash().Bytes(), genesis.DevAccounts()[0].PrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blk.WithSignature(sig).Header()
+	}
+	b0 := new(block.Builder).Build().Header()
+	b1 := sign(new(block.Builder).ParentID(b0.ID()).Build())
+	b2 := sign(new(block.Builder).ParentID(b1.ID()).Build())
+	b2x := sign(new(block.Builder).ParentID(b1.ID()).Timestamp(1).Build())
+	write(b1, a)
+	write(b2, a)
+	// reorg
+	write(b2x, b)
+
+	events, err := db.FilterEvents(context.Background(), nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 2, "removed logs excluded by default")
+
+	events, err = db.FilterEvents(context.Background(), &logdb.EventFilter{IncludeRemoved: true})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 3) {
+		assert.False(t, events[1].Removed)
+		assert.Equal(t, b2x.ID(), events[1].BlockID)
+		assert.True(t, events[2].Removed)
+		assert.Equal(t, b2.ID(), events[2].BlockID)
+	}
+
+	transfers, err := db.FilterTransfers(context.Background(), &logdb.TransferFilter{
+		CriteriaSet:    []*logdb.TransferCriteria{{Sender: &a}},
+		IncludeRemoved: true,
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, transfers, 2) {
+		assert.False(t, transfers[0].Removed)
+		assert.True(t, transfers[1].Removed)
+	}
+	count, err := db.CountTransfers(context.Background(), &logdb.TransferFilter{IncludeRemoved: true})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	_, err = db.FilterEvents(context.Background(), &logdb.EventFilter{
+		IncludeRemoved: true,
+		Options:        &logdb.Options{Limit: 1, Cursor: events[0].Cursor()},
+	})
+	assert.Equal(t, logdb.ErrCursorWithRemoved, err)
+
+	// the branch adopted again
+	assert.Nil(t, db.Truncate(b1.Number()))
+	write(b2, a)
+	count, err = db.CountEvents(context.Background(), &logdb.EventFilter{IncludeRemoved: true})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), count)
+	events, err = db.FilterEvents(context.Background(), &logdb.EventFilter{IncludeRemoved: true})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, b2.ID(), events[1].BlockID)
+		assert.False(t, events[1].Removed)
+		assert.Equal(t, b2x.ID(), events[2].BlockID)
+		assert.True(t, events[2].Removed)
+	}
+
+	// deleted if not kept
+	db.SetKeepRemoved(false)
+	assert.Nil(t, db.Truncate(b1.Number()))
+	count, err = db.CountEvents(context.Background(), &logdb.EventFilter{IncludeRemoved: true})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), count)
+
+	n, err := db.Prune(b2.Number() + 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), n, "removed logs pruned")
+}
+
+func TestLogIndex(t *testing.T) {
+	db, err := logdb.NewMem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	addr := thor.BytesToAddress([]byte("addr"))
+	header := new(block.Builder).Build().Header()
+	header = new(block.Builder).ParentID(header.ID()).Build().Header()
+	batch := db.Prepare(header)
+	batch.ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(tx.Events{{Address: addr}, {Address: addr}}, nil, 0)
+	batch.ForTransaction(thor.Bytes32{}, thor.Address{}).Insert(tx.Events{{Address: addr}}, nil, 1)
+	assert.Nil(t, batch.Commit())
+
+	events, err := db.FilterEvents(context.Background(), &logdb.EventFilter{})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, uint32(0), events[0].LogIndex)
+		assert.Equal(t, uint32(1), events[1].LogIndex)
+		assert.Equal(t, uint32(0), events[2].LogIndex, "index within the clause")
+	}
+
+	logIndex := uint32(0)
+	events, err = db.FilterEvents(context.Background(), &logdb.EventFilter{
+		CriteriaSet: []*logdb.EventCriteria{{Address: &addr, LogIndex: &logIndex}},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, uint32(0), events[0].Index)
+		assert.Equal(t, uint32(2), events[1].Index)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// count of free pages released per step of incremental vacuum
+const vacuumStepPages = 1000
+
+// Stats is the size statistics of the log db.
+type Stats struct {
+	PageSize   uint64 // in bytes
+	PageCount  uint64 // count of pages in the db file, including free ones
+	FreePages  uint64 // count of pages to be released by vacuum
+	WALSize    int64  // in bytes, 0 for in-memory db
+	AutoVacuum bool   // whether free pages can be released by Maintain, or a full Vacuum is required
+}
+
+// Maintainer is implemented by stores which need regular maintenance.
+type Maintainer interface {
+	Maintain(ctx context.Context) error
+}
+
+var _ Maintainer = (*LogDB)(nil)
+
+// Stats returns the size statistics.
+func (db *LogDB) Stats() (*Stats, error) {
+	var (
+		stats      Stats
+		autoVacuum int
+	)
+	for _, p := range []struct {
+		pragma string
+		dest   interface{}
+	}{
+		{"page_size", &stats.PageSize},
+		{"page_count", &stats.PageCount},
+		{"freelist_count", &stats.FreePages},
+		{"auto_vacuum", &autoVacuum},
+	} {
+		if err := db.db.QueryRow("PRAGMA " + p.pragma).Scan(p.dest); err != nil {
+			return nil, err
+		}
+	}
+	// 2 for incremental
+	stats.AutoVacuum = autoVacuum == 2
+	if fi, err := os.Stat(db.path + "-wal"); err == nil {
+		stats.WALSize = fi.Size()
+	}
+	return &stats, nil
+}
+
+// Maintain releases free pages step by step if auto vacuum is enabled, then checkpoints the WAL
+// into the db file and truncates it. Checkpointing is partial if readers are busy.
+func (db *LogDB) Maintain(ctx context.Context) error {
+	stats, err := db.Stats()
+	if err != nil {
+		return err
+	}
+	if stats.AutoVacuum {
+		for free := stats.FreePages; free > 0; {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			n := uint64(vacuumStepPages)
+			if free < n {
+				n = free
+			}
+			if err := db.incrementalVacuum(ctx, n); err != nil {
+				return err
+			}
+			free -= n
+		}
+	}
+
+	var busy, logPages, checkpointed int
+	if err := db.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logPages, &checkpointed); err != nil {
+		return err
+	}
+	if busy != 0 {
+		log.Debug("wal checkpoint blocked by readers", "pages", logPages, "checkpointed", checkpointed)
+	}
+	return nil
+}
+
+// incrementalVacuum releases n free pages. The pragma releases a page per step, so is run as a query
+// to be stepped through, rather than Exec which steps once.
+func (db *LogDB) incrementalVacuum(ctx context.Context, n uint64) error {
+	// pragmas take no bound parameters
+	rows, err := db.db.QueryContext(ctx, fmt.Sprintf("PRAGMA incremental_vacuum(%d)", n))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// Vacuum rebuilds the db file to release all free pages, and enables auto vacuum for dbs created
+// without it. It takes long and blocks writes for large dbs, so should only run on demand.
+func (db *LogDB) Vacuum() error {
+	if _, err := db.db.Exec("PRAGMA auto_vacuum = INCREMENTAL"); err != nil {
+		return err
+	}
+	_, err := db.db.Exec("VACUUM")
+	return err
+}
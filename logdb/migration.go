@@ -0,0 +1,118 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+)
+
+var configSchemaVersionKey = "schemaVersion"
+
+// migrations upgrade the schema step by step, and the schema version is the count of migrations applied.
+// Migrations are append only, and must never be changed once released.
+var migrations = []string{
+	// 1: the initial schema, which may exist without the version recorded
+	configTableSchema + eventTableSchema + transferTableSchema,
+	// 2: to filter events by tx
+	`CREATE INDEX IF NOT EXISTS event_i7 ON event(txID, blockNumber, eventIndex);`,
+	// 3: blooms of events per block, covering blocks logged from now on
+	`CREATE TABLE IF NOT EXISTS event_bloom (
+		blockNumber INTEGER PRIMARY KEY,
+		bloom BLOB NOT NULL
+	);
+	INSERT OR REPLACE INTO config(key, value) SELECT 'bloomFrom', COALESCE(MAX(blockNumber) + 1, 0) FROM event;`,
+	// 4: to sort transfers by amount
+	`CREATE INDEX IF NOT EXISTS transfer_i3 ON transfer(LENGTH(amount), amount, blockNumber, transferIndex);`,
+	// 5: logs of orphaned blocks, of the same columns
+	`CREATE TABLE IF NOT EXISTS removed_event AS SELECT * FROM event WHERE 0;
+	CREATE UNIQUE INDEX IF NOT EXISTS removed_event_i0 ON removed_event(blockID, eventIndex);
+	CREATE INDEX IF NOT EXISTS removed_event_i1 ON removed_event(blockNumber);
+	CREATE TABLE IF NOT EXISTS removed_transfer AS SELECT * FROM transfer WHERE 0;
+	CREATE UNIQUE INDEX IF NOT EXISTS removed_transfer_i0 ON removed_transfer(blockID, transferIndex);
+	CREATE INDEX IF NOT EXISTS removed_transfer_i1 ON removed_transfer(blockNumber);`,
+	// 6: index of events in receipt outputs, derived for existing events, which are in order within clauses
+	`ALTER TABLE event ADD COLUMN logIndex INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE removed_event ADD COLUMN logIndex INTEGER NOT NULL DEFAULT 0;
+	UPDATE event SET logIndex = eventIndex - (SELECT MIN(e.eventIndex) FROM event AS e
+		WHERE e.blockNumber = event.blockNumber AND e.txID = event.txID AND e.clauseIndex = event.clauseIndex);
+	UPDATE removed_event SET logIndex = eventIndex - (SELECT MIN(e.eventIndex) FROM removed_event AS e
+		WHERE e.blockID = removed_event.blockID AND e.txID = removed_event.txID AND e.clauseIndex = removed_event.clauseIndex);`,
+}
+
+// latestSchemaVersion is the version of the schema supported.
+var latestSchemaVersion = uint32(len(migrations))
+
+// migrate applies migrations not applied yet, each in a transaction along with the version update.
+func migrate(db *sql.DB) error {
+	ver, err := querySchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if ver > latestSchemaVersion {
+		return fmt.Errorf("schema version %v is newer than supported %v", ver, latestSchemaVersion)
+	}
+	// new dbs are created silently
+	created := ver == 0
+	for ; ver < latestSchemaVersion; ver++ {
+		if !created {
+			log.Info("migrating log db schema", "from", ver, "to", ver+1)
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(migrations[ver]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate schema to version %v: %v", ver+1, err)
+		}
+		var b4 [4]byte
+		binary.BigEndian.PutUint32(b4[:], ver+1)
+		if _, err := tx.Exec("INSERT OR REPLACE INTO config(key, value) VALUES(?,?)", configSchemaVersionKey, b4[:]); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSchemaVersion checks the schema is up to date, for dbs which can't be migrated, e.g. opened read-only.
+func checkSchemaVersion(db *sql.DB) error {
+	ver, err := querySchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if ver != latestSchemaVersion {
+		return fmt.Errorf("schema version %v mismatches supported %v, open in writable mode to migrate", ver, latestSchemaVersion)
+	}
+	return nil
+}
+
+// querySchemaVersion returns the recorded schema version, 0 if not recorded.
+func querySchemaVersion(db *sql.DB) (uint32, error) {
+	var exists bool
+	if err := db.QueryRow("SELECT COUNT(*) > 0 FROM sqlite_master WHERE type='table' AND name='config'").Scan(&exists); err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+	var data []byte
+	if err := db.QueryRow("SELECT value FROM config WHERE key=?", configSchemaVersionKey).Scan(&data); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if len(data) != 4 {
+		return 0, fmt.Errorf("invalid schema version %x", data)
+	}
+	return binary.BigEndian.Uint32(data), nil
+}
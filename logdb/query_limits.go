@@ -0,0 +1,67 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// approximate sizes of logs in memory, excluding variable length data
+const (
+	eventFixedSize    = 4 + 4 + 32 + 8 + 32 + 20 + 4 + 20
+	transferFixedSize = 4 + 4 + 32 + 8 + 32 + 20 + 4 + 20 + 20 + 32
+)
+
+// QueryLimits caps results of each filter query, so that a filter matching too many logs fails fast
+// instead of exhausting memory. Zero means no cap.
+// Streaming queries page by page, so the caps apply to each page.
+type QueryLimits struct {
+	MaxRows  uint64
+	MaxBytes uint64 // approximate size of logs in memory
+}
+
+// ResultTooLargeError is returned when the result of a query exceeds the limits.
+type ResultTooLargeError struct {
+	Limit string // "rows" or "bytes"
+	Max   uint64
+}
+
+func (e *ResultTooLargeError) Error() string {
+	return fmt.Sprintf("result too large: exceeds %v %v, narrow the filter or set a limit", e.Max, e.Limit)
+}
+
+// SetQueryLimits sets limits of results of filter queries.
+func (db *LogDB) SetQueryLimits(limits QueryLimits) {
+	atomic.StoreUint64(&db.maxRows, limits.MaxRows)
+	atomic.StoreUint64(&db.maxBytes, limits.MaxBytes)
+}
+
+// resultGuard counts rows and bytes of a query against the limits.
+type resultGuard struct {
+	maxRows, maxBytes uint64
+	rows, bytes       uint64
+}
+
+func (db *LogDB) newResultGuard() *resultGuard {
+	return &resultGuard{
+		maxRows:  atomic.LoadUint64(&db.maxRows),
+		maxBytes: atomic.LoadUint64(&db.maxBytes),
+	}
+}
+
+// add adds a row of the size, and returns error if limits exceeded.
+func (g *resultGuard) add(size int) error {
+	g.rows++
+	g.bytes += uint64(size)
+	if g.maxRows > 0 && g.rows > g.maxRows {
+		return &ResultTooLargeError{"rows", g.maxRows}
+	}
+	if g.maxBytes > 0 && g.bytes > g.maxBytes {
+		return &ResultTooLargeError{"bytes", g.maxBytes}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/tx"
+)
+
+// interval in blocks to report progress of rebuilding
+const rebuildLogInterval = 10000
+
+// Rebuild replaces logs of trunk blocks from the given number up to the best block, with logs
+// replayed from receipts stored in the chain, so that a broken or lost log db can be recovered
+// without syncing blocks again.
+// Genesis logs are not in receipts but written on chain initialization, so from 0 is treated as 1.
+func Rebuild(ctx context.Context, store Store, chain *chain.Chain, from uint32) error {
+	if from == 0 {
+		from = 1
+	}
+	best := chain.BestBlock().Header().Number()
+	if from > best+1 {
+		return fmt.Errorf("rebuild from #%v beyond best block #%v", from, best)
+	}
+	// also drops logs ahead of the chain
+	if err := store.Truncate(from - 1); err != nil {
+		return err
+	}
+	for num := from; num <= best; num++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := CommitTrunkBlock(store, chain, num); err != nil {
+			return err
+		}
+		if (num-from+1)%rebuildLogInterval == 0 {
+			log.Info("rebuilding logs", "number", num, "best", best)
+		}
+	}
+	return nil
+}
+
+// CommitTrunkBlock writes logs of the trunk block with given number, from receipts stored in the chain.
+func CommitTrunkBlock(store Store, chain *chain.Chain, num uint32) error {
+	blk, err := chain.GetTrunkBlock(num)
+	if err != nil {
+		return fmt.Errorf("get trunk block #%v: %v", num, err)
+	}
+	var receipts tx.Receipts
+	if len(blk.Transactions()) > 0 {
+		if receipts, err = chain.GetBlockReceipts(blk.Header().ID()); err != nil {
+			return fmt.Errorf("get receipts of block #%v: %v", num, err)
+		}
+	}
+	batch := NewBlockBatch(store, blk.Header())
+	for i, tx := range blk.Transactions() {
+		origin, _ := tx.Signer()
+		txBatch := batch.ForTransaction(tx.ID(), origin)
+		for j, output := range receipts[i].Outputs {
+			txBatch.Insert(output.Events, output.Transfers, uint32(j))
+		}
+	}
+	return batch.Commit()
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+	"errors"
+	"sync/atomic"
+
+	"github.com/vechain/thor/thor"
+)
+
+// Logs of orphaned blocks, if kept, are moved into tables 'removed_event' and 'removed_transfer' of the
+// same columns, since their positions may be taken by logs of the new branch.
+
+// ErrCursorWithRemoved is returned if a cursor is given along with removed logs included, since removed
+// logs may share positions with others.
+var ErrCursorWithRemoved = errors.New("cursor not supported with removed logs")
+
+// SetKeepRemoved sets whether to keep logs removed by reorgs or truncation, rather than delete them.
+// Kept logs are returned by filters with IncludeRemoved, and pruned as others.
+func (db *LogDB) SetKeepRemoved(keep bool) {
+	var v int32
+	if keep {
+		v = 1
+	}
+	atomic.StoreInt32(&db.keepRemoved, v)
+}
+
+// logSource returns the table of logs to query, along with removed logs if included.
+func logSource(table string, includeRemoved bool) string {
+	if !includeRemoved {
+		return table
+	}
+	return "(SELECT *, 0 AS removed FROM " + table + " UNION ALL SELECT *, 1 FROM removed_" + table + ")"
+}
+
+// logColumns returns columns to select from the log source, which end with the removed flag.
+func logColumns(includeRemoved bool) string {
+	if !includeRemoved {
+		return "*, 0"
+	}
+	return "*"
+}
+
+// removeLogs moves logs of blocks from the given number into removed tables if kept.
+// Logs are deleted by the caller anyway.
+func (db *LogDB) removeLogs(tx *sql.Tx, from uint32) error {
+	if atomic.LoadInt32(&db.keepRemoved) == 0 {
+		return nil
+	}
+	for _, table := range []string{"event", "transfer"} {
+		if _, err := tx.Exec("INSERT OR REPLACE INTO removed_"+table+" SELECT * FROM "+table+" WHERE blockNumber >= ?", from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreLogs drops removed logs of the block, which is written back, e.g. the branch is adopted again.
+func restoreLogs(tx *sql.Tx, num uint32, id thor.Bytes32) error {
+	for _, table := range []string{"event", "transfer"} {
+		if _, err := tx.Exec("DELETE FROM removed_"+table+" WHERE blockNumber = ? AND blockID = ?", num, id.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,7 +5,7 @@
 
 package logdb
 
-// create a table for events
+// the initial schema, applied as the first migration, so never change it but add migrations
 const (
 	configTableSchema = `CREATE TABLE IF NOT EXISTS config (
 	key CHAR(20) PRIMARY KEY,
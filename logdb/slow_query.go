@@ -0,0 +1,89 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/inconshreveable/log15"
+)
+
+var log = log15.New("pkg", "logdb")
+
+// SetSlowQueryThreshold sets the duration, over which filter queries are logged with their query plans.
+// Zero disables it.
+func (db *LogDB) SetSlowQueryThreshold(d time.Duration) {
+	atomic.StoreInt64(&db.slowQueryThreshold, int64(d))
+}
+
+// logSlowQuery logs the query if it took longer than the threshold.
+// It must be called after rows of the query closed, since the db has only one connection.
+func (db *LogDB) logSlowQuery(start time.Time, stmt string, args []interface{}, scanned int, queryErr error) {
+	threshold := time.Duration(atomic.LoadInt64(&db.slowQueryThreshold))
+	elapsed := time.Since(start)
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+	plan, err := db.explainQueryPlan(stmt, args)
+	if err != nil {
+		plan = "error: " + err.Error()
+	}
+	log.Warn("slow query",
+		"elapsed", elapsed,
+		"scanned", scanned,
+		"err", queryErr,
+		"sql", stmt,
+		"args", argShapes(args),
+		"plan", plan)
+}
+
+// explainQueryPlan returns details of query plan steps, joined by '; '.
+func (db *LogDB) explainQueryPlan(stmt string, args []interface{}) (string, error) {
+	rows, err := db.reader.Query("EXPLAIN QUERY PLAN "+stmt, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var details []string
+	for rows.Next() {
+		// columns differ among sqlite versions, and detail is always the last one
+		values := make([]interface{}, len(cols))
+		var detail string
+		for i := range values {
+			values[i] = new(interface{})
+		}
+		values[len(values)-1] = &detail
+		if err := rows.Scan(values...); err != nil {
+			return "", err
+		}
+		details = append(details, detail)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(details, "; "), nil
+}
+
+// argShapes describes types of bound args, with values left out.
+func argShapes(args []interface{}) string {
+	shapes := make([]string, 0, len(args))
+	for _, arg := range args {
+		if b, ok := arg.([]byte); ok {
+			shapes = append(shapes, fmt.Sprintf("blob(%d)", len(b)))
+		} else {
+			shapes = append(shapes, fmt.Sprintf("%T", arg))
+		}
+	}
+	return "[" + strings.Join(shapes, ", ") + "]"
+}
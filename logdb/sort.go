@@ -0,0 +1,80 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"errors"
+	"strings"
+)
+
+// SortKey is the column to sort logs by, ahead of the position of logs.
+type SortKey string
+
+// Keys to sort events or transfers by.
+const (
+	SortByBlockTime SortKey = "blockTime" // events and transfers
+	SortByAddress   SortKey = "address"   // events
+	SortBySender    SortKey = "sender"    // transfers
+	SortByRecipient SortKey = "recipient" // transfers
+	SortByAmount    SortKey = "amount"    // transfers
+)
+
+// Sort is a key to sort logs by, in the order.
+type Sort struct {
+	Key   SortKey
+	Order Order // default asc
+}
+
+var (
+	// ErrUnsupportedSortKey is returned if the key is not supported to sort logs of the kind.
+	ErrUnsupportedSortKey = errors.New("unsupported sort key")
+	// ErrCursorWithSort is returned if a cursor is given along with sort keys, since cursors are
+	// positions in the default order.
+	ErrCursorWithSort = errors.New("cursor not supported with sort keys")
+)
+
+// sort expressions of keys, each served by an index led by it along with the position.
+var (
+	eventSortExprs = map[SortKey][]string{
+		// block time never decreases along block number
+		SortByBlockTime: {"blockNumber"},
+		SortByAddress:   {"address"},
+	}
+	transferSortExprs = map[SortKey][]string{
+		SortByBlockTime: {"blockNumber"},
+		SortBySender:    {"sender"},
+		SortByRecipient: {"recipient"},
+		// amounts are stored as big-endian bytes without leading zeros
+		SortByAmount: {"LENGTH(amount)", "amount"},
+	}
+)
+
+// orderClause returns the ORDER BY clause of sort keys, followed by the position in the order, then
+// removed logs after others at the same position if included.
+func orderClause(sorts []*Sort, exprs map[SortKey][]string, indexColumn string, order Order, includeRemoved bool) (string, error) {
+	var terms []string
+	for _, s := range sorts {
+		keyExprs, ok := exprs[s.Key]
+		if !ok {
+			return "", ErrUnsupportedSortKey
+		}
+		for _, expr := range keyExprs {
+			terms = append(terms, expr+orderDirection(s.Order))
+		}
+	}
+	terms = append(terms, "blockNumber"+orderDirection(order), indexColumn+orderDirection(order))
+	if includeRemoved {
+		terms = append(terms, "removed ASC")
+	}
+	return " ORDER BY " + strings.Join(terms, ",") + " ", nil
+}
+
+func orderDirection(order Order) string {
+	if order == DESC {
+		return " DESC"
+	}
+	return " ASC"
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/thor"
+)
+
+// Store is the storage backend of logs. LogDB on sqlite is the built-in one, and others,
+// e.g. on a hosted database, can be plugged in by Register.
+type Store interface {
+	// WriteBlock writes logs of the block atomically, replacing logs of blocks not lower than it.
+	// Logs of the genesis block are written without replacing.
+	WriteBlock(header *block.Header, events []*Event, transfers []*Transfer) error
+	// Truncate removes logs of blocks after num.
+	Truncate(num uint32) error
+	// Prune removes logs of blocks before num, and returns the count of removed logs.
+	Prune(num uint32) (int64, error)
+
+	// QueryLastBlockNumber returns the number of the last written block.
+	QueryLastBlockNumber() (uint32, error)
+	// QueryLastLoggedBlock returns the block of the last written log, found is false if no log.
+	QueryLastLoggedBlock() (num uint32, id thor.Bytes32, found bool, err error)
+
+	FilterEvents(ctx context.Context, filter *EventFilter) ([]*Event, error)
+	FilterTransfers(ctx context.Context, filter *TransferFilter) ([]*Transfer, error)
+
+	CountEvents(ctx context.Context, filter *EventFilter) (uint64, error)
+	CountTransfers(ctx context.Context, filter *TransferFilter) (uint64, error)
+	// CountEventsBy and CountTransfersBy return ErrUnsupportedGroupBy for columns not supported.
+	CountEventsBy(ctx context.Context, filter *EventFilter, by GroupBy, limit uint64) ([]*GroupCount, error)
+	CountTransfersBy(ctx context.Context, filter *TransferFilter, by GroupBy, limit uint64) ([]*GroupCount, error)
+	// AggregateEvents and AggregateTransfers return ErrUnsupportedBucket for buckets not supported.
+	AggregateEvents(ctx context.Context, filter *EventFilter, bucket Bucket) ([]*Aggregate, error)
+	AggregateTransfers(ctx context.Context, filter *TransferFilter, bucket Bucket) ([]*Aggregate, error)
+
+	Close()
+}
+
+var _ Store = (*LogDB)(nil)
+
+// OpenFunc opens a store with the backend specific source, e.g. the file path or connection string.
+type OpenFunc func(source string) (Store, error)
+
+var backends = struct {
+	sync.Mutex
+	m map[string]OpenFunc
+}{m: make(map[string]OpenFunc)}
+
+func init() {
+	Register("sqlite", func(source string) (Store, error) {
+		return New(source)
+	})
+}
+
+// Register makes a backend available by the name, usually called in init of the backend package.
+// It panics if the name is registered twice.
+func Register(name string, open OpenFunc) {
+	backends.Lock()
+	defer backends.Unlock()
+	if _, dup := backends.m[name]; dup {
+		panic("logdb: backend registered twice: " + name)
+	}
+	backends.m[name] = open
+}
+
+// Backends returns sorted names of registered backends.
+func Backends() []string {
+	backends.Lock()
+	defer backends.Unlock()
+	names := make([]string, 0, len(backends.m))
+	for name := range backends.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens a store by url in form of 'backend://source', e.g. 'sqlite:///path/to/logs.db'.
+func Open(url string) (Store, error) {
+	i := strings.Index(url, "://")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid url %q, expected backend://source", url)
+	}
+	name, source := url[:i], url[i+3:]
+
+	backends.Lock()
+	open, ok := backends.m[name]
+	backends.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q (registered: %v)", name, strings.Join(Backends(), ", "))
+	}
+	return open(source)
+}
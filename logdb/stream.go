@@ -0,0 +1,107 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"context"
+	"math"
+)
+
+// count of logs fetched per page when streaming
+const streamPageSize = 1000
+
+// StreamEvents calls fn with each event matching the filter, in order of the filter.
+// Events are fetched page by page with cursors, so that memory is bounded, and the store is not
+// held while fn is running. Options of the filter, if given, limit the whole stream.
+// Streaming stops at the first error returned by fn, and the error is returned.
+// With sort keys or removed logs included, pages are fetched by offsets, which is slower and may skip or repeat logs written
+// meanwhile.
+func StreamEvents(ctx context.Context, store Store, filter *EventFilter, fn func(*Event) error) error {
+	var f EventFilter
+	if filter != nil {
+		f = *filter
+	}
+	p := newStreamPager(f.Options, len(f.Sort) == 0 && !f.IncludeRemoved)
+	for {
+		f.Options = p.options()
+		events, err := store.FilterEvents(ctx, &f)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if len(events) == 0 || !p.next(uint64(len(events)), events[len(events)-1].Cursor()) {
+			return nil
+		}
+	}
+}
+
+// StreamTransfers calls fn with each transfer matching the filter, in order of the filter.
+// See StreamEvents.
+func StreamTransfers(ctx context.Context, store Store, filter *TransferFilter, fn func(*Transfer) error) error {
+	var f TransferFilter
+	if filter != nil {
+		f = *filter
+	}
+	p := newStreamPager(f.Options, len(f.Sort) == 0 && !f.IncludeRemoved)
+	for {
+		f.Options = p.options()
+		transfers, err := store.FilterTransfers(ctx, &f)
+		if err != nil {
+			return err
+		}
+		for _, transfer := range transfers {
+			if err := fn(transfer); err != nil {
+				return err
+			}
+		}
+		if len(transfers) == 0 || !p.next(uint64(len(transfers)), transfers[len(transfers)-1].Cursor()) {
+			return nil
+		}
+	}
+}
+
+// streamPager splits the range of options into pages.
+type streamPager struct {
+	offset    uint64
+	remaining uint64
+	cursor    *Cursor
+	byCursor  bool   // pages by cursors, or by offsets if logs are not in the order of positions
+	size      uint64 // size of the current page
+}
+
+func newStreamPager(opts *Options, byCursor bool) *streamPager {
+	if opts == nil {
+		return &streamPager{remaining: math.MaxUint64, byCursor: byCursor}
+	}
+	return &streamPager{offset: opts.Offset, remaining: opts.Limit, cursor: opts.Cursor, byCursor: byCursor}
+}
+
+func (p *streamPager) options() *Options {
+	p.size = streamPageSize
+	if p.remaining < p.size {
+		p.size = p.remaining
+	}
+	return &Options{Offset: p.offset, Limit: p.size, Cursor: p.cursor}
+}
+
+// next moves to the page after the cursor, returns false if no more.
+func (p *streamPager) next(n uint64, cursor *Cursor) bool {
+	if n < p.size {
+		return false
+	}
+	p.remaining -= n
+	if p.byCursor {
+		p.offset = 0
+		p.cursor = cursor
+	} else {
+		p.offset += n
+	}
+	return p.remaining > 0
+}
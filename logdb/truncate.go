@@ -0,0 +1,77 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package logdb
+
+import (
+	"database/sql"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/vechain/thor/thor"
+)
+
+// maxTruncateTxIDs limits count of tx IDs carried by a truncate event, e.g. on truncating a large range.
+const maxTruncateTxIDs = 10000
+
+// TruncateEvent will be posted when logs are removed by Truncate, or replaced by WriteBlock on reorgs.
+type TruncateEvent struct {
+	From    uint32         // logs of blocks from it are removed
+	TxIDs   []thor.Bytes32 // distinct IDs of txs which had logs removed, nil if TooMany
+	TooMany bool           // too many txs had logs removed, so all logs from From should be taken as removed
+}
+
+// SubscribeTruncateEvent receivers will receive removals of logs. Writes are blocked until events are
+// received, so receivers should use buffered channels and keep up.
+// Removed tx IDs are collected only while there are subscribers.
+func (db *LogDB) SubscribeTruncateEvent(ch chan *TruncateEvent) event.Subscription {
+	atomic.AddInt32(&db.truncateSubs, 1)
+	return db.scope.Track(&truncateSubscription{Subscription: db.truncateFeed.Subscribe(ch), db: db})
+}
+
+// truncateSubscription counts off the subscriber on unsubscribing.
+type truncateSubscription struct {
+	event.Subscription
+	db   *LogDB
+	once sync.Once
+}
+
+func (s *truncateSubscription) Unsubscribe() {
+	s.once.Do(func() { atomic.AddInt32(&s.db.truncateSubs, -1) })
+	s.Subscription.Unsubscribe()
+}
+
+// removedLogs returns the truncate event for logs of blocks from the given number, nil if nothing removed
+// or no subscriber.
+func (db *LogDB) removedLogs(tx *sql.Tx, from uint32) (*TruncateEvent, error) {
+	if atomic.LoadInt32(&db.truncateSubs) == 0 {
+		return nil, nil
+	}
+	rows, err := tx.Query("SELECT txID FROM event WHERE blockNumber >= ? UNION SELECT txID FROM transfer WHERE blockNumber >= ? LIMIT ?",
+		from, from, maxTruncateTxIDs+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var txIDs []thor.Bytes32
+	for rows.Next() {
+		var txID []byte
+		if err := rows.Scan(&txID); err != nil {
+			return nil, err
+		}
+		txIDs = append(txIDs, thor.BytesToBytes32(txID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(txIDs) == 0:
+		return nil, nil
+	case len(txIDs) > maxTruncateTxIDs:
+		return &TruncateEvent{From: from, TooMany: true}, nil
+	}
+	return &TruncateEvent{From: from, TxIDs: txIDs}, nil
+}
@@ -6,6 +6,9 @@
 package logdb
 
 import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"math/big"
 
 	"github.com/vechain/thor/block"
@@ -25,10 +28,12 @@ type Event struct {
 	Address     thor.Address // always a contract address
 	Topics      [5]*thor.Bytes32
 	Data        []byte
+	LogIndex    uint32 // index in events of the receipt output of the clause
+	Removed     bool   // whether the block is orphaned, only returned if removed logs are included
 }
 
 //newEvent converts tx.Event to Event.
-func newEvent(header *block.Header, index uint32, txID thor.Bytes32, txOrigin thor.Address, clauseIndex uint32, txEvent *tx.Event) *Event {
+func newEvent(header *block.Header, index uint32, txID thor.Bytes32, txOrigin thor.Address, clauseIndex uint32, logIndex uint32, txEvent *tx.Event) *Event {
 	ev := &Event{
 		BlockNumber: header.Number(),
 		Index:       index,
@@ -39,6 +44,7 @@ func newEvent(header *block.Header, index uint32, txID thor.Bytes32, txOrigin th
 		ClauseIndex: clauseIndex,
 		Address:     txEvent.Address, // always a contract address
 		Data:        txEvent.Data,
+		LogIndex:    logIndex,
 	}
 	for i := 0; i < len(txEvent.Topics) && i < len(ev.Topics); i++ {
 		ev.Topics[i] = &txEvent.Topics[i]
@@ -58,6 +64,7 @@ type Transfer struct {
 	Sender      thor.Address
 	Recipient   thor.Address
 	Amount      *big.Int
+	Removed     bool // whether the block is orphaned, only returned if removed logs are included
 }
 
 //newTransfer converts tx.Transfer to Transfer.
@@ -99,19 +106,68 @@ type Range struct {
 type Options struct {
 	Offset uint64
 	Limit  uint64
+	Cursor *Cursor // if set, logs are returned from after the cursor, which is much faster than offset
+}
+
+// Cursor is the position of a log, encoded as an opaque string in text form.
+// A page of logs can be continued by passing back the cursor of its last log.
+type Cursor struct {
+	BlockNumber uint32
+	Index       uint32
+}
+
+// Cursor returns the cursor of the event.
+func (e *Event) Cursor() *Cursor {
+	return &Cursor{e.BlockNumber, e.Index}
+}
+
+// Cursor returns the cursor of the transfer.
+func (t *Transfer) Cursor() *Cursor {
+	return &Cursor{t.BlockNumber, t.Index}
+}
+
+// String returns the text form of the cursor.
+func (c *Cursor) String() string {
+	var b [8]byte
+	binary.BigEndian.PutUint32(b[:], c.BlockNumber)
+	binary.BigEndian.PutUint32(b[4:], c.Index)
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c *Cursor) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Cursor) UnmarshalText(text []byte) error {
+	b, err := base64.RawURLEncoding.DecodeString(string(text))
+	if err != nil || len(b) != 8 {
+		return errors.New("invalid cursor")
+	}
+	c.BlockNumber = binary.BigEndian.Uint32(b)
+	c.Index = binary.BigEndian.Uint32(b[4:])
+	return nil
 }
 
 type EventCriteria struct {
-	Address *thor.Address // always a contract address
-	Topics  [5]*thor.Bytes32
+	Address     *thor.Address // always a contract address
+	Topics      [5]*thor.Bytes32
+	TxID        *thor.Bytes32 // the tx which emitted events
+	ClauseIndex *uint32       // the clause which emitted events
+	LogIndex    *uint32       // index in events emitted by the clause
 }
 
 //EventFilter filter
 type EventFilter struct {
-	CriteriaSet []*EventCriteria
-	Range       *Range
-	Options     *Options
-	Order       Order //default asc
+	CriteriaSet  []*EventCriteria
+	ExclusionSet []*EventCriteria // events matching any of them are excluded
+	Range        *Range
+	Options      *Options
+	Order        Order   //default asc
+	Sort         []*Sort // keys to sort by ahead of the position, which is in Order
+
+	IncludeRemoved bool // to include logs of orphaned blocks, if kept
 }
 
 type TransferCriteria struct {
@@ -121,9 +177,13 @@ type TransferCriteria struct {
 }
 
 type TransferFilter struct {
-	TxID        *thor.Bytes32
-	CriteriaSet []*TransferCriteria
-	Range       *Range
-	Options     *Options
-	Order       Order //default asc
+	TxID         *thor.Bytes32
+	CriteriaSet  []*TransferCriteria
+	ExclusionSet []*TransferCriteria // transfers matching any of them are excluded
+	Range        *Range
+	Options      *Options
+	Order        Order   //default asc
+	Sort         []*Sort // keys to sort by ahead of the position, which is in Order
+
+	IncludeRemoved bool // to include logs of orphaned blocks, if kept
 }
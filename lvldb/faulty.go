@@ -0,0 +1,180 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package lvldb
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vechain/thor/kv"
+)
+
+var _ kv.GetPutCloser = (*FaultyDB)(nil)
+
+// ErrInjected is the error returned by operations failed by fault injection.
+var ErrInjected = errors.New("lvldb: injected fault")
+
+// Faults describes faults injected by FaultyDB.
+// Rates are probabilities in range [0, 1].
+type Faults struct {
+	ReadErrorRate    float64       // of Get and Has
+	WriteErrorRate   float64       // of Put, Delete and batch Write, which leave db untouched
+	PartialBatchRate float64       // of batch Write, which applies a leading part of ops then fails
+	Latency          time.Duration // added to each operation
+}
+
+// FaultyDB is an in-memory level db with fault injection, for testing error handling paths.
+// Faults are drawn from a seeded random source, so runs with the same seed and ops are reproducible.
+type FaultyDB struct {
+	*LevelDB
+	lock   sync.Mutex
+	faults Faults
+	rand   *rand.Rand
+}
+
+// NewMemFaulty creates an in-memory level db, which injects faults drawn from random source of the seed.
+func NewMemFaulty(faults Faults, seed int64) (*FaultyDB, error) {
+	db, err := NewMem()
+	if err != nil {
+		return nil, err
+	}
+	return &FaultyDB{
+		LevelDB: db,
+		faults:  faults,
+		rand:    rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// SetFaults replaces faults to be injected. Zero value turns off fault injection.
+func (db *FaultyDB) SetFaults(faults Faults) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.faults = faults
+}
+
+// inject sleeps for the latency, and returns whether the fault of the rate is hit.
+func (db *FaultyDB) inject(rate func(f *Faults) float64) bool {
+	db.lock.Lock()
+	latency := db.faults.Latency
+	hit := db.rand.Float64() < rate(&db.faults)
+	db.lock.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+	return hit
+}
+
+// partial returns count of ops to be applied before failing a batch write, or -1 if not to fail.
+func (db *FaultyDB) partial(n int) int {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	if db.rand.Float64() < db.faults.PartialBatchRate {
+		return db.rand.Intn(n + 1)
+	}
+	return -1
+}
+
+func readErrorRate(f *Faults) float64  { return f.ReadErrorRate }
+func writeErrorRate(f *Faults) float64 { return f.WriteErrorRate }
+
+// Get implements kv.Getter.
+func (db *FaultyDB) Get(key []byte) ([]byte, error) {
+	if db.inject(readErrorRate) {
+		return nil, ErrInjected
+	}
+	return db.LevelDB.Get(key)
+}
+
+// Has implements kv.Getter.
+func (db *FaultyDB) Has(key []byte) (bool, error) {
+	if db.inject(readErrorRate) {
+		return false, ErrInjected
+	}
+	return db.LevelDB.Has(key)
+}
+
+// Put implements kv.Putter.
+func (db *FaultyDB) Put(key, value []byte) error {
+	if db.inject(writeErrorRate) {
+		return ErrInjected
+	}
+	return db.LevelDB.Put(key, value)
+}
+
+// Delete implements kv.Putter.
+func (db *FaultyDB) Delete(key []byte) error {
+	if db.inject(writeErrorRate) {
+		return ErrInjected
+	}
+	return db.LevelDB.Delete(key)
+}
+
+// NewBatch implements kv.Putter.
+func (db *FaultyDB) NewBatch() kv.Batch {
+	return &faultyBatch{db: db}
+}
+
+type batchOp struct {
+	key, value []byte
+	delete     bool
+}
+
+// faultyBatch records ops to be able to apply them partially.
+type faultyBatch struct {
+	db  *FaultyDB
+	ops []batchOp
+}
+
+func (b *faultyBatch) Put(key, value []byte) error {
+	b.ops = append(b.ops, batchOp{
+		key:   append([]byte(nil), key...),
+		value: append([]byte(nil), value...),
+	})
+	return nil
+}
+
+func (b *faultyBatch) Delete(key []byte) error {
+	b.ops = append(b.ops, batchOp{key: append([]byte(nil), key...), delete: true})
+	return nil
+}
+
+func (b *faultyBatch) NewBatch() kv.Batch {
+	return b.db.NewBatch()
+}
+
+func (b *faultyBatch) Len() int {
+	return len(b.ops)
+}
+
+func (b *faultyBatch) Write() error {
+	if b.db.inject(writeErrorRate) {
+		return ErrInjected
+	}
+	ops := b.ops
+	n := b.db.partial(len(ops))
+	if n >= 0 {
+		ops = ops[:n]
+	}
+	var batch leveldb.Batch
+	for _, op := range ops {
+		if op.delete {
+			batch.Delete(op.key)
+		} else {
+			batch.Put(op.key, op.value)
+		}
+	}
+	if err := b.db.db.Write(&batch, &writeOpt); err != nil {
+		return err
+	}
+	if n >= 0 {
+		return ErrInjected
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package lvldb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+)
+
+func TestFaultyDB(t *testing.T) {
+	db, err := lvldb.NewMemFaulty(lvldb.Faults{}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	key, value := []byte("key"), []byte("value")
+	assert.Nil(t, db.Put(key, value))
+	v, err := db.Get(key)
+	assert.Nil(t, err)
+	assert.Equal(t, value, v)
+
+	db.SetFaults(lvldb.Faults{ReadErrorRate: 1, WriteErrorRate: 1})
+	_, err = db.Get(key)
+	assert.Equal(t, lvldb.ErrInjected, err)
+	_, err = db.Has(key)
+	assert.Equal(t, lvldb.ErrInjected, err)
+	assert.Equal(t, lvldb.ErrInjected, db.Put([]byte("other"), value))
+	assert.Equal(t, lvldb.ErrInjected, db.Delete(key))
+
+	batch := db.NewBatch()
+	batch.Put([]byte("other"), value)
+	assert.Equal(t, lvldb.ErrInjected, batch.Write())
+
+	db.SetFaults(lvldb.Faults{})
+	has, err := db.Has([]byte("other"))
+	assert.Nil(t, err)
+	assert.False(t, has, "failed writes should leave db untouched")
+	has, err = db.Has(key)
+	assert.Nil(t, err)
+	assert.True(t, has)
+
+	db.SetFaults(lvldb.Faults{Latency: 10 * time.Millisecond})
+	start := time.Now()
+	_, err = db.Get(key)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 10*time.Millisecond)
+}
+
+func TestFaultyDBPartialBatch(t *testing.T) {
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}
+	write := func(seed int64) []bool {
+		db, err := lvldb.NewMemFaulty(lvldb.Faults{PartialBatchRate: 1}, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		batch := db.NewBatch()
+		for _, key := range keys {
+			batch.Put(key, key)
+		}
+		assert.Equal(t, 4, batch.Len())
+		assert.Equal(t, lvldb.ErrInjected, batch.Write())
+
+		db.SetFaults(lvldb.Faults{})
+		written := make([]bool, len(keys))
+		for i, key := range keys {
+			written[i], _ = db.Has(key)
+		}
+		return written
+	}
+
+	for seed := int64(0); seed < 10; seed++ {
+		written := write(seed)
+		// ops applied in order
+		for i := 1; i < len(written); i++ {
+			assert.False(t, written[i] && !written[i-1])
+		}
+		assert.Equal(t, written, write(seed), "should be deterministic")
+	}
+}
@@ -21,6 +21,7 @@ var _ kv.GetPutCloser = (*LevelDB)(nil)
 type Options struct {
 	CacheSize              int
 	OpenFilesCacheCapacity int
+	ReadOnly               bool
 }
 
 var writeOpt = opt.WriteOptions{}
@@ -48,9 +49,10 @@ func New(path string, opts Options) (*LevelDB, error) {
 		BlockCacheCapacity:            opts.CacheSize / 2 * opt.MiB,
 		WriteBuffer:                   opts.CacheSize / 4 * opt.MiB, // Two of these are used internally
 		Filter:                        filter.NewBloomFilter(10),
+		ReadOnly:                      opts.ReadOnly,
 	})
 
-	if _, corrupted := err.(*dberrors.ErrCorrupted); corrupted {
+	if _, corrupted := err.(*dberrors.ErrCorrupted); corrupted && !opts.ReadOnly {
 		db, err = leveldb.RecoverFile(path, nil)
 	}
 
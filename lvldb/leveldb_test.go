@@ -20,7 +20,7 @@ func TestLevelDB(t *testing.T) {
 		inValidKey = []byte("abc")
 	)
 	//TODO
-	lvldb, err := New("/tmp/lvldbDB.tmp", Options{16, 16})
+	lvldb, err := New("/tmp/lvldbDB.tmp", Options{16, 16, false})
 
 	defer lvldb.Close()
 	assert.Equal(t, err, nil)
@@ -72,7 +72,7 @@ func TestLevelDBBach(t *testing.T) {
 		key   = []byte("123")
 		value = []byte("456")
 	)
-	lvldb, err := New("/tmp/lvldbDBBatch.tmp", Options{16, 16})
+	lvldb, err := New("/tmp/lvldbDBBatch.tmp", Options{16, 16, false})
 
 	defer lvldb.Close()
 	assert.Equal(t, err, nil)
@@ -0,0 +1,20 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package p2psrv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxDialingNodes(t *testing.T) {
+	srv := New(&Options{MaxPeers: 25})
+	assert.Equal(t, 5, srv.maxDialingNodes())
+
+	assert.Nil(t, srv.SetMaxPeers(3))
+	assert.Equal(t, 1, srv.maxDialingNodes(), "still dialing with max peers lower than dial ratio")
+}
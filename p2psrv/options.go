@@ -39,6 +39,12 @@ type Options struct {
 	ListenAddr string
 
 	KnownNodes Nodes
+
+	// TrustedNodes are always connected and reconnected on disconnection.
+	// They are allowed to connect even above the peer limit, which means
+	// connection slots are reserved for them.
+	TrustedNodes Nodes
+
 	// BootstrapNodes are used to establish connectivity
 	// with the rest of the network using the V5 discovery
 	// protocol.
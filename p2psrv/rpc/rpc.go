@@ -9,6 +9,7 @@ import (
 	"context"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/p2p"
@@ -43,6 +44,7 @@ type RPC struct {
 	pendings map[uint32]*resultListener
 	lock     sync.Mutex
 	logger   log15.Logger
+	latency  int64 // round-trip time of last successful call, in nanoseconds
 }
 
 // New create a new RPC instance.
@@ -69,6 +71,12 @@ func (r *RPC) Done() <-chan struct{} {
 	return r.doneCh
 }
 
+// Latency returns round-trip time of last successful call.
+// Zero returned if no call completed yet.
+func (r *RPC) Latency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&r.latency))
+}
+
 // Serve handles peer's IO loop, and dispatches calls and results.
 func (r *RPC) Serve(handleFunc HandleFunc, maxMsgSize uint32) error {
 	defer func() { close(r.doneCh) }()
@@ -200,6 +208,7 @@ func (r *RPC) Call(ctx context.Context, msgCode uint64, arg interface{}, result
 	})
 	defer r.finalizeCall(id)
 
+	startTime := time.Now()
 	if err := p2p.Send(r.rw, msgCode, &msgData{id, false, arg}); err != nil {
 		return err
 	}
@@ -210,6 +219,9 @@ func (r *RPC) Call(ctx context.Context, msgCode uint64, arg interface{}, result
 	case <-ctx.Done():
 		return ctx.Err()
 	case err := <-errCh:
+		if err == nil {
+			atomic.StoreInt64(&r.latency, int64(time.Since(startTime)))
+		}
 		return err
 	}
 }
@@ -6,8 +6,10 @@
 package p2psrv
 
 import (
+	"fmt"
 	"math"
 	"net"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common/mclock"
@@ -32,6 +34,8 @@ type Server struct {
 	knownNodes      *cache.PrioCache
 	discoveredNodes *cache.RandCache
 	dialingNodes    *nodeMap
+	maxPeers        int32         // target count of peers, tunable at runtime
+	maxPeersCh      chan struct{} // signaled when maxPeers changed
 }
 
 // New create a p2p server.
@@ -47,22 +51,26 @@ func New(opts *Options) *Server {
 		opts: *opts,
 		srv: &p2p.Server{
 			Config: p2p.Config{
-				Name:        opts.Name,
-				PrivateKey:  opts.PrivateKey,
-				MaxPeers:    opts.MaxPeers,
-				NoDiscovery: true,
-				DiscoveryV5: false, // disable discovery inside p2p.Server instance
-				ListenAddr:  opts.ListenAddr,
-				NetRestrict: opts.NetRestrict,
-				NAT:         opts.NAT,
-				NoDial:      opts.NoDial,
-				DialRatio:   int(math.Sqrt(float64(opts.MaxPeers))),
+				Name:         opts.Name,
+				PrivateKey:   opts.PrivateKey,
+				MaxPeers:     opts.MaxPeers,
+				NoDiscovery:  true,
+				DiscoveryV5:  false, // disable discovery inside p2p.Server instance
+				ListenAddr:   opts.ListenAddr,
+				NetRestrict:  opts.NetRestrict,
+				NAT:          opts.NAT,
+				NoDial:       opts.NoDial,
+				DialRatio:    int(math.Sqrt(float64(opts.MaxPeers))),
+				StaticNodes:  opts.TrustedNodes,
+				TrustedNodes: opts.TrustedNodes,
 			},
 		},
 		done:            make(chan struct{}),
 		knownNodes:      knownNodes,
 		discoveredNodes: discoveredNodes,
 		dialingNodes:    newNodeMap(),
+		maxPeers:        int32(opts.MaxPeers),
+		maxPeersCh:      make(chan struct{}, 1),
 	}
 }
 
@@ -84,6 +92,11 @@ func (s *Server) Start(protocols []*Protocol) error {
 			}
 			log := log.New("peer", peer, "dir", dir)
 
+			// peers over the target are dropped, but slots are kept for trusted ones
+			if s.srv.PeerCount() > s.MaxPeers() && !peer.Info().Network.Trusted {
+				log.Debug("peer dropped, too many peers")
+				return p2p.DiscTooManyPeers
+			}
 			log.Debug("peer connected")
 			startTime := mclock.Now()
 			defer func() {
@@ -105,8 +118,14 @@ func (s *Server) Start(protocols []*Protocol) error {
 		if err := s.listenDiscV5(); err != nil {
 			return err
 		}
+		registered := make(map[discv5.Topic]bool)
 		for _, proto := range protocols {
 			topicToRegister := discv5.Topic(proto.DiscTopic)
+			// protocols of different versions may share the same topic
+			if registered[topicToRegister] {
+				continue
+			}
+			registered[topicToRegister] = true
 			log.Debug("registering topic", "topic", topicToRegister)
 			s.goes.Go(func() {
 				s.discv5.RegisterTopic(topicToRegister, s.done)
@@ -157,6 +176,54 @@ func (s *Server) RemoveStatic(node *discover.Node) {
 	s.srv.RemovePeer(node)
 }
 
+// AddTrustedPeer adds the given node to the trusted set, which is allowed to connect
+// even above the peer limit. It's also dialed and kept connected like static node.
+func (s *Server) AddTrustedPeer(node *discover.Node) {
+	s.srv.AddTrustedPeer(node)
+	s.srv.AddPeer(node)
+}
+
+// RemoveTrustedPeer removes the given node from the trusted set.
+func (s *Server) RemoveTrustedPeer(node *discover.Node) {
+	s.srv.RemoveTrustedPeer(node)
+	s.srv.RemovePeer(node)
+}
+
+// MaxPeers returns the target count of peers.
+func (s *Server) MaxPeers() int {
+	return int(atomic.LoadInt32(&s.maxPeers))
+}
+
+// SetMaxPeers adjusts the target count of peers at runtime. It can't exceed Options.MaxPeers,
+// which limits connections of the underlying server.
+// Peers over the new target are disconnected, except trusted ones.
+func (s *Server) SetMaxPeers(n int) error {
+	if n < 1 || n > s.opts.MaxPeers {
+		return fmt.Errorf("max peers should be in range [1, %v]", s.opts.MaxPeers)
+	}
+	atomic.StoreInt32(&s.maxPeers, int32(n))
+	select {
+	case s.maxPeersCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// dropExcessPeers disconnects untrusted peers over the target count.
+func (s *Server) dropExcessPeers() {
+	excess := s.srv.PeerCount() - s.MaxPeers()
+	for _, peer := range s.srv.Peers() {
+		if excess <= 0 {
+			return
+		}
+		if !peer.Info().Network.Trusted {
+			log.Debug("peer dropped, too many peers", "peer", peer)
+			peer.Disconnect(p2p.DiscTooManyPeers)
+			excess--
+		}
+	}
+}
+
 // NodeInfo gathers and returns a collection of metadata known about the host.
 func (s *Server) NodeInfo() *p2p.NodeInfo {
 	return s.srv.NodeInfo()
@@ -255,6 +322,15 @@ func (s *Server) discoverLoop(topic discv5.Topic) {
 	}
 }
 
+// maxDialingNodes returns the limit of nodes being dialed, which is at least 1 even if max peers tuned
+// lower than the dial ratio.
+func (s *Server) maxDialingNodes() int {
+	if n := s.MaxPeers() / s.srv.DialRatio; n > 0 {
+		return n
+	}
+	return 1
+}
+
 func (s *Server) dialLoop() {
 	const fastDialDur = 500 * time.Millisecond
 	const nonFastDialDur = 2 * time.Second
@@ -272,7 +348,7 @@ func (s *Server) dialLoop() {
 				continue
 			}
 
-			if s.dialingNodes.Len() >= s.srv.MaxPeers/s.srv.DialRatio {
+			if s.dialingNodes.Len() >= s.maxDialingNodes() {
 				continue
 			}
 
@@ -302,7 +378,7 @@ func (s *Server) dialLoop() {
 				ticker.Stop()
 				ticker = time.NewTicker(nonFastDialDur)
 			} else if dialCount > 20 {
-				if s.srv.PeerCount() > s.srv.MaxPeers/2 {
+				if s.srv.PeerCount() > s.MaxPeers()/2 {
 					ticker.Stop()
 					ticker = time.NewTicker(stableDialDur)
 				} else {
@@ -310,6 +386,8 @@ func (s *Server) dialLoop() {
 					ticker = time.NewTicker(nonFastDialDur)
 				}
 			}
+		case <-s.maxPeersCh:
+			s.dropExcessPeers()
 		case <-s.done:
 			return
 		}
@@ -4,3 +4,22 @@
 // file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
 
 package p2psrv_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/p2psrv"
+)
+
+func TestSetMaxPeers(t *testing.T) {
+	srv := p2psrv.New(&p2psrv.Options{MaxPeers: 25})
+	assert.Equal(t, 25, srv.MaxPeers())
+
+	assert.Nil(t, srv.SetMaxPeers(10))
+	assert.Equal(t, 10, srv.MaxPeers())
+
+	assert.NotNil(t, srv.SetMaxPeers(0))
+	assert.NotNil(t, srv.SetMaxPeers(26))
+	assert.Equal(t, 10, srv.MaxPeers())
+}
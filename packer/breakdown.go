@@ -0,0 +1,31 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package packer
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/thor"
+)
+
+// TxPayment the payment of a packed tx.
+type TxPayment struct {
+	TxID     thor.Bytes32
+	GasPayer thor.Address
+	GasUsed  uint64
+	Paid     *big.Int // energy paid by gas payer
+	Reward   *big.Int // energy rewarded to beneficiary
+}
+
+// Breakdown the economics of a packed block.
+type Breakdown struct {
+	BlockID     thor.Bytes32
+	Number      uint32
+	Beneficiary thor.Address
+	TotalPaid   *big.Int
+	TotalReward *big.Int
+	Txs         []*TxPayment
+}
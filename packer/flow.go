@@ -7,6 +7,7 @@ package packer
 
 import (
 	"crypto/ecdsa"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
@@ -74,7 +75,9 @@ func (f *Flow) Adopt(tx *tx.Transaction) error {
 		return badTxError{"chain tag mismatch"}
 	case tx.HasReservedFields():
 		return badTxError{"reserved fields not empty"}
-	case f.runtime.Context().Number < tx.BlockRef().Number():
+	case !tx.Features().IsSupported(f.packer.forkConfig, f.runtime.Context().Number):
+		return badTxError{"unsupported features"}
+	case tx.IsRefFuture(f.runtime.Context().Number):
 		return errTxNotAdoptableNow
 	case tx.IsExpired(f.runtime.Context().Number):
 		return badTxError{"expired"}
@@ -122,20 +125,20 @@ func (f *Flow) Adopt(tx *tx.Transaction) error {
 	return nil
 }
 
-// Pack build and sign the new block.
-func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, tx.Receipts, error) {
+// Pack build and sign the new block, along with the breakdown of payments and rewards.
+func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, tx.Receipts, *Breakdown, error) {
 	if f.packer.nodeMaster != thor.Address(crypto.PubkeyToAddress(privateKey.PublicKey)) {
-		return nil, nil, nil, errors.New("private key mismatch")
+		return nil, nil, nil, nil, errors.New("private key mismatch")
 	}
 
 	if err := f.runtime.Seeker().Err(); err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	stage := f.runtime.State().Stage()
 	stateRoot, err := stage.Hash()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	builder := new(block.Builder).
@@ -154,7 +157,32 @@ func (f *Flow) Pack(privateKey *ecdsa.PrivateKey) (*block.Block, *state.Stage, t
 
 	sig, err := crypto.Sign(newBlock.Header().SigningHash().Bytes(), privateKey)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
-	return newBlock.WithSignature(sig), stage, f.receipts, nil
+	newBlock = newBlock.WithSignature(sig)
+	return newBlock, stage, f.receipts, f.breakdown(newBlock.Header()), nil
+}
+
+func (f *Flow) breakdown(header *block.Header) *Breakdown {
+	b := &Breakdown{
+		BlockID:     header.ID(),
+		Number:      header.Number(),
+		Beneficiary: header.Beneficiary(),
+		TotalPaid:   new(big.Int),
+		TotalReward: new(big.Int),
+		Txs:         make([]*TxPayment, 0, len(f.txs)),
+	}
+	for i, tx := range f.txs {
+		receipt := f.receipts[i]
+		b.TotalPaid.Add(b.TotalPaid, receipt.Paid)
+		b.TotalReward.Add(b.TotalReward, receipt.Reward)
+		b.Txs = append(b.Txs, &TxPayment{
+			TxID:     tx.ID(),
+			GasPayer: receipt.GasPayer,
+			GasUsed:  receipt.GasUsed,
+			Paid:     receipt.Paid,
+			Reward:   receipt.Reward,
+		})
+	}
+	return b
 }
@@ -14,7 +14,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
-	"github.com/vechain/thor/builtin"
 	"github.com/vechain/thor/chain"
 	"github.com/vechain/thor/consensus"
 	"github.com/vechain/thor/genesis"
@@ -42,13 +41,9 @@ func (ti *txIterator) Next() *tx.Transaction {
 	a0 := accs[0]
 	a1 := accs[1]
 
-	method, _ := builtin.Energy.ABI.MethodByName("transfer")
-
-	data, _ := method.EncodeInput(a1.Address, big.NewInt(1))
-
 	tx := new(tx.Builder).
 		ChainTag(ti.chainTag).
-		Clause(tx.NewClause(&builtin.Energy.Address).WithData(data)).
+		Clause(tx.NewEnergyTransferClause(a1.Address, big.NewInt(1))).
 		Gas(300000).GasPriceCoef(0).Nonce(nonce).Expiration(math.MaxUint32).Build()
 	nonce++
 	sig, _ := crypto.Sign(tx.SigningHash().Bytes(), a0.PrivateKey)
@@ -94,7 +89,7 @@ func TestP(t *testing.T) {
 			flow.Adopt(tx)
 		}
 
-		blk, stage, receipts, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
+		blk, stage, receipts, _, err := flow.Pack(genesis.DevAccounts()[0].PrivateKey)
 		root, _ := stage.Commit()
 		assert.Equal(t, root, blk.Header().StateRoot())
 		fmt.Println(consensus.New(c, stateCreator).Process(blk, uint64(time.Now().Unix()*2)))
@@ -112,3 +107,49 @@ func TestP(t *testing.T) {
 	fmt.Println(best.Header().Number(), best.Header().GasUsed())
 	//	fmt.Println(best)
 }
+
+func TestBreakdown(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	defer kv.Close()
+
+	stateCreator := state.NewCreator(kv)
+	b0, _, _ := genesis.NewDevnet().Build(stateCreator)
+	c, _ := chain.New(kv, b0)
+
+	a0 := genesis.DevAccounts()[0]
+	flow, err := packer.New(c, stateCreator, a0.Address, &a0.Address).Schedule(b0.Header(), uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter := &txIterator{chainTag: c.Tag()}
+	var txs tx.Transactions
+	for i := 0; i < 2; i++ {
+		trx := iter.Next()
+		assert.Nil(t, flow.Adopt(trx))
+		txs = append(txs, trx)
+	}
+
+	blk, _, receipts, breakdown, err := flow.Pack(a0.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, blk.Header().ID(), breakdown.BlockID)
+	assert.Equal(t, blk.Header().Number(), breakdown.Number)
+	assert.Equal(t, a0.Address, breakdown.Beneficiary)
+	assert.Equal(t, 2, len(breakdown.Txs))
+
+	totalPaid, totalReward := new(big.Int), new(big.Int)
+	for i, payment := range breakdown.Txs {
+		assert.Equal(t, txs[i].ID(), payment.TxID)
+		assert.Equal(t, a0.Address, payment.GasPayer)
+		assert.Equal(t, receipts[i].GasUsed, payment.GasUsed)
+		assert.Equal(t, receipts[i].Paid, payment.Paid)
+		assert.Equal(t, receipts[i].Reward, payment.Reward)
+		totalPaid.Add(totalPaid, payment.Paid)
+		totalReward.Add(totalReward, payment.Reward)
+	}
+	assert.Equal(t, totalPaid, breakdown.TotalPaid)
+	assert.Equal(t, totalReward, breakdown.TotalReward)
+	assert.True(t, breakdown.TotalPaid.Sign() > 0)
+	assert.True(t, breakdown.TotalReward.Cmp(breakdown.TotalPaid) < 0)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package poa
+
+import (
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/vechain/thor/block"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// ProposerCache caches proposers of blocks, which are authority candidates qualified by endorsement
+// in the block state. They are shared by children of the block, e.g. competing blocks, and the schedule
+// of upcoming slots.
+type ProposerCache struct {
+	stateC *state.Creator
+	cache  *lru.Cache
+}
+
+// NewProposerCache creates a proposer cache.
+func NewProposerCache(stateC *state.Creator) *ProposerCache {
+	cache, _ := lru.New(32)
+	return &ProposerCache{stateC, cache}
+}
+
+// Proposers returns proposers in the state of the block, which schedule children of the block.
+// The returned slice is shared, and must not be modified.
+func (c *ProposerCache) Proposers(header *block.Header) ([]Proposer, error) {
+	if cached, ok := c.cache.Get(header.ID()); ok {
+		return cached.([]Proposer), nil
+	}
+	st, err := c.stateC.NewState(header.StateRoot())
+	if err != nil {
+		return nil, err
+	}
+	endorsement := builtin.Params.Native(st).Get(thor.KeyProposerEndorsement)
+	candidates := builtin.Authority.Native(st).Candidates(endorsement, thor.MaxBlockProposers)
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	proposers := make([]Proposer, 0, len(candidates))
+	for _, c := range candidates {
+		proposers = append(proposers, Proposer{
+			Address: c.NodeMaster,
+			Active:  c.Active,
+		})
+	}
+	c.cache.Add(header.ID(), proposers)
+	return proposers, nil
+}
@@ -127,6 +127,58 @@ func (s *Scheduler) Updates(newBlockTime uint64) (updates []Proposer, score uint
 	return
 }
 
+// Skipped returns proposers of time slots skipped right before newBlockTime, one per slot in time order.
+// Like Updates, at most MaxBlockProposers slots are counted.
+func (s *Scheduler) Skipped(newBlockTime uint64) []Proposer {
+	var skipped []Proposer
+	t := newBlockTime - thor.BlockInterval
+	for i := uint64(0); i < thor.MaxBlockProposers && t > s.parentBlockTime; i++ {
+		skipped = append([]Proposer{s.whoseTurn(t)}, skipped...)
+		t -= thor.BlockInterval
+	}
+	return skipped
+}
+
+// Slot a block time slot and its proposer.
+type Slot struct {
+	Timestamp uint64
+	Proposer  thor.Address
+}
+
+// Upcoming returns n slots since fromTime following the parent block, with proposers in turn.
+// It holds as long as no block is produced upon the parent. Only active proposers are scheduled,
+// as an inactive one is counted in only when it schedules itself.
+func Upcoming(proposers []Proposer, parentBlockNumber uint32, parentBlockTime uint64, fromTime uint64, n int) []Slot {
+	const T = thor.BlockInterval
+
+	actives := make([]Proposer, 0, len(proposers))
+	for _, p := range proposers {
+		if p.Active {
+			actives = append(actives, p)
+		}
+	}
+	if len(actives) == 0 {
+		return nil
+	}
+	s := &Scheduler{
+		actives:           actives,
+		parentBlockNumber: parentBlockNumber,
+		parentBlockTime:   parentBlockTime,
+	}
+
+	t := parentBlockTime + T
+	if fromTime > t {
+		// T aligned, and >= fromTime
+		t += (fromTime - t + T - 1) / T * T
+	}
+	slots := make([]Slot, 0, n)
+	for i := 0; i < n; i++ {
+		slots = append(slots, Slot{t, s.whoseTurn(t).Address})
+		t += T
+	}
+	return slots
+}
+
 // dprp deterministic pseudo-random process.
 // H(B, t)[:8]
 func dprp(blockNumber uint32, time uint64) uint64 {
@@ -80,3 +80,39 @@ func TestUpdates(t *testing.T) {
 		assert.Equal(t, tt.want, score)
 	}
 }
+
+func TestSkipped(t *testing.T) {
+
+	sched, _ := poa.NewScheduler(p1, proposers, 1, parentTime)
+
+	assert.Equal(t, 0, len(sched.Skipped(parentTime+thor.BlockInterval)))
+
+	skipped := sched.Skipped(parentTime + thor.BlockInterval*30)
+	assert.Equal(t, 29, len(skipped))
+	for _, p := range skipped {
+		// only p1 and p2 are scheduled
+		assert.True(t, p.Address == p1 || p.Address == p2)
+	}
+
+	assert.Equal(t, int(thor.MaxBlockProposers), len(sched.Skipped(parentTime+thor.BlockInterval*1000)))
+}
+
+func TestUpcoming(t *testing.T) {
+	actives := []poa.Proposer{{p1, true}, {p2, true}, {p3, false}}
+
+	slots := poa.Upcoming(actives, 1, parentTime, 0, 20)
+	assert.Equal(t, 20, len(slots))
+	for i, slot := range slots {
+		assert.Equal(t, parentTime+uint64(i+1)*thor.BlockInterval, slot.Timestamp)
+		assert.NotEqual(t, p3, slot.Proposer)
+
+		sched, _ := poa.NewScheduler(slot.Proposer, actives, 1, parentTime)
+		assert.True(t, sched.IsTheTime(slot.Timestamp))
+	}
+
+	// aligned to slots since the from time
+	slots = poa.Upcoming(actives, 1, parentTime, parentTime+thor.BlockInterval*5+1, 1)
+	assert.Equal(t, parentTime+thor.BlockInterval*6, slots[0].Timestamp)
+
+	assert.Nil(t, poa.Upcoming(proposers[2:], 1, parentTime, 0, 1))
+}
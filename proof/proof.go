@@ -0,0 +1,136 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package proof produces and verifies merkle proofs of txs and receipts inclusion
+// against block header roots.
+package proof
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/trie"
+	"github.com/vechain/thor/tx"
+)
+
+// Proof merkle proof of the item at Index of a list, e.g. txs or receipts of a block.
+type Proof struct {
+	Index uint
+	Nodes [][]byte
+}
+
+// ProveTransaction creates proof of the tx at index, against the txs root.
+func ProveTransaction(txs tx.Transactions, index int) (*Proof, error) {
+	items := make(list, len(txs))
+	for i, t := range txs {
+		items[i] = t
+	}
+	return prove(items, index)
+}
+
+// ProveReceipt creates proof of the receipt at index, against the receipts root.
+func ProveReceipt(receipts tx.Receipts, index int) (*Proof, error) {
+	items := make(list, len(receipts))
+	for i, r := range receipts {
+		items[i] = r
+	}
+	return prove(items, index)
+}
+
+// VerifyTransaction verifies the proof against the txs root, and returns the proved tx.
+func VerifyTransaction(txsRoot thor.Bytes32, proof *Proof) (*tx.Transaction, error) {
+	value, err := proof.Verify(txsRoot)
+	if err != nil {
+		return nil, err
+	}
+	var t tx.Transaction
+	if err := rlp.DecodeBytes(value, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// VerifyReceipt verifies the proof against the receipts root, and returns the proved receipt.
+func VerifyReceipt(receiptsRoot thor.Bytes32, proof *Proof) (*tx.Receipt, error) {
+	value, err := proof.Verify(receiptsRoot)
+	if err != nil {
+		return nil, err
+	}
+	var r tx.Receipt
+	if err := rlp.DecodeBytes(value, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Verify verifies the proof against the root, and returns the rlp encoded item.
+func (p *Proof) Verify(root thor.Bytes32) ([]byte, error) {
+	db := make(nodeSet, len(p.Nodes))
+	for _, node := range p.Nodes {
+		db.Put(thor.Blake2b(node).Bytes(), node)
+	}
+	key, _ := rlp.EncodeToBytes(p.Index)
+	value, err, _ := trie.VerifyProof(root, key, db)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, errors.New("item not included")
+	}
+	return value, nil
+}
+
+func prove(items list, index int) (*Proof, error) {
+	if index < 0 || index >= len(items) {
+		return nil, fmt.Errorf("index %v out of range", index)
+	}
+	var nodes nodeList
+	if err := trie.DeriveProof(items, index, &nodes); err != nil {
+		return nil, err
+	}
+	return &Proof{uint(index), nodes}, nil
+}
+
+// list implements trie.DerivableList.
+type list []interface{}
+
+func (l list) Len() int { return len(l) }
+
+func (l list) GetRlp(i int) []byte {
+	data, err := rlp.EncodeToBytes(l[i])
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// nodeList collects proof nodes in path order.
+type nodeList [][]byte
+
+func (l *nodeList) Put(key, value []byte) error {
+	*l = append(*l, append([]byte(nil), value...))
+	return nil
+}
+
+type nodeSet map[string][]byte
+
+func (s nodeSet) Put(key, value []byte) error {
+	s[string(key)] = value
+	return nil
+}
+
+func (s nodeSet) Get(key []byte) ([]byte, error) {
+	if value, ok := s[string(key)]; ok {
+		return value, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (s nodeSet) Has(key []byte) (bool, error) {
+	_, ok := s[string(key)]
+	return ok, nil
+}
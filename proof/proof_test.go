@@ -0,0 +1,64 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package proof_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/proof"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestProof(t *testing.T) {
+	var (
+		txs      tx.Transactions
+		receipts tx.Receipts
+	)
+	for i := 0; i < 200; i++ {
+		txs = append(txs, new(tx.Builder).Nonce(uint64(i)).Build())
+		receipts = append(receipts, &tx.Receipt{GasUsed: uint64(i), Paid: big.NewInt(int64(i)), Reward: &big.Int{}})
+	}
+	txsRoot := txs.RootHash()
+	receiptsRoot := receipts.RootHash()
+
+	for _, i := range []int{0, 1, 127, 128, 199} {
+		p, err := proof.ProveTransaction(txs, i)
+		assert.Nil(t, err)
+		trx, err := proof.VerifyTransaction(txsRoot, p)
+		assert.Nil(t, err)
+		assert.Equal(t, txs[i].ID(), trx.ID())
+
+		_, err = proof.VerifyTransaction(receiptsRoot, p)
+		assert.NotNil(t, err, "wrong root")
+
+		p, err = proof.ProveReceipt(receipts, i)
+		assert.Nil(t, err)
+		r, err := proof.VerifyReceipt(receiptsRoot, p)
+		assert.Nil(t, err)
+		assert.Equal(t, receipts[i].GasUsed, r.GasUsed)
+	}
+
+	// single item
+	p, err := proof.ProveTransaction(txs[:1], 0)
+	assert.Nil(t, err)
+	trx, err := proof.VerifyTransaction(txs[:1].RootHash(), p)
+	assert.Nil(t, err)
+	assert.Equal(t, txs[0].ID(), trx.ID())
+
+	// tampered index
+	p, _ = proof.ProveTransaction(txs, 3)
+	p.Index = 150
+	_, err = proof.VerifyTransaction(txsRoot, p)
+	assert.NotNil(t, err)
+
+	_, err = proof.ProveTransaction(txs, 200)
+	assert.NotNil(t, err)
+	_, err = proof.VerifyTransaction(thor.Bytes32{}, &proof.Proof{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package runtime
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/thor"
+)
+
+// AccountOverride describes account fields to be overridden.
+// Nil fields are left unchanged.
+type AccountOverride struct {
+	Balance *big.Int
+	Energy  *big.Int
+	Code    []byte // empty non-nil value clears code
+	Storage map[thor.Bytes32]thor.Bytes32
+}
+
+// StateOverrides maps address to account override.
+// It's used to simulate calls against a modified state.
+type StateOverrides map[thor.Address]*AccountOverride
+
+// Apply applies overrides to the state.
+// The state should be a throwaway one, since changes are not reverted.
+func (o StateOverrides) Apply(state *state.State, blockTime uint64) {
+	for addr, acc := range o {
+		if acc == nil {
+			continue
+		}
+		if acc.Balance != nil {
+			state.SetBalance(addr, acc.Balance)
+		}
+		if acc.Energy != nil {
+			state.SetEnergy(addr, acc.Energy, blockTime)
+		}
+		if acc.Code != nil {
+			state.SetCode(addr, acc.Code)
+		}
+		for key, value := range acc.Storage {
+			state.SetStorage(addr, key, value)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 type ResolvedTransaction struct {
 	tx           *tx.Transaction
 	Origin       thor.Address
+	Delegator    *thor.Address // nil if not delegated
 	IntrinsicGas uint64
 	Clauses      []*tx.Clause
 }
@@ -31,6 +32,20 @@ func ResolveTransaction(tx *tx.Transaction) (*ResolvedTransaction, error) {
 	if err != nil {
 		return nil, err
 	}
+	delegator, err := tx.Delegator()
+	if err != nil {
+		return nil, err
+	}
+	return resolveTransaction(tx, origin, delegator)
+}
+
+// ResolveUnsignedTransaction resolves the transaction as if signed by the origin, and the delegator if not nil.
+// It's for simulation only, since signatures are not verified.
+func ResolveUnsignedTransaction(tx *tx.Transaction, origin thor.Address, delegator *thor.Address) (*ResolvedTransaction, error) {
+	return resolveTransaction(tx, origin, delegator)
+}
+
+func resolveTransaction(tx *tx.Transaction, origin thor.Address, delegator *thor.Address) (*ResolvedTransaction, error) {
 	intrinsicGas, err := tx.IntrinsicGas()
 	if err != nil {
 		return nil, err
@@ -56,6 +71,7 @@ func ResolveTransaction(tx *tx.Transaction) (*ResolvedTransaction, error) {
 	return &ResolvedTransaction{
 		tx,
 		origin,
+		delegator,
 		intrinsicGas,
 		clauses,
 	}, nil
@@ -103,6 +119,14 @@ func (r *ResolvedTransaction) BuyGas(state *state.State, blockTime uint64) (
 	}
 
 	prepaid := new(big.Int).Mul(new(big.Int).SetUint64(r.tx.Gas()), gasPrice)
+	if r.Delegator != nil {
+		// delegated tx is paid by the delegator only
+		if energy.Sub(*r.Delegator, prepaid) {
+			return baseGasPrice, gasPrice, *r.Delegator, func(rgas uint64) { doReturnGas(rgas) }, nil
+		}
+		return nil, nil, thor.Address{}, nil, errors.New("insufficient energy")
+	}
+
 	commonTo := r.CommonTo()
 	if commonTo != nil {
 		binding := builtin.Prototype.Native(state).Bind(*commonTo)
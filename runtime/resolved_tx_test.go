@@ -171,6 +171,17 @@ func (tr *testResolvedTransaction) TestBuyGas() {
 		genesis.DevAccounts()[2].Address,
 		buyGas(txSign(txBuild().Clause(clause().WithValue(big.NewInt(100))))),
 	)
+
+	// delegator pays regardless of sponsor
+	delegated := tx.NewUnsigned(txBuild().Clause(clause().WithValue(big.NewInt(100))).Features(tx.DelegationFeature).Build())
+	delegated.Sign(genesis.DevAccounts()[0].PrivateKey)
+	delegated.SignAsDelegator(genesis.DevAccounts()[0].Address, genesis.DevAccounts()[3].PrivateKey)
+	delegatedTx, err := delegated.Assemble()
+	tr.assert.Nil(err)
+	tr.assert.Equal(
+		genesis.DevAccounts()[3].Address,
+		buyGas(delegatedTx),
+	)
 }
 
 func clause() *tx.Clause {
@@ -65,6 +65,14 @@ type Output struct {
 	RefundGas       uint64
 	VMErr           error         // VMErr identify the execution result of the contract function, not evm function's err.
 	ContractAddress *thor.Address // if create a new contract, or is nil.
+	GasBreakdown    GasBreakdown
+}
+
+// GasBreakdown describes gas consumption of a clause.
+type GasBreakdown struct {
+	Intrinsic uint64 // intrinsic gas of the clause, thor.TxGas excluded
+	Execution uint64 // gas consumed by vm execution
+	Refund    uint64 // gas refund claimed by vm execution, before capped
 }
 
 type TransactionExecutor struct {
@@ -266,6 +274,21 @@ func (rt *Runtime) ExecuteClause(
 	return output
 }
 
+// TraceClause executes single clause with the tracer attached, e.g. vm.StructLogger or vm.CallLogger.
+// Tracing affects only this execution.
+func (rt *Runtime) TraceClause(
+	clause *tx.Clause,
+	clauseIndex uint32,
+	gas uint64,
+	txCtx *xenv.TransactionContext,
+	tracer vm.Tracer,
+) *Output {
+	traced := *rt
+	traced.vmConfig.Debug = true
+	traced.vmConfig.Tracer = tracer
+	return traced.ExecuteClause(clause, clauseIndex, gas, txCtx)
+}
+
 // PrepareClause prepare to execute clause.
 // It allows to interrupt execution.
 func (rt *Runtime) PrepareClause(
@@ -294,12 +317,19 @@ func (rt *Runtime) PrepareClause(
 		}
 
 		interrupted := atomic.LoadUint32(&interruptFlag) != 0
+		// error ignored since intrinsic gas is validated along with tx
+		intrinsicGas, _ := tx.ClauseIntrinsicGas(clause)
 		output := &Output{
 			Data:            data,
 			LeftOverGas:     leftOverGas,
 			RefundGas:       stateDB.GetRefund(),
 			VMErr:           vmErr,
 			ContractAddress: contractAddr,
+			GasBreakdown: GasBreakdown{
+				Intrinsic: intrinsicGas,
+				Execution: gas - leftOverGas,
+				Refund:    stateDB.GetRefund(),
+			},
 		}
 		output.Events, output.Transfers = stateDB.GetLogs()
 		return output, interrupted
@@ -333,6 +363,15 @@ func (rt *Runtime) PrepareTransaction(tx *tx.Transaction) (*TransactionExecutor,
 	if err != nil {
 		return nil, err
 	}
+	return rt.PrepareResolvedTransaction(resolvedTx)
+}
+
+// PrepareResolvedTransaction prepare to execute the resolved tx.
+func (rt *Runtime) PrepareResolvedTransaction(resolvedTx *ResolvedTransaction) (*TransactionExecutor, error) {
+	tx := resolvedTx.tx
+	if !tx.Features().IsSupported(rt.forkConfig, rt.ctx.Number) {
+		return nil, errors.New("unsupported features")
+	}
 
 	baseGasPrice, gasPrice, payer, returnGas, err := resolvedTx.BuyGas(rt.state, rt.ctx.Time)
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	"github.com/vechain/thor/state"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/vm"
 	"github.com/vechain/thor/xenv"
 )
 
@@ -194,3 +195,96 @@ func TestExecuteTransaction(t *testing.T) {
 	// _ = receipt
 	// assert.Equal(t, state.GetBalance(addr1), new(big.Int).Sub(balance1, big.NewInt(10)))
 }
+
+func TestTraceClause(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	g := genesis.NewDevnet()
+	b0, _, err := g.Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := chain.New(kv, b0)
+	state, _ := state.New(b0.Header().StateRoot(), kv)
+	rt := runtime.New(ch.NewSeeker(b0.Header().ID()), state, &xenv.BlockContext{Time: b0.Header().Timestamp()})
+
+	// transfer from an account without energy
+	origin := thor.BytesToAddress([]byte("poor"))
+	clause := tx.NewEnergyTransferClause(genesis.DevAccounts()[0].Address, big.NewInt(1))
+
+	callLogger := vm.NewCallLogger()
+	out := rt.TraceClause(clause, 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin}, callLogger)
+	assert.NotNil(t, out.VMErr)
+
+	root := callLogger.Result()
+	assert.Equal(t, "CALL", root.Type)
+	assert.Equal(t, common.Address(origin), root.From)
+	assert.Equal(t, common.Address(builtin.Energy.Address), root.To)
+	assert.Equal(t, clause.Data(), []byte(root.Input))
+	assert.Equal(t, out.VMErr.Error(), root.Error)
+	assert.Equal(t, "builtin: insufficient balance", root.RevertReason)
+	assert.True(t, root.GasUsed > 0)
+
+	// the native call
+	assert.Equal(t, 1, len(root.Calls))
+	assert.Equal(t, common.Address(builtin.Energy.Address), root.Calls[0].From)
+	assert.Equal(t, common.Address(builtin.Energy.Address), root.Calls[0].To)
+	assert.Equal(t, "", root.Calls[0].Error)
+
+	structLogger := vm.NewStructLogger(nil)
+	rt.TraceClause(clause, 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin}, structLogger)
+	logs := structLogger.StructLogs()
+	assert.NotEqual(t, 0, len(logs))
+	assert.Equal(t, "REVERT", logs[len(logs)-1].Op.String())
+
+	// tracing is per execution
+	out = rt.ExecuteClause(clause, 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin})
+	assert.NotNil(t, out.VMErr)
+	assert.Equal(t, len(logs), len(structLogger.StructLogs()))
+}
+
+func TestStateOverrides(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+
+	g := genesis.NewDevnet()
+	b0, _, err := g.Build(state.NewCreator(kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := chain.New(kv, b0)
+	state, _ := state.New(b0.Header().StateRoot(), kv)
+	blockTime := b0.Header().Timestamp()
+	rt := runtime.New(ch.NewSeeker(b0.Header().ID()), state, &xenv.BlockContext{Time: blockTime})
+
+	origin := thor.BytesToAddress([]byte("poor"))
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	clause := tx.NewEnergyTransferClause(recipient, big.NewInt(1))
+
+	out := rt.ExecuteClause(clause, 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin})
+	assert.NotNil(t, out.VMErr, "insufficient energy")
+
+	// contract returns storage slot 0:
+	//
+	// PUSH1 0 SLOAD PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	code, _ := hex.DecodeString("60005460005260206000f3")
+	contract := thor.BytesToAddress([]byte("contract"))
+	slotValue := thor.BytesToBytes32([]byte("value"))
+
+	runtime.StateOverrides{
+		origin: {Energy: big.NewInt(100), Balance: big.NewInt(200)},
+		contract: {
+			Code:    code,
+			Storage: map[thor.Bytes32]thor.Bytes32{{}: slotValue},
+		},
+	}.Apply(state, blockTime)
+
+	assert.Equal(t, big.NewInt(200), state.GetBalance(origin))
+
+	out = rt.ExecuteClause(clause, 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin})
+	assert.Nil(t, out.VMErr)
+	assert.Equal(t, big.NewInt(99), state.GetEnergy(origin, blockTime))
+
+	out = rt.ExecuteClause(tx.NewClause(&contract), 0, math.MaxUint64, &xenv.TransactionContext{Origin: origin})
+	assert.Nil(t, out.VMErr)
+	assert.Equal(t, slotValue.Bytes(), out.Data)
+}
@@ -70,6 +70,12 @@ func (co *cachedObject) GetStorage(key thor.Bytes32) (rlp.RawValue, error) {
 	return v, nil
 }
 
+// cachedStorage returns storage value for given key, only if it's in cache.
+func (co *cachedObject) cachedStorage(key thor.Bytes32) (rlp.RawValue, bool) {
+	v, ok := co.cache.storage[key]
+	return v, ok
+}
+
 // GetCode returns the code of the account.
 func (co *cachedObject) GetCode() ([]byte, error) {
 	cache := &co.cache
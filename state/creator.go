@@ -6,8 +6,11 @@
 package state
 
 import (
+	"context"
+
 	"github.com/vechain/thor/kv"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tracing"
 )
 
 // Creator state creator to cut-off kv dependency.
@@ -24,3 +27,13 @@ func NewCreator(kv kv.GetPutter) *Creator {
 func (c *Creator) NewState(root thor.Bytes32) (*State, error) {
 	return New(root, c.kv)
 }
+
+// NewStateWithContext create a new state object, which records loads from tries into the span of ctx.
+func (c *Creator) NewStateWithContext(ctx context.Context, root thor.Bytes32) (*State, error) {
+	state, err := New(root, c.kv)
+	if err != nil {
+		return nil, err
+	}
+	state.span = tracing.SpanFromContext(ctx)
+	return state, nil
+}
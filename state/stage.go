@@ -6,6 +6,11 @@
 package state
 
 import (
+	"bytes"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/co"
 	"github.com/vechain/thor/kv"
 	"github.com/vechain/thor/thor"
 	"github.com/vechain/thor/trie"
@@ -19,6 +24,7 @@ type Stage struct {
 	accountTrie  *trie.SecureTrie
 	storageTries []*trie.SecureTrie
 	codes        []codeWithHash
+	usages       map[thor.Bytes32]*StorageUsage
 }
 
 type codeWithHash struct {
@@ -34,7 +40,44 @@ func newStage(root thor.Bytes32, kv kv.GetPutter, changes map[thor.Address]*chan
 	}
 
 	storageTries := make([]*trie.SecureTrie, 0, len(changes))
+	storageRoots := make(map[thor.Address]thor.Bytes32)
 	codes := make([]codeWithHash, 0, len(changes))
+	usages := make(map[thor.Bytes32]*StorageUsage)
+
+	// storage tries are independent, so updated and hashed in parallel
+	var (
+		lock     sync.Mutex
+		firstErr error
+	)
+	<-co.Parallel(func(queue chan<- func()) {
+		for addr, obj := range changes {
+			// skip storage changes if account is empty
+			if obj.data.IsEmpty() || len(obj.storage) == 0 {
+				continue
+			}
+			addr, obj := addr, obj
+			queue <- func() {
+				strie, root, usage, err := updateStorage(kv, obj)
+
+				lock.Lock()
+				defer lock.Unlock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					return
+				}
+				storageTries = append(storageTries, strie)
+				storageRoots[addr] = root
+				if usage != nil {
+					usages[root] = usage
+				}
+			}
+		}
+	})
+	if firstErr != nil {
+		return &Stage{err: firstErr}
+	}
 
 	for addr, obj := range changes {
 		dataCpy := obj.data
@@ -44,24 +87,9 @@ func newStage(root thor.Bytes32, kv kv.GetPutter, changes map[thor.Address]*chan
 				code: obj.code,
 				hash: dataCpy.CodeHash})
 		}
-
-		// skip storage changes if account is empty
-		if !dataCpy.IsEmpty() {
-			if len(obj.storage) > 0 {
-				strie, err := trCache.Get(thor.BytesToBytes32(dataCpy.StorageRoot), kv, true)
-				if err != nil {
-					return &Stage{err: err}
-				}
-				storageTries = append(storageTries, strie)
-				for k, v := range obj.storage {
-					if err := saveStorage(strie, k, v); err != nil {
-						return &Stage{err: err}
-					}
-				}
-				dataCpy.StorageRoot = strie.Hash().Bytes()
-			}
+		if root, ok := storageRoots[addr]; ok {
+			dataCpy.StorageRoot = root.Bytes()
 		}
-
 		if err := saveAccount(accountTrie, addr, &dataCpy); err != nil {
 			return &Stage{err: err}
 		}
@@ -71,9 +99,50 @@ func newStage(root thor.Bytes32, kv kv.GetPutter, changes map[thor.Address]*chan
 		accountTrie:  accountTrie,
 		storageTries: storageTries,
 		codes:        codes,
+		usages:       usages,
 	}
 }
 
+// updateStorage applies storage changes of the object to its storage trie, and tracks the usage.
+// The updated trie is returned with its root.
+func updateStorage(kv kv.GetPutter, obj *changedObject) (*trie.SecureTrie, thor.Bytes32, *StorageUsage, error) {
+	strie, err := trCache.Get(thor.BytesToBytes32(obj.data.StorageRoot), kv, true)
+	if err != nil {
+		return nil, thor.Bytes32{}, nil, err
+	}
+	// usage is tracked only if the usage of the original storage is known
+	usage, err := loadStorageUsage(kv, obj.data.StorageRoot)
+	if err != nil {
+		return nil, thor.Bytes32{}, nil, err
+	}
+	// original values read during execution are reused, if the storage root unchanged
+	base := obj.base
+	if base != nil && !bytes.Equal(base.data.StorageRoot, obj.data.StorageRoot) {
+		base = nil
+	}
+	for k, v := range obj.storage {
+		if usage != nil {
+			var (
+				old rlp.RawValue
+				ok  bool
+			)
+			if base != nil {
+				old, ok = base.cachedStorage(k)
+			}
+			if !ok {
+				if old, err = loadStorage(strie, k); err != nil {
+					return nil, thor.Bytes32{}, nil, err
+				}
+			}
+			usage.add(old, v)
+		}
+		if err := saveStorage(strie, k, v); err != nil {
+			return nil, thor.Bytes32{}, nil, err
+		}
+	}
+	return strie, strie.Hash(), usage, nil
+}
+
 // Hash computes hash of the main accounts trie.
 func (s *Stage) Hash() (thor.Bytes32, error) {
 	if s.err != nil {
@@ -95,13 +164,38 @@ func (s *Stage) Commit() (thor.Bytes32, error) {
 		}
 	}
 
-	// commit storage tries
-	for _, strie := range s.storageTries {
-		root, err := strie.CommitTo(batch)
-		if err != nil {
+	// commit storage tries in parallel, with puts into the batch serialized
+	var (
+		lock     sync.Mutex
+		firstErr error
+	)
+	lockedBatch := &lockedPutter{putter: batch}
+	<-co.Parallel(func(queue chan<- func()) {
+		for _, strie := range s.storageTries {
+			strie := strie
+			queue <- func() {
+				root, err := strie.CommitTo(lockedBatch)
+				if err != nil {
+					lock.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					lock.Unlock()
+					return
+				}
+				trCache.Add(root, strie, s.kv)
+			}
+		}
+	})
+	if firstErr != nil {
+		return thor.Bytes32{}, firstErr
+	}
+
+	// write storage usages
+	for root, usage := range s.usages {
+		if err := saveStorageUsage(batch, root, usage); err != nil {
 			return thor.Bytes32{}, err
 		}
-		trCache.Add(root, strie, s.kv)
 	}
 
 	// commit accounts trie
@@ -118,3 +212,15 @@ func (s *Stage) Commit() (thor.Bytes32, error) {
 
 	return root, nil
 }
+
+// lockedPutter serializes puts to the underlying putter.
+type lockedPutter struct {
+	lock   sync.Mutex
+	putter kv.Putter
+}
+
+func (lp *lockedPutter) Put(key, value []byte) error {
+	lp.lock.Lock()
+	defer lp.lock.Unlock()
+	return lp.putter.Put(key, value)
+}
@@ -53,3 +53,32 @@ func TestStage(t *testing.T) {
 		assert.Equal(t, v, state.GetStorage(addr, k))
 	}
 }
+
+func TestStageManyStorageTries(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+
+	// enough accounts and slots to be updated and committed in parallel
+	for i := 0; i < 20; i++ {
+		addr := thor.BytesToAddress([]byte{byte(i)})
+		state.SetBalance(addr, big.NewInt(1))
+		for j := 0; j < 200; j++ {
+			state.SetStorage(addr, thor.BytesToBytes32([]byte{byte(j)}), thor.BytesToBytes32([]byte{byte(i), byte(j)}))
+		}
+	}
+
+	stage := state.Stage()
+	hash, err := stage.Hash()
+	assert.Nil(t, err)
+	root, err := stage.Commit()
+	assert.Nil(t, err)
+	assert.Equal(t, hash, root)
+
+	state, _ = New(root, kv)
+	for i := 0; i < 20; i++ {
+		addr := thor.BytesToAddress([]byte{byte(i)})
+		for j := 0; j < 200; j++ {
+			assert.Equal(t, thor.BytesToBytes32([]byte{byte(i), byte(j)}), state.GetStorage(addr, thor.BytesToBytes32([]byte{byte(j)})))
+		}
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/vechain/thor/kv"
 	"github.com/vechain/thor/stackedmap"
 	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tracing"
 	"github.com/vechain/thor/trie"
 )
 
@@ -27,6 +29,7 @@ type State struct {
 	sm       *stackedmap.StackedMap         // keeps revisions of accounts state
 	err      error
 	setError func(err error)
+	span     *tracing.Span // to record loads from tries
 }
 
 // to constrain ability of trie
@@ -78,6 +81,9 @@ func (s *State) Spawn(root thor.Bytes32) *State {
 
 // implements stackedmap.MapGetter
 func (s *State) cacheGetter(key interface{}) (value interface{}, exist bool) {
+	if s.span != nil {
+		defer func(start time.Time) { s.span.AddDuration("state.load", time.Since(start)) }(time.Now())
+	}
 	switch k := key.(type) {
 	case thor.Address: // get account
 		return &s.getCachedObject(k).data, true
@@ -109,7 +115,8 @@ func (s *State) changes() map[thor.Address]*changedObject {
 		if obj, ok := changes[addr]; ok {
 			return obj
 		}
-		obj := &changedObject{data: s.getCachedObject(addr).data}
+		co := s.getCachedObject(addr)
+		obj := &changedObject{data: co.data, base: co}
 		changes[addr] = obj
 		return obj
 	}
@@ -414,5 +421,6 @@ type (
 		data    Account
 		storage map[thor.Bytes32]rlp.RawValue
 		code    []byte
+		base    *cachedObject // the object before changes, with storage values read
 	}
 )
@@ -0,0 +1,76 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/kv"
+	"github.com/vechain/thor/thor"
+)
+
+var (
+	storageUsagePrefix = []byte("storageusage")
+	emptyStorageRoot   = thor.Blake2b(rlp.EmptyString)
+)
+
+// StorageUsage is the usage of a storage trie.
+// It's tracked incrementally when storage tries committed, keyed by storage root.
+type StorageUsage struct {
+	Slots uint64 // count of non-empty slots
+	Bytes uint64 // total size of slot keys and rlp encoded values
+}
+
+// add applies the change of a slot.
+func (u *StorageUsage) add(oldValue, newValue rlp.RawValue) {
+	if len(oldValue) > 0 {
+		u.Slots--
+		u.Bytes -= uint64(len(thor.Bytes32{}) + len(oldValue))
+	}
+	if len(newValue) > 0 {
+		u.Slots++
+		u.Bytes += uint64(len(thor.Bytes32{}) + len(newValue))
+	}
+}
+
+// loadStorageUsage loads usage of storage trie with the root.
+// It returns nil if not tracked.
+func loadStorageUsage(getter kv.Getter, root []byte) (*StorageUsage, error) {
+	if r := thor.BytesToBytes32(root); r.IsZero() || r == emptyStorageRoot {
+		return &StorageUsage{}, nil
+	}
+	data, err := getter.Get(append(storageUsagePrefix, root...))
+	if err != nil {
+		if getter.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var u StorageUsage
+	if err := rlp.DecodeBytes(data, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func saveStorageUsage(putter kv.Putter, root thor.Bytes32, u *StorageUsage) error {
+	data, err := rlp.EncodeToBytes(u)
+	if err != nil {
+		return err
+	}
+	return putter.Put(append(storageUsagePrefix, root[:]...), data)
+}
+
+// GetStorageUsage returns storage usage of the given address.
+// It returns nil if the storage was not tracked. There's no backfill, so storage is tracked only
+// if it's empty or all its changes are committed by nodes with usage tracking.
+func (s *State) GetStorageUsage(addr thor.Address) *StorageUsage {
+	u, err := loadStorageUsage(s.kv, s.getAccount(addr).StorageRoot)
+	if err != nil {
+		s.setError(err)
+		return nil
+	}
+	return u
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/thor"
+)
+
+func TestStorageUsage(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	state, _ := New(thor.Bytes32{}, kv)
+
+	addr := thor.BytesToAddress([]byte("acc1"))
+	assert.Equal(t, &StorageUsage{}, state.GetStorageUsage(addr))
+
+	k1, k2 := thor.BytesToBytes32([]byte("k1")), thor.BytesToBytes32([]byte("k2"))
+	state.SetBalance(addr, big.NewInt(1))
+	state.SetStorage(addr, k1, thor.BytesToBytes32([]byte{1}))
+	state.SetStorage(addr, k2, thor.BytesToBytes32([]byte{1, 2}))
+	root, err := state.Stage().Commit()
+	assert.Nil(t, err)
+
+	state, _ = New(root, kv)
+	// 32 bytes key plus rlp encoded value for each slot
+	assert.Equal(t, &StorageUsage{Slots: 2, Bytes: 32 + 1 + 32 + 3}, state.GetStorageUsage(addr))
+
+	// the old value read before changed is reused
+	assert.Equal(t, thor.BytesToBytes32([]byte{1, 2}), state.GetStorage(addr, k2))
+	state.SetStorage(addr, k1, thor.Bytes32{})
+	state.SetStorage(addr, k2, thor.BytesToBytes32([]byte{1, 2, 3}))
+	root, err = state.Stage().Commit()
+	assert.Nil(t, err)
+
+	state, _ = New(root, kv)
+	assert.Equal(t, &StorageUsage{Slots: 1, Bytes: 32 + 4}, state.GetStorageUsage(addr))
+
+	// storage changed before tracking
+	storageRoot := thor.BytesToBytes32(state.getAccount(addr).StorageRoot)
+	assert.Nil(t, kv.Delete(append(storageUsagePrefix, storageRoot[:]...)))
+	state, _ = New(root, kv)
+	assert.Nil(t, state.GetStorageUsage(addr))
+
+	state.SetStorage(addr, k1, thor.BytesToBytes32([]byte{1}))
+	root, err = state.Stage().Commit()
+	assert.Nil(t, err)
+	state, _ = New(root, kv)
+	assert.Nil(t, state.GetStorageUsage(addr), "still untracked")
+	assert.Nil(t, state.Err())
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package telemetry reports anonymous node statistics to an endpoint, when opted in.
+//
+// Reports are posted as JSON objects of Report, one per request, with content type 'application/json'.
+// Fields are flat and stable within a format version, so that collectors can aggregate them directly,
+// e.g. count distinct instance IDs grouped by genesis ID and version.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/thor"
+)
+
+var log = log15.New("pkg", "telemetry")
+
+// FormatVersion version of the report format, increased on incompatible changes.
+const FormatVersion = 1
+
+// Report anonymous statistics of a node.
+type Report struct {
+	Format        int          `json:"format"`
+	InstanceID    string       `json:"instanceID"` // random, not linked to node key or address
+	Version       string       `json:"version"`
+	GenesisID     thor.Bytes32 `json:"genesisID"`
+	BestNumber    uint32       `json:"bestNumber"`
+	BestTimestamp uint64       `json:"bestTimestamp"`
+	PeerCount     int          `json:"peerCount"`
+	OS            string       `json:"os"`
+	Arch          string       `json:"arch"`
+	GoVersion     string       `json:"goVersion"`
+	Timestamp     uint64       `json:"timestamp"` // when the report is made
+}
+
+// NewReport makes a report of current status of the node.
+func NewReport(instanceID, version string, chain *chain.Chain, peerCount int) *Report {
+	best := chain.BestBlock().Header()
+	return &Report{
+		Format:        FormatVersion,
+		InstanceID:    instanceID,
+		Version:       version,
+		GenesisID:     chain.GenesisBlock().Header().ID(),
+		BestNumber:    best.Number(),
+		BestTimestamp: best.Timestamp(),
+		PeerCount:     peerCount,
+		OS:            goruntime.GOOS,
+		Arch:          goruntime.GOARCH,
+		GoVersion:     goruntime.Version(),
+		Timestamp:     uint64(time.Now().Unix()),
+	}
+}
+
+// Reporter posts reports to the endpoint periodically.
+type Reporter struct {
+	url      string
+	interval time.Duration
+	collect  func() *Report
+	client   *http.Client
+}
+
+// New creates a reporter, which posts reports made by collect to the url every interval.
+func New(url string, interval time.Duration, collect func() *Report) *Reporter {
+	return &Reporter{
+		url:      url,
+		interval: interval,
+		collect:  collect,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Run reports at once and then periodically, until ctx done.
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Report(ctx); err != nil {
+			log.Debug("failed to report", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report posts a report.
+func (r *Reporter) Report(ctx context.Context) error {
+	data, err := json.Marshal(r.collect())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", r.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	ioutil.ReadAll(res.Body)
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("status %v", res.Status)
+	}
+	return nil
+}
+
+// LoadOrCreateInstanceID loads the instance ID from file at path, or creates a random one.
+func LoadOrCreateInstanceID(path string) (string, error) {
+	if data, err := ioutil.ReadFile(path); err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b[:])
+	if err := ioutil.WriteFile(path, []byte(id), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/chain"
+	"github.com/vechain/thor/genesis"
+	"github.com/vechain/thor/lvldb"
+	"github.com/vechain/thor/state"
+	"github.com/vechain/thor/telemetry"
+)
+
+func TestReport(t *testing.T) {
+	db, _ := lvldb.NewMem()
+	b0, _, err := genesis.NewDevnet().Build(state.NewCreator(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := chain.New(db, b0)
+
+	reports := make(chan *telemetry.Report, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var report telemetry.Report
+		if err := json.NewDecoder(req.Body).Decode(&report); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reports <- &report
+	}))
+	defer ts.Close()
+
+	r := telemetry.New(ts.URL, 0, func() *telemetry.Report {
+		return telemetry.NewReport("id", "1.0.0", c, 3)
+	})
+	assert.Nil(t, r.Report(context.Background()))
+
+	report := <-reports
+	assert.Equal(t, telemetry.FormatVersion, report.Format)
+	assert.Equal(t, "id", report.InstanceID)
+	assert.Equal(t, "1.0.0", report.Version)
+	assert.Equal(t, b0.Header().ID(), report.GenesisID)
+	assert.Equal(t, uint32(0), report.BestNumber)
+	assert.Equal(t, 3, report.PeerCount)
+	assert.NotEmpty(t, report.OS)
+
+	r = telemetry.New(ts.URL+"/404", 0, func() *telemetry.Report { return &telemetry.Report{} })
+	assert.NotNil(t, r.Report(context.Background()))
+}
+
+func TestInstanceID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telemetry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "telemetry.id")
+	id, err := telemetry.LoadOrCreateInstanceID(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 32, len(id))
+
+	loaded, err := telemetry.LoadOrCreateInstanceID(path)
+	assert.Nil(t, err)
+	assert.Equal(t, id, loaded)
+}
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -27,13 +28,31 @@ type Address common.Address
 var (
 	_ json.Marshaler   = (*Address)(nil)
 	_ json.Unmarshaler = (*Address)(nil)
+
+	// non-zero to marshal addresses checksumed
+	checksumedJSON int32
 )
 
+// SetChecksumedJSON sets whether addresses are marshaled to JSON in EIP-55 checksumed form.
+// Lower-case form is used by default.
+func SetChecksumedJSON(checksumed bool) {
+	var v int32
+	if checksumed {
+		v = 1
+	}
+	atomic.StoreInt32(&checksumedJSON, v)
+}
+
 // String implements the stringer interface
 func (a Address) String() string {
 	return "0x" + hex.EncodeToString(a[:])
 }
 
+// Checksumed returns EIP-55 mixed-case checksumed form of address.
+func (a Address) Checksumed() string {
+	return common.Address(a).Hex()
+}
+
 // Bytes returns byte slice form of address.
 func (a Address) Bytes() []byte {
 	return a[:]
@@ -49,6 +68,9 @@ func (a *Address) MarshalJSON() ([]byte, error) {
 	if a == nil {
 		return json.Marshal(nil)
 	}
+	if atomic.LoadInt32(&checksumedJSON) != 0 {
+		return json.Marshal(a.Checksumed())
+	}
 	return json.Marshal(a.String())
 }
 
@@ -67,6 +89,7 @@ func (a *Address) UnmarshalJSON(data []byte) error {
 }
 
 // ParseAddress convert string presented address into Address type.
+// Mixed-case string is validated as EIP-55 checksumed address.
 func ParseAddress(s string) (Address, error) {
 	if len(s) == AddressLength*2 {
 	} else if len(s) == AddressLength*2+2 {
@@ -83,6 +106,11 @@ func ParseAddress(s string) (Address, error) {
 	if err != nil {
 		return Address{}, err
 	}
+	if s != strings.ToLower(s) && s != strings.ToUpper(s) {
+		if "0x"+s != addr.Checksumed() {
+			return Address{}, errors.New("invalid checksum")
+		}
+	}
 	return addr, nil
 }
 
@@ -6,17 +6,20 @@ import (
 )
 
 // ForkConfig config for a fork.
+// Each field is the number of the block, since which the fork activated.
 type ForkConfig struct {
 	FixTransferLog uint32
+	VIP191         uint32 // tx delegation
 }
 
 func (fc ForkConfig) String() string {
-	return fmt.Sprintf("FTRL: #%v", fc.FixTransferLog)
+	return fmt.Sprintf("FTRL: #%v, VIP191: #%v", fc.FixTransferLog, fc.VIP191)
 }
 
 // NoFork a special config without any forks.
 var NoFork = ForkConfig{
 	FixTransferLog: math.MaxUint32,
+	VIP191:         math.MaxUint32,
 }
 
 // for well-known networks
@@ -24,14 +27,17 @@ var forkConfigs = map[Bytes32]ForkConfig{
 	// mainnet
 	MustParseBytes32("0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"): {
 		FixTransferLog: 1072000,
+		VIP191:         math.MaxUint32, // not scheduled yet
 	},
 	// testnet
 	MustParseBytes32("0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127"): {
 		FixTransferLog: 1080000,
+		VIP191:         math.MaxUint32, // not scheduled yet
 	},
 }
 
 // GetForkConfig get fork config for given genesis ID.
+// For networks not well-known, all forks are activated since genesis.
 func GetForkConfig(genesisID Bytes32) ForkConfig {
 	return forkConfigs[genesisID]
 }
@@ -7,6 +7,7 @@ package thor
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -31,3 +32,35 @@ func TestAddress(t *testing.T) {
 	assert.Nil(t, json.Unmarshal(data, &dec))
 	assert.Equal(t, addr, dec)
 }
+
+func TestChecksumedAddress(t *testing.T) {
+	// from EIP-55
+	for _, s := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		addr, err := ParseAddress(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, s, addr.Checksumed())
+
+		_, err = ParseAddress(strings.ToLower(s))
+		assert.Nil(t, err)
+		_, err = ParseAddress("0x" + strings.ToUpper(s[2:]))
+		assert.Nil(t, err)
+	}
+
+	_, err := ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	assert.NotNil(t, err, "bad checksum")
+
+	addr := MustParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	SetChecksumedJSON(true)
+	data, _ := json.Marshal(&addr)
+	SetChecksumedJSON(false)
+	assert.Equal(t, `"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"`, string(data))
+
+	var dec Address
+	assert.Nil(t, json.Unmarshal(data, &dec))
+	assert.Equal(t, addr, dec)
+}
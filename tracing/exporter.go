@@ -0,0 +1,223 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/vechain/thor/co"
+)
+
+var log = log15.New("pkg", "tracing")
+
+// Exporter exports ended spans.
+type Exporter interface {
+	Export(span *SpanData)
+}
+
+// LogExporter writes spans into log.
+type LogExporter struct{}
+
+// Export implements Exporter.
+func (LogExporter) Export(span *SpanData) {
+	ctx := []interface{}{
+		"name", span.Name,
+		"trace", span.TraceID,
+		"span", span.SpanID,
+		"parent", span.ParentID,
+		"elapsed", span.End.Sub(span.Start),
+	}
+	for k, v := range span.Attributes {
+		ctx = append(ctx, k, v)
+	}
+	log.Info("span", ctx...)
+}
+
+const (
+	otlpBatchSize     = 512
+	otlpFlushInterval = 2 * time.Second
+)
+
+// OTLPExporter exports spans to OpenTelemetry collector via OTLP/HTTP in JSON encoding.
+type OTLPExporter struct {
+	url     string
+	service string
+	client  *http.Client
+	spanCh  chan *SpanData
+	done    chan struct{}
+	goes    co.Goes
+}
+
+// NewOTLPExporter creates an OTLP exporter, which posts spans in batch to the url,
+// e.g. http://localhost:4318/v1/traces.
+func NewOTLPExporter(url string, service string) *OTLPExporter {
+	e := &OTLPExporter{
+		url:     url,
+		service: service,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		spanCh:  make(chan *SpanData, otlpBatchSize*4),
+		done:    make(chan struct{}),
+	}
+	e.goes.Go(e.loop)
+	return e
+}
+
+// Export implements Exporter. Spans are dropped if the queue is full.
+func (e *OTLPExporter) Export(span *SpanData) {
+	select {
+	case e.spanCh <- span:
+	default:
+	}
+}
+
+// Close flushes queued spans and stops the exporter.
+func (e *OTLPExporter) Close() {
+	close(e.done)
+	e.goes.Wait()
+}
+
+func (e *OTLPExporter) loop() {
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	var batch []*SpanData
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.post(batch); err != nil {
+			log.Debug("failed to export spans", "count", len(batch), "err", err)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case span := <-e.spanCh:
+			if batch = append(batch, span); len(batch) >= otlpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.done:
+			for {
+				select {
+				case span := <-e.spanCh:
+					batch = append(batch, span)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *OTLPExporter) post(spans []*SpanData) error {
+	data, err := json.Marshal(otlpRequest(e.service, spans))
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Post(e.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+	return nil
+}
+
+type (
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	}
+)
+
+func otlpAttribute(key string, value interface{}) otlpKeyValue {
+	var v otlpValue
+	switch val := value.(type) {
+	case bool:
+		v.BoolValue = &val
+	case int:
+		s := strconv.FormatInt(int64(val), 10)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(val, 10)
+		v.IntValue = &s
+	case uint32:
+		s := strconv.FormatUint(uint64(val), 10)
+		v.IntValue = &s
+	case uint64:
+		s := strconv.FormatUint(val, 10)
+		v.IntValue = &s
+	case float64:
+		v.DoubleValue = &val
+	default:
+		s := fmt.Sprint(val)
+		v.StringValue = &s
+	}
+	return otlpKeyValue{key, v}
+}
+
+func otlpRequest(service string, spans []*SpanData) interface{} {
+	converted := make([]*otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := &otlpSpan{
+			TraceID:           span.TraceID.String(),
+			SpanID:            span.SpanID.String(),
+			Name:              span.Name,
+			Kind:              1, // internal
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		}
+		if !span.ParentID.IsZero() {
+			s.ParentSpanID = span.ParentID.String()
+		}
+		for k, v := range span.Attributes {
+			s.Attributes = append(s.Attributes, otlpAttribute(k, v))
+		}
+		converted = append(converted, s)
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpKeyValue{otlpAttribute("service.name", service)},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": "github.com/vechain/thor/tracing"},
+						"spans": converted,
+					},
+				},
+			},
+		},
+	}
+}
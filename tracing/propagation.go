@@ -0,0 +1,94 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C trace context header.
+const TraceparentHeader = "traceparent"
+
+// ParseTraceparent parses the W3C traceparent value.
+func ParseTraceparent(value string) (traceID TraceID, parentID SpanID, ok bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil {
+		return
+	}
+	if _, err := hex.Decode(parentID[:], []byte(parts[2])); err != nil {
+		return
+	}
+	if traceID.IsZero() || parentID.IsZero() {
+		return
+	}
+	return traceID, parentID, true
+}
+
+// FormatTraceparent formats W3C traceparent value of the span.
+func FormatTraceparent(span *Span) string {
+	return fmt.Sprintf("00-%v-%v-01", span.TraceID(), span.SpanID())
+}
+
+// StartRemote starts a span continuing the trace propagated by the traceparent value.
+// A new trace is started if the value is invalid.
+func StartRemote(ctx context.Context, name string, traceparent string) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+	if traceID, parentID, ok := ParseTraceparent(traceparent); ok {
+		return startSpan(ctx, name, traceID, parentID)
+	}
+	return Start(ctx, name)
+}
+
+// Handler wraps the http handler to trace requests.
+// The trace is continued if the request carries traceparent header, and the traceparent of
+// the request span is written in response header.
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !Enabled() {
+			h.ServeHTTP(w, req)
+			return
+		}
+		ctx, span := StartRemote(req.Context(), req.Method+" "+req.URL.Path, req.Header.Get(TraceparentHeader))
+		defer span.End()
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.target", req.URL.RequestURI())
+		w.Header().Set(TraceparentHeader, FormatTraceparent(span))
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, req.WithContext(ctx))
+		span.SetAttribute("http.status_code", int64(rec.status))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack keeps websocket working.
+func (r *statusRecorder) Hijack() (c net.Conn, rw *bufio.ReadWriter, err error) {
+	if hj, ok := r.ResponseWriter.(http.Hijacker); ok {
+		return hj.Hijack()
+	}
+	return nil, nil, errors.New("hijack not supported")
+}
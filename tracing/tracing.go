@@ -0,0 +1,178 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package tracing implements lightweight distributed tracing, whose spans are propagated by context,
+// and exported in OpenTelemetry compatible format.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+func (id TraceID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsZero returns whether the id is zero value.
+func (id TraceID) IsZero() bool { return id == TraceID{} }
+
+// SpanID identifies a span in a trace.
+type SpanID [8]byte
+
+func (id SpanID) String() string { return hex.EncodeToString(id[:]) }
+
+// IsZero returns whether the id is zero value.
+func (id SpanID) IsZero() bool { return id == SpanID{} }
+
+// SpanData is the data of an ended span.
+type SpanData struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	ParentID   SpanID
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+}
+
+// Span is an operation being traced.
+// All methods are safe to be called on nil span, which is returned when tracing disabled.
+type Span struct {
+	lock  sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+type spanKey struct{}
+
+var exporter atomic.Value // holds exporterHolder
+
+type exporterHolder struct{ Exporter }
+
+// SetExporter sets the exporter of ended spans. Tracing is disabled if nil.
+func SetExporter(e Exporter) {
+	exporter.Store(exporterHolder{e})
+}
+
+func currentExporter() Exporter {
+	if h, ok := exporter.Load().(exporterHolder); ok {
+		return h.Exporter
+	}
+	return nil
+}
+
+// Enabled returns whether tracing is enabled.
+func Enabled() bool {
+	return currentExporter() != nil
+}
+
+// SpanFromContext returns the span in the context, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithSpan returns a copy of ctx with the span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// Start starts a span as child of the span in ctx, or a new trace if there's none.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	if !Enabled() {
+		return ctx, nil
+	}
+	var traceID TraceID
+	var parentID SpanID
+	if parent := SpanFromContext(ctx); parent != nil {
+		traceID, parentID = parent.data.TraceID, parent.data.SpanID
+	} else {
+		rand.Read(traceID[:])
+	}
+	return startSpan(ctx, name, traceID, parentID)
+}
+
+func startSpan(ctx context.Context, name string, traceID TraceID, parentID SpanID) (context.Context, *Span) {
+	span := &Span{data: SpanData{
+		TraceID:  traceID,
+		ParentID: parentID,
+		Name:     name,
+		Start:    time.Now(),
+	}}
+	rand.Read(span.data.SpanID[:])
+	return ContextWithSpan(ctx, span), span
+}
+
+// TraceID returns the trace id.
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.data.TraceID
+}
+
+// SpanID returns the span id.
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.data.SpanID
+}
+
+// SetAttribute sets the attribute. Value should be string, bool, integer or float.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	s.data.Attributes[key] = value
+}
+
+// AddDuration accumulates count and duration of an operation done within the span,
+// as attributes '<key>.count' and '<key>.ns'.
+func (s *Span) AddDuration(key string, d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]interface{})
+	}
+	count, _ := s.data.Attributes[key+".count"].(int64)
+	ns, _ := s.data.Attributes[key+".ns"].(int64)
+	s.data.Attributes[key+".count"] = count + 1
+	s.data.Attributes[key+".ns"] = ns + int64(d)
+}
+
+// End ends the span and exports it. It's no-op if already ended.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.lock.Unlock()
+
+	if e := currentExporter(); e != nil {
+		e.Export(&data)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/tracing"
+)
+
+type recorder struct {
+	lock  sync.Mutex
+	spans []*tracing.SpanData
+}
+
+func (r *recorder) Export(span *tracing.SpanData) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func TestDisabled(t *testing.T) {
+	tracing.SetExporter(nil)
+	assert.False(t, tracing.Enabled())
+
+	ctx, span := tracing.Start(context.Background(), "noop")
+	assert.Nil(t, span)
+	assert.Nil(t, tracing.SpanFromContext(ctx))
+	// no panic
+	span.SetAttribute("k", "v")
+	span.AddDuration("d", time.Second)
+	span.End()
+}
+
+func TestSpan(t *testing.T) {
+	r := &recorder{}
+	tracing.SetExporter(r)
+	defer tracing.SetExporter(nil)
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+	_, child := tracing.Start(ctx, "child")
+	child.SetAttribute("k", "v")
+	child.AddDuration("d", time.Millisecond)
+	child.AddDuration("d", time.Millisecond)
+	child.End()
+	child.End()
+	parent.End()
+
+	assert.Len(t, r.spans, 2)
+	c, p := r.spans[0], r.spans[1]
+	assert.Equal(t, "child", c.Name)
+	assert.Equal(t, "parent", p.Name)
+	assert.Equal(t, p.TraceID, c.TraceID)
+	assert.Equal(t, p.SpanID, c.ParentID)
+	assert.True(t, p.ParentID.IsZero())
+	assert.Equal(t, "v", c.Attributes["k"])
+	assert.Equal(t, int64(2), c.Attributes["d.count"])
+	assert.Equal(t, int64(2*time.Millisecond), c.Attributes["d.ns"])
+}
+
+func TestTraceparent(t *testing.T) {
+	tracing.SetExporter(&recorder{})
+	defer tracing.SetExporter(nil)
+
+	_, span := tracing.Start(context.Background(), "span")
+	value := tracing.FormatTraceparent(span)
+	traceID, parentID, ok := tracing.ParseTraceparent(value)
+	assert.True(t, ok)
+	assert.Equal(t, span.TraceID(), traceID)
+	assert.Equal(t, span.SpanID(), parentID)
+
+	for _, invalid := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01",
+	} {
+		_, _, ok := tracing.ParseTraceparent(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := &recorder{}
+	tracing.SetExporter(r)
+	defer tracing.SetExporter(nil)
+
+	ts := httptest.NewServer(tracing.Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, span := tracing.Start(req.Context(), "inner")
+		span.End()
+		w.WriteHeader(http.StatusTeapot)
+	})))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL+"/path?q=1", nil)
+	req.Header.Set(tracing.TraceparentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	traceID, _, ok := tracing.ParseTraceparent(res.Header.Get(tracing.TraceparentHeader))
+	assert.True(t, ok)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", traceID.String())
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	assert.Len(t, r.spans, 2)
+	inner, outer := r.spans[0], r.spans[1]
+	assert.Equal(t, "GET /path", outer.Name)
+	assert.Equal(t, "b7ad6b7169203331", outer.ParentID.String())
+	assert.Equal(t, outer.SpanID, inner.ParentID)
+	assert.Equal(t, "/path?q=1", outer.Attributes["http.target"])
+	assert.Equal(t, int64(http.StatusTeapot), outer.Attributes["http.status_code"])
+}
+
+func TestOTLPExporter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, _ := ioutil.ReadAll(req.Body)
+		bodies <- data
+	}))
+	defer ts.Close()
+
+	exporter := tracing.NewOTLPExporter(ts.URL, "thor")
+	tracing.SetExporter(exporter)
+	_, span := tracing.Start(context.Background(), "span")
+	span.SetAttribute("k", "v")
+	span.End()
+	tracing.SetExporter(nil)
+	exporter.Close()
+
+	var body map[string]interface{}
+	assert.Nil(t, json.Unmarshal(<-bodies, &body))
+	assert.Contains(t, body, "resourceSpans")
+}
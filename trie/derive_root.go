@@ -24,3 +24,17 @@ func DeriveRoot(list DerivableList) thor.Bytes32 {
 	}
 	return trie.Hash()
 }
+
+// DeriveProof constructs a merkle proof for the i-th item of the list, against the root
+// computed by DeriveRoot. See Prove.
+func DeriveProof(list DerivableList, i int, proofDb DatabaseWriter) error {
+	keybuf := new(bytes.Buffer)
+	trie := new(Trie)
+	for j := 0; j < list.Len(); j++ {
+		keybuf.Reset()
+		rlp.Encode(keybuf, uint(j))
+		trie.Update(keybuf.Bytes(), list.GetRlp(j))
+	}
+	key, _ := rlp.EncodeToBytes(uint(i))
+	return trie.Prove(key, 0, proofDb)
+}
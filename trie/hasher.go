@@ -30,6 +30,7 @@ type hasher struct {
 	tmp                  *bytes.Buffer
 	sha                  hash.Hash
 	cachegen, cachelimit uint16
+	parallel             bool // hash children of the root full node concurrently
 }
 
 // hashers live in a global pool.
@@ -39,9 +40,9 @@ var hasherPool = sync.Pool{
 	},
 }
 
-func newHasher(cachegen, cachelimit uint16) *hasher {
+func newHasher(cachegen, cachelimit uint16, parallel bool) *hasher {
 	h := hasherPool.Get().(*hasher)
-	h.cachegen, h.cachelimit = cachegen, cachelimit
+	h.cachegen, h.cachelimit, h.parallel = cachegen, cachelimit, parallel
 	return h
 }
 
@@ -123,14 +124,20 @@ func (h *hasher) hashChildren(original node, db DatabaseWriter) (node, node, err
 		// Hash the full node's children, caching the newly hashed subtrees
 		collapsed, cached := n.copy(), n.copy()
 
-		for i := 0; i < 16; i++ {
-			if n.Children[i] != nil {
-				collapsed.Children[i], cached.Children[i], err = h.hash(n.Children[i], db, false)
-				if err != nil {
-					return original, original, err
+		if h.parallel {
+			if err := h.hashFullNodeChildrenParallel(n, collapsed, cached, db); err != nil {
+				return original, original, err
+			}
+		} else {
+			for i := 0; i < 16; i++ {
+				if n.Children[i] != nil {
+					collapsed.Children[i], cached.Children[i], err = h.hash(n.Children[i], db, false)
+					if err != nil {
+						return original, original, err
+					}
+				} else {
+					collapsed.Children[i] = valueNode(nil) // Ensure that nil children are encoded as empty strings.
 				}
-			} else {
-				collapsed.Children[i] = valueNode(nil) // Ensure that nil children are encoded as empty strings.
 			}
 		}
 		cached.Children[16] = n.Children[16]
@@ -145,6 +152,50 @@ func (h *hasher) hashChildren(original node, db DatabaseWriter) (node, node, err
 	}
 }
 
+// hashFullNodeChildrenParallel hashes children of the full node concurrently, one goroutine per subtrie.
+// Subtries are independent, and writes to db are serialized.
+func (h *hasher) hashFullNodeChildrenParallel(n, collapsed, cached *fullNode, db DatabaseWriter) error {
+	if db != nil {
+		db = &lockedWriter{w: db}
+	}
+	var (
+		wg   sync.WaitGroup
+		errs [16]error
+	)
+	for i := 0; i < 16; i++ {
+		if n.Children[i] == nil {
+			collapsed.Children[i] = valueNode(nil) // Ensure that nil children are encoded as empty strings.
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ch := newHasher(h.cachegen, h.cachelimit, false)
+			defer returnHasherToPool(ch)
+			collapsed.Children[i], cached.Children[i], errs[i] = ch.hash(n.Children[i], db, false)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockedWriter serializes writes to the underlying writer.
+type lockedWriter struct {
+	lock sync.Mutex
+	w    DatabaseWriter
+}
+
+func (lw *lockedWriter) Put(key, value []byte) error {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	return lw.w.Put(key, value)
+}
+
 func (h *hasher) store(n node, db DatabaseWriter, force bool) (node, error) {
 	// Don't store hashes or empty nodes.
 	if _, isHash := n.(hashNode); n == nil || isHash {
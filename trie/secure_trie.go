@@ -190,7 +190,7 @@ func (t *SecureTrie) hashKey(key []byte) []byte {
 		return hk.([]byte)
 	}
 
-	h := newHasher(0, 0)
+	h := newHasher(0, 0, false)
 	h.sha.Reset()
 	h.sha.Write(key)
 	var buf thor.Bytes32
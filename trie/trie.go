@@ -34,6 +34,9 @@ var (
 	emptyState = thor.Blake2b(nil)
 )
 
+// parallelHashThreshold is the count of updates, over which the trie is hashed or committed in parallel.
+const parallelHashThreshold = 100
+
 var (
 	cacheMissCounter   = metrics.NewRegisteredCounter("trie/cachemiss", nil)
 	cacheUnloadCounter = metrics.NewRegisteredCounter("trie/cacheunload", nil)
@@ -88,6 +91,9 @@ type Trie struct {
 	// new nodes are tagged with the current generation and unloaded
 	// when their generation is older than than cachegen-cachelimit.
 	cachegen, cachelimit uint16
+
+	// count of updates since last hashing and commit, to decide whether to hash in parallel
+	unhashed, uncommitted int
 }
 
 // SetCacheLimit sets the number of 'cache generations' to keep.
@@ -209,6 +215,8 @@ func (t *Trie) Update(key, value []byte) {
 //
 // If a node was not found in the database, a MissingNodeError is returned.
 func (t *Trie) TryUpdate(key, value []byte) error {
+	t.unhashed++
+	t.uncommitted++
 	k := keybytesToHex(key)
 	if len(value) != 0 {
 		_, n, err := t.insert(t.root, nil, k, valueNode(value))
@@ -305,6 +313,8 @@ func (t *Trie) Delete(key []byte) {
 // TryDelete removes any existing value for key from the trie.
 // If a node was not found in the database, a MissingNodeError is returned.
 func (t *Trie) TryDelete(key []byte) error {
+	t.unhashed++
+	t.uncommitted++
 	k := keybytesToHex(key)
 	_, n, err := t.delete(t.root, nil, k)
 	if err != nil {
@@ -460,6 +470,7 @@ func (t *Trie) Root() []byte { return t.Hash().Bytes() }
 func (t *Trie) Hash() thor.Bytes32 {
 	hash, cached, _ := t.hashRoot(nil)
 	t.root = cached
+	t.unhashed = 0
 	return thor.BytesToBytes32(hash.(hashNode))
 }
 
@@ -488,6 +499,7 @@ func (t *Trie) CommitTo(db DatabaseWriter) (root thor.Bytes32, err error) {
 		return (thor.Bytes32{}), err
 	}
 	t.root = cached
+	t.unhashed, t.uncommitted = 0, 0
 	t.cachegen++
 	return thor.BytesToBytes32(hash.(hashNode)), nil
 }
@@ -496,7 +508,9 @@ func (t *Trie) hashRoot(db DatabaseWriter) (node, node, error) {
 	if t.root == nil {
 		return hashNode(emptyRoot.Bytes()), nil, nil
 	}
-	h := newHasher(t.cachegen, t.cachelimit)
+	// it's not worth to hash in parallel for few changes
+	parallel := t.unhashed >= parallelHashThreshold || (db != nil && t.uncommitted >= parallelHashThreshold)
+	h := newHasher(t.cachegen, t.cachelimit, parallel)
 	defer returnHasherToPool(h)
 	return h.hash(t.root, db, true)
 }
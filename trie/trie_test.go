@@ -610,3 +610,53 @@ func updateString(trie *Trie, k, v string) {
 func deleteString(trie *Trie, k string) {
 	trie.Delete([]byte(k))
 }
+
+func TestParallelHash(t *testing.T) {
+	serialDB, parallelDB := ethdb.NewMemDatabase(), ethdb.NewMemDatabase()
+	serial, _ := New(thor.Bytes32{}, serialDB)
+	parallel, _ := New(thor.Bytes32{}, parallelDB)
+
+	update := func(key, value []byte) {
+		serial.Update(key, value)
+		parallel.Update(key, value)
+		// keep serial trie under the threshold
+		if serial.unhashed >= parallelHashThreshold-1 {
+			serial.Hash()
+		}
+		serial.uncommitted = 0
+	}
+	for i := 0; i < 1000; i++ {
+		update(crypto.Keccak256([]byte{byte(i), byte(i >> 8)}), []byte(fmt.Sprintf("value%v", i)))
+	}
+	if serial.Hash() != parallel.Hash() {
+		t.Fatal("hash mismatch")
+	}
+
+	for i := 0; i < 500; i++ {
+		update(crypto.Keccak256([]byte{byte(i), byte(i >> 8)}), nil)
+	}
+	if parallel.unhashed < parallelHashThreshold {
+		t.Fatal("parallel trie should be hashed in parallel")
+	}
+	root1, err := serial.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root2, err := parallel.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root1 != root2 {
+		t.Fatalf("root mismatch %v != %v", root1, root2)
+	}
+	if serialDB.Len() != parallelDB.Len() {
+		t.Fatalf("committed nodes mismatch %v != %v", serialDB.Len(), parallelDB.Len())
+	}
+	for _, key := range serialDB.Keys() {
+		v1, _ := serialDB.Get(key)
+		v2, _ := parallelDB.Get(key)
+		if !bytes.Equal(v1, v2) {
+			t.Fatalf("node %x mismatch", key)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+	"github.com/vechain/thor/abi"
+	"github.com/vechain/thor/thor"
+)
+
+// EnergyAddress address of the builtin Energy(VTHO) contract.
+// It can't be referred from package builtin due to import cycle.
+var EnergyAddress = thor.BytesToAddress([]byte("Energy"))
+
+// transfer method of VIP180 (ERC20 compatible) token.
+var tokenTransferMethod = func() *abi.Method {
+	data := []byte(`[{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`)
+	abi, err := abi.New(data)
+	if err != nil {
+		panic(errors.Wrap(err, "load token ABI"))
+	}
+	method, _ := abi.MethodByName("transfer")
+	return method
+}()
+
+// NewClauseFromABI create a clause calling method of contract 'to', with data ABI encoded from args.
+func NewClauseFromABI(to thor.Address, method *abi.Method, args ...interface{}) (*Clause, error) {
+	data, err := method.EncodeInput(args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "encode input")
+	}
+	return NewClause(&to).WithData(data), nil
+}
+
+// NewTokenTransferClause create a clause transferring VIP180 token to recipient.
+func NewTokenTransferClause(token thor.Address, recipient thor.Address, amount *big.Int) *Clause {
+	clause, err := NewClauseFromABI(token, tokenTransferMethod, recipient, amount)
+	if err != nil {
+		// args are well typed
+		panic(err)
+	}
+	return clause
+}
+
+// NewEnergyTransferClause create a clause transferring energy(VTHO) to recipient.
+func NewEnergyTransferClause(recipient thor.Address, amount *big.Int) *Clause {
+	return NewTokenTransferClause(EnergyAddress, recipient, amount)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/builtin"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestClauseFromABI(t *testing.T) {
+	assert.Equal(t, builtin.Energy.Address, tx.EnergyAddress)
+
+	recipient := thor.BytesToAddress([]byte("recipient"))
+	amount := big.NewInt(1000)
+
+	method, _ := builtin.Energy.ABI.MethodByName("transfer")
+	expected, err := method.EncodeInput(recipient, amount)
+	assert.Nil(t, err)
+
+	clause, err := tx.NewClauseFromABI(builtin.Energy.Address, method, recipient, amount)
+	assert.Nil(t, err)
+	assert.Equal(t, builtin.Energy.Address, *clause.To())
+	assert.Equal(t, expected, clause.Data())
+	assert.Equal(t, 0, clause.Value().Sign())
+
+	_, err = tx.NewClauseFromABI(builtin.Energy.Address, method, recipient)
+	assert.NotNil(t, err, "should fail with bad args")
+
+	assert.Equal(t, clause, tx.NewEnergyTransferClause(recipient, amount))
+
+	token := thor.BytesToAddress([]byte("token"))
+	clause = tx.NewTokenTransferClause(token, recipient, amount)
+	assert.Equal(t, token, *clause.To())
+	assert.Equal(t, expected, clause.Data())
+}
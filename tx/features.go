@@ -0,0 +1,91 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+)
+
+// Features bitset contains tx features.
+type Features uint32
+
+const (
+	// DelegationFeature tx fee is paid by a designated gas payer (delegator).
+	// See VIP-191 for more detail.
+	DelegationFeature Features = 1
+)
+
+// IsDelegated returns whether tx is delegated.
+func (f Features) IsDelegated() bool {
+	return (f & DelegationFeature) == DelegationFeature
+}
+
+// IsSupported returns whether all features are supported by the block of the given number.
+func (f Features) IsSupported(forkConfig thor.ForkConfig, blockNum uint32) bool {
+	var supported Features
+	if blockNum >= forkConfig.VIP191 {
+		supported |= DelegationFeature
+	}
+	return f&^supported == 0
+}
+
+// SetDelegated sets tx delegated flag.
+func (f *Features) SetDelegated(flag bool) {
+	if flag {
+		*f |= DelegationFeature
+	} else {
+		*f &= ^DelegationFeature
+	}
+}
+
+// reserved is the reserved field of tx body.
+// The first element is features, and the rest are unused.
+// It's encoded as an empty list if all elements are empty.
+type reserved struct {
+	Features Features
+	Unused   []rlp.RawValue
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (r *reserved) EncodeRLP(w io.Writer) error {
+	if r.Features == 0 && len(r.Unused) == 0 {
+		return rlp.Encode(w, []interface{}{})
+	}
+	list := make([]interface{}, 0, len(r.Unused)+1)
+	list = append(list, r.Features)
+	for _, v := range r.Unused {
+		list = append(list, v)
+	}
+	return rlp.Encode(w, list)
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (r *reserved) DecodeRLP(s *rlp.Stream) error {
+	var raws []rlp.RawValue
+	if err := s.Decode(&raws); err != nil {
+		return err
+	}
+	if len(raws) == 0 {
+		*r = reserved{}
+		return nil
+	}
+
+	// trailing empty values make the encoding ambiguous
+	if last := raws[len(raws)-1]; len(last) == 1 && (last[0] == 0x80 || last[0] == 0xc0) {
+		return errors.New("invalid reserved fields: not trimmed")
+	}
+
+	var features Features
+	if err := rlp.DecodeBytes(raws[0], &features); err != nil {
+		return err
+	}
+	*r = reserved{features, raws[1:]}
+	return nil
+}
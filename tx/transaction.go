@@ -24,8 +24,12 @@ import (
 
 var (
 	errIntrinsicGasOverflow = errors.New("intrinsic gas overflow")
+	errSignatureLength      = errors.New("invalid signature length")
+	errNotDelegated         = errors.New("tx not delegated")
 )
 
+const signatureLength = 65
+
 // Transaction is an immutable tx type.
 type Transaction struct {
 	body body
@@ -33,6 +37,7 @@ type Transaction struct {
 	cache struct {
 		signingHash  atomic.Value
 		signer       atomic.Value
+		delegator    atomic.Value
 		id           atomic.Value
 		unprovedWork atomic.Value
 		size         atomic.Value
@@ -51,7 +56,7 @@ type body struct {
 	Gas          uint64
 	DependsOn    *thor.Bytes32 `rlp:"nil"`
 	Nonce        uint64
-	Reserved     []interface{}
+	Reserved     reserved
 	Signature    []byte
 }
 
@@ -144,7 +149,7 @@ func (t *Transaction) EvaluateWork(signer thor.Address) func(nonce uint64) *big.
 		t.body.GasPriceCoef,
 		t.body.Gas,
 		t.body.DependsOn,
-		t.body.Reserved,
+		&t.body.Reserved,
 		signer,
 	})
 
@@ -167,8 +172,13 @@ func (t *Transaction) SigningHash() (hash thor.Bytes32) {
 	}
 	defer func() { t.cache.signingHash.Store(hash) }()
 
-	hw := thor.NewBlake2b()
-	rlp.Encode(hw, []interface{}{
+	return thor.Blake2b(t.SigningMessage())
+}
+
+// SigningMessage returns the RLP encoded message, whose blake2b hash is the signing hash.
+// It lets signers like hardware wallets decode the tx for display and hash it by themselves.
+func (t *Transaction) SigningMessage() []byte {
+	data, _ := rlp.EncodeToBytes([]interface{}{
 		t.body.ChainTag,
 		t.body.BlockRef,
 		t.body.Expiration,
@@ -177,10 +187,20 @@ func (t *Transaction) SigningHash() (hash thor.Bytes32) {
 		t.body.Gas,
 		t.body.DependsOn,
 		t.body.Nonce,
-		t.body.Reserved,
+		&t.body.Reserved,
 	})
-	hw.Sum(hash[:0])
-	return
+	return data
+}
+
+// DelegatorSigningHash returns hash of tx to be signed by the delegator (gas payer).
+// It binds the tx signer, so that the delegator signature can't be reused for other signers.
+func (t *Transaction) DelegatorSigningHash(signer thor.Address) thor.Bytes32 {
+	return thor.Blake2b(t.SigningHash().Bytes(), signer.Bytes())
+}
+
+// Features returns features.
+func (t *Transaction) Features() Features {
+	return t.body.Reserved.Features
 }
 
 // GasPriceCoef returns gas price coef.
@@ -224,7 +244,11 @@ func (t *Transaction) Signer() (signer thor.Address, err error) {
 		}
 	}()
 
-	pub, err := crypto.SigToPub(t.SigningHash().Bytes(), t.body.Signature)
+	sig, err := t.signerSignature()
+	if err != nil {
+		return thor.Address{}, err
+	}
+	pub, err := crypto.SigToPub(t.SigningHash().Bytes(), sig)
 	if err != nil {
 		return thor.Address{}, err
 	}
@@ -232,7 +256,45 @@ func (t *Transaction) Signer() (signer thor.Address, err error) {
 	return
 }
 
+// Delegator extract delegator (gas payer) of tx from signature.
+// Nil returned if tx is not delegated.
+func (t *Transaction) Delegator() (delegator *thor.Address, err error) {
+	if !t.Features().IsDelegated() {
+		return nil, nil
+	}
+	if cached := t.cache.delegator.Load(); cached != nil {
+		addr := cached.(thor.Address)
+		return &addr, nil
+	}
+
+	signer, err := t.Signer()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(t.DelegatorSigningHash(signer).Bytes(), t.body.Signature[signatureLength:])
+	if err != nil {
+		return nil, err
+	}
+	addr := thor.Address(crypto.PubkeyToAddress(*pub))
+	t.cache.delegator.Store(addr)
+	return &addr, nil
+}
+
+// signerSignature returns signature part of the signer, after length checked.
+// For delegated tx, the signature is the concatenation of signer's and delegator's.
+func (t *Transaction) signerSignature() ([]byte, error) {
+	if t.Features().IsDelegated() {
+		if len(t.body.Signature) != signatureLength*2 {
+			return nil, errSignatureLength
+		}
+	} else if len(t.body.Signature) != signatureLength {
+		return nil, errSignatureLength
+	}
+	return t.body.Signature[:signatureLength], nil
+}
+
 // WithSignature create a new tx with signature set.
+// For delegated tx, the signature should be the concatenation of signer's and delegator's.
 func (t *Transaction) WithSignature(sig []byte) *Transaction {
 	newTx := Transaction{
 		body: t.body,
@@ -242,10 +304,25 @@ func (t *Transaction) WithSignature(sig []byte) *Transaction {
 	return &newTx
 }
 
-// HasReservedFields returns if there're reserved fields.
+// WithDelegatorSignature create a new tx with delegator's signature appended to signer's.
+// The tx should be delegated and already signed by the signer.
+func (t *Transaction) WithDelegatorSignature(sig []byte) (*Transaction, error) {
+	if !t.Features().IsDelegated() {
+		return nil, errNotDelegated
+	}
+	if len(t.body.Signature) < signatureLength || len(sig) != signatureLength {
+		return nil, errSignatureLength
+	}
+	newSig := make([]byte, 0, signatureLength*2)
+	newSig = append(newSig, t.body.Signature[:signatureLength]...)
+	newSig = append(newSig, sig...)
+	return t.WithSignature(newSig), nil
+}
+
+// HasReservedFields returns if there're unused reserved fields.
 // Reserved fields are for backward compatibility purpose.
 func (t *Transaction) HasReservedFields() bool {
-	return len(t.body.Reserved) > 0
+	return len(t.body.Reserved.Unused) > 0
 }
 
 // EncodeRLP implements rlp.Encoder
@@ -391,7 +468,7 @@ func IntrinsicGas(clauses ...*Clause) (uint64, error) {
 	var total = thor.TxGas
 	var overflow bool
 	for _, c := range clauses {
-		gas, err := dataGas(c.body.Data)
+		gas, err := ClauseIntrinsicGas(c)
 		if err != nil {
 			return 0, err
 		}
@@ -399,19 +476,28 @@ func IntrinsicGas(clauses ...*Clause) (uint64, error) {
 		if overflow {
 			return 0, errIntrinsicGasOverflow
 		}
+	}
+	return total, nil
+}
 
-		var cgas uint64
-		if c.IsCreatingContract() {
-			// contract creation
-			cgas = thor.ClauseGasContractCreation
-		} else {
-			cgas = thor.ClauseGas
-		}
+// ClauseIntrinsicGas calculate intrinsic gas cost of a single clause, which excludes thor.TxGas.
+func ClauseIntrinsicGas(c *Clause) (uint64, error) {
+	gas, err := dataGas(c.body.Data)
+	if err != nil {
+		return 0, err
+	}
 
-		total, overflow = math.SafeAdd(total, cgas)
-		if overflow {
-			return 0, errIntrinsicGasOverflow
-		}
+	var cgas uint64
+	if c.IsCreatingContract() {
+		// contract creation
+		cgas = thor.ClauseGasContractCreation
+	} else {
+		cgas = thor.ClauseGas
+	}
+
+	total, overflow := math.SafeAdd(gas, cgas)
+	if overflow {
+		return 0, errIntrinsicGasOverflow
 	}
 	return total, nil
 }
@@ -51,6 +51,61 @@ func TestTx(t *testing.T) {
 	)
 }
 
+func TestDelegatedTx(t *testing.T) {
+	signer, _ := crypto.GenerateKey()
+	delegator, _ := crypto.GenerateKey()
+
+	var features tx.Features
+	features.SetDelegated(true)
+	assert.True(t, features.IsDelegated())
+
+	trx := new(tx.Builder).ChainTag(1).Features(features).Build()
+	assert.Equal(t, features, trx.Features())
+
+	_, err := trx.WithDelegatorSignature(make([]byte, 65))
+	assert.NotNil(t, err, "should be signed by signer first")
+
+	sig, _ := crypto.Sign(trx.SigningHash().Bytes(), signer)
+	trx = trx.WithSignature(sig)
+	_, err = trx.Signer()
+	assert.NotNil(t, err, "delegator signature missing")
+
+	signerAddr := thor.Address(crypto.PubkeyToAddress(signer.PublicKey))
+	dsig, _ := crypto.Sign(trx.DelegatorSigningHash(signerAddr).Bytes(), delegator)
+	trx, err = trx.WithDelegatorSignature(dsig)
+	assert.Nil(t, err)
+
+	data, _ := rlp.EncodeToBytes(trx)
+	var decoded tx.Transaction
+	assert.Nil(t, rlp.DecodeBytes(data, &decoded))
+
+	s, err := decoded.Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, signerAddr, s)
+
+	d, err := decoded.Delegator()
+	assert.Nil(t, err)
+	assert.Equal(t, thor.Address(crypto.PubkeyToAddress(delegator.PublicKey)), *d)
+	assert.False(t, decoded.HasReservedFields())
+
+	d, err = new(tx.Builder).Build().Delegator()
+	assert.Nil(t, err)
+	assert.Nil(t, d, "not delegated")
+
+	_, err = new(tx.Builder).Build().WithDelegatorSignature(dsig)
+	assert.NotNil(t, err, "not delegated")
+}
+
+func TestReservedNotTrimmed(t *testing.T) {
+	data, _ := rlp.EncodeToBytes([]interface{}{
+		byte(1), uint64(0), uint32(0), []interface{}{}, uint8(0), uint64(0), []byte{}, uint64(0),
+		[]interface{}{uint32(1), []byte{}},
+		[]byte{},
+	})
+	var trx tx.Transaction
+	assert.NotNil(t, rlp.DecodeBytes(data, &trx))
+}
+
 func TestIntrinsicGas(t *testing.T) {
 	gas, err := tx.IntrinsicGas()
 	assert.Nil(t, err)
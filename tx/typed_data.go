@@ -0,0 +1,123 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/vechain/thor/thor"
+)
+
+// typedDataPrefix makes typed data signing hash never collide with tx signing hash,
+// since RLP encoded tx never starts with 0x19.
+const typedDataPrefix = "\x19VeChain Thor Typed Data:\n"
+
+// TypedDomain separates signing domains, to prevent signature replayed across chains or applications.
+type TypedDomain struct {
+	Name     string `json:"name"`
+	ChainTag byte   `json:"chainTag"`
+}
+
+// TypedField named field of typed data. Values are human readable strings.
+type TypedField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// TypedData structured data to be signed, in an EIP-712 like manner.
+// Its canonical JSON encoding is the payload displayed to and audited by user.
+type TypedData struct {
+	Domain TypedDomain  `json:"domain"`
+	Type   string       `json:"type"`
+	Fields []TypedField `json:"fields"`
+}
+
+// NewTypedMessage creates typed data for arbitrary message.
+func NewTypedMessage(domain TypedDomain, typ string, fields ...TypedField) *TypedData {
+	return &TypedData{
+		Domain: domain,
+		Type:   typ,
+		Fields: append([]TypedField(nil), fields...),
+	}
+}
+
+// NewTypedTx creates typed data describing the tx, for user to audit before signing the tx.
+func NewTypedTx(t *Transaction) *TypedData {
+	dependsOn := ""
+	if t.body.DependsOn != nil {
+		dependsOn = t.body.DependsOn.String()
+	}
+	br := t.BlockRef()
+	fields := []TypedField{
+		{"blockRef", hexutil.Encode(br[:])},
+		{"expiration", fmt.Sprint(t.body.Expiration)},
+		{"gasPriceCoef", fmt.Sprint(t.body.GasPriceCoef)},
+		{"gas", fmt.Sprint(t.body.Gas)},
+		{"dependsOn", dependsOn},
+		{"nonce", fmt.Sprint(t.body.Nonce)},
+		{"features", fmt.Sprint(uint32(t.Features()))},
+	}
+	for i, c := range t.body.Clauses {
+		to := ""
+		if c.body.To != nil {
+			to = c.body.To.String()
+		}
+		prefix := fmt.Sprintf("clauses[%d].", i)
+		fields = append(fields,
+			TypedField{prefix + "to", to},
+			TypedField{prefix + "value", c.body.Value.String()},
+			TypedField{prefix + "data", hexutil.Encode(c.body.Data)},
+		)
+	}
+	return &TypedData{
+		Domain: TypedDomain{"tx", t.body.ChainTag},
+		Type:   "Transaction",
+		Fields: fields,
+	}
+}
+
+// Payload returns the canonical JSON encoding.
+func (d *TypedData) Payload() []byte {
+	// never fails, since only strings and integers inside
+	data, _ := json.Marshal(d)
+	return data
+}
+
+// SigningHash returns hash of typed data to be signed.
+func (d *TypedData) SigningHash() thor.Bytes32 {
+	return thor.Blake2b([]byte(typedDataPrefix), d.Payload())
+}
+
+// Sign signs the typed data.
+func (d *TypedData) Sign(priv *ecdsa.PrivateKey) ([]byte, error) {
+	return crypto.Sign(d.SigningHash().Bytes(), priv)
+}
+
+// Signer recovers signer of typed data from signature.
+func (d *TypedData) Signer(sig []byte) (thor.Address, error) {
+	if len(sig) != signatureLength {
+		return thor.Address{}, errSignatureLength
+	}
+	pub, err := crypto.SigToPub(d.SigningHash().Bytes(), sig)
+	if err != nil {
+		return thor.Address{}, err
+	}
+	return thor.Address(crypto.PubkeyToAddress(*pub)), nil
+}
+
+// VerifyTx verifies that the typed data exactly describes the tx.
+// Signing device should verify it before displaying typed data and signing the tx.
+func (d *TypedData) VerifyTx(t *Transaction) error {
+	if string(NewTypedTx(t).Payload()) != string(d.Payload()) {
+		return errors.New("typed data not match tx")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestTypedMessage(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	domain := tx.TypedDomain{Name: "example.com", ChainTag: 1}
+	msg := tx.NewTypedMessage(domain, "Login", tx.TypedField{Name: "user", Value: "alice"})
+
+	assert.Equal(t, `{"domain":{"name":"example.com","chainTag":1},"type":"Login","fields":[{"name":"user","value":"alice"}]}`, string(msg.Payload()))
+
+	sig, err := msg.Sign(key)
+	assert.Nil(t, err)
+	signer, err := msg.Signer(sig)
+	assert.Nil(t, err)
+	assert.Equal(t, thor.Address(crypto.PubkeyToAddress(key.PublicKey)), signer)
+
+	domain.ChainTag = 2
+	other := tx.NewTypedMessage(domain, "Login", tx.TypedField{Name: "user", Value: "alice"})
+	assert.NotEqual(t, msg.SigningHash(), other.SigningHash(), "domain separated")
+}
+
+func TestTypedTx(t *testing.T) {
+	to, _ := thor.ParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+	trx := new(tx.Builder).ChainTag(1).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10000))).
+		Gas(21000).
+		Build()
+
+	typed := tx.NewTypedTx(trx)
+	assert.Nil(t, typed.VerifyTx(trx))
+	assert.NotEqual(t, trx.SigningHash(), typed.SigningHash())
+
+	other := new(tx.Builder).ChainTag(1).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(20000))).
+		Gas(21000).
+		Build()
+	assert.NotNil(t, typed.VerifyTx(other))
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"io"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+)
+
+// Unsigned is a tx collecting signatures from multiple parties (the signer and the delegator),
+// before assembled into the final tx.
+// Each signature is validated once added, and it's RLP encodable to be passed between services.
+type Unsigned struct {
+	tx           *Transaction // signature excluded
+	signer       *thor.Address
+	signerSig    []byte
+	delegatorSig []byte
+}
+
+// NewUnsigned creates an unsigned tx from the given tx. Signature of the given tx is dropped.
+func NewUnsigned(tx *Transaction) *Unsigned {
+	return &Unsigned{tx: tx.WithSignature(nil)}
+}
+
+// Transaction returns the tx without signature.
+func (u *Unsigned) Transaction() *Transaction {
+	return u.tx
+}
+
+// Signer returns the signer if known, which is determined by the added signature.
+func (u *Unsigned) Signer() *thor.Address {
+	if u.signer == nil {
+		return nil
+	}
+	cpy := *u.signer
+	return &cpy
+}
+
+// AddSignerSignature validates and adds signature of the signer.
+func (u *Unsigned) AddSignerSignature(sig []byte) error {
+	if len(sig) != signatureLength {
+		return errSignatureLength
+	}
+	pub, err := crypto.SigToPub(u.tx.SigningHash().Bytes(), sig)
+	if err != nil {
+		return err
+	}
+	signer := thor.Address(crypto.PubkeyToAddress(*pub))
+	if u.signer != nil && *u.signer != signer {
+		return errors.New("signer mismatch")
+	}
+	u.signer = &signer
+	u.signerSig = append([]byte(nil), sig...)
+	return nil
+}
+
+// AddDelegatorSignature validates and adds signature of the delegator, which is signed for the given signer.
+// The tx should be delegated.
+func (u *Unsigned) AddDelegatorSignature(signer thor.Address, sig []byte) error {
+	if !u.tx.Features().IsDelegated() {
+		return errNotDelegated
+	}
+	if u.signer != nil && *u.signer != signer {
+		return errors.New("signer mismatch")
+	}
+	if len(sig) != signatureLength {
+		return errSignatureLength
+	}
+	if _, err := crypto.SigToPub(u.tx.DelegatorSigningHash(signer).Bytes(), sig); err != nil {
+		return err
+	}
+	u.signer = &signer
+	u.delegatorSig = append([]byte(nil), sig...)
+	return nil
+}
+
+// Sign signs the tx as the signer.
+func (u *Unsigned) Sign(priv *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(u.tx.SigningHash().Bytes(), priv)
+	if err != nil {
+		return err
+	}
+	return u.AddSignerSignature(sig)
+}
+
+// SignAsDelegator signs the tx as the delegator for the given signer.
+func (u *Unsigned) SignAsDelegator(signer thor.Address, priv *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(u.tx.DelegatorSigningHash(signer).Bytes(), priv)
+	if err != nil {
+		return err
+	}
+	return u.AddDelegatorSignature(signer, sig)
+}
+
+// Merge adds signatures collected by other into u. Both should be of the same tx.
+func (u *Unsigned) Merge(other *Unsigned) error {
+	if u.tx.SigningHash() != other.tx.SigningHash() {
+		return errors.New("tx mismatch")
+	}
+	if len(other.signerSig) > 0 {
+		if err := u.AddSignerSignature(other.signerSig); err != nil {
+			return err
+		}
+	}
+	if len(other.delegatorSig) > 0 {
+		if err := u.AddDelegatorSignature(*other.signer, other.delegatorSig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Assemble assembles the final tx when all required signatures are collected.
+func (u *Unsigned) Assemble() (*Transaction, error) {
+	if len(u.signerSig) == 0 {
+		return nil, errors.New("signer signature missing")
+	}
+	if !u.tx.Features().IsDelegated() {
+		return u.tx.WithSignature(u.signerSig), nil
+	}
+	if len(u.delegatorSig) == 0 {
+		return nil, errors.New("delegator signature missing")
+	}
+	return u.tx.WithSignature(append(append([]byte(nil), u.signerSig...), u.delegatorSig...)), nil
+}
+
+type unsignedRLP struct {
+	Tx           *Transaction
+	Signer       *thor.Address `rlp:"nil"`
+	SignerSig    []byte
+	DelegatorSig []byte
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (u *Unsigned) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &unsignedRLP{u.tx, u.signer, u.signerSig, u.delegatorSig})
+}
+
+// DecodeRLP implements rlp.Decoder. Signatures are validated again.
+func (u *Unsigned) DecodeRLP(s *rlp.Stream) error {
+	var obj unsignedRLP
+	if err := s.Decode(&obj); err != nil {
+		return err
+	}
+	if len(obj.Tx.body.Signature) > 0 {
+		return errors.New("unexpected tx signature")
+	}
+
+	decoded, err := restoreUnsigned(obj.Tx, obj.Signer, obj.SignerSig, obj.DelegatorSig)
+	if err != nil {
+		return err
+	}
+	*u = *decoded
+	return nil
+}
+
+// restoreUnsigned restores unsigned tx from decoded parts, with signatures validated.
+func restoreUnsigned(tx *Transaction, signer *thor.Address, signerSig, delegatorSig []byte) (*Unsigned, error) {
+	u := &Unsigned{tx: tx, signer: signer}
+	if len(signerSig) > 0 {
+		if err := u.AddSignerSignature(signerSig); err != nil {
+			return nil, err
+		}
+	}
+	if len(delegatorSig) > 0 {
+		if signer == nil {
+			return nil, errors.New("signer missing")
+		}
+		if err := u.AddDelegatorSignature(*signer, delegatorSig); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/thor"
+)
+
+// jsonClause is the json form of clause.
+type jsonClause struct {
+	To    *thor.Address         `json:"to"`
+	Value *math.HexOrDecimal256 `json:"value"`
+	Data  hexutil.Bytes         `json:"data"`
+}
+
+// jsonUnsigned is the json interchange form of unsigned tx.
+// Field names and value formats are kept stable, to let txs be built online and signed offline.
+type jsonUnsigned struct {
+	ChainTag           uint8          `json:"chainTag"`
+	BlockRef           hexutil.Bytes  `json:"blockRef"`
+	Expiration         uint32         `json:"expiration"`
+	Clauses            []jsonClause   `json:"clauses"`
+	GasPriceCoef       uint8          `json:"gasPriceCoef"`
+	Gas                uint64         `json:"gas"`
+	DependsOn          *thor.Bytes32  `json:"dependsOn"`
+	Nonce              hexutil.Uint64 `json:"nonce"`
+	Features           Features       `json:"features"`
+	Signer             *thor.Address  `json:"signer,omitempty"`
+	SignerSignature    hexutil.Bytes  `json:"signerSignature,omitempty"`
+	DelegatorSignature hexutil.Bytes  `json:"delegatorSignature,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u *Unsigned) MarshalJSON() ([]byte, error) {
+	if u.tx.HasReservedFields() {
+		return nil, errors.New("reserved fields not supported")
+	}
+	br := u.tx.BlockRef()
+	obj := jsonUnsigned{
+		ChainTag:           u.tx.ChainTag(),
+		BlockRef:           br[:],
+		Expiration:         u.tx.Expiration(),
+		Clauses:            make([]jsonClause, 0, len(u.tx.Clauses())),
+		GasPriceCoef:       u.tx.GasPriceCoef(),
+		Gas:                u.tx.Gas(),
+		DependsOn:          u.tx.DependsOn(),
+		Nonce:              hexutil.Uint64(u.tx.Nonce()),
+		Features:           u.tx.Features(),
+		Signer:             u.Signer(),
+		SignerSignature:    u.signerSig,
+		DelegatorSignature: u.delegatorSig,
+	}
+	for _, c := range u.tx.Clauses() {
+		obj.Clauses = append(obj.Clauses, jsonClause{
+			c.To(),
+			(*math.HexOrDecimal256)(c.Value()),
+			c.Data(),
+		})
+	}
+	return json.Marshal(&obj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Signatures are validated again.
+func (u *Unsigned) UnmarshalJSON(data []byte) error {
+	var obj jsonUnsigned
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	var br BlockRef
+	if len(obj.BlockRef) != len(br) {
+		return errors.New("invalid block ref length")
+	}
+	copy(br[:], obj.BlockRef)
+
+	builder := new(Builder).
+		ChainTag(obj.ChainTag).
+		BlockRef(br).
+		Expiration(obj.Expiration).
+		GasPriceCoef(obj.GasPriceCoef).
+		Gas(obj.Gas).
+		DependsOn(obj.DependsOn).
+		Nonce(uint64(obj.Nonce)).
+		Features(obj.Features)
+	for _, c := range obj.Clauses {
+		clause := NewClause(c.To).WithData(c.Data)
+		if c.Value != nil {
+			clause = clause.WithValue((*big.Int)(c.Value))
+		}
+		if clause.Value().Sign() < 0 {
+			return errors.New("negative clause value")
+		}
+		builder.Clause(clause)
+	}
+
+	decoded, err := restoreUnsigned(builder.Build(), obj.Signer, obj.SignerSignature, obj.DelegatorSignature)
+	if err != nil {
+		return err
+	}
+	*u = *decoded
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestUnsigned(t *testing.T) {
+	signer, _ := crypto.GenerateKey()
+	delegator, _ := crypto.GenerateKey()
+	signerAddr := thor.Address(crypto.PubkeyToAddress(signer.PublicKey))
+	delegatorAddr := thor.Address(crypto.PubkeyToAddress(delegator.PublicKey))
+
+	u := tx.NewUnsigned(new(tx.Builder).ChainTag(1).Features(tx.DelegationFeature).Build())
+
+	// gas payer signs first
+	assert.Nil(t, u.SignAsDelegator(signerAddr, delegator))
+	_, err := u.Assemble()
+	assert.NotNil(t, err, "signer signature missing")
+
+	// passed to another service
+	data, err := rlp.EncodeToBytes(u)
+	assert.Nil(t, err)
+	var u2 tx.Unsigned
+	assert.Nil(t, rlp.DecodeBytes(data, &u2))
+	assert.Equal(t, signerAddr, *u2.Signer())
+
+	other, _ := crypto.GenerateKey()
+	assert.NotNil(t, u2.Sign(other), "signer mismatch")
+	assert.Nil(t, u2.Sign(signer))
+
+	trx, err := u2.Assemble()
+	assert.Nil(t, err)
+	s, err := trx.Signer()
+	assert.Nil(t, err)
+	assert.Equal(t, signerAddr, s)
+	d, err := trx.Delegator()
+	assert.Nil(t, err)
+	assert.Equal(t, delegatorAddr, *d)
+
+	// signatures collected separately
+	u3 := tx.NewUnsigned(u.Transaction())
+	assert.Nil(t, u3.Sign(signer))
+	assert.Nil(t, u.Merge(u3))
+	trx, err = u.Assemble()
+	assert.Nil(t, err)
+	d, _ = trx.Delegator()
+	assert.Equal(t, delegatorAddr, *d)
+	assert.NotNil(t, u.Merge(tx.NewUnsigned(new(tx.Builder).ChainTag(2).Build())), "tx mismatch")
+
+	// not delegated
+	u = tx.NewUnsigned(new(tx.Builder).ChainTag(1).Build())
+	assert.NotNil(t, u.SignAsDelegator(signerAddr, delegator))
+	assert.Nil(t, u.Sign(signer))
+	trx, err = u.Assemble()
+	assert.Nil(t, err)
+	s, _ = trx.Signer()
+	assert.Equal(t, signerAddr, s)
+}
+
+func TestUnsignedJSON(t *testing.T) {
+	to, _ := thor.ParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+	dependsOn := thor.BytesToBytes32([]byte("dep"))
+	u := tx.NewUnsigned(new(tx.Builder).ChainTag(1).
+		BlockRef(tx.BlockRef{0, 0, 0, 0, 0xaa, 0xbb, 0xcc, 0xdd}).
+		Expiration(32).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10000)).WithData([]byte{0, 0, 0, 0x60, 0x60, 0x60})).
+		Clause(tx.NewClause(nil)).
+		GasPriceCoef(128).
+		Gas(21000).
+		DependsOn(&dependsOn).
+		Nonce(12345678).Build())
+
+	data, err := json.Marshal(u)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"chainTag":1,"blockRef":"0x00000000aabbccdd","expiration":32,`+
+		`"clauses":[{"to":"0x7567d83b7b8d80addcb281a71d54fc7b3364ffed","value":"0x2710","data":"0x000000606060"},{"to":null,"value":"0x0","data":"0x"}],`+
+		`"gasPriceCoef":128,"gas":21000,"dependsOn":"`+dependsOn.String()+`","nonce":"0xbc614e","features":0}`, string(data))
+
+	var u2 tx.Unsigned
+	assert.Nil(t, json.Unmarshal(data, &u2))
+	assert.Equal(t, u.Transaction().SigningHash(), u2.Transaction().SigningHash())
+	assert.Nil(t, u2.Signer())
+
+	// signed offline and passed back
+	signer, _ := crypto.GenerateKey()
+	assert.Nil(t, u2.Sign(signer))
+	data, err = json.Marshal(&u2)
+	assert.Nil(t, err)
+
+	var u3 tx.Unsigned
+	assert.Nil(t, json.Unmarshal(data, &u3))
+	assert.Equal(t, u2.Signer(), u3.Signer())
+	trx, err := u3.Assemble()
+	assert.Nil(t, err)
+	s, _ := trx.Signer()
+	assert.Equal(t, *u2.Signer(), s)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"blockRef":"0x00"}`), &u3), "bad block ref")
+	assert.NotNil(t, json.Unmarshal([]byte(`{"blockRef":"0x0000000000000000","signerSignature":"0x00"}`), &u3), "bad signature")
+}
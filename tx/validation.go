@@ -0,0 +1,68 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx
+
+import (
+	"errors"
+
+	"github.com/vechain/thor/thor"
+)
+
+const (
+	// MaxSize max size in bytes of RLP encoded tx, which is accepted by tx pool.
+	MaxSize = 64 * 1024
+
+	// MaxBlockRefAhead max count of blocks that block ref can be ahead of head block,
+	// for tx to be accepted by tx pool.
+	MaxBlockRefAhead = uint32(3600 * 24 / thor.BlockInterval)
+)
+
+// IsSizeExceeded returns whether the tx size exceeds MaxSize.
+func (t *Transaction) IsSizeExceeded() bool {
+	return t.Size() > MaxSize
+}
+
+// IsRefFuture returns whether the block ref refers to a block after the given block number.
+// Such tx can't be included in the block of the given number.
+func (t *Transaction) IsRefFuture(blockNum uint32) bool {
+	return t.BlockRef().Number() > blockNum
+}
+
+// IsRefOutOfSchedule returns whether the block ref is too far ahead of the head block.
+func (t *Transaction) IsRefOutOfSchedule(headBlockNum uint32) bool {
+	return uint64(t.BlockRef().Number()) > uint64(headBlockNum)+uint64(MaxBlockRefAhead) // cast to uint64 to prevent potential overflow
+}
+
+// Validate performs stateless validations done by node before accepting the tx, for the given head block number.
+// It's useful for wallets and relayers to pre-validate tx.
+func (t *Transaction) Validate(chainTag byte, forkConfig thor.ForkConfig, headBlockNum uint32) error {
+	switch {
+	case t.ChainTag() != chainTag:
+		return errors.New("chain tag mismatch")
+	case t.HasReservedFields():
+		return errors.New("reserved fields not empty")
+	case !t.Features().IsSupported(forkConfig, headBlockNum+1):
+		return errors.New("unsupported features")
+	case t.IsSizeExceeded():
+		return errors.New("size too large")
+	case t.IsExpired(headBlockNum):
+		return errors.New("expired")
+	case t.IsRefOutOfSchedule(headBlockNum):
+		return errors.New("block ref out of schedule")
+	}
+
+	if _, err := t.Signer(); err != nil {
+		return err
+	}
+	intrinsicGas, err := t.IntrinsicGas()
+	if err != nil {
+		return err
+	}
+	if t.Gas() < intrinsicGas {
+		return errors.New("intrinsic gas exceeds provided gas")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package tx_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+func TestClauseIntrinsicGas(t *testing.T) {
+	gas, err := tx.ClauseIntrinsicGas(tx.NewClause(&thor.Address{}).WithData([]byte{0, 1}))
+	assert.Nil(t, err)
+	assert.Equal(t, thor.ClauseGas+4+68, gas)
+
+	gas, err = tx.ClauseIntrinsicGas(tx.NewClause(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, thor.ClauseGasContractCreation, gas)
+}
+
+func TestValidate(t *testing.T) {
+	forkConfig := thor.ForkConfig{}
+	key, _ := crypto.GenerateKey()
+	build := func(b *tx.Builder) *tx.Transaction {
+		trx := b.Build()
+		sig, _ := crypto.Sign(trx.SigningHash().Bytes(), key)
+		return trx.WithSignature(sig)
+	}
+
+	trx := build(new(tx.Builder).ChainTag(1).BlockRef(tx.NewBlockRef(10)).Expiration(10).Gas(21000))
+	assert.Nil(t, trx.Validate(1, forkConfig, 10))
+	assert.Nil(t, trx.Validate(1, forkConfig, 20))
+	assert.NotNil(t, trx.Validate(2, forkConfig, 10), "chain tag mismatch")
+	assert.NotNil(t, trx.Validate(1, forkConfig, 21), "expired")
+
+	assert.True(t, trx.IsRefFuture(9))
+	assert.False(t, trx.IsRefFuture(10))
+	assert.Nil(t, trx.Validate(1, forkConfig, 9), "future ref is acceptable by pool")
+
+	far := build(new(tx.Builder).ChainTag(1).BlockRef(tx.NewBlockRef(tx.MaxBlockRefAhead + 1)).Gas(21000))
+	assert.True(t, far.IsRefOutOfSchedule(0))
+	assert.NotNil(t, far.Validate(1, forkConfig, 0))
+
+	lowGas := build(new(tx.Builder).ChainTag(1).Gas(20000))
+	assert.NotNil(t, lowGas.Validate(1, forkConfig, 0), "intrinsic gas")
+
+	unsigned := new(tx.Builder).ChainTag(1).Gas(21000).Build()
+	assert.NotNil(t, unsigned.Validate(1, forkConfig, 0), "signer unavailable")
+
+	big := build(new(tx.Builder).ChainTag(1).Gas(21000).Clause(tx.NewClause(nil).WithData(make([]byte, tx.MaxSize))))
+	assert.True(t, big.IsSizeExceeded())
+	assert.NotNil(t, big.Validate(1, forkConfig, 0))
+
+	delegated := build(new(tx.Builder).ChainTag(1).Gas(21000).Features(tx.DelegationFeature))
+	assert.EqualError(t, delegated.Validate(1, thor.NoFork, 0), "unsupported features")
+}
+
+func TestFeaturesSupported(t *testing.T) {
+	forkConfig := thor.ForkConfig{VIP191: 10}
+
+	assert.True(t, tx.Features(0).IsSupported(forkConfig, 0))
+	assert.False(t, tx.DelegationFeature.IsSupported(forkConfig, 9))
+	assert.True(t, tx.DelegationFeature.IsSupported(forkConfig, 10))
+	assert.False(t, tx.Features(2).IsSupported(forkConfig, 10), "unknown feature")
+	assert.False(t, tx.DelegationFeature.IsSupported(thor.NoFork, math.MaxUint32-1))
+}
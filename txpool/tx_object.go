@@ -51,7 +51,7 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 		return false, errors.New("gas too large")
 	case o.IsExpired(headBlock.Number()):
 		return false, errors.New("expired")
-	case o.BlockRef().Number() > headBlock.Number()+uint32(3600*24/thor.BlockInterval):
+	case o.IsRefOutOfSchedule(headBlock.Number()):
 		return false, errors.New("block ref out of schedule")
 	}
 
@@ -76,7 +76,7 @@ func (o *txObject) Executable(chain *chain.Chain, state *state.State, headBlock
 		}
 	}
 
-	if o.BlockRef().Number() > headBlock.Number() {
+	if o.IsRefFuture(headBlock.Number()) {
 		return false, nil
 	}
 
@@ -17,12 +17,14 @@ import (
 type txObjectMap struct {
 	lock     sync.RWMutex
 	txObjMap map[thor.Bytes32]*txObject
+	idMap    map[thor.Bytes32]*txObject // by tx id, the first added one kept if txs of the same id
 	quota    map[thor.Address]int
 }
 
 func newTxObjectMap() *txObjectMap {
 	return &txObjectMap{
 		txObjMap: make(map[thor.Bytes32]*txObject),
+		idMap:    make(map[thor.Bytes32]*txObject),
 		quota:    make(map[thor.Address]int),
 	}
 }
@@ -48,9 +50,23 @@ func (m *txObjectMap) Add(txObj *txObject, limitPerAccount int) error {
 
 	m.quota[txObj.Origin()]++
 	m.txObjMap[txObj.Hash()] = txObj
+	m.indexID(txObj)
 	return nil
 }
 
+// GetByID returns the tx object of the tx id, nil if not found.
+func (m *txObjectMap) GetByID(txID thor.Bytes32) *txObject {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.idMap[txID]
+}
+
+func (m *txObjectMap) indexID(txObj *txObject) {
+	if _, found := m.idMap[txObj.ID()]; !found {
+		m.idMap[txObj.ID()] = txObj
+	}
+}
+
 func (m *txObjectMap) Remove(txHash thor.Bytes32) bool {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -62,6 +78,9 @@ func (m *txObjectMap) Remove(txHash thor.Bytes32) bool {
 			delete(m.quota, txObj.Origin())
 		}
 		delete(m.txObjMap, txHash)
+		if m.idMap[txObj.ID()] == txObj {
+			delete(m.idMap, txObj.ID())
+		}
 		return true
 	}
 	return false
@@ -100,6 +119,7 @@ func (m *txObjectMap) Fill(txObjs []*txObject) {
 
 		m.quota[txObj.Origin()]++
 		m.txObjMap[txObj.Hash()] = txObj
+		m.indexID(txObj)
 	}
 }
 
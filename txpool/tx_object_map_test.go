@@ -45,8 +45,12 @@ func TestTxObjMap(t *testing.T) {
 	assert.False(t, m.Contains(tx2.Hash()))
 	assert.True(t, m.Contains(tx3.Hash()))
 
+	assert.Equal(t, txObj1, m.GetByID(tx1.ID()))
+	assert.Nil(t, m.GetByID(tx2.ID()))
+
 	assert.True(t, m.Remove(tx1.Hash()))
 	assert.False(t, m.Contains(tx1.Hash()))
+	assert.Nil(t, m.GetByID(tx1.ID()))
 	assert.False(t, m.Remove(tx2.Hash()))
 
 	assert.Equal(t, []*txObject{txObj3}, m.ToTxObjects())
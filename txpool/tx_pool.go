@@ -23,11 +23,6 @@ import (
 	"github.com/vechain/thor/tx"
 )
 
-const (
-	// max size of tx allowed
-	maxTxSize = 64 * 1024
-)
-
 var (
 	log = log15.New("pkg", "txpool")
 )
@@ -45,32 +40,43 @@ type TxEvent struct {
 	Executable *bool
 }
 
+// TxDropEvent will be posted when tx is washed out of the pool.
+type TxDropEvent struct {
+	Tx     *tx.Transaction
+	Reason string
+}
+
 // TxPool maintains unprocessed transactions.
 type TxPool struct {
-	options      Options
+	options      atomic.Value
+	clock        atomic.Value // func() uint64, returns current timestamp, to tell whether the chain is synced
 	chain        *chain.Chain
 	stateCreator *state.Creator
+	forkConfig   thor.ForkConfig
 
 	executables    atomic.Value
 	all            *txObjectMap
 	addedAfterWash uint32
 
-	done   chan struct{}
-	txFeed event.Feed
-	scope  event.SubscriptionScope
-	goes   co.Goes
+	done       chan struct{}
+	txFeed     event.Feed
+	txDropFeed event.Feed
+	scope      event.SubscriptionScope
+	goes       co.Goes
 }
 
 // New create a new TxPool instance.
 // Shutdown is required to be called at end.
 func New(chain *chain.Chain, stateCreator *state.Creator, options Options) *TxPool {
 	pool := &TxPool{
-		options:      options,
 		chain:        chain,
 		stateCreator: stateCreator,
+		forkConfig:   thor.GetForkConfig(chain.GenesisBlock().Header().ID()),
 		all:          newTxObjectMap(),
 		done:         make(chan struct{}),
 	}
+	pool.options.Store(options)
+	pool.clock.Store(func() uint64 { return uint64(time.Now().Unix()) })
 	pool.goes.Go(pool.housekeeping)
 	return pool
 }
@@ -94,7 +100,7 @@ func (p *TxPool) housekeeping() {
 				headBlock = newHeadBlock
 				headBlockChanged = true
 			}
-			if !isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
+			if !isChainSynced(p.now(), headBlock.Timestamp()) {
 				// skip washing txs if not synced
 				continue
 			}
@@ -104,7 +110,7 @@ func (p *TxPool) housekeeping() {
 			// 2. pool size exceeds limit
 			// 3. new tx added while pool size is small
 			if headBlockChanged ||
-				poolLen > p.options.Limit ||
+				poolLen > p.Options().Limit ||
 				(poolLen < 200 && atomic.LoadUint32(&p.addedAfterWash) > 0) {
 
 				atomic.StoreUint32(&p.addedAfterWash, 0)
@@ -130,6 +136,27 @@ func (p *TxPool) housekeeping() {
 	}
 }
 
+// Options returns current options of the pool.
+func (p *TxPool) Options() Options {
+	return p.options.Load().(Options)
+}
+
+// SetOptions updates options of the pool at runtime.
+// New limits take effect on next tx adding or washing.
+func (p *TxPool) SetOptions(options Options) {
+	p.options.Store(options)
+}
+
+// SetClock replaces the wall clock, which the pool compares with the best block to tell whether the
+// chain is synced, and txs are checked and washed only if synced. E.g. the solo chain can go ahead of the wall clock.
+func (p *TxPool) SetClock(now func() uint64) {
+	p.clock.Store(now)
+}
+
+func (p *TxPool) now() uint64 {
+	return p.clock.Load().(func() uint64)()
+}
+
 // Close cleanup inner go routines.
 func (p *TxPool) Close() {
 	close(p.done)
@@ -143,6 +170,11 @@ func (p *TxPool) SubscribeTxEvent(ch chan *TxEvent) event.Subscription {
 	return p.scope.Track(p.txFeed.Subscribe(ch))
 }
 
+// SubscribeTxDropEvent receivers will receive txs washed out, with reasons.
+func (p *TxPool) SubscribeTxDropEvent(ch chan *TxDropEvent) event.Subscription {
+	return p.scope.Track(p.txDropFeed.Subscribe(ch))
+}
+
 func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 	if p.all.Contains(newTx.Hash()) {
 		// tx already in the pool
@@ -155,7 +187,9 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 		return badTxError{"chain tag mismatch"}
 	case newTx.HasReservedFields():
 		return badTxError{"reserved fields not empty"}
-	case newTx.Size() > maxTxSize:
+	case !newTx.Features().IsSupported(p.forkConfig, p.chain.BestBlock().Header().Number()+1):
+		return badTxError{"unsupported features"}
+	case newTx.IsSizeExceeded():
 		return txRejectedError{"size too large"}
 	}
 
@@ -164,8 +198,10 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 		return badTxError{err.Error()}
 	}
 
+	options := p.Options()
+
 	headBlock := p.chain.BestBlock().Header()
-	if isChainSynced(uint64(time.Now().Unix()), headBlock.Timestamp()) {
+	if isChainSynced(p.now(), headBlock.Timestamp()) {
 		state, err := p.stateCreator.NewState(headBlock.StateRoot())
 		if err != nil {
 			return err
@@ -180,7 +216,7 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 			return txRejectedError{"tx is not executable"}
 		}
 
-		if err := p.all.Add(txObj, p.options.LimitPerAccount); err != nil {
+		if err := p.all.Add(txObj, options.LimitPerAccount); err != nil {
 			return txRejectedError{err.Error()}
 		}
 
@@ -192,11 +228,11 @@ func (p *TxPool) add(newTx *tx.Transaction, rejectNonexecutable bool) error {
 	} else {
 		// we skip steps that rely on head block when chain is not synced,
 		// but check the pool's limit
-		if p.all.Len() >= p.options.Limit {
+		if p.all.Len() >= options.Limit {
 			return txRejectedError{"pool is full"}
 		}
 
-		if err := p.all.Add(txObj, p.options.LimitPerAccount); err != nil {
+		if err := p.all.Add(txObj, options.LimitPerAccount); err != nil {
 			return txRejectedError{err.Error()}
 		}
 		log.Debug("tx added", "id", newTx.ID())
@@ -246,6 +282,14 @@ func (p *TxPool) Fill(txs tx.Transactions) {
 	p.all.Fill(txObjs)
 }
 
+// Get returns the tx of the id in the pool, nil if not found.
+func (p *TxPool) Get(txID thor.Bytes32) *tx.Transaction {
+	if txObj := p.all.GetByID(txID); txObj != nil {
+		return txObj.Transaction
+	}
+	return nil
+}
+
 // Dump dumps all txs in the pool.
 func (p *TxPool) Dump() tx.Transactions {
 	return p.all.ToTxs()
@@ -255,23 +299,43 @@ func (p *TxPool) Dump() tx.Transactions {
 // this method should only be called in housekeeping go routine
 func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, removed int, err error) {
 	all := p.all.ToTxObjects()
-	var toRemove []*txObject
+	options := p.Options()
+	var (
+		toRemove []*txObject
+		reasons  []string
+	)
+	drop := func(txObj *txObject, reason string) {
+		toRemove = append(toRemove, txObj)
+		reasons = append(reasons, reason)
+	}
 	defer func() {
+		var dropped []*TxDropEvent
 		if err != nil {
 			// in case of error, simply cut pool size to limit
 			for i, txObj := range all {
-				if len(all)-i <= p.options.Limit {
+				if len(all)-i <= options.Limit {
 					break
 				}
 				removed++
-				p.all.Remove(txObj.Hash())
+				if p.all.Remove(txObj.Hash()) {
+					dropped = append(dropped, &TxDropEvent{txObj.Transaction, "pool limit"})
+				}
 			}
 		} else {
-			for _, txObj := range toRemove {
-				p.all.Remove(txObj.Hash())
+			for i, txObj := range toRemove {
+				if p.all.Remove(txObj.Hash()) {
+					dropped = append(dropped, &TxDropEvent{txObj.Transaction, reasons[i]})
+				}
 			}
 			removed = len(toRemove)
 		}
+		if len(dropped) > 0 {
+			p.goes.Go(func() {
+				for _, ev := range dropped {
+					p.txDropFeed.Send(ev)
+				}
+			})
+		}
 	}()
 
 	state, err := p.stateCreator.NewState(headBlock.StateRoot())
@@ -287,15 +351,15 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 	)
 	for _, txObj := range all {
 		// out of lifetime
-		if now > txObj.timeAdded+int64(p.options.MaxLifetime) {
-			toRemove = append(toRemove, txObj)
+		if now > txObj.timeAdded+int64(options.MaxLifetime) {
+			drop(txObj, "out of lifetime")
 			log.Debug("tx washed out", "id", txObj.ID(), "err", "out of lifetime")
 			continue
 		}
 		// settled, out of energy or dep broken
 		executable, err := txObj.Executable(p.chain, state, headBlock)
 		if err != nil {
-			toRemove = append(toRemove, txObj)
+			drop(txObj, err.Error())
 			log.Debug("tx washed out", "id", txObj.ID(), "err", err)
 			continue
 		}
@@ -322,23 +386,23 @@ func (p *TxPool) wash(headBlock *block.Header) (executables tx.Transactions, rem
 	// sort objs by price from high to low
 	sortTxObjsByOverallGasPriceDesc(executableObjs)
 
-	limit := p.options.Limit
+	limit := options.Limit
 
 	// remove over limit txs, from non-executables to low priced
 	if len(executableObjs) > limit {
 		for _, txObj := range nonExecutableObjs {
-			toRemove = append(toRemove, txObj)
+			drop(txObj, "pool limit")
 			log.Debug("non-executable tx washed out due to pool limit", "id", txObj.ID())
 		}
 		for _, txObj := range executableObjs[limit:] {
-			toRemove = append(toRemove, txObj)
+			drop(txObj, "pool limit")
 			log.Debug("executable tx washed out due to pool limit", "id", txObj.ID())
 		}
 		executableObjs = executableObjs[:limit]
 	} else if len(executableObjs)+len(nonExecutableObjs) > limit {
 		// executableObjs + nonExecutableObjs over pool limit
 		for _, txObj := range nonExecutableObjs[limit-len(executableObjs):] {
-			toRemove = append(toRemove, txObj)
+			drop(txObj, "pool limit")
 			log.Debug("non-executable tx washed out due to pool limit", "id", txObj.ID())
 		}
 	}
@@ -62,6 +62,29 @@ func TestSubscribeNewTx(t *testing.T) {
 	assert.Equal(t, &TxEvent{tx, &v}, <-txCh)
 }
 
+func TestSubscribeTxDrop(t *testing.T) {
+	kv, _ := lvldb.NewMem()
+	chain := newChain(kv)
+	pool := New(chain, state.NewCreator(kv), Options{
+		Limit:           10,
+		LimitPerAccount: 2,
+		MaxLifetime:     time.Millisecond,
+	})
+	defer pool.Close()
+
+	dropCh := make(chan *TxDropEvent)
+	pool.SubscribeTxDropEvent(dropCh)
+
+	tx := newTx(pool.chain.Tag(), nil, 21000, tx.BlockRef{}, 100, nil, genesis.DevAccounts()[0])
+	assert.Nil(t, pool.Add(tx))
+	time.Sleep(2 * time.Millisecond)
+
+	txs, _, err := pool.wash(pool.chain.BestBlock().Header())
+	assert.Nil(t, err)
+	assert.Zero(t, len(txs))
+	assert.Equal(t, &TxDropEvent{tx, "out of lifetime"}, <-dropCh)
+}
+
 func TestWashTxs(t *testing.T) {
 	pool := newPool()
 	defer pool.Close()
@@ -0,0 +1,150 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+// Package txsign helps to sign txs on offline machines.
+//
+// The workflow is:
+//  1. build the tx online, and wrap it with tx.NewUnsigned
+//  2. Encode (or EncodeToString) it, and carry it to the offline machine
+//  3. Decode it offline, check the tx, and sign it with Sign, or sign NewPayload with a hardware wallet
+//  4. carry the signed envelope back and Merge it into the original one, or add bare signatures to it
+//  5. Assemble the final tx and Verify the parties before sending
+//
+// The envelope is RLP encoded with a leading version, which is kept stable across releases.
+// Incompatible changes come with a new version, and envelopes of unknown versions are rejected.
+package txsign
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+)
+
+// Version of the envelope format.
+const Version = 1
+
+type envelope struct {
+	Version  uint
+	Unsigned *tx.Unsigned
+}
+
+// Encode encodes the unsigned tx, with signatures collected, into an envelope.
+func Encode(u *tx.Unsigned) ([]byte, error) {
+	return rlp.EncodeToBytes(&envelope{Version, u})
+}
+
+// Decode decodes the envelope. Signatures are validated.
+func Decode(data []byte) (*tx.Unsigned, error) {
+	var head struct {
+		Version uint
+		Rest    rlp.RawValue `rlp:"tail"`
+	}
+	if err := rlp.DecodeBytes(data, &head); err != nil {
+		return nil, err
+	}
+	if head.Version != Version {
+		return nil, fmt.Errorf("unsupported envelope version %v", head.Version)
+	}
+	var env envelope
+	if err := rlp.DecodeBytes(data, &env); err != nil {
+		return nil, err
+	}
+	return env.Unsigned, nil
+}
+
+// EncodeToString encodes the envelope into 0x prefixed hex string, which suits text channels like QR codes.
+func EncodeToString(u *tx.Unsigned) (string, error) {
+	data, err := Encode(u)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
+// DecodeString decodes the envelope from hex string.
+func DecodeString(str string) (*tx.Unsigned, error) {
+	data, err := hexutil.Decode(str)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// Payload is what to be signed by a party. Hash is always blake2b of Message, so that devices
+// can display the decoded message, and hash it by themselves rather than trusting the given hash.
+type Payload struct {
+	// RLP encoded tx body for the signer,
+	// or signing hash of the tx followed by the signer address for the delegator.
+	Message []byte
+	Hash    thor.Bytes32
+}
+
+// NewPayload returns the payload for the signer, or for the delegator if delegateFor is given.
+func NewPayload(u *tx.Unsigned, delegateFor *thor.Address) *Payload {
+	trx := u.Transaction()
+	var msg []byte
+	if delegateFor == nil {
+		msg = trx.SigningMessage()
+	} else {
+		msg = append(trx.SigningHash().Bytes(), delegateFor.Bytes()...)
+	}
+	return &Payload{msg, thor.Blake2b(msg)}
+}
+
+// SignPayload signs the payload with the private key, after the hash is checked against the message.
+func SignPayload(p *Payload, priv *ecdsa.PrivateKey) ([]byte, error) {
+	if thor.Blake2b(p.Message) != p.Hash {
+		return nil, errors.New("payload hash mismatch")
+	}
+	return crypto.Sign(p.Hash.Bytes(), priv)
+}
+
+// Sign signs the unsigned tx as the signer, or as the delegator if delegateFor is given.
+func Sign(u *tx.Unsigned, delegateFor *thor.Address, priv *ecdsa.PrivateKey) error {
+	if delegateFor == nil {
+		return u.Sign(priv)
+	}
+	return u.SignAsDelegator(*delegateFor, priv)
+}
+
+// Merge merges signatures collected by the signed envelope into u.
+// Both should be of the same tx.
+func Merge(u, signed *tx.Unsigned) error {
+	if signed.Signer() == nil {
+		return errors.New("no signature to merge")
+	}
+	return u.Merge(signed)
+}
+
+// Verify verifies the assembled tx is signed by the expected signer, and delegated by the expected
+// delegator if given.
+func Verify(trx *tx.Transaction, signer thor.Address, delegator *thor.Address) error {
+	s, err := trx.Signer()
+	if err != nil {
+		return err
+	}
+	if s != signer {
+		return fmt.Errorf("unexpected signer %v", s)
+	}
+	d, err := trx.Delegator()
+	if err != nil {
+		return err
+	}
+	switch {
+	case delegator == nil && d != nil:
+		return fmt.Errorf("unexpected delegator %v", d)
+	case delegator != nil && d == nil:
+		return errors.New("not delegated")
+	case delegator != nil && *d != *delegator:
+		return fmt.Errorf("unexpected delegator %v", d)
+	}
+	return nil
+}
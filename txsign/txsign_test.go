@@ -0,0 +1,102 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package txsign_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/vechain/thor/thor"
+	"github.com/vechain/thor/tx"
+	"github.com/vechain/thor/txsign"
+)
+
+func newTx(features tx.Features) *tx.Transaction {
+	to, _ := thor.ParseAddress("0x7567d83b7b8d80addcb281a71d54fc7b3364ffed")
+	return new(tx.Builder).
+		ChainTag(0x27).
+		BlockRef(tx.NewBlockRefFromID(thor.Bytes32{0, 0, 0, 1})).
+		Expiration(720).
+		Clause(tx.NewClause(&to).WithValue(big.NewInt(10000))).
+		GasPriceCoef(128).
+		Gas(21000).
+		Nonce(12345678).
+		Features(features).
+		Build()
+}
+
+// envelope of newTx(0), which must never change within the version
+const v1Envelope = "0xf701f5f1278501000000008202d0dad9947567d83b7b8d80addcb281a71d54fc7b3364ffed8227108081808252088083bc614ec080808080"
+
+func TestEncoding(t *testing.T) {
+	u := tx.NewUnsigned(newTx(0))
+	str, err := txsign.EncodeToString(u)
+	assert.Nil(t, err)
+	assert.Equal(t, v1Envelope, str)
+
+	decoded, err := txsign.DecodeString(v1Envelope)
+	assert.Nil(t, err)
+	assert.Equal(t, u.Transaction().ID(), decoded.Transaction().ID())
+	assert.Nil(t, decoded.Signer())
+
+	// unknown version
+	data, _ := rlp.EncodeToBytes([]interface{}{uint(2), u})
+	_, err = txsign.Decode(data)
+	assert.NotNil(t, err)
+
+	_, err = txsign.DecodeString("0x")
+	assert.NotNil(t, err)
+}
+
+func TestOfflineSigning(t *testing.T) {
+	signer, _ := crypto.GenerateKey()
+	delegator, _ := crypto.GenerateKey()
+	signerAddr := thor.Address(crypto.PubkeyToAddress(signer.PublicKey))
+	delegatorAddr := thor.Address(crypto.PubkeyToAddress(delegator.PublicKey))
+
+	online := tx.NewUnsigned(newTx(tx.DelegationFeature))
+	data, err := txsign.Encode(online)
+	assert.Nil(t, err)
+
+	// the signer signs offline with a device
+	offline, err := txsign.Decode(data)
+	assert.Nil(t, err)
+	payload := txsign.NewPayload(offline, nil)
+	assert.Equal(t, offline.Transaction().SigningHash(), payload.Hash)
+	sig, err := txsign.SignPayload(payload, signer)
+	assert.Nil(t, err)
+	assert.Nil(t, offline.AddSignerSignature(sig))
+	data, err = txsign.Encode(offline)
+	assert.Nil(t, err)
+
+	signed, err := txsign.Decode(data)
+	assert.Nil(t, err)
+	assert.Nil(t, txsign.Merge(online, signed))
+	assert.Equal(t, signerAddr, *online.Signer())
+	_, err = online.Assemble()
+	assert.NotNil(t, err, "delegator signature missing")
+
+	// the delegator signs
+	payload = txsign.NewPayload(online, &signerAddr)
+	assert.Equal(t, online.Transaction().DelegatorSigningHash(signerAddr), payload.Hash)
+	payload.Hash = thor.Bytes32{}
+	_, err = txsign.SignPayload(payload, delegator)
+	assert.NotNil(t, err, "hash mismatch")
+	assert.Nil(t, txsign.Sign(online, &signerAddr, delegator))
+
+	trx, err := online.Assemble()
+	assert.Nil(t, err)
+	assert.Nil(t, txsign.Verify(trx, signerAddr, &delegatorAddr))
+	assert.NotNil(t, txsign.Verify(trx, signerAddr, nil))
+	assert.NotNil(t, txsign.Verify(trx, delegatorAddr, &delegatorAddr))
+
+	// nothing to merge, or of another tx
+	assert.NotNil(t, txsign.Merge(online, tx.NewUnsigned(newTx(tx.DelegationFeature))))
+	assert.NotNil(t, txsign.Merge(online, tx.NewUnsigned(newTx(0))))
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// CallTracer is a Tracer also notified when entering and exiting internal calls.
+// Tracers passed by Config are checked against it.
+type CallTracer interface {
+	Tracer
+	CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
+	CaptureExit(output []byte, gasUsed uint64, err error)
+}
+
+// CallFrame is a node of the call tree.
+type CallFrame struct {
+	Type         string                `json:"type"`
+	From         common.Address        `json:"from"`
+	To           common.Address        `json:"to"`
+	Value        *math.HexOrDecimal256 `json:"value,omitempty"`
+	Gas          uint64                `json:"gas"`
+	GasUsed      uint64                `json:"gasUsed"`
+	Input        hexutil.Bytes         `json:"input"`
+	Output       hexutil.Bytes         `json:"output"`
+	Error        string                `json:"error,omitempty"`
+	RevertReason string                `json:"revertReason,omitempty"`
+	Calls        []*CallFrame          `json:"calls,omitempty"`
+}
+
+func (f *CallFrame) end(output []byte, gasUsed uint64, err error) {
+	f.Output = common.CopyBytes(output)
+	f.GasUsed = gasUsed
+	if err != nil {
+		f.Error = err.Error()
+		if err == errExecutionReverted {
+			f.RevertReason, _ = UnpackRevertReason(output)
+		}
+	}
+}
+
+// CallLogger is a CallTracer which records the call tree, including internal calls,
+// value transfers and gas usage.
+type CallLogger struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+// NewCallLogger returns a new call logger.
+func NewCallLogger() *CallLogger {
+	return &CallLogger{}
+}
+
+func (l *CallLogger) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := CALL
+	if create {
+		typ = CREATE
+	}
+	l.root = newCallFrame(typ, from, to, input, gas, value)
+	l.stack = []*CallFrame{l.root}
+	return nil
+}
+
+func (l *CallLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+func (l *CallLogger) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+func (l *CallLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	if l.root != nil {
+		l.root.end(output, gasUsed, err)
+	}
+	l.stack = nil
+	return nil
+}
+
+func (l *CallLogger) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if len(l.stack) == 0 {
+		return
+	}
+	frame := newCallFrame(typ, from, to, input, gas, value)
+	parent := l.stack[len(l.stack)-1]
+	parent.Calls = append(parent.Calls, frame)
+	l.stack = append(l.stack, frame)
+}
+
+func (l *CallLogger) CaptureExit(output []byte, gasUsed uint64, err error) {
+	// the root frame is ended by CaptureEnd
+	if len(l.stack) < 2 {
+		return
+	}
+	l.stack[len(l.stack)-1].end(output, gasUsed, err)
+	l.stack = l.stack[:len(l.stack)-1]
+}
+
+// Result returns the captured call tree. Nil returned if nothing captured.
+func (l *CallLogger) Result() *CallFrame { return l.root }
+
+func newCallFrame(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) *CallFrame {
+	frame := &CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    to,
+		Gas:   gas,
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*math.HexOrDecimal256)(new(big.Int).Set(value))
+	}
+	return frame
+}
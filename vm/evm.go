@@ -174,6 +174,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if !evm.Context.CanTransfer(evm.StateDB, caller.Address(), value) {
 		return nil, gas, ErrInsufficientBalance
 	}
+	if tracer, ok := evm.callTracer(); ok {
+		tracer.CaptureEnter(CALL, caller.Address(), addr, input, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	var (
 		to       = AccountRef(addr)
@@ -244,6 +248,10 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	if !evm.CanTransfer(evm.StateDB, caller.Address(), value) {
 		return nil, gas, ErrInsufficientBalance
 	}
+	if tracer, ok := evm.callTracer(); ok {
+		tracer.CaptureEnter(CALLCODE, caller.Address(), addr, input, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	var (
 		snapshot = evm.StateDB.Snapshot()
@@ -278,6 +286,10 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
 	}
+	if tracer, ok := evm.callTracer(); ok {
+		tracer.CaptureEnter(DELEGATECALL, caller.Address(), addr, input, gas, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	var (
 		snapshot = evm.StateDB.Snapshot()
@@ -310,6 +322,10 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	if evm.depth > int(params.CallCreateDepth) {
 		return nil, gas, ErrDepth
 	}
+	if tracer, ok := evm.callTracer(); ok {
+		tracer.CaptureEnter(STATICCALL, caller.Address(), addr, input, gas, new(big.Int))
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	// Make sure the readonly is only set if we aren't in readonly yet
 	// this makes also sure that the readonly flag isn't removed for
 	// child calls.
@@ -363,6 +379,11 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	contractAddr = evm.NewContractAddress(evm, evm.contractCreationCount)
 	evm.contractCreationCount++
 
+	if tracer, ok := evm.callTracer(); ok {
+		tracer.CaptureEnter(CREATE, caller.Address(), contractAddr, code, gas, value)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
+
 	//
 	contractHash := evm.StateDB.GetCodeHash(contractAddr)
 	if evm.StateDB.GetNonce(contractAddr) != 0 || (contractHash != (common.Hash{}) && contractHash != emptyCodeHash) {
@@ -433,6 +454,15 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 	return ret, contractAddr, contract.Gas, err
 }
 
+// callTracer returns the call tracer if configured, and the evm is performing internal calls.
+func (evm *EVM) callTracer() (CallTracer, bool) {
+	if !evm.vmConfig.Debug || evm.depth == 0 {
+		return nil, false
+	}
+	tracer, ok := evm.vmConfig.Tracer.(CallTracer)
+	return tracer, ok
+}
+
 // ChainConfig returns the environment's chain configuration
 func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 
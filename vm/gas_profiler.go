@@ -0,0 +1,200 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// GasProfileEntry is gas usage aggregated by contract address and function selector.
+type GasProfileEntry struct {
+	Address  common.Address `json:"address"`
+	Selector hexutil.Bytes  `json:"selector"` // empty if input shorter than 4 bytes, or contract creation
+	Calls    uint64         `json:"calls"`
+	Reverted uint64         `json:"reverted"`
+	GasUsed  uint64         `json:"gasUsed"` // including gas used by internal calls
+	SelfGas  uint64         `json:"selfGas"` // excluding gas used by internal calls
+}
+
+type gasProfileKey struct {
+	addr     common.Address
+	selector [4]byte
+	create   bool
+	short    bool
+}
+
+// GasProfile aggregates gas usage of executions profiled by GasProfiler.
+// It's safe for concurrent use.
+type GasProfile struct {
+	lock    sync.Mutex
+	entries map[gasProfileKey]*GasProfileEntry
+}
+
+// NewGasProfile creates an empty gas profile.
+func NewGasProfile() *GasProfile {
+	return &GasProfile{entries: make(map[gasProfileKey]*GasProfileEntry)}
+}
+
+func (p *GasProfile) add(key gasProfileKey, gasUsed, selfGas uint64, reverted bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	entry := p.entries[key]
+	if entry == nil {
+		entry = &GasProfileEntry{Address: key.addr}
+		if !key.create && !key.short {
+			entry.Selector = append(hexutil.Bytes(nil), key.selector[:]...)
+		}
+		p.entries[key] = entry
+	}
+	entry.Calls++
+	if reverted {
+		entry.Reverted++
+	}
+	entry.GasUsed += gasUsed
+	entry.SelfGas += selfGas
+}
+
+// Report returns copies of aggregated entries, sorted by self gas in descending order.
+func (p *GasProfile) Report() []*GasProfileEntry {
+	p.lock.Lock()
+	report := make([]*GasProfileEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		cpy := *entry
+		report = append(report, &cpy)
+	}
+	p.lock.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].SelfGas != report[j].SelfGas {
+			return report[i].SelfGas > report[j].SelfGas
+		}
+		if c := bytes.Compare(report[i].Address[:], report[j].Address[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(report[i].Selector, report[j].Selector) < 0
+	})
+	return report
+}
+
+// Reset clears aggregated entries.
+func (p *GasProfile) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.entries = make(map[gasProfileKey]*GasProfileEntry)
+}
+
+type gasProfileFrame struct {
+	key      gasProfileKey
+	childGas uint64
+}
+
+// GasProfiler is a CallTracer which attributes gas used by each call frame to the callee
+// address and function selector, and aggregates into the profile.
+// Events are forwarded to the inner tracer if any.
+type GasProfiler struct {
+	profile *GasProfile
+	inner   Tracer
+	stack   []gasProfileFrame
+}
+
+// NewGasProfiler creates a gas profiler aggregating into the profile. The inner tracer can be nil.
+func NewGasProfiler(profile *GasProfile, inner Tracer) *GasProfiler {
+	return &GasProfiler{profile: profile, inner: inner}
+}
+
+func (p *GasProfiler) push(typ OpCode, to common.Address, input []byte) {
+	key := gasProfileKey{addr: to}
+	switch {
+	case typ == CREATE:
+		key.create = true
+	case len(input) < 4:
+		key.short = true
+	default:
+		copy(key.selector[:], input)
+	}
+	p.stack = append(p.stack, gasProfileFrame{key: key})
+}
+
+func (p *GasProfiler) pop(gasUsed uint64, err error) {
+	if len(p.stack) == 0 {
+		return
+	}
+	frame := p.stack[len(p.stack)-1]
+	p.stack = p.stack[:len(p.stack)-1]
+
+	selfGas := uint64(0)
+	if gasUsed > frame.childGas {
+		selfGas = gasUsed - frame.childGas
+	}
+	p.profile.add(frame.key, gasUsed, selfGas, err != nil)
+	if len(p.stack) > 0 {
+		p.stack[len(p.stack)-1].childGas += gasUsed
+	}
+}
+
+func (p *GasProfiler) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := CALL
+	if create {
+		typ = CREATE
+	}
+	p.stack = p.stack[:0]
+	p.push(typ, to, input)
+	if p.inner != nil {
+		return p.inner.CaptureStart(from, to, create, input, gas, value)
+	}
+	return nil
+}
+
+func (p *GasProfiler) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	if p.inner != nil {
+		return p.inner.CaptureState(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	return nil
+}
+
+func (p *GasProfiler) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	if p.inner != nil {
+		return p.inner.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	return nil
+}
+
+func (p *GasProfiler) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	// internal frames left by aborted execution are discarded
+	if len(p.stack) > 1 {
+		p.stack = p.stack[:1]
+	}
+	p.pop(gasUsed, err)
+	if p.inner != nil {
+		return p.inner.CaptureEnd(output, gasUsed, t, err)
+	}
+	return nil
+}
+
+func (p *GasProfiler) CaptureEnter(typ OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	p.push(typ, to, input)
+	if tracer, ok := p.inner.(CallTracer); ok {
+		tracer.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (p *GasProfiler) CaptureExit(output []byte, gasUsed uint64, err error) {
+	// the root frame is popped by CaptureEnd
+	if len(p.stack) > 1 {
+		p.pop(gasUsed, err)
+	}
+	if tracer, ok := p.inner.(CallTracer); ok {
+		tracer.CaptureExit(output, gasUsed, err)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// revertReasonSelector is the method id of 'Error(string)'.
+	revertReasonSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the method id of 'Panic(uint256)'.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+	// panicReasons solidity panic codes.
+	panicReasons = map[uint64]string{
+		0x00: "generic panic",
+		0x01: "assert(false)",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "enum overflow",
+		0x22: "invalid encoded storage byte array accessed",
+		0x31: "out-of-bounds array access; popping on an empty array",
+		0x32: "out-of-bounds access of an array or bytesN",
+		0x41: "out of memory",
+		0x51: "uninitialized function",
+	}
+)
+
+// UnpackRevertReason decodes human-readable reason from revert data, which is
+// encoded as 'Error(string)' or 'Panic(uint256)'.
+func UnpackRevertReason(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+	switch {
+	case bytes.Equal(data[:4], revertReasonSelector):
+		return unpackErrorString(data[4:])
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) != 4+32 {
+			return "", false
+		}
+		code := new(big.Int).SetBytes(data[4:])
+		if code.IsUint64() {
+			if reason, ok := panicReasons[code.Uint64()]; ok {
+				return fmt.Sprintf("panic: %s (0x%x)", reason, code), true
+			}
+		}
+		return fmt.Sprintf("panic: unknown code 0x%x", code), true
+	}
+	return "", false
+}
+
+// unpackErrorString decodes abi encoded string.
+func unpackErrorString(data []byte) (string, bool) {
+	if len(data) < 64 {
+		return "", false
+	}
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data))-32 {
+		return "", false
+	}
+	start := offset.Uint64()
+	size := new(big.Int).SetBytes(data[start : start+32])
+	if !size.IsUint64() || size.Uint64() > uint64(len(data))-start-32 {
+		return "", false
+	}
+	return string(data[start+32 : start+32+size.Uint64()]), true
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2018 The VeChainThor developers
+
+// Distributed under the GNU Lesser General Public License v3.0 software license, see the accompanying
+// file LICENSE or <https://www.gnu.org/licenses/lgpl-3.0.html>
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnpackRevertReason(t *testing.T) {
+	tests := []struct {
+		data   string
+		reason string
+		ok     bool
+	}{
+		{"", "", false},
+		{"08c379a0", "", false},
+		{"08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000004" +
+			"626f6f6d00000000000000000000000000000000000000000000000000000000", "boom", true},
+		// size out of range
+		{"08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"00000000000000000000000000000000000000000000000000000000000000ff" +
+			"626f6f6d00000000000000000000000000000000000000000000000000000000", "", false},
+		// offset out of range
+		{"08c379a0" +
+			"ff00000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000004", "", false},
+		{"4e487b71" +
+			"0000000000000000000000000000000000000000000000000000000000000011", "panic: arithmetic underflow or overflow (0x11)", true},
+		{"4e487b71" +
+			"00000000000000000000000000000000000000000000000000000000000000ff", "panic: unknown code 0xff", true},
+		{"4e487b71", "", false},
+		{"12345678" +
+			"0000000000000000000000000000000000000000000000000000000000000011", "", false},
+	}
+	for _, tt := range tests {
+		reason, ok := UnpackRevertReason(common.Hex2Bytes(tt.data))
+		assert.Equal(t, tt.ok, ok, tt.data)
+		assert.Equal(t, tt.reason, reason, tt.data)
+	}
+}